@@ -16,11 +16,21 @@ def generate_config_file(rd_server_v,db_name_v,redis_ip_v,redis_port_v,redis_use
         os.mkdir(output)
 
     # apiserver.conf
-    apiserver_file_template_str ='''
-    '''
+    apiserver_file_template_str ='''[redis]
+host=$redis_host
+usr=$redis_user
+pwd=$redis_pass
+database=0
+port=$redis_port
+
+[cache]
+rule.objectattr = method=POST path=/api/v3/find/objectattr ttl=300
+rule.object = method=POST path=/api/v3/find/object ttl=300
+rule.topomodelmainline = method=POST path=/api/v3/find/topomodelmainline ttl=300
+'''
 
     template = FileTemplate(apiserver_file_template_str)
-    result = template.substitute()
+    result = template.substitute(dict(redis_host=redis_ip_v,redis_port=redis_port_v,redis_user=redis_user_v,redis_pass=redis_pass_v))
     with open( output + "apiserver.conf",'w') as tmp_file:
         tmp_file.write(result)
 
@@ -34,6 +44,14 @@ port = $mongo_port
 maxOpenConns = 3000
 maxIdleConns = 1000
 mechanism=SCRAM-SHA-1
+
+[audit]
+checkpointKey =
+checkpointInterval = 3600
+archiveDir =
+archiveInterval = 86400
+retentionDays = 0
+retentionRules =
 '''
     template = FileTemplate(auditcontroller_file_template_str)
     result = template.substitute(dict(db=db_name_v,mongo_user=mongo_user_v,mongo_host=mongo_ip_v,mongo_pass=mongo_pass_v,mongo_port=mongo_port_v))
@@ -173,6 +191,13 @@ maxOpenConns = 3000
 maxIDleConns = 1000
 mechanism=SCRAM-SHA-1
 
+[redis]
+host=$redis_host
+usr=$redis_user
+pwd=$redis_pass
+database=0
+port=$redis_port
+
 [confs]
 dir = $configures_dir
 
@@ -352,6 +377,34 @@ html_root=$ui_root
 
 [app]
 agent_app_url=${agent_url}/console/?app=bk_agent_setup
+
+[login]
+version=self
+
+[oidc]
+issuer=
+client_id=
+client_secret=
+redirect_url=
+scope=openid profile email
+username_claim=preferred_username
+groups_claim=groups
+role_mapping=
+default_role=
+user_list_url=
+
+[ldap]
+url=
+bind_dn=
+bind_password=
+base_dn=
+user_filter=(uid=%s)
+user_list_filter=(objectClass=person)
+username_attr=uid
+display_attr=cn
+group_attr=memberOf
+role_mapping=
+default_role=
 '''
     ui_root_v = os.getcwd()+"/web"
     template = FileTemplate(webserver_file_template_str)
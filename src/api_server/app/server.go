@@ -18,13 +18,18 @@ import (
 	"os"
 
 	"configcenter/src/api_server/app/options"
+	"configcenter/src/api_server/cache"
+	"configcenter/src/api_server/ratelimit"
 	apisvc "configcenter/src/api_server/service"
 	"configcenter/src/api_server/service/v3"
 	"configcenter/src/apimachinery/util"
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metric"
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
+	"configcenter/src/storage/dal/redis"
 
 	"github.com/emicklei/go-restful"
 )
@@ -37,6 +42,10 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 
 	v2Service := apisvc.NewService()
 	v3Service := new(v3.Service)
+	v3Service.Limiter = ratelimit.NewLimiter()
+	metric.DefaultRegistry.Register(v3Service.Limiter)
+	v3Service.Cache = cache.NewCache()
+	metric.DefaultRegistry.Register(v3Service.Cache)
 	v3Service.Client, err = util.NewClient(&util.TLSClientConfig{})
 	if err != nil {
 		return fmt.Errorf("new proxy client failed, err: %v", err)
@@ -46,9 +55,12 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	ctnr.Router(restful.CurlyRouter{})
 	ctnr.Add(v2Service.WebService())
 	ctnr.Add(v3Service.V3WebService())
+	ctnr.Add(v3Service.BatchWebService())
+	ctnr.Add(v3Service.OpenAPIWebService())
 	ctnr.Add(v3Service.V3Healthz())
+	v3Service.Handler = ctnr
 
-	apiSvr := new(APIServer)
+	apiSvr := &APIServer{ctx: ctx, Limiter: v3Service.Limiter, Cache: v3Service.Cache}
 	input := &backbone.BackboneParameter{
 		ConfigUpdate: apiSvr.onHostConfigUpdate,
 		ConfigPath:   op.ServConf.ExConfig,
@@ -71,11 +83,49 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 }
 
 type APIServer struct {
-	Core *backbone.Engine
+	ctx     context.Context
+	Core    *backbone.Engine
+	Limiter *ratelimit.Limiter
+	Cache   *cache.Cache
+	// watching whether the cache is watching the model events
+	watching bool
 }
 
 func (h *APIServer) onHostConfigUpdate(previous, current cc.ProcessConfig) {
+	rules, errs := ratelimit.ParseRules(current.ConfigMap)
+	for _, err := range errs {
+		blog.Errorf("parse rate limit rule failed, the rule is ignored, err: %v", err)
+	}
+	h.Limiter.Update(rules)
+	blog.Infof("%d rate limit rules loaded", len(rules))
 
+	cacheRules, errs := cache.ParseRules(current.ConfigMap)
+	for _, err := range errs {
+		blog.Errorf("parse cache rule failed, the rule is ignored, err: %v", err)
+	}
+	h.Cache.Update(cacheRules)
+	blog.Infof("%d cache rules loaded", len(cacheRules))
+	h.watchModelEvents(redis.ParseConfigFromKV("redis", current.ConfigMap))
+}
+
+// watchModelEvents start invalidating the cache with the model events once the redis is configured,
+// the cached responses are only removed when they expire without it.
+func (h *APIServer) watchModelEvents(config redis.Config) {
+	if h.watching {
+		return
+	}
+	if config.Address == "" {
+		blog.Warnf("redis is not configured, the cached responses are not invalidated by the model events")
+		return
+	}
+
+	client, err := redis.NewFromConfig(config)
+	if err != nil {
+		blog.Errorf("new redis client failed, the cached responses are not invalidated by the model events, err: %v", err)
+		return
+	}
+	h.watching = true
+	go h.Cache.Watch(h.ctx, client)
 }
 
 func newServerInfo(op *options.ServerOption) (*types.ServerInfo, error) {
@@ -0,0 +1,210 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metric"
+)
+
+// maxBytes the max total size of the cached responses, the expired ones are removed when it's exceeded,
+// and all of them are removed if it's still exceeded
+const maxBytes = 128 << 20
+
+// Entry the cached response
+type Entry struct {
+	ContentType string
+	Body        []byte
+	ETag        string
+	expire      time.Time
+}
+
+// NewEntry create the entry of the response body, the ETag is the hash of the body
+func NewEntry(contentType string, body []byte) *Entry {
+	sum := sha1.Sum(body)
+	return &Entry{
+		ContentType: contentType,
+		Body:        body,
+		ETag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+	}
+}
+
+// Cache the responses of the routes opted in by the rules, all of them are invalidated when the models change
+type Cache struct {
+	sync.Mutex
+	rules   []*Rule
+	entries map[string]*Entry
+	size    int
+	// generation is increased by each invalidation, the responses of the requests started before it are not cached
+	generation uint64
+
+	requests      *metric.CounterVec
+	invalidations *metric.CounterVec
+	cached        *metric.GaugeVec
+}
+
+// NewCache create a cache without any rule, no response is cached until the rules are updated
+func NewCache() *Cache {
+	return &Cache{
+		rules:   make([]*Rule, 0),
+		entries: make(map[string]*Entry),
+		requests: metric.NewCounterVec("cc_apiserver_cache_requests_total",
+			"Total number of the requests matched with the cache rules.", "rule", "result"),
+		invalidations: metric.NewCounterVec("cc_apiserver_cache_invalidations_total",
+			"Total number of the invalidations of the cached responses.", "reason"),
+		cached: metric.NewGaugeVec("cc_apiserver_cache_bytes",
+			"The total size of the cached responses."),
+	}
+}
+
+// Update replace the rules, the cached responses are removed if the rules change
+func (c *Cache) Update(rules []*Rule) {
+	c.Lock()
+	defer c.Unlock()
+
+	if rulesString(c.rules) != rulesString(rules) {
+		for _, rule := range rules {
+			blog.Infof("cache rule %s is loaded", rule)
+		}
+		c.clear()
+	}
+	c.rules = rules
+}
+
+func rulesString(rules []*Rule) string {
+	items := make([]string, len(rules))
+	for i, rule := range rules {
+		items[i] = rule.String()
+	}
+	return strings.Join(items, ";")
+}
+
+// Match return the first rule matched with the request
+func (c *Cache) Match(method, path string) *Rule {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, rule := range c.rules {
+		if rule.Match(method, path) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Get get the cached response which is not expired
+func (c *Cache) Get(rule *Rule, key string) (*Entry, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expire) {
+		c.remove(key)
+		ok = false
+	}
+	if ok {
+		c.requests.Inc(rule.Name, "hit")
+	} else {
+		c.requests.Inc(rule.Name, "miss")
+	}
+	return entry, ok
+}
+
+// Generation the generation of the cache, it should be got before the request is processed
+func (c *Cache) Generation() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.generation
+}
+
+// Set cache the response of the request started in the generation, the response is dropped
+// if the cache is invalidated after the request started, because it may be stale.
+func (c *Cache) Set(rule *Rule, key string, generation uint64, entry *Entry) {
+	c.Lock()
+	defer c.Unlock()
+
+	if generation != c.generation || len(entry.Body) > maxBytes {
+		return
+	}
+	c.remove(key)
+	if c.size+len(entry.Body) > maxBytes {
+		now := time.Now()
+		for key, cached := range c.entries {
+			if now.After(cached.expire) {
+				c.remove(key)
+			}
+		}
+	}
+	if c.size+len(entry.Body) > maxBytes {
+		blog.Warnf("the cached responses exceed %d bytes, all of them are removed", maxBytes)
+		c.clear()
+	}
+
+	entry.expire = time.Now().Add(rule.TTL)
+	c.entries[key] = entry
+	c.size += len(entry.Body)
+}
+
+// Invalidate remove all the cached responses
+func (c *Cache) Invalidate(reason string) {
+	c.Lock()
+	defer c.Unlock()
+
+	blog.V(4).Infof("the cached responses are invalidated by %s", reason)
+	c.invalidations.Inc(reason)
+	c.generation++
+	c.clear()
+}
+
+// remove the cached response, it should be called with the lock held
+func (c *Cache) remove(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.size -= len(entry.Body)
+		delete(c.entries, key)
+	}
+}
+
+// clear remove all the cached responses, it should be called with the lock held
+func (c *Cache) clear() {
+	c.entries = make(map[string]*Entry)
+	c.size = 0
+}
+
+// WritePrometheus implements metric.PrometheusMetric
+func (c *Cache) WritePrometheus(w io.Writer) {
+	c.Lock()
+	c.cached.Set(float64(c.size))
+	c.Unlock()
+
+	c.requests.WritePrometheus(w)
+	c.invalidations.WritePrometheus(w)
+	c.cached.WritePrometheus(w)
+}
+
+// ETagMatch check whether the If-None-Match header matches the ETag, the weak comparison is used
+func ETagMatch(ifNoneMatch, etag string) bool {
+	for _, item := range strings.Split(ifNoneMatch, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.TrimPrefix(item, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
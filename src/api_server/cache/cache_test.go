@@ -0,0 +1,179 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/redis.v5"
+
+	"configcenter/src/common"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, errs := ParseRules(map[string]string{
+		"cache.rule.objectattr": "method=post path=/api/v3/find/objectattr ttl=60 vary=BK_User",
+		"cache.rule.find":       "path=/api/v3/find/*",
+		"cache.rule.write":      "method=PUT path=/api/v3/update/object",
+		"cache.rule.nopath":     "ttl=60",
+		"cache.rule.badttl":     "path=/api/v3/find/object ttl=-1",
+		"ratelimit.rule.all":    "qps=1",
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expect 3 errors, got %v", errs)
+	}
+	if len(rules) != 2 || rules[0].Name != "find" || rules[1].Name != "objectattr" {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+	if rules[0].TTL != DefaultTTL || rules[1].TTL != time.Minute || rules[1].Method != http.MethodPost {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+
+	find := rules[0]
+	if !find.Match(http.MethodGet, "/api/v3/find/object") || !find.Match(http.MethodPost, "/api/v3/find/object") {
+		t.Errorf("rule %s should match the read requests", find)
+	}
+	if find.Match(http.MethodDelete, "/api/v3/find/object") {
+		t.Errorf("rule %s should not match the delete requests", find)
+	}
+	if rules[1].Match(http.MethodGet, "/api/v3/find/objectattr") || rules[1].Match(http.MethodPost, "/api/v3/find/objectattrs") {
+		t.Errorf("rule %s should only match the post requests of the path", rules[1])
+	}
+}
+
+func TestKey(t *testing.T) {
+	rule, _ := ParseRule("objectattr", "path=/api/v3/find/objectattr vary=BK_User")
+	request := func(owner, user string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1/api/v3/find/objectattr", nil)
+		req.Header.Set(common.BKHTTPOwnerID, owner)
+		req.Header.Set(common.BKHTTPHeaderUser, user)
+		return req
+	}
+
+	key := rule.Key(request("0", "admin"), []byte(`{"bk_obj_id":"host"}`))
+	if key != rule.Key(request("0", "admin"), []byte(`{"bk_obj_id":"host"}`)) {
+		t.Error("the same requests should have the same key")
+	}
+	for _, other := range []string{
+		rule.Key(request("1", "admin"), []byte(`{"bk_obj_id":"host"}`)),
+		rule.Key(request("0", "guest"), []byte(`{"bk_obj_id":"host"}`)),
+		rule.Key(request("0", "admin"), []byte(`{"bk_obj_id":"set"}`)),
+	} {
+		if key == other {
+			t.Error("the requests of the different owners, vary headers or bodies should have different keys")
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache()
+	if c.Match(http.MethodPost, "/api/v3/find/object") != nil {
+		t.Fatal("no request should be matched without rules")
+	}
+
+	rule, _ := ParseRule("object", "method=POST path=/api/v3/find/object ttl=60")
+	c.Update([]*Rule{rule})
+	if c.Match(http.MethodPost, "/api/v3/find/object") != rule {
+		t.Fatal("the request should be matched with the rule")
+	}
+
+	entry := NewEntry("application/json", []byte(`{"result":true}`))
+	c.Set(rule, "key", c.Generation(), entry)
+	if cached, ok := c.Get(rule, "key"); !ok || cached != entry {
+		t.Fatal("the response should be cached")
+	}
+
+	// the invalidation removes the responses and the responses of the requests started before it are dropped
+	generation := c.Generation()
+	c.Invalidate("modelupdate")
+	if _, ok := c.Get(rule, "key"); ok {
+		t.Fatal("the response should be invalidated")
+	}
+	c.Set(rule, "key", generation, entry)
+	if _, ok := c.Get(rule, "key"); ok {
+		t.Fatal("the stale response should not be cached")
+	}
+
+	c.Set(rule, "key", c.Generation(), entry)
+	entry.expire = time.Now().Add(-time.Second)
+	if _, ok := c.Get(rule, "key"); ok {
+		t.Fatal("the expired response should be removed")
+	}
+
+	// the responses are removed when the rules change
+	c.Set(rule, "key", c.Generation(), NewEntry("application/json", []byte(`{"result":true}`)))
+	c.Update([]*Rule{rule})
+	if _, ok := c.Get(rule, "key"); !ok {
+		t.Fatal("the response should be kept if the rules don't change")
+	}
+	shorter, _ := ParseRule("object", "method=POST path=/api/v3/find/object ttl=10")
+	c.Update([]*Rule{shorter})
+	if _, ok := c.Get(shorter, "key"); ok {
+		t.Fatal("the response should be removed if the rules change")
+	}
+
+	buf := new(bytes.Buffer)
+	c.WritePrometheus(buf)
+	for _, want := range []string{
+		`cc_apiserver_cache_requests_total{rule="object",result="hit"} 2`,
+		`cc_apiserver_cache_invalidations_total{reason="modelupdate"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("metrics should contain %s, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestETagMatch(t *testing.T) {
+	entry := NewEntry("application/json", []byte(`{"result":true}`))
+	if entry.ETag != NewEntry("application/json", []byte(`{"result":true}`)).ETag {
+		t.Error("the same bodies should have the same ETag")
+	}
+	for header, want := range map[string]bool{
+		entry.ETag:               true,
+		"W/" + entry.ETag:        true,
+		`"other", ` + entry.ETag: true,
+		"*":                      true,
+		`"other"`:                false,
+		"":                       false,
+	} {
+		if ETagMatch(header, entry.ETag) != want {
+			t.Errorf("If-None-Match %s should match %v", header, want)
+		}
+	}
+}
+
+func TestHandleMessage(t *testing.T) {
+	c := NewCache()
+	generation := c.Generation()
+
+	// the resubscription after reconnecting invalidates the cache like the first subscription
+	c.handleMessage(&redis.Subscription{Kind: "subscribe", Channel: common.EventCacheModelChannel, Count: 1})
+	c.handleMessage(&redis.Subscription{Kind: "subscribe", Channel: common.EventCacheModelChannel, Count: 1})
+	c.handleMessage(&redis.Pong{})
+	c.handleMessage(&redis.Message{Channel: common.EventCacheModelChannel, Payload: `{"event_type":"model","obj_type":"modelattribute","action":"update"}`})
+	c.handleMessage(&redis.Message{Channel: common.EventCacheModelChannel, Payload: "invalid"})
+	if c.Generation() != generation+4 {
+		t.Fatalf("expect 4 invalidations, got %d", c.Generation()-generation)
+	}
+
+	buf := new(bytes.Buffer)
+	c.WritePrometheus(buf)
+	if want := `cc_apiserver_cache_invalidations_total{reason="subscribe"} 2`; !strings.Contains(buf.String(), want) {
+		t.Errorf("metrics should contain %s, got %s", want, buf.String())
+	}
+}
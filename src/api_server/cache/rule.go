@@ -0,0 +1,151 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+)
+
+const (
+	// RuleConfigPrefix the prefix of the config keys of the rules, the rules are configured in
+	// the cache section of the config, such as:
+	//  [cache]
+	//  rule.objectattr = method=POST path=/api/v3/find/objectattr ttl=300
+	//  rule.object = method=POST path=/api/v3/find/object ttl=300 vary=BK_User
+	RuleConfigPrefix = "cache.rule."
+
+	// DefaultTTL the time to live of the cached responses if the ttl of the rule is not set
+	DefaultTTL = 5 * time.Minute
+)
+
+// defaultVary the headers which the responses vary with, the responses are cached for each supplier account and language
+var defaultVary = []string{common.BKHTTPOwnerID, common.BKHTTPLanguage}
+
+// Rule cache the successful responses of the read requests matched, the empty method matches GET and POST.
+type Rule struct {
+	Name string
+	// Method the http method matched, only GET and POST are allowed
+	Method string
+	// Path the pattern of the request path, * matches any characters
+	Path string
+	// TTL the time to live of the cached responses, they are removed early when the models change
+	TTL time.Duration
+	// Vary the request headers which the responses vary with besides the supplier account and the language
+	Vary []string
+
+	pathRegexp *regexp.Regexp
+}
+
+// Match check whether the request is matched with the rule
+func (r *Rule) Match(method, path string) bool {
+	if r.Method == "" && method != http.MethodGet && method != http.MethodPost {
+		return false
+	}
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	return r.pathRegexp.MatchString(path)
+}
+
+// Key the key of the cached response, the requests with the same method, url, body and vary headers share a response
+func (r *Rule) Key(req *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.RequestURI()+"\n")
+	for _, name := range append(defaultVary, r.Vary...) {
+		io.WriteString(h, name+": "+req.Header.Get(name)+"\n")
+	}
+	h.Write(body)
+	return r.Name + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *Rule) String() string {
+	return fmt.Sprintf("%s(method=%s path=%s ttl=%s vary=%s)", r.Name, r.Method, r.Path, r.TTL, strings.Join(r.Vary, ","))
+}
+
+// ParseRules parse the rules from the process config, the rules are ordered by the name
+func ParseRules(configMap map[string]string) ([]*Rule, []error) {
+	rules := make([]*Rule, 0)
+	errs := make([]error, 0)
+	for key, value := range configMap {
+		if !strings.HasPrefix(key, RuleConfigPrefix) {
+			continue
+		}
+		rule, err := ParseRule(strings.TrimPrefix(key, RuleConfigPrefix), value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	return rules, errs
+}
+
+// ParseRule parse the rule with space separated key=value fields, such as
+// method=POST path=/api/v3/find/objectattr ttl=300 vary=BK_User
+func ParseRule(name, value string) (*Rule, error) {
+	if name == "" {
+		return nil, fmt.Errorf("cache rule name can not be empty")
+	}
+	rule := &Rule{Name: name, TTL: DefaultTTL}
+	for _, field := range strings.Fields(value) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("cache rule %s has invalid field %s", name, field)
+		}
+
+		switch kv[0] {
+		case "method":
+			rule.Method = strings.ToUpper(kv[1])
+			if rule.Method != http.MethodGet && rule.Method != http.MethodPost {
+				return nil, fmt.Errorf("cache rule %s has method %s, only GET and POST can be cached", name, kv[1])
+			}
+		case "path":
+			rule.Path = kv[1]
+		case "ttl":
+			seconds, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || seconds <= 0 {
+				return nil, fmt.Errorf("cache rule %s has invalid ttl %s, it should be the positive seconds", name, kv[1])
+			}
+			rule.TTL = time.Duration(seconds) * time.Second
+		case "vary":
+			for _, header := range strings.Split(kv[1], ",") {
+				if header != "" {
+					rule.Vary = append(rule.Vary, http.CanonicalHeaderKey(header))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("cache rule %s has unknown field %s", name, kv[0])
+		}
+	}
+
+	if rule.Path == "" {
+		return nil, fmt.Errorf("cache rule %s must have a path", name)
+	}
+	pattern := "^" + strings.Replace(regexp.QuoteMeta(rule.Path), `\*`, ".*", -1) + "$"
+	rule.pathRegexp = regexp.MustCompile(pattern)
+
+	return rule, nil
+}
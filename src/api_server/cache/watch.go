@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"gopkg.in/redis.v5"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+const (
+	// resubscribeInterval the interval to subscribe the model events again after the subscription fails
+	resubscribeInterval = 5 * time.Second
+	// receiveTimeout the connection is checked with a ping if no message is received in the timeout
+	receiveTimeout = 30 * time.Second
+)
+
+// Watch invalidate the cache with the model events published by the event server until the context is done
+func (c *Cache) Watch(ctx context.Context, client *redis.Client) {
+	for {
+		pubsub, err := client.Subscribe(common.EventCacheModelChannel)
+		if err != nil {
+			blog.Errorf("subscribe the model events failed, retry after %s, err: %v", resubscribeInterval, err)
+		} else {
+			c.receive(ctx, pubsub)
+			// the events published before the next subscription are lost
+			c.Invalidate("disconnect")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeInterval):
+		}
+	}
+}
+
+// receive invalidate the cache with the model events until the subscription is closed.
+// the messages are received without the reconnection of ReceiveMessage, which drops the events published
+// while reconnecting silently, so that the cache is invalidated each time the subscription is confirmed.
+func (c *Cache) receive(ctx context.Context, pubsub *redis.PubSub) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			pubsub.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		msg, err := pubsub.ReceiveTimeout(receiveTimeout)
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() && ctx.Err() == nil {
+			err = pubsub.Ping()
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				blog.Errorf("receive the model events failed, err: %v", err)
+				pubsub.Close()
+			}
+			return
+		}
+		c.handleMessage(msg)
+	}
+}
+
+// handleMessage invalidate the cache with the message received from the subscription
+func (c *Cache) handleMessage(msg interface{}) {
+	switch msg := msg.(type) {
+	case *redis.Subscription:
+		// the events published before the subscription is confirmed are lost
+		if msg.Kind == "subscribe" {
+			c.Invalidate("subscribe")
+		}
+	case *redis.Message:
+		event := new(metadata.EventInst)
+		if err := json.Unmarshal([]byte(msg.Payload), event); err != nil {
+			blog.Errorf("unmarshal the model event %s failed, err: %v", msg.Payload, err)
+			event.ObjType = metadata.EventTypeModel
+		}
+		c.Invalidate(event.GetType())
+	}
+}
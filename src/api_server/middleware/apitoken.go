@@ -0,0 +1,78 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/apitoken"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// APITokenFilter authenticate the requests with the personal api token in the Authorization header,
+// and set the user and supplier account of the token to the request header.
+// the requests without a token are passed through unchanged.
+func APITokenFilter(engine func() *backbone.Engine) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		// the token id header is only set by the gateway, never trust the caller
+		req.Request.Header.Del(common.BKHTTPAPITokenID)
+
+		token := apitoken.FromHeader(req.Request.Header)
+		if "" == token {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		e := engine()
+		rid := util.GetHTTPCCRequestID(req.Request.Header)
+		defErr := e.CCErr.CreateDefaultCCErrorIf(util.GetActionLanguage(req))
+
+		tok, err := apitoken.Authenticate(req.Request.Context(), e.CoreAPI, req.Request.Header, token)
+		if nil != err {
+			blog.Errorf("authenticate api token failed, err: %v, rid: %s", err, rid)
+			resp.WriteError(http.StatusUnauthorized, &metadata.RespError{Msg: defErr.Error(common.CCErrAPITokenInvalid), ErrCode: common.CCErrAPITokenInvalid})
+			return
+		}
+
+		// the body is only needed to find out the businesses and objects the request refers to
+		var body []byte
+		if len(tok.Scope.BizIDs) > 0 || len(tok.Scope.ObjectIDs) > 0 {
+			body, err = apitoken.ReadBody(req.Request)
+			if apitoken.ErrBodyTooLarge == err {
+				blog.Errorf("the scope of api token %d can not be checked, err: %v, rid: %s", tok.ID, err, rid)
+				resp.WriteError(http.StatusForbidden, &metadata.RespError{Msg: defErr.Error(common.CCErrAPITokenNoPermission), ErrCode: common.CCErrAPITokenNoPermission})
+				return
+			}
+			if nil != err {
+				blog.Errorf("read request body failed, err: %v, rid: %s", err, rid)
+				resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPReadBodyFailed)})
+				return
+			}
+		}
+		if err := apitoken.Authorize(tok.Scope, req.Request.Method, req.Request.URL.Path, req.Request.URL.Query(), body); nil != err {
+			blog.Errorf("api token %d of user %s has no permission to %s %s, rid: %s", tok.ID, tok.User, req.Request.Method, req.Request.URL.Path, rid)
+			resp.WriteError(http.StatusForbidden, &metadata.RespError{Msg: defErr.Error(common.CCErrAPITokenNoPermission), ErrCode: common.CCErrAPITokenNoPermission})
+			return
+		}
+
+		apitoken.SetHeaders(req.Request.Header, tok)
+		req.Request.Header.Del("Authorization")
+		chain.ProcessFilter(req, resp)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/api_server/cache"
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// CacheFilter serve the requests matched with the cache rules from the cached responses, the successful
+// responses are cached. the responses have the ETag header, and the requests with the If-None-Match header
+// matched are responded with 304.
+func CacheFilter(engine func() *backbone.Engine, c *cache.Cache) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		rule := c.Match(req.Request.Method, req.Request.URL.Path)
+		if rule == nil {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		rid := util.GetHTTPCCRequestID(req.Request.Header)
+		body, err := ioutil.ReadAll(req.Request.Body)
+		if err != nil {
+			blog.Errorf("read the body of request %s %s failed, err: %v, rid: %s", req.Request.Method, req.Request.URL.Path, err, rid)
+			defErr := engine().CCErr.CreateDefaultCCErrorIf(util.GetActionLanguage(req))
+			resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommHTTPReadBodyFailed), ErrCode: common.CCErrCommHTTPReadBodyFailed})
+			return
+		}
+		req.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		key := rule.Key(req.Request, body)
+		entry, ok := c.Get(rule, key)
+		if ok {
+			resp.Header().Set("X-Cache", "HIT")
+			writeCacheEntry(req, resp, entry)
+			return
+		}
+
+		generation := c.Generation()
+		recorder := &cacheRecorder{header: make(http.Header), code: http.StatusOK, body: new(bytes.Buffer)}
+		writer := resp.ResponseWriter
+		resp.ResponseWriter = recorder
+		chain.ProcessFilter(req, resp)
+		resp.ResponseWriter = writer
+
+		if !recorder.cacheable() {
+			for key, values := range recorder.header {
+				resp.Header()[key] = values
+			}
+			resp.WriteHeader(recorder.code)
+			if _, err := resp.Write(recorder.body.Bytes()); err != nil {
+				blog.Errorf("response request %s %s failed, err: %v, rid: %s", req.Request.Method, req.Request.URL.Path, err, rid)
+			}
+			return
+		}
+
+		entry = cache.NewEntry(recorder.header.Get("Content-Type"), recorder.body.Bytes())
+		c.Set(rule, key, generation, entry)
+		resp.Header().Set("X-Cache", "MISS")
+		writeCacheEntry(req, resp, entry)
+	}
+}
+
+func writeCacheEntry(req *restful.Request, resp *restful.Response, entry *cache.Entry) {
+	resp.Header().Set("Content-Type", entry.ContentType)
+	resp.Header().Set("ETag", entry.ETag)
+	// the clients should always revalidate the response with the ETag
+	resp.Header().Set("Cache-Control", "no-cache")
+	if cache.ETagMatch(req.Request.Header.Get("If-None-Match"), entry.ETag) {
+		resp.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	resp.WriteHeader(http.StatusOK)
+	if _, err := resp.Write(entry.Body); err != nil {
+		blog.Errorf("response request %s %s failed, err: %v, rid: %s", req.Request.Method, req.Request.URL.Path, err, util.GetHTTPCCRequestID(req.Request.Header))
+	}
+}
+
+// cacheRecorder record the response of the backend, so that it can be cached before it's written
+type cacheRecorder struct {
+	header http.Header
+	code   int
+	body   *bytes.Buffer
+}
+
+func (r *cacheRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *cacheRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *cacheRecorder) WriteHeader(code int) {
+	r.code = code
+}
+
+// cacheable only the successful responses which are not encoded are cached
+func (r *cacheRecorder) cacheable() bool {
+	if r.code != http.StatusOK || r.header.Get("Content-Encoding") != "" {
+		return false
+	}
+	result := metadata.BaseResp{}
+	if err := json.Unmarshal(r.body.Bytes(), &result); err != nil {
+		return false
+	}
+	return result.Result
+}
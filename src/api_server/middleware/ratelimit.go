@@ -0,0 +1,62 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/api_server/ratelimit"
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// RateLimitFilter admit the requests with the rate limit rules of the authenticated app code and user,
+// the requests exceeding the limit are rejected with 429 and the seconds to wait in the Retry-After header.
+func RateLimitFilter(engine func() *backbone.Engine, limiter *ratelimit.Limiter) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		header := req.Request.Header
+		app, user := rateLimitIdentity(header)
+		allowed, rule, wait := limiter.Allow(app, user, req.Request.Method, req.Request.URL.Path)
+		if allowed {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		rid := util.GetHTTPCCRequestID(header)
+		blog.Warnf("request %s %s of app %s user %s is rejected by the rate limit rule %s, rid: %s", req.Request.Method, req.Request.URL.Path, app, user, rule, rid)
+
+		defErr := engine().CCErr.CreateDefaultCCErrorIf(util.GetActionLanguage(req))
+		resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		resp.WriteError(http.StatusTooManyRequests, &metadata.RespError{Msg: defErr.Errorf(common.CCErrAPIRateLimited, rule.Name), ErrCode: common.CCErrAPIRateLimited})
+	}
+}
+
+// rateLimitIdentity return the app code and the user the rate limit rules are applied with. only the user of
+// the api token is authenticated here, the app code and the user headers of the other requests are set by the
+// caller, and a new bucket would be created for each value of them. so the requests without an api token are
+// matched with the rules of any app and user, and share the buckets of the rules.
+func rateLimitIdentity(header http.Header) (string, string) {
+	// the token id header is removed from the request and only set after the token is authenticated
+	if "" == header.Get(common.BKHTTPAPITokenID) {
+		return "", ""
+	}
+	// the token is not issued to an app, so the app code is not authenticated either
+	return "", util.GetUser(header)
+}
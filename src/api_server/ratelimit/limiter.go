@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"container/list"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"configcenter/src/apimachinery/flowctrl"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metric"
+)
+
+const (
+	// the buckets not used for the idle time are removed, the removed bucket is full when it is created again
+	bucketIdleTime = 10 * time.Minute
+	purgeInterval  = time.Minute
+	// the max number of the buckets of a rule, the least recently used bucket is evicted when it is exceeded
+	maxRuleBuckets = 10000
+)
+
+type bucket struct {
+	flowctrl.RateLimiter
+	key      bucketKey
+	lastUsed time.Time
+}
+
+// ruleBuckets the buckets of a rule, the buckets in the list are ordered from the most recently used
+type ruleBuckets struct {
+	items map[bucketKey]*list.Element
+	lru   *list.List
+}
+
+func newRuleBuckets() *ruleBuckets {
+	return &ruleBuckets{items: make(map[bucketKey]*list.Element), lru: list.New()}
+}
+
+// get return the bucket of the key and mark it as the most recently used one,
+// the bucket is created if it does not exist
+func (r *ruleBuckets) get(rule *Rule, key bucketKey, now time.Time) *bucket {
+	if elem, ok := r.items[key]; ok {
+		r.lru.MoveToFront(elem)
+		b := elem.Value.(*bucket)
+		b.lastUsed = now
+		return b
+	}
+
+	if r.lru.Len() >= maxRuleBuckets {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.items, oldest.Value.(*bucket).key)
+	}
+	b := &bucket{RateLimiter: flowctrl.NewRateLimiter(rule.QPS, rule.Burst), key: key, lastUsed: now}
+	r.items[key] = r.lru.PushFront(b)
+	return b
+}
+
+// purge remove the buckets not used for the idle time
+func (r *ruleBuckets) purge(now time.Time) {
+	for elem := r.lru.Back(); elem != nil; elem = r.lru.Back() {
+		b := elem.Value.(*bucket)
+		if now.Sub(b.lastUsed) <= bucketIdleTime {
+			return
+		}
+		r.lru.Remove(elem)
+		delete(r.items, b.key)
+	}
+}
+
+// Limiter admit the requests with the token buckets of the rules
+type Limiter struct {
+	sync.Mutex
+	rules     []*Rule
+	buckets   map[string]*ruleBuckets
+	lastPurge time.Time
+
+	rejected  *metric.CounterVec
+	available *metric.GaugeVec
+	qps       *metric.GaugeVec
+}
+
+type bucketKey struct {
+	app  string
+	user string
+}
+
+// NewLimiter create a limiter without any rule, all the requests are admitted until the rules are updated
+func NewLimiter() *Limiter {
+	return &Limiter{
+		rules:     make([]*Rule, 0),
+		buckets:   make(map[string]*ruleBuckets),
+		lastPurge: time.Now(),
+		rejected: metric.NewCounterVec("cc_apiserver_ratelimit_rejected_total",
+			"Total number of the requests rejected by the rate limit rules.", "rule"),
+		available: metric.NewGaugeVec("cc_apiserver_ratelimit_tokens_available",
+			"The least tokens can be taken immediately from the buckets of the rate limit rule.", "rule"),
+		qps: metric.NewGaugeVec("cc_apiserver_ratelimit_rule_qps",
+			"The qps of the rate limit rule.", "rule"),
+	}
+}
+
+// Update replace the rules, the buckets of the rules not changed are kept
+func (l *Limiter) Update(rules []*Rule) {
+	l.Lock()
+	defer l.Unlock()
+
+	previous := make(map[string]*Rule)
+	for _, rule := range l.rules {
+		previous[rule.Name] = rule
+	}
+
+	buckets := make(map[string]*ruleBuckets)
+	for _, rule := range rules {
+		if prev, ok := previous[rule.Name]; ok && prev.String() == rule.String() {
+			buckets[rule.Name] = l.buckets[rule.Name]
+			continue
+		}
+		buckets[rule.Name] = newRuleBuckets()
+		blog.Infof("rate limit rule %s is updated", rule)
+	}
+
+	l.rules = rules
+	l.buckets = buckets
+}
+
+// Allow take a token from the buckets of all the rules matched with the request. if any of them has no token,
+// the request is rejected with the rule and the duration to wait before retry, and no token is taken.
+func (l *Limiter) Allow(app, user, method, path string) (bool, *Rule, time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPurge) > purgeInterval {
+		l.purge(now)
+	}
+
+	matched := make([]*bucket, 0)
+	for _, rule := range l.rules {
+		if !rule.Match(app, user, method, path) {
+			continue
+		}
+
+		bucketApp, bucketUser := rule.bucketKey(app, user)
+		b := l.buckets[rule.Name].get(rule, bucketKey{app: bucketApp, user: bucketUser}, now)
+
+		if b.Available() < 1 {
+			l.rejected.Inc(rule.Name)
+			// the time to get the next token
+			wait := time.Duration(math.Ceil(float64(time.Second) / float64(rule.QPS)))
+			return false, rule, wait
+		}
+		matched = append(matched, b)
+	}
+
+	// the buckets are only taken with the lock held, so the tokens checked are still available
+	for _, b := range matched {
+		b.TryAccept()
+	}
+	return true, nil, 0
+}
+
+// purge remove the idle buckets, it should be called with the lock held
+func (l *Limiter) purge(now time.Time) {
+	l.lastPurge = now
+	for _, buckets := range l.buckets {
+		buckets.purge(now)
+	}
+}
+
+// WritePrometheus implements metric.PrometheusMetric, the state of the buckets is exported
+func (l *Limiter) WritePrometheus(w io.Writer) {
+	l.Lock()
+	l.available.Reset()
+	l.qps.Reset()
+	for _, rule := range l.rules {
+		l.qps.Set(float64(rule.QPS), rule.Name)
+		// the apps and users are not exported as labels, so that the number of the series is bounded
+		least := int64(-1)
+		for elem := l.buckets[rule.Name].lru.Front(); elem != nil; elem = elem.Next() {
+			if available := elem.Value.(*bucket).Available(); least < 0 || available < least {
+				least = available
+			}
+		}
+		if least >= 0 {
+			l.available.Set(float64(least), rule.Name)
+		}
+	}
+	l.Unlock()
+
+	l.rejected.WritePrometheus(w)
+	l.available.WritePrometheus(w)
+	l.qps.WritePrometheus(w)
+}
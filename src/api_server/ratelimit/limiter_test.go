@@ -0,0 +1,154 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, errs := ParseRules(map[string]string{
+		"ratelimit.rule.per_app": "app=* qps=100 burst=200",
+		"ratelimit.rule.create":  "user=admin method=post path=/api/v3/create/* qps=10",
+		"ratelimit.rule.bad":     "app=* qps=0",
+		"ratelimit.rule.unknown": "host=1 qps=1",
+		"errors.res":             "conf/errors",
+	})
+	if len(errs) != 2 {
+		t.Fatalf("expect 2 errors, got %v", errs)
+	}
+	if len(rules) != 2 || rules[0].Name != "create" || rules[1].Name != "per_app" {
+		t.Fatalf("unexpected rules %v", rules)
+	}
+	if rules[0].Burst != 10 || rules[0].Method != http.MethodPost {
+		t.Fatalf("unexpected rule %s", rules[0])
+	}
+
+	create := rules[0]
+	if !create.Match("", "admin", http.MethodPost, "/api/v3/create/objectattr") {
+		t.Errorf("rule %s should match the request", create)
+	}
+	if create.Match("", "admin", http.MethodPost, "/api/v3/find/objectattr") {
+		t.Errorf("rule %s should not match the path", create)
+	}
+	if create.Match("", "guest", http.MethodPost, "/api/v3/create/objectattr") {
+		t.Errorf("rule %s should not match the user", create)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter()
+	if ok, _, _ := limiter.Allow("app", "user", http.MethodGet, "/api/v3/find/object"); !ok {
+		t.Fatal("the request should be allowed without rules")
+	}
+
+	perApp, _ := ParseRule("per_app", "app=* qps=1 burst=2")
+	limiter.Update([]*Rule{perApp})
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := limiter.Allow("app1", "user", http.MethodGet, "/api/v3/find/object"); !ok {
+			t.Fatalf("request %d should be allowed within the burst", i)
+		}
+	}
+	ok, rule, wait := limiter.Allow("app1", "user", http.MethodGet, "/api/v3/find/object")
+	if ok || rule.Name != "per_app" || wait != time.Second {
+		t.Fatalf("the request exceeding the burst should be rejected, got %v %v %v", ok, rule, wait)
+	}
+	// each app has its own bucket
+	if ok, _, _ := limiter.Allow("app2", "user", http.MethodGet, "/api/v3/find/object"); !ok {
+		t.Fatal("the request of another app should be allowed")
+	}
+
+	// the bucket of the unchanged rule is kept after update
+	same, _ := ParseRule("per_app", "app=* qps=1 burst=2")
+	limiter.Update([]*Rule{same})
+	if ok, _, _ := limiter.Allow("app1", "user", http.MethodGet, "/api/v3/find/object"); ok {
+		t.Fatal("the bucket should be kept with the unchanged rule")
+	}
+	changed, _ := ParseRule("per_app", "app=* qps=1 burst=3")
+	limiter.Update([]*Rule{changed})
+	if ok, _, _ := limiter.Allow("app1", "user", http.MethodGet, "/api/v3/find/object"); !ok {
+		t.Fatal("the bucket should be reset with the changed rule")
+	}
+
+	buf := &bytes.Buffer{}
+	limiter.WritePrometheus(buf)
+	for _, line := range []string{
+		`cc_apiserver_ratelimit_rejected_total{rule="per_app"} 2`,
+		`cc_apiserver_ratelimit_tokens_available{rule="per_app"} 2`,
+		`cc_apiserver_ratelimit_rule_qps{rule="per_app"} 1`,
+		"# TYPE cc_apiserver_ratelimit_tokens_available gauge",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("%s not found in:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestLimiterRejectWithoutTaking(t *testing.T) {
+	perApp, _ := ParseRule("per_app", "app=* qps=1 burst=5")
+	create, _ := ParseRule("create", "method=post qps=1 burst=1")
+	limiter := NewLimiter()
+	limiter.Update([]*Rule{perApp, create})
+
+	if ok, _, _ := limiter.Allow("app1", "user", http.MethodPost, "/api/v3/create/object"); !ok {
+		t.Fatal("the first request should be allowed")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, rule, _ := limiter.Allow("app1", "user", http.MethodPost, "/api/v3/create/object"); ok || rule.Name != "create" {
+			t.Fatalf("request %d should be rejected by the create rule, got %v %v", i, ok, rule)
+		}
+	}
+	// the rejected requests take no token of the per app rule
+	for i := 0; i < 4; i++ {
+		if ok, _, _ := limiter.Allow("app1", "user", http.MethodGet, "/api/v3/find/object"); !ok {
+			t.Fatalf("request %d should be allowed by the per app rule", i)
+		}
+	}
+}
+
+func TestLimiterEvictBuckets(t *testing.T) {
+	perUser, _ := ParseRule("per_user", "user=* qps=1 burst=1")
+	limiter := NewLimiter()
+	limiter.Update([]*Rule{perUser})
+
+	if ok, _, _ := limiter.Allow("", "user0", http.MethodGet, "/api/v3/find/object"); !ok {
+		t.Fatal("the first request should be allowed")
+	}
+	for i := 1; i < maxRuleBuckets; i++ {
+		limiter.Allow("", "user"+strconv.Itoa(i), http.MethodGet, "/api/v3/find/object")
+	}
+	// user0 is used again, so the least recently used bucket is user1's
+	if ok, _, _ := limiter.Allow("", "user0", http.MethodGet, "/api/v3/find/object"); ok {
+		t.Fatal("the bucket of user0 should be kept")
+	}
+	limiter.Allow("", "new", http.MethodGet, "/api/v3/find/object")
+	if n := len(limiter.buckets["per_user"].items); n != maxRuleBuckets {
+		t.Fatalf("expect %d buckets, got %d", maxRuleBuckets, n)
+	}
+	if ok, _, _ := limiter.Allow("", "user0", http.MethodGet, "/api/v3/find/object"); ok {
+		t.Fatal("the bucket of user0 should not be evicted")
+	}
+	if ok, _, _ := limiter.Allow("", "user1", http.MethodGet, "/api/v3/find/object"); !ok {
+		t.Fatal("the bucket of user1 should be evicted and created again")
+	}
+
+	limiter.purge(time.Now().Add(bucketIdleTime + time.Second))
+	if n := len(limiter.buckets["per_user"].items); n != 0 {
+		t.Fatalf("the idle buckets should be purged, got %d", n)
+	}
+}
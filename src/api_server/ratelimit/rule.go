@@ -0,0 +1,152 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RuleConfigPrefix the prefix of the config keys of the rules, the rules are configured in
+	// the ratelimit section of the config, such as:
+	//  [ratelimit]
+	//  rule.per_app = app=* qps=100 burst=200
+	//  rule.nightly_script = app=nightly user=* method=POST path=/api/v3/create/* qps=10 burst=20
+	RuleConfigPrefix = "ratelimit.rule."
+
+	// AnyValue the app or user of the rule, which means every app or user has its own bucket
+	AnyValue = "*"
+)
+
+// Rule limit the requests matched with a token bucket. the requests are matched with the authenticated app code,
+// the authenticated user, the method and the path, the empty condition matches all the requests.
+type Rule struct {
+	Name string
+	// App the app code matched, * means all the apps and each app has its own bucket
+	App string
+	// User the user matched, * means all the users and each user has its own bucket
+	User string
+	// Method the http method matched
+	Method string
+	// Path the pattern of the request path, * matches any characters
+	Path  string
+	QPS   int64
+	Burst int64
+
+	pathRegexp *regexp.Regexp
+}
+
+// Match check whether the request is matched with the rule
+func (r *Rule) Match(app, user, method, path string) bool {
+	if r.App != "" && r.App != AnyValue && r.App != app {
+		return false
+	}
+	if r.User != "" && r.User != AnyValue && r.User != user {
+		return false
+	}
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if r.pathRegexp != nil && !r.pathRegexp.MatchString(path) {
+		return false
+	}
+	return true
+}
+
+// bucketKey return the key of the bucket of the request, the requests with the same key share a bucket
+func (r *Rule) bucketKey(app, user string) (string, string) {
+	if r.App == "" {
+		app = ""
+	}
+	if r.User == "" {
+		user = ""
+	}
+	return app, user
+}
+
+func (r *Rule) String() string {
+	return fmt.Sprintf("%s(app=%s user=%s method=%s path=%s qps=%d burst=%d)", r.Name, r.App, r.User, r.Method, r.Path, r.QPS, r.Burst)
+}
+
+// ParseRules parse the rules from the process config, the rules are ordered by the name
+func ParseRules(configMap map[string]string) ([]*Rule, []error) {
+	rules := make([]*Rule, 0)
+	errs := make([]error, 0)
+	for key, value := range configMap {
+		if !strings.HasPrefix(key, RuleConfigPrefix) {
+			continue
+		}
+		rule, err := ParseRule(strings.TrimPrefix(key, RuleConfigPrefix), value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	return rules, errs
+}
+
+// ParseRule parse the rule with space separated key=value fields, such as
+// app=* user=admin method=POST path=/api/v3/find/* qps=10 burst=20
+func ParseRule(name, value string) (*Rule, error) {
+	if name == "" {
+		return nil, fmt.Errorf("rate limit rule name can not be empty")
+	}
+	rule := &Rule{Name: name}
+	for _, field := range strings.Fields(value) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("rate limit rule %s has invalid field %s", name, field)
+		}
+
+		var err error
+		switch kv[0] {
+		case "app":
+			rule.App = kv[1]
+		case "user":
+			rule.User = kv[1]
+		case "method":
+			rule.Method = strings.ToUpper(kv[1])
+		case "path":
+			rule.Path = kv[1]
+		case "qps":
+			rule.QPS, err = strconv.ParseInt(kv[1], 10, 64)
+		case "burst":
+			rule.Burst, err = strconv.ParseInt(kv[1], 10, 64)
+		default:
+			return nil, fmt.Errorf("rate limit rule %s has unknown field %s", name, kv[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rate limit rule %s has invalid %s %s, err: %v", name, kv[0], kv[1], err)
+		}
+	}
+
+	if rule.QPS <= 0 {
+		return nil, fmt.Errorf("rate limit rule %s must have a positive qps", name)
+	}
+	if rule.Burst <= 0 {
+		rule.Burst = rule.QPS
+	}
+	if rule.Path != "" {
+		pattern := "^" + strings.Replace(regexp.QuoteMeta(rule.Path), `\*`, ".*", -1) + "$"
+		rule.pathRegexp = regexp.MustCompile(pattern)
+	}
+
+	return rule, nil
+}
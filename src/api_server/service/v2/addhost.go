@@ -25,7 +25,7 @@ import (
 )
 
 func (s *Service) addHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	err := req.Request.ParseForm()
 	if err != nil {
@@ -85,7 +85,7 @@ func (s *Service) addHost(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) enterIP(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
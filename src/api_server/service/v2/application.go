@@ -42,7 +42,7 @@ const (
 )
 
 func (s *Service) getAppList(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	param := &params.SearchParams{Condition: nil}
@@ -69,7 +69,7 @@ func (s *Service) getAppList(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getAppByID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -126,7 +126,7 @@ func (s *Service) getAppByID(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getAppByUin(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -197,7 +197,7 @@ func (s *Service) getAppByUin(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getUserRoleApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -261,7 +261,7 @@ func (s *Service) getUserRoleApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getAppSetModuleTreeByAppId(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -307,7 +307,7 @@ func (s *Service) getAppSetModuleTreeByAppId(req *restful.Request, resp *restful
 }
 
 func (s *Service) addApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -396,7 +396,7 @@ func (s *Service) addApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) deleteApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -429,7 +429,7 @@ func (s *Service) deleteApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) editApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -516,7 +516,7 @@ func (s *Service) editApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getHostAppByCompanyId(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -565,7 +565,7 @@ func (s *Service) getHostAppByCompanyId(req *restful.Request, resp *restful.Resp
 }
 
 func (s *Service) getAppByOwnerAndUin(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -29,7 +29,7 @@ import (
 )
 
 func (s *Service) updateHostStatus(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -83,7 +83,7 @@ func (s *Service) updateHostStatus(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) updateHostByAppID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -151,7 +151,7 @@ func (s *Service) updateHostByAppID(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) getCompanyIDByIps(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -202,7 +202,7 @@ func (s *Service) getCompanyIDByIps(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) getHostListByAppIDAndField(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -252,7 +252,7 @@ func (s *Service) getHostListByAppIDAndField(req *restful.Request, resp *restful
 }
 
 func (s *Service) updateHostModule(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	const (
@@ -438,7 +438,7 @@ func (s *Service) updateHostModule(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) updateCustomProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -485,7 +485,7 @@ func (s *Service) updateCustomProperty(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) cloneHostProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -547,7 +547,7 @@ func (s *Service) cloneHostProperty(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) delHostInApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -590,7 +590,7 @@ func (s *Service) delHostInApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getGitServerIp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	appName := common.WhiteListAppName
@@ -618,7 +618,7 @@ func (s *Service) getGitServerIp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) GetHostHardInfo(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -36,7 +36,7 @@ const (
 )
 
 func (s *Service) getModulesByApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -87,7 +87,7 @@ func (s *Service) getModulesByApp(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) updateModule(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -179,7 +179,7 @@ func (s *Service) updateModule(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) addModule(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -264,7 +264,7 @@ func (s *Service) addModule(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) deleteModule(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -27,7 +27,7 @@ import (
 )
 
 func (s *Service) updateHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -80,7 +80,7 @@ func (s *Service) updateHost(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getPlats(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	result, err := s.CoreAPI.HostServer().GetPlat(srvData.ctx, srvData.header)
@@ -106,7 +106,7 @@ func (s *Service) getPlats(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) deletePlats(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -145,7 +145,7 @@ func (s *Service) deletePlats(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) createPlats(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
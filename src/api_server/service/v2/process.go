@@ -27,7 +27,7 @@ import (
 )
 
 func (s *Service) getProcessPortByApplicationID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	defLang := srvData.ccLang
 
@@ -87,7 +87,7 @@ func (s *Service) getProcessPortByApplicationID(req *restful.Request, resp *rest
 }
 
 func (s *Service) getProcessPortByIP(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	defLang := srvData.ccLang
 
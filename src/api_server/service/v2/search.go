@@ -68,7 +68,7 @@ func (s *Service) getModuleInfoByApp(ctx context.Context, appID int64, srvData *
 }
 
 func (s *Service) getIPAndProxyByCompany(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -118,7 +118,7 @@ func (s *Service) getIPAndProxyByCompany(req *restful.Request, resp *restful.Res
 }
 
 func (s *Service) getHostListByIP(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -190,7 +190,7 @@ func (s *Service) getHostListByIP(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) getSetHostList(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -252,7 +252,7 @@ func (s *Service) getSetHostList(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getModuleHostList(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -313,7 +313,7 @@ func (s *Service) getModuleHostList(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) getAppHostList(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -370,7 +370,7 @@ func (s *Service) getAppHostList(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getHostsByProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
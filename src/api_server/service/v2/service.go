@@ -43,9 +43,9 @@ type srvComm struct {
 	lgc           *logics.Logics
 }
 
-func (s *Service) newSrvComm(header http.Header) *srvComm {
+func (s *Service) newSrvComm(parent context.Context, header http.Header) *srvComm {
 	lang := util.GetLanguage(header)
-	ctx, cancel := s.Engine.CCCtx.WithCancel()
+	ctx, cancel := backbone.NewContextWithHeader(parent, header)
 	return &srvComm{
 		header:        header,
 		rid:           util.GetHTTPCCRequestID(header),
@@ -31,7 +31,7 @@ import (
 )
 
 func (s *Service) getSets(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -158,7 +158,7 @@ func (s *Service) getsetproperty(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getModulesByProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -224,7 +224,7 @@ func (s *Service) getModulesByProperty(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) addSet(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	defLang := srvData.ccLang
 
@@ -406,7 +406,7 @@ func getSetServiceAndEnv(formData url.Values) (string, string) {
 }
 
 func (s *Service) updateSet(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -499,7 +499,7 @@ func (s *Service) updateSet(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) updateSetServiceStatus(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -563,7 +563,7 @@ func (s *Service) updateSetServiceStatus(req *restful.Request, resp *restful.Res
 }
 
 func (s *Service) delSet(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -605,7 +605,7 @@ func (s *Service) delSet(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) delSetHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
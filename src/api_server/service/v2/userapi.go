@@ -29,7 +29,7 @@ import (
 )
 
 func (s *Service) getCustomerGroupList(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
@@ -91,7 +91,7 @@ func (s *Service) getCustomerGroupList(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) getContentByCustomerGroupID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	err := req.Request.ParseForm()
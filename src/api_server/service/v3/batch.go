@@ -0,0 +1,351 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	cErr "configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/rdapi"
+	"configcenter/src/common/util"
+)
+
+const (
+	batchPath = "/batch"
+	// maxBatchRequests the max count of the sub requests in a batch request
+	maxBatchRequests = 100
+)
+
+// BatchRequest the sub requests executed in order, the later sub request can refer to the response
+// of the former ones with {{id.json.path}} in its path or body, such as {{obj.data.id}}.
+// if the reference is the whole string value in the body, it is replaced with the referred json value.
+type BatchRequest struct {
+	Requests []BatchSubRequest `json:"requests"`
+	// ContinueOnError the sub requests are still executed after one failed, they are skipped by default
+	ContinueOnError bool `json:"continue_on_error"`
+}
+
+// BatchSubRequest a v3 api call in the batch request
+type BatchSubRequest struct {
+	// ID the id referred by the later sub requests, it is optional
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	// Path the path of the v3 api, with or without the /api/v3 prefix, the query is allowed
+	Path string          `json:"path"`
+	Body json.RawMessage `json:"body"`
+}
+
+// BatchSubResponse the result of the sub request
+type BatchSubResponse struct {
+	ID         string          `json:"id"`
+	StatusCode int             `json:"status_code"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	// Skipped the sub request is not executed because a former one failed
+	Skipped bool `json:"skipped"`
+	// Error the reason why the sub request can not be executed
+	Error string `json:"error,omitempty"`
+}
+
+// BatchWebService the batch api does not authenticate the request itself, every sub request goes through
+// the v3 web service with its filters, such as the api token and the rate limit.
+func (s *Service) BatchWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	getErrFunc := func() cErr.CCErrorIf {
+		return s.Engine.CCErr
+	}
+	// the user and the supplier account are checked by the sub requests, they may be set by the api token
+	ws.Path(rootPath + batchPath).
+		Filter(rdapi.HTTPRequestIDFilter(getErrFunc)).
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.POST("").Doc("execute the v3 api calls in order").Reads(BatchRequest{}).To(s.Batch))
+	return ws
+}
+
+func (s *Service) Batch(req *restful.Request, resp *restful.Response) {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+	defErr := s.Engine.CCErr.CreateDefaultCCErrorIf(util.GetActionLanguage(req))
+
+	input := new(BatchRequest)
+	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
+		blog.Errorf("batch request failed, decode body err: %v, rid: %s", err, rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if len(input.Requests) == 0 {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "requests")})
+		return
+	}
+	if len(input.Requests) > maxBatchRequests {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommXXExceedLimit, "requests", maxBatchRequests)})
+		return
+	}
+	ids := make(map[string]bool)
+	for _, sub := range input.Requests {
+		if sub.ID == "" {
+			continue
+		}
+		if ids[sub.ID] {
+			resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsIsInvalid, "id "+sub.ID)})
+			return
+		}
+		ids[sub.ID] = true
+	}
+
+	results := make([]BatchSubResponse, 0, len(input.Requests))
+	// the decoded response bodies of the sub requests with id, for the references
+	bodies := make(map[string]interface{})
+	failed := false
+	for _, sub := range input.Requests {
+		result := BatchSubResponse{ID: sub.ID}
+		if failed && !input.ContinueOnError {
+			result.Skipped = true
+			results = append(results, result)
+			continue
+		}
+
+		result.StatusCode, result.Body, result.Error = s.doBatchSubRequest(req.Request.Header, sub, bodies)
+		if !batchSucceeded(result) {
+			failed = true
+			blog.Warnf("batch sub request %s %s failed, status: %d, err: %s, rid: %s", sub.Method, sub.Path, result.StatusCode, result.Error, rid)
+		}
+		if sub.ID != "" && len(result.Body) != 0 {
+			var body interface{}
+			decoder := json.NewDecoder(bytes.NewReader(result.Body))
+			decoder.UseNumber()
+			if err := decoder.Decode(&body); err == nil {
+				bodies[sub.ID] = body
+			}
+		}
+		results = append(results, result)
+	}
+
+	resp.WriteEntity(metadata.Response{BaseResp: metadata.SuccessBaseResp, Data: results})
+}
+
+// doBatchSubRequest execute the sub request with the v3 web service, the header of the batch request is inherited
+func (s *Service) doBatchSubRequest(header http.Header, sub BatchSubRequest, bodies map[string]interface{}) (int, json.RawMessage, string) {
+	subPath, err := resolveString(sub.Path, bodies, url.PathEscape)
+	if err != nil {
+		return 0, nil, err.Error()
+	}
+	subPath, err = cleanBatchSubPath(subPath)
+	if err != nil {
+		return 0, nil, err.Error()
+	}
+
+	var body []byte
+	if len(sub.Body) != 0 {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(sub.Body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return 0, nil, fmt.Sprintf("invalid body, err: %v", err)
+		}
+		value, err = resolveValue(value, bodies)
+		if err != nil {
+			return 0, nil, err.Error()
+		}
+		if body, err = json.Marshal(value); err != nil {
+			return 0, nil, err.Error()
+		}
+	}
+
+	method := strings.ToUpper(sub.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	subReq, err := http.NewRequest(method, subPath, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err.Error()
+	}
+	// the proxy rewrite the request with the request uri
+	subReq.RequestURI = subPath
+	for key, values := range header {
+		if key == "Content-Length" {
+			continue
+		}
+		subReq.Header[key] = append([]string(nil), values...)
+	}
+	subReq.Header.Set("Content-Type", common.BKHTTPMIMEJSON)
+
+	recorder := newBatchRecorder()
+	s.Handler.ServeHTTP(recorder, subReq)
+	return recorder.code, json.RawMessage(recorder.body.Bytes()), ""
+}
+
+// cleanBatchSubPath clean the path of the sub request, the path without the root path is relative to it.
+// the cleaned path must be under the root path and must not be the batch api itself.
+func cleanBatchSubPath(subPath string) (string, error) {
+	if !strings.HasPrefix(subPath, rootPath+"/") {
+		subPath = rootPath + "/" + strings.TrimPrefix(subPath, "/")
+	}
+	u, err := url.Parse(subPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s, err: %v", subPath, err)
+	}
+	// the escaped dots and slashes are decoded in the path, so that they are cleaned too
+	cleaned := path.Clean(u.Path)
+	if !strings.HasPrefix(cleaned, rootPath+"/") {
+		return "", fmt.Errorf("the path %s is out of %s", subPath, rootPath)
+	}
+	if strings.HasPrefix(cleaned, rootPath+batchPath) {
+		return "", errors.New("the batch api can not be nested")
+	}
+	if cleaned != u.Path {
+		u.Path = cleaned
+		u.RawPath = ""
+	}
+	return u.RequestURI(), nil
+}
+
+// batchSucceeded the sub request is succeeded if it returns 200 with a successful result
+func batchSucceeded(result BatchSubResponse) bool {
+	if result.Error != "" || result.StatusCode != http.StatusOK {
+		return false
+	}
+	base := new(metadata.BaseResp)
+	if err := json.Unmarshal(result.Body, base); err != nil {
+		return false
+	}
+	return base.Result
+}
+
+// batchReference {{id.path.to.field}}
+var batchReference = regexp.MustCompile(`\{\{\s*([^.{}\s]+)((?:\.[^.{}\s]+)*)\s*\}\}`)
+
+// resolveValue replace the references in the strings of the json value
+func resolveValue(value interface{}, bodies map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		// the whole string is a reference, keep the type of the referred value
+		if match := batchReference.FindStringSubmatch(v); match != nil && match[0] == v {
+			return lookupReference(match, bodies)
+		}
+		return resolveString(v, bodies, nil)
+	case map[string]interface{}:
+		for key, item := range v {
+			resolved, err := resolveValue(item, bodies)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		for idx, item := range v {
+			resolved, err := resolveValue(item, bodies)
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = resolved
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveString replace the references in the string with the text of the referred values
+func resolveString(s string, bodies map[string]interface{}, escape func(string) string) (string, error) {
+	var resolveErr error
+	resolved := batchReference.ReplaceAllStringFunc(s, func(ref string) string {
+		value, err := lookupReference(batchReference.FindStringSubmatch(ref), bodies)
+		if err != nil {
+			resolveErr = err
+			return ref
+		}
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = v
+		case json.Number:
+			text = v.String()
+		default:
+			js, _ := json.Marshal(v)
+			text = string(js)
+		}
+		if escape != nil {
+			text = escape(text)
+		}
+		return text
+	})
+	return resolved, resolveErr
+}
+
+func lookupReference(match []string, bodies map[string]interface{}) (interface{}, error) {
+	value, ok := bodies[match[1]]
+	if !ok {
+		return nil, fmt.Errorf("reference %s refers to a sub request not executed", match[0])
+	}
+
+	for _, field := range strings.Split(strings.TrimPrefix(match[2], "."), ".") {
+		if field == "" {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if value, ok = v[field]; !ok {
+				return nil, fmt.Errorf("reference %s refers to a field not exist", match[0])
+			}
+		case []interface{}:
+			idx, err := strconv.Atoi(field)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("reference %s refers to an invalid index %s", match[0], field)
+			}
+			value = v[idx]
+		default:
+			return nil, fmt.Errorf("reference %s refers to a field not exist", match[0])
+		}
+	}
+	return value, nil
+}
+
+type batchRecorder struct {
+	header http.Header
+	code   int
+	body   *bytes.Buffer
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{
+		header: make(http.Header),
+		code:   http.StatusOK,
+		body:   new(bytes.Buffer),
+	}
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *batchRecorder) WriteHeader(code int) {
+	r.code = code
+}
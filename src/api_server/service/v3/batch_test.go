@@ -0,0 +1,150 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+func TestBatch(t *testing.T) {
+	s := &Service{Engine: &backbone.Engine{CCErr: errors.NewFromCtx(errors.EmptyErrorsSetting)}}
+
+	// echo the path and the body of the request, the create api returns an id
+	echo := new(restful.WebService)
+	echo.Path(rootPath).Produces(restful.MIME_JSON)
+	echo.Route(echo.POST("{.*}").To(func(req *restful.Request, resp *restful.Response) {
+		body, _ := ioutil.ReadAll(req.Request.Body)
+		if strings.HasSuffix(req.Request.URL.Path, "/fail") {
+			resp.WriteHeaderAndJson(http.StatusOK, metadata.BaseResp{Result: false, Code: 1}, restful.MIME_JSON)
+			return
+		}
+		resp.WriteEntity(metadata.Response{BaseResp: metadata.SuccessBaseResp, Data: map[string]interface{}{
+			"id":   12,
+			"path": req.Request.RequestURI,
+			"user": req.Request.Header.Get("BK_User"),
+			"body": json.RawMessage(body),
+		}})
+	}))
+
+	ctnr := restful.NewContainer()
+	ctnr.Router(restful.CurlyRouter{})
+	ctnr.Add(echo)
+	ctnr.Add(s.BatchWebService())
+	s.Handler = ctnr
+
+	input := `{"requests": [
+		{"id": "obj", "method": "POST", "path": "/create/object", "body": {"bk_obj_id": "switch"}},
+		{"id": "attr", "method": "POST", "path": "/api/v3/create/objectattr/{{obj.data.id}}", "body": {"id": "{{obj.data.id}}", "name": "attr of {{obj.data.body.bk_obj_id}}"}},
+		{"method": "POST", "path": "/fail"},
+		{"method": "POST", "path": "/create/object"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, rootPath+batchPath, strings.NewReader(input))
+	req.Header.Set("BK_User", "admin")
+	recorder := httptest.NewRecorder()
+	ctnr.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d, body: %s", recorder.Code, recorder.Body.String())
+	}
+
+	result := new(struct {
+		metadata.BaseResp
+		Data []BatchSubResponse `json:"data"`
+	})
+	if err := json.Unmarshal(recorder.Body.Bytes(), result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Data) != 4 {
+		t.Fatalf("unexpected result %s", recorder.Body.String())
+	}
+
+	attr := new(struct {
+		Data struct {
+			Path string `json:"path"`
+			User string `json:"user"`
+			Body struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"body"`
+		} `json:"data"`
+	})
+	if err := json.Unmarshal(result.Data[1].Body, attr); err != nil {
+		t.Fatal(err)
+	}
+	if attr.Data.Path != "/api/v3/create/objectattr/12" || attr.Data.User != "admin" ||
+		attr.Data.Body.ID != 12 || attr.Data.Body.Name != "attr of switch" {
+		t.Errorf("the references are not resolved, got %s", string(result.Data[1].Body))
+	}
+
+	if result.Data[2].StatusCode != http.StatusOK || result.Data[2].Skipped {
+		t.Errorf("the failed sub request should be executed, got %+v", result.Data[2])
+	}
+	if !result.Data[3].Skipped {
+		t.Errorf("the sub request after the failed one should be skipped, got %+v", result.Data[3])
+	}
+}
+
+func TestBatchUnresolvedReference(t *testing.T) {
+	bodies := map[string]interface{}{"obj": map[string]interface{}{"data": []interface{}{"a"}}}
+	if _, err := resolveString("{{obj.data.0}}", bodies, nil); err != nil {
+		t.Errorf("resolve array index failed, err: %v", err)
+	}
+	for _, ref := range []string{"{{host.data}}", "{{obj.data.1}}", "{{obj.info}}"} {
+		if _, err := resolveValue(ref, bodies); err == nil {
+			t.Errorf("reference %s should not be resolved", ref)
+		}
+	}
+}
+
+func TestCleanBatchSubPath(t *testing.T) {
+	expects := map[string]string{
+		"/biz/search/0":                    rootPath + "/biz/search/0",
+		rootPath + "/biz/search/0?page=1":  rootPath + "/biz/search/0?page=1",
+		rootPath + "/biz/./search//0":      rootPath + "/biz/search/0",
+		rootPath + "/biz/../host/search/0": rootPath + "/host/search/0",
+	}
+	for subPath, expect := range expects {
+		cleaned, err := cleanBatchSubPath(subPath)
+		if err != nil {
+			t.Errorf("clean path %s failed, err: %v", subPath, err)
+			continue
+		}
+		if cleaned != expect {
+			t.Errorf("clean path %s, expect %s, got %s", subPath, expect, cleaned)
+		}
+	}
+
+	for _, subPath := range []string{
+		"/batch",
+		rootPath + "/batch",
+		rootPath + "//batch",
+		rootPath + "/x/../batch",
+		rootPath + "/x/%2e%2e/batch",
+		rootPath + "/../../foo",
+		"/../foo",
+	} {
+		if cleaned, err := cleanBatchSubPath(subPath); err == nil {
+			t.Errorf("path %s should be rejected, got %s", subPath, cleaned)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common/blog"
+	cErr "configcenter/src/common/errors"
+	"configcenter/src/common/openapi"
+	"configcenter/src/common/rdapi"
+	"configcenter/src/common/util"
+	"configcenter/src/common/version"
+)
+
+const (
+	// openapiCacheTTL the aggregated document is cached, the backends are requested again after it expires
+	openapiCacheTTL = 5 * time.Minute
+	// openapiFetchTimeout the timeout of requesting the document of a backend
+	openapiFetchTimeout = 5 * time.Second
+	// openapiMinBackoff the backends are requested again after the backoff when any of them is unavailable,
+	// the backoff is doubled on each failure until openapiCacheTTL
+	openapiMinBackoff = 10 * time.Second
+)
+
+// backendPath a public path prefix and the backend path prefix it is rewritten to by V3URLPath
+type backendPath struct {
+	public   string
+	internal string
+}
+
+// backendDocs the backends whose documents are aggregated, the paths are tried in order
+var backendDocs = []struct {
+	kind  RequestType
+	paths []backendPath
+}{
+	{kind: TopoType, paths: []backendPath{
+		{public: rootPath + "/biz", internal: "/topo/v3/app"},
+		{public: rootPath + "/object/attr", internal: "/topo/v3/objectattr"},
+		{public: rootPath, internal: "/topo/v3"},
+	}},
+	{kind: HostType, paths: []backendPath{{public: rootPath, internal: "/host/v3"}}},
+	{kind: ProcType, paths: []backendPath{{public: rootPath + "/proc", internal: "/process/v3"}}},
+	{kind: EventType, paths: []backendPath{{public: rootPath + "/event", internal: "/event/v3"}}},
+	{kind: DataCollectType, paths: []backendPath{{public: rootPath + "/collector", internal: "/collector/v3"}}},
+}
+
+// OpenAPIWebService serve the openapi document of the v3 apis, it is aggregated from the documents of the backends.
+func (s *Service) OpenAPIWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	getErrFunc := func() cErr.CCErrorIf {
+		return s.Engine.CCErr
+	}
+	ws.Path(rootPath + openapi.DocPath).
+		Filter(rdapi.HTTPRequestIDFilter(getErrFunc)).
+		Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").Doc("the openapi document of the v3 apis").To(s.OpenAPI))
+	return ws
+}
+
+func (s *Service) OpenAPI(req *restful.Request, resp *restful.Response) {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+
+	if err := resp.WriteAsJson(s.openAPIDoc(rid)); err != nil {
+		blog.Errorf("response openapi document failed, err: %v, rid: %s", err, rid)
+	}
+}
+
+// openAPIDoc return the cached document, the expired document is served while it is aggregated again in the
+// background, so the requests never wait for the backends except the first one
+func (s *Service) openAPIDoc(rid string) *openapi.Document {
+	s.docLock.Lock()
+	doc := s.doc
+	refresh := !s.docFetching && time.Now().After(s.docExpire)
+	if refresh {
+		s.docFetching = true
+	}
+	s.docLock.Unlock()
+
+	if doc == nil {
+		// no document to serve yet, aggregate it with this request
+		doc, complete := s.aggregateOpenAPI(rid)
+		return s.storeOpenAPI(doc, complete, refresh)
+	}
+	if refresh {
+		go func() {
+			doc, complete := s.aggregateOpenAPI(rid)
+			s.storeOpenAPI(doc, complete, true)
+		}()
+	}
+	return doc
+}
+
+// storeOpenAPI cache the aggregated document and return the document to serve. the incomplete document
+// does not replace the previous one, and the backends are requested again after the backoff.
+func (s *Service) storeOpenAPI(doc *openapi.Document, complete, fetching bool) *openapi.Document {
+	s.docLock.Lock()
+	defer s.docLock.Unlock()
+	if fetching {
+		s.docFetching = false
+	}
+
+	if complete {
+		s.doc = doc
+		s.docExpire = time.Now().Add(openapiCacheTTL)
+		s.docBackoff = 0
+		return s.doc
+	}
+
+	s.docBackoff *= 2
+	if s.docBackoff < openapiMinBackoff {
+		s.docBackoff = openapiMinBackoff
+	}
+	if s.docBackoff > openapiCacheTTL {
+		s.docBackoff = openapiCacheTTL
+	}
+	s.docExpire = time.Now().Add(s.docBackoff)
+	if s.doc == nil {
+		s.doc = doc
+	}
+	return s.doc
+}
+
+// aggregateOpenAPI merge the documents of the backends with the public paths and the document of the api server itself,
+// complete is false if the document of any backend is not available.
+func (s *Service) aggregateOpenAPI(rid string) (doc *openapi.Document, complete bool) {
+	doc = openapi.NewDocument("bk-cmdb", version.CCVersion)
+	complete = true
+
+	if ctnr, ok := s.Handler.(*restful.Container); ok {
+		for _, ws := range ctnr.RegisteredWebServices() {
+			if !strings.HasPrefix(ws.RootPath(), rootPath) {
+				continue
+			}
+			for _, route := range ws.Routes() {
+				// the routes proxied to the backends are described by the backends
+				if strings.Contains(route.Path, "{.*}") {
+					continue
+				}
+				doc.AddRoute(ws.RootPath(), route)
+			}
+		}
+	}
+
+	for _, backend := range backendDocs {
+		backendDoc, err := s.backendOpenAPI(backend.kind)
+		if err != nil {
+			blog.Errorf("get the openapi document of %s server failed, err: %v, rid: %s", backend.kind, err, rid)
+			complete = false
+			continue
+		}
+
+		backendDoc.TrimPathParam("version", "v3")
+		public := openapi.NewDocument(doc.Info.Title, doc.Info.Version)
+		public.Components = backendDoc.Components
+		for path, item := range backendDoc.Paths {
+			publicPath, ok := publicPathOf(backend.kind, path)
+			if !ok {
+				// not proxied by the api server
+				continue
+			}
+			for method, op := range *item {
+				op.Tags = []string{string(backend.kind)}
+				public.AddOperation(method, publicPath, op)
+			}
+		}
+		doc.Merge(public)
+	}
+
+	return doc, complete
+}
+
+func (s *Service) backendOpenAPI(kind RequestType) (*openapi.Document, error) {
+	servers, err := s.servers(kind)
+	if err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no %s server", kind)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, servers[0]+openapi.DocPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), openapiFetchTimeout)
+	defer cancel()
+
+	response, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	doc := new(openapi.Document)
+	if err := json.NewDecoder(response.Body).Decode(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// publicPathOf return the v3 path which is rewritten to the backend path by V3URLPath,
+// ok is false if the backend path is not proxied by the api server.
+func publicPathOf(kind RequestType, internal string) (public string, ok bool) {
+	for _, backend := range backendDocs {
+		if backend.kind != kind {
+			continue
+		}
+		for _, p := range backend.paths {
+			if internal != p.internal && !strings.HasPrefix(internal, p.internal+"/") {
+				continue
+			}
+			candidate := p.public + internal[len(p.internal):]
+			req := restful.NewRequest(&http.Request{URL: &url.URL{Path: candidate}, RequestURI: candidate})
+			hit, err := V3URLPath(candidate).FilterChain(req)
+			if err == nil && hit == kind && req.Request.URL.Path == internal {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
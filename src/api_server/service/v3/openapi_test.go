@@ -0,0 +1,76 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common/openapi"
+)
+
+func TestPublicPathOf(t *testing.T) {
+	cases := []struct {
+		kind     RequestType
+		internal string
+		public   string
+		ok       bool
+	}{
+		{TopoType, "/topo/v3/app/search/{owner_id}", "/api/v3/biz/search/{owner_id}", true},
+		{TopoType, "/topo/v3/objectattr/search", "/api/v3/object/attr/search", true},
+		{TopoType, "/topo/v3/inst/association/action/search", "/api/v3/inst/association/action/search", true},
+		{TopoType, "/topo/v3/objects", "/api/v3/objects", true},
+		{TopoType, "/topo/v3/healthz", "", false},
+		{HostType, "/host/v3/hosts/search", "/api/v3/hosts/search", true},
+		{HostType, "/host/v3/plat", "", false},
+		{ProcType, "/process/v3/{bk_supplier_account}/{bk_biz_id}", "/api/v3/proc/{bk_supplier_account}/{bk_biz_id}", true},
+		{EventType, "/event/v3/subscribe/search/{bk_supplier_account}/{bk_biz_id}", "/api/v3/event/subscribe/search/{bk_supplier_account}/{bk_biz_id}", true},
+		{DataCollectType, "/collector/v3/netcollect/device/action/search", "/api/v3/collector/netcollect/device/action/search", true},
+	}
+
+	for _, c := range cases {
+		public, ok := publicPathOf(c.kind, c.internal)
+		if ok != c.ok || public != c.public {
+			t.Errorf("public path of %s: got (%q, %v), want (%q, %v)", c.internal, public, ok, c.public, c.ok)
+		}
+	}
+}
+
+func TestStoreOpenAPI(t *testing.T) {
+	s := new(Service)
+	partial := openapi.NewDocument("partial", "v3")
+	if doc := s.storeOpenAPI(partial, false, false); doc != partial {
+		t.Fatalf("the incomplete document should be served without a previous one")
+	}
+	if s.docBackoff != openapiMinBackoff || time.Until(s.docExpire) > openapiMinBackoff {
+		t.Fatalf("the backends should be requested again after the backoff, got %v", s.docBackoff)
+	}
+
+	complete := openapi.NewDocument("complete", "v3")
+	s.docFetching = true
+	if doc := s.storeOpenAPI(complete, true, true); doc != complete || s.docFetching || s.docBackoff != 0 {
+		t.Fatalf("the complete document should replace the previous one")
+	}
+	if time.Until(s.docExpire) <= openapiCacheTTL-time.Minute {
+		t.Fatalf("the complete document should be cached for the ttl")
+	}
+
+	for i := 0; i < 10; i++ {
+		if doc := s.storeOpenAPI(openapi.NewDocument("partial", "v3"), false, true); doc != complete {
+			t.Fatalf("the previous document should be kept when the backends are unavailable")
+		}
+	}
+	if s.docBackoff != openapiCacheTTL {
+		t.Fatalf("the backoff should be bounded by the ttl, got %v", s.docBackoff)
+	}
+}
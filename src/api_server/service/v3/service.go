@@ -18,15 +18,21 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/emicklei/go-restful"
 
+	"configcenter/src/api_server/cache"
+	"configcenter/src/api_server/middleware"
+	"configcenter/src/api_server/ratelimit"
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/blog"
 	cErr "configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/types"
 )
@@ -36,8 +42,19 @@ type HttpClient interface {
 }
 
 type Service struct {
-	Engine *backbone.Engine
-	Client HttpClient
+	Engine  *backbone.Engine
+	Client  HttpClient
+	Limiter *ratelimit.Limiter
+	Cache   *cache.Cache
+	// Handler serve the sub requests of the batch api, it is the container of the web services
+	Handler http.Handler
+
+	// the aggregated openapi document and when it expires, the lock is not held while aggregating it
+	docLock     sync.Mutex
+	doc         *openapi.Document
+	docExpire   time.Time
+	docBackoff  time.Duration
+	docFetching bool
 }
 
 const (
@@ -49,8 +66,14 @@ func (s *Service) V3WebService() *restful.WebService {
 	getErrFunc := func() cErr.CCErrorIf {
 		return s.Engine.CCErr
 	}
+	getEngineFunc := func() *backbone.Engine {
+		return s.Engine
+	}
 	ws.Path(rootPath).
+		Filter(middleware.APITokenFilter(getEngineFunc)).
 		Filter(rdapi.AllGlobalFilter(getErrFunc)).
+		Filter(middleware.RateLimitFilter(getEngineFunc, s.Limiter)).
+		Filter(middleware.CacheFilter(getEngineFunc, s.Cache)).
 		Produces(restful.MIME_JSON)
 	ws.Route(ws.GET("{.*}").Filter(s.URLFilterChan).To(s.Get))
 	ws.Route(ws.POST("{.*}").Filter(s.URLFilterChan).To(s.Post))
@@ -161,25 +184,8 @@ func (s *Service) URLFilterChan(req *restful.Request, resp *restful.Response, ch
 		}
 	}()
 
-	servers := make([]string, 0)
-	switch kind {
-	case TopoType:
-		servers, err = s.Engine.Discovery().TopoServer().GetServers()
-
-	case ProcType:
-		servers, err = s.Engine.Discovery().ProcServer().GetServers()
-
-	case EventType:
-		servers, err = s.Engine.Discovery().EventServer().GetServers()
-
-	case HostType:
-		servers, err = s.Engine.Discovery().HostServer().GetServers()
-
-	case DataCollectType:
-		servers, err = s.Engine.Discovery().DataCollect().GetServers()
-
-	}
-
+	var servers []string
+	servers, err = s.servers(kind)
 	if err != nil {
 		return
 	}
@@ -195,6 +201,28 @@ func (s *Service) URLFilterChan(req *restful.Request, resp *restful.Response, ch
 	chain.ProcessFilter(req, resp)
 }
 
+// servers return the servers of the backend which serves the kind of requests
+func (s *Service) servers(kind RequestType) ([]string, error) {
+	switch kind {
+	case TopoType:
+		return s.Engine.Discovery().TopoServer().GetServers()
+
+	case ProcType:
+		return s.Engine.Discovery().ProcServer().GetServers()
+
+	case EventType:
+		return s.Engine.Discovery().EventServer().GetServers()
+
+	case HostType:
+		return s.Engine.Discovery().HostServer().GetServers()
+
+	case DataCollectType:
+		return s.Engine.Discovery().DataCollect().GetServers()
+
+	}
+	return nil, fmt.Errorf("unknown request type %s", kind)
+}
+
 func (s *Service) V3Healthz() *restful.WebService {
 	ws := new(restful.WebService)
 	getErrFunc := func() cErr.CCErrorIf {
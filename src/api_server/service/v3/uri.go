@@ -56,6 +56,9 @@ func (u *V3URLPath) WithTopo(req *restful.Request) (isHit bool) {
 	case strings.HasPrefix(string(*u), rootPath+"/audit/"):
 		from, to, isHit = rootPath, topoRoot, true
 
+	case string(*u) == rootPath+"/graphql":
+		from, to, isHit = rootPath, topoRoot, true
+
 	case strings.HasPrefix(string(*u), rootPath+"/biz/"):
 		from, to, isHit = rootPath+"/biz", topoRoot+"/app", true
 
@@ -185,6 +188,12 @@ func (u *V3URLPath) WithHost(req *restful.Request) (isHit bool) {
 	case strings.HasPrefix(string(*u), rootPath+"/usercustom/"):
 		from, to, isHit = rootPath, hostRoot, true
 
+	case string(*u) == (rootPath + "/usertoken"):
+		from, to, isHit = rootPath, hostRoot, true
+
+	case strings.HasPrefix(string(*u), rootPath+"/usertoken/"):
+		from, to, isHit = rootPath, hostRoot, true
+
 	case string(*u) == (rootPath + "/modulehost"):
 		from, to, isHit = rootPath, hostRoot, true
 
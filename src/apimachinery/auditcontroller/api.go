@@ -48,6 +48,31 @@ func (t *auditctl) GetAuditLog(ctx context.Context, h http.Header, opt *metadata
 	return
 }
 
+func (t *auditctl) VerifyAuditChain(ctx context.Context, h http.Header) (resp *metadata.AuditChainVerifyResp, err error) {
+	resp = new(metadata.AuditChainVerifyResp)
+	subPath := "/chain/verify"
+
+	err = t.client.Post().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (t *auditctl) ExportAuditLog(ctx context.Context, h http.Header, opt *metadata.AuditExportOption) (resp *http.Response, err error) {
+	subPath := "/export"
+
+	return t.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResource(subPath).
+		WithHeaders(h).
+		Stream()
+}
+
 func (t *auditctl) AddHostLog(ctx context.Context, ownerID string, businessID string, user string, h http.Header, log interface{}) (resp *metadata.Response, err error) {
 	resp = new(metadata.Response)
 	subPath := fmt.Sprintf("/host/%s/%s/%s", ownerID, businessID, user)
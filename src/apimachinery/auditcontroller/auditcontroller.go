@@ -25,6 +25,8 @@ import (
 type AuditCtrlInterface interface {
 	AddBusinessLog(ctx context.Context, ownerID string, businessID string, user string, h http.Header, dat interface{}) (resp *metadata.Response, err error)
 	GetAuditLog(ctx context.Context, h http.Header, opt *metadata.QueryInput) (resp *metadata.Response, err error)
+	VerifyAuditChain(ctx context.Context, h http.Header) (resp *metadata.AuditChainVerifyResp, err error)
+	ExportAuditLog(ctx context.Context, h http.Header, opt *metadata.AuditExportOption) (resp *http.Response, err error)
 
 	AddHostLog(ctx context.Context, ownerID string, businessID string, user string, h http.Header, log interface{}) (resp *metadata.Response, err error)
 	AddHostLogs(ctx context.Context, ownerID string, businessID string, user string, h http.Header, logs interface{}) (resp *metadata.Response, err error)
@@ -76,6 +76,20 @@ func (inst *instance) UpdateInstance(ctx context.Context, h http.Header, objID s
 	return
 }
 
+func (inst *instance) ValidateManyInstance(ctx context.Context, h http.Header, objID string, input *metadata.ValidateManyModelInstance) (resp *metadata.ValidatedManyOptionResult, err error) {
+	resp = new(metadata.ValidatedManyOptionResult)
+	subPath := fmt.Sprintf("/validatemany/model/%s/instance", objID)
+
+	err = inst.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
 func (inst *instance) ReadInstance(ctx context.Context, h http.Header, objID string, input *metadata.QueryCondition) (resp *metadata.QueryConditionResult, err error) {
 	resp = new(metadata.QueryConditionResult)
 	subPath := fmt.Sprintf("/read/model/%s/instances", objID)
@@ -14,6 +14,7 @@ package synchronize
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"configcenter/src/common/metadata"
@@ -88,3 +89,31 @@ func (inst *synchronize) SynchronizeClearData(ctx context.Context, h http.Header
 		Into(resp)
 	return
 }
+
+func (inst *synchronize) SearchSynchronizeConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictSearchParameter) (resp *metadata.SynchronizeConflictSearchResult, err error) {
+	resp = new(metadata.SynchronizeConflictSearchResult)
+	subPath := "/read/synchronize/conflict"
+
+	err = inst.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (inst *synchronize) ResolveSynchronizeConflict(ctx context.Context, h http.Header, id int64, input *metadata.SynchronizeConflictResolveParameter) (resp *metadata.Response, err error) {
+	resp = new(metadata.Response)
+	subPath := fmt.Sprintf("/update/synchronize/conflict/resolve/%d", id)
+
+	err = inst.client.Put().
+		WithContext(ctx).
+		Body(input).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
@@ -26,6 +26,8 @@ type SynchronizeClientInterface interface {
 	SynchronizeAssociation(ctx context.Context, h http.Header, input *metadata.SynchronizeParameter) (resp *metadata.SynchronizeResult, err error)
 	SynchronizeFind(ctx context.Context, h http.Header, input *metadata.SynchronizeFindInfoParameter) (resp *metadata.ResponseInstData, err error)
 	SynchronizeClearData(ctx context.Context, h http.Header, input *metadata.SynchronizeClearDataParameter) (resp *metadata.Response, err error)
+	SearchSynchronizeConflict(ctx context.Context, h http.Header, input *metadata.SynchronizeConflictSearchParameter) (resp *metadata.SynchronizeConflictSearchResult, err error)
+	ResolveSynchronizeConflict(ctx context.Context, h http.Header, id int64, input *metadata.SynchronizeConflictResolveParameter) (resp *metadata.Response, err error)
 }
 
 // NewSynchronizeClientInterface new public api
@@ -16,7 +16,6 @@ import (
 	"fmt"
 
 	"configcenter/src/common"
-	"configcenter/src/common/backbone/service_mange/zk"
 	"configcenter/src/common/registerdiscover"
 	"configcenter/src/common/types"
 )
@@ -48,8 +47,9 @@ type Interface interface {
 	GetServers() ([]string, error)
 }
 
-func NewDiscoveryInterface(client *zk.ZkClient) (DiscoveryInterface, error) {
-	disc := registerdiscover.NewRegDiscoverEx(client)
+// NewDiscoveryInterface discover the cc components with the register-discover backend, such as zookeeper or etcd
+func NewDiscoveryInterface(rdServer registerdiscover.RegDiscvServer) (DiscoveryInterface, error) {
+	disc := registerdiscover.NewRegDiscoverWithServer(rdServer)
 
 	d := &discover{
 		servers: make(map[string]*server),
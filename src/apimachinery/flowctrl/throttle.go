@@ -27,6 +27,9 @@ type RateLimiter interface {
 
 	// Burst returns the burst of this rate limiter
 	Burst() int64
+
+	// Available returns the count of the tokens can be taken immediately
+	Available() int64
 }
 
 func NewRateLimiter(qps, burst int64) RateLimiter {
@@ -60,6 +63,10 @@ func (t *tokenBucket) Burst() int64 {
 	return t.burst
 }
 
+func (t *tokenBucket) Available() int64 {
+	return t.limiter.Available()
+}
+
 func NewMockRateLimiter() RateLimiter {
 	return &mockRatelimiter{}
 }
@@ -81,3 +88,7 @@ func (*mockRatelimiter) QPS() int64 {
 func (*mockRatelimiter) Burst() int64 {
 	return 0
 }
+
+func (*mockRatelimiter) Available() int64 {
+	return 0
+}
@@ -145,3 +145,59 @@ func (u *user) GetDefaultUserCustom(ctx context.Context, user string, h http.Hea
 		Into(resp)
 	return
 }
+
+func (u *user) AddUserToken(ctx context.Context, h http.Header, dat *metadata.APIToken) (resp *metadata.APITokenResult, err error) {
+	resp = new(metadata.APITokenResult)
+	subPath := "/usertoken"
+
+	err = u.client.Post().
+		WithContext(ctx).
+		Body(dat).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (u *user) SearchUserToken(ctx context.Context, h http.Header, cond map[string]interface{}) (resp *metadata.SearchAPITokenResult, err error) {
+	resp = new(metadata.SearchAPITokenResult)
+	subPath := "/usertoken/search"
+
+	err = u.client.Post().
+		WithContext(ctx).
+		Body(cond).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (u *user) RevokeUserToken(ctx context.Context, h http.Header, id int64) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/usertoken/%d/revoke", id)
+
+	err = u.client.Put().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
+
+func (u *user) TouchUserToken(ctx context.Context, h http.Header, id int64) (resp *metadata.BaseResp, err error) {
+	resp = new(metadata.BaseResp)
+	subPath := fmt.Sprintf("/usertoken/%d/lastused", id)
+
+	err = u.client.Put().
+		WithContext(ctx).
+		Body(nil).
+		SubResource(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+	return
+}
@@ -31,6 +31,11 @@ type UserInterface interface {
 	UpdateUserCustomByID(ctx context.Context, user string, id string, h http.Header, dat map[string]interface{}) (resp *metadata.BaseResp, err error)
 	GetUserCustomByUser(ctx context.Context, user string, h http.Header) (resp *metadata.GetUserCustomResult, err error)
 	GetDefaultUserCustom(ctx context.Context, user string, h http.Header) (resp *metadata.GetUserCustomResult, err error)
+
+	AddUserToken(ctx context.Context, h http.Header, dat *metadata.APIToken) (resp *metadata.APITokenResult, err error)
+	SearchUserToken(ctx context.Context, h http.Header, cond map[string]interface{}) (resp *metadata.SearchAPITokenResult, err error)
+	RevokeUserToken(ctx context.Context, h http.Header, id int64) (resp *metadata.BaseResp, err error)
+	TouchUserToken(ctx context.Context, h http.Header, id int64) (resp *metadata.BaseResp, err error)
 }
 
 func NewUserInterface(client rest.ClientInterface) UserInterface {
@@ -25,13 +25,17 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"configcenter/src/apimachinery/util"
+	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/idempotency"
+	"configcenter/src/common/trace"
 	commonUtil "configcenter/src/common/util"
 )
 
@@ -73,6 +77,9 @@ type Request struct {
 	// request timeout value
 	timeout time.Duration
 
+	// identify the logical write, the retries of the write carry the same key
+	idempotencyKey string
+
 	peek bool
 	err  error
 }
@@ -124,6 +131,13 @@ func (r *Request) WithTimeout(d time.Duration) *Request {
 	return r
 }
 
+// WithIdempotencyKey set the idempotency key of the write, a key is generated for every write if it's not set.
+// the key is only sent when the idempotency store of the process is shared by all the servers.
+func (r *Request) WithIdempotencyKey(key string) *Request {
+	r.idempotencyKey = key
+	return r
+}
+
 func (r *Request) SubResource(subPath string) *Request {
 	subPath = strings.TrimLeft(subPath, "/")
 	r.subPath = subPath
@@ -210,6 +224,102 @@ func (r *Request) Do() *Result {
 		return r.handleMockResult()
 	}
 
+	span := r.startSpan()
+	defer func() {
+		span.SetError(result.Err)
+		if result.StatusCode != 0 {
+			span.SetAttribute("http.status_code", strconv.Itoa(result.StatusCode))
+		}
+		span.Finish()
+	}()
+
+	ctx, cancel := r.context()
+	defer cancel()
+
+	// the server replays the recorded response to the retries of the write, so that it can be retried safely.
+	// it only works when the responses are recorded in the store shared by all the servers.
+	idempotencyKey := ""
+	if r.verb != GET && idempotency.Shared() && !r.isReadPost() {
+		idempotencyKey = r.idempotencyKey
+		if idempotencyKey == "" {
+			idempotencyKey = commonUtil.GenerateRID()
+		}
+	}
+
+	setHeader := func(header http.Header) {
+		header.Set("Content-Type", "application/json")
+		header.Set("Accept", "application/json")
+		trace.Inject(header, span)
+		if idempotencyKey != "" {
+			header.Set(common.BKHTTPIdempotencyKey, idempotencyKey)
+		}
+	}
+	// "Connection reset by peer" is a special err which in most scenario is a a transient error.
+	// Which means that we can retry it. And so does the GET operation.
+	// While the other "write" operation can only be retried with an idempotency key, so that
+	// the server replays the response instead of doing it again if the write has been done.
+	retryable := func(err error) bool {
+		return isConnectionReset(err) && (r.verb == GET || idempotencyKey != "")
+	}
+	handle := func(url string, resp *http.Response) (bool, error) {
+		var body []byte
+		if resp.Body != nil {
+			data, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return err == io.ErrUnexpectedEOF, err
+			}
+			body = data
+		}
+		blog.V(4).InfoDepthf(3, "[apimachinary][peek] %s %s with body %s, response %s, rid: %s", string(r.verb), url, r.body, body, commonUtil.GetHTTPCCRequestID(r.headers))
+		result.Body = body
+		result.StatusCode = resp.StatusCode
+		if r.peek {
+			blog.Infof("[apimachinary][peek] %s %s with body %s, response %s", string(r.verb), url, r.body, body)
+		}
+		return false, nil
+	}
+
+	result.Err = r.send(ctx, setHeader, retryable, handle)
+	return result
+}
+
+func (r *Request) Stream() (*http.Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	setHeader := func(header http.Header) {
+		header.Set("Content-Type", "application/json")
+	}
+	// only the idempotent GET operation can be retried on another host
+	retryable := func(err error) bool {
+		return isConnectionReset(err) && r.verb == GET
+	}
+	var stream *http.Response
+	handle := func(url string, resp *http.Response) (bool, error) {
+		blog.V(4).InfoDepthf(3, "[apimachinary][stream] %s %s with body %s, status %s, rid: %s", string(r.verb), url, r.body, resp.Status, commonUtil.GetHTTPCCRequestID(r.headers))
+		stream = resp
+		return false, nil
+	}
+
+	// the context is canceled when the response body is closed
+	ctx, cancel := r.context()
+	if err := r.send(ctx, setHeader, retryable, handle); err != nil {
+		cancel()
+		return nil, err
+	}
+	stream.Body = &cancelBody{ReadCloser: stream.Body, cancel: cancel}
+	return stream, nil
+}
+
+const maxRetryCycle = 3
+
+// send try the request on the discovered hosts in turn. the failed request is retried on the next host
+// when retryable accepts its error, or handle asks to retry with the response.
+func (r *Request) send(ctx context.Context, setHeader func(http.Header), retryable func(error) bool,
+	handle func(url string, resp *http.Response) (bool, error)) error {
+
 	client := r.capability.Client
 	if client == nil {
 		client = http.DefaultClient
@@ -217,88 +327,120 @@ func (r *Request) Do() *Result {
 
 	hosts, err := r.capability.Discover.GetServers()
 	if err != nil {
-		result.Err = err
-		return result
+		return err
 	}
 
-	maxRetryCycle := 3
-	var retries int
 	for try := 0; try < maxRetryCycle; try++ {
 		for index, host := range hosts {
-			retries = try + index
+			// the caller has given up the request, do not try the other hosts any more
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			url := host + r.WrapURL().String()
 			req, err := http.NewRequest(string(r.verb), url, bytes.NewReader(r.body))
 			if err != nil {
-				result.Err = err
-				return result
+				return err
 			}
+			req = req.WithContext(ctx)
 
-			if r.ctx != nil {
-				req.WithContext(r.ctx)
+			// copy the headers, so that the traceparent of the caller's header is not replaced
+			req.Header = make(http.Header, len(r.headers)+3)
+			for key, values := range r.headers {
+				req.Header[key] = values
 			}
+			setHeader(req.Header)
+			setTimeoutHeader(ctx, req.Header)
 
-			req.Header = r.headers
-			if len(req.Header) == 0 {
-				req.Header = make(http.Header)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("Accept", "application/json")
-
-			if retries > 0 {
+			if try+index > 0 {
 				r.tryThrottle(url)
 			}
 
 			resp, err := client.Do(req)
+			retry := false
 			if err != nil {
-				// "Connection reset by peer" is a special err which in most scenario is a a transient error.
-				// Which means that we can retry it. And so does the GET operation.
-				// While the other "write" operation can not simply retry it again, because they are not idempotent.
-
-				if !isConnectionReset(err) || r.verb != GET {
-					result.Err = err
-					if r.peek {
-						blog.Infof("[apimachinary][peek] %s %s with body %s, but %v", string(r.verb), url, r.body, err)
-					}
-					return result
-				}
-
-				// retry now
-				time.Sleep(20 * time.Millisecond)
-				continue
-
+				retry = retryable(err)
+			} else {
+				retry, err = handle(url, resp)
 			}
-
-			var body []byte
-			if resp.Body != nil {
-				data, err := ioutil.ReadAll(resp.Body)
-				if err != nil {
-					if err == io.ErrUnexpectedEOF {
-						// retry now
-						time.Sleep(20 * time.Millisecond)
-						continue
-					}
-					result.Err = err
-					if r.peek {
-						blog.Infof("[apimachinary][peek] %s %s with body %s, but %v", string(r.verb), url, r.body, err)
-					}
-					return result
+			if retry {
+				// retry now
+				if err := waitRetry(ctx); err != nil {
+					return err
 				}
-				body = data
+				continue
 			}
-			blog.V(4).InfoDepthf(2, "[apimachinary][peek] %s %s with body %s, response %s, rid: %s", string(r.verb), url, r.body, body, commonUtil.GetHTTPCCRequestID(r.headers))
-			result.Body = body
-			result.StatusCode = resp.StatusCode
-			if r.peek {
-				blog.Infof("[apimachinary][peek] %s %s with body %s, response %s", string(r.verb), url, r.body, body)
+			if err != nil && r.peek {
+				blog.Infof("[apimachinary][peek] %s %s with body %s, but %v", string(r.verb), url, r.body, err)
 			}
-
-			return result
+			return err
 		}
+	}
 
+	return errors.New("unexpected error")
+}
+
+// cancelBody cancel the context of the request when the response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// context returns the context of the request, which is done when the context set by WithContext is done,
+// or the timeout set by WithTimeout expires.
+func (r *Request) context() (context.Context, context.CancelFunc) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r.timeout > 0 {
+		return context.WithTimeout(ctx, r.timeout)
 	}
+	return context.WithCancel(ctx)
+}
 
-	result.Err = errors.New("unexpected error")
-	return result
+// setTimeoutHeader tell the server the time left of the request, so that it can give up the request in time.
+// the timeout inherited from the header of the caller is removed when the request has no deadline.
+func setTimeoutHeader(ctx context.Context, header http.Header) {
+	if deadline, ok := ctx.Deadline(); ok {
+		commonUtil.SetHTTPCCRequestTimeout(header, time.Until(deadline))
+		return
+	}
+	header.Del(common.BKHTTPCCRequestTimeout)
+}
+
+const retryInterval = 20 * time.Millisecond
+
+// waitRetry wait a moment before the request is retried, it returns the error of the context if it's done.
+func waitRetry(ctx context.Context) error {
+	timer := time.NewTimer(retryInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// startSpan start a client span of the request, its parent is the span carried by the context,
+// or the span carried by the request header if the context does not carry one.
+func (r *Request) startSpan() *trace.Span {
+	ctx := r.ctx
+	if _, ok := trace.SpanContextFromContext(ctx); !ok {
+		ctx = trace.ContextWithHeader(ctx, r.headers)
+	}
+	span, _ := trace.StartSpan(ctx, fmt.Sprintf("%s /%s", r.verb, r.subPath), trace.SpanKindClient)
+	span.SetAttribute("http.method", string(r.verb))
+	span.SetAttribute("http.path", "/"+r.subPath)
+	span.SetAttribute("rid", commonUtil.GetHTTPCCRequestID(r.headers))
+	return span
 }
 
 const maxLatency = 100 * time.Millisecond
@@ -417,3 +559,23 @@ func isConnectionReset(err error) bool {
 	}
 	return false
 }
+
+// readPathPrefixes the post requests whose path segment starts with them only read the data.
+// they need not be retried with the idempotency keys, misjudging a write only makes it not retried.
+var readPathPrefixes = []string{"search", "find", "read", "count", "get"}
+
+// isReadPost returns whether the post request only reads the data
+func (r *Request) isReadPost() bool {
+	if r.verb != POST {
+		return false
+	}
+	for _, seg := range strings.Split(r.subPath, "/") {
+		seg = strings.ToLower(seg)
+		for _, prefix := range readPathPrefixes {
+			if strings.HasPrefix(seg, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"configcenter/src/apimachinery/util"
+	"configcenter/src/common"
+	"configcenter/src/common/idempotency"
+)
+
+type staticDiscover []string
+
+func (d staticDiscover) GetServers() ([]string, error) {
+	return d, nil
+}
+
+func TestRequestTimeout(t *testing.T) {
+	timeouts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeouts <- r.Header.Get(common.BKHTTPCCRequestTimeout)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	client := NewRESTClient(&util.Capability{Discover: staticDiscover{server.URL}}, "/api/v3")
+
+	start := time.Now()
+	result := client.Get().SubResource("/slow").WithTimeout(100 * time.Millisecond).Do()
+	if result.Err == nil {
+		t.Fatalf("the request should be timeout")
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Fatalf("the request should be given up after the timeout, but took %v", cost)
+	}
+	timeout := <-timeouts
+	if timeout == "" || timeout == "0" {
+		t.Fatalf("the timeout header should be set, got %q", timeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = client.Get().SubResource("/slow").WithContext(ctx).Do()
+	if result.Err != context.Canceled {
+		t.Fatalf("the canceled request should not be sent, got err: %v", result.Err)
+	}
+}
+
+func TestRequestStaleTimeout(t *testing.T) {
+	timeouts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeouts <- r.Header.Get(common.BKHTTPCCRequestTimeout)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	client := NewRESTClient(&util.Capability{Discover: staticDiscover{server.URL}}, "/api/v3")
+
+	// the header is inherited from the caller, but the request is not bounded by its deadline
+	header := http.Header{}
+	header.Set(common.BKHTTPCCRequestTimeout, "10")
+	result := client.Get().SubResource("/fast").WithHeaders(header).Do()
+	if result.Err != nil {
+		t.Fatalf("the request should be done, got err: %v", result.Err)
+	}
+	if timeout := <-timeouts; timeout != "" {
+		t.Fatalf("the stale timeout header should be removed, got %q", timeout)
+	}
+	if header.Get(common.BKHTTPCCRequestTimeout) != "10" {
+		t.Fatalf("the header of the caller should not be changed")
+	}
+}
+
+func TestRequestIdempotencyKey(t *testing.T) {
+	keys := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(common.BKHTTPIdempotencyKey)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	client := NewRESTClient(&util.Capability{Discover: staticDiscover{server.URL}}, "/api/v3")
+	send := func(req *Request) string {
+		if result := req.Do(); result.Err != nil {
+			t.Fatalf("request failed, err: %v", result.Err)
+		}
+		return <-keys
+	}
+
+	if key := send(client.Post().SubResource("/create").Body(map[string]string{"name": "a"})); key != "" {
+		t.Fatalf("a write should not carry an idempotency key without the shared store, got %s", key)
+	}
+
+	idempotency.SetStore(idempotency.NewRedisStore(nil), idempotency.DefaultWindow)
+	defer idempotency.SetStore(idempotency.NewMemoryStore(), idempotency.DefaultWindow)
+
+	if key := send(client.Post().SubResource("/create").Body(map[string]string{"name": "a"})); key == "" {
+		t.Fatalf("a write should carry an idempotency key")
+	}
+	if key := send(client.Get().SubResource("/find")); key != "" {
+		t.Fatalf("a read should not carry an idempotency key, got %s", key)
+	}
+	if key := send(client.Post().SubResource("/search/object").Body(map[string]string{"name": "a"})); key != "" {
+		t.Fatalf("a search should not carry an idempotency key, got %s", key)
+	}
+}
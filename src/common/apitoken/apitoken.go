@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package apitoken authenticate and authorize the requests with the personal api tokens
+package apitoken
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"configcenter/src/apimachinery"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+const (
+	// TokenPrefix the prefix of the personal api token, which make the leaked tokens easy to be found
+	TokenPrefix = "cctk_"
+	// DisplayPrefixLength the length of the token prefix kept to recognize the token
+	DisplayPrefixLength = len(TokenPrefix) + 6
+
+	// touchInterval the last used time is updated at most once in the interval
+	touchInterval = time.Minute
+	// maxScopeBodySize the max size of the request body parsed to check the scope
+	maxScopeBodySize = 4 << 20
+)
+
+var (
+	// ErrInvalidToken the token is not found, expired or revoked
+	ErrInvalidToken = errors.New("invalid api token")
+	// ErrNoPermission the request is out of the scope of the token
+	ErrNoPermission = errors.New("api token has no permission")
+	// ErrBodyTooLarge the request body is too large to check the scope
+	ErrBodyTooLarge = errors.New("request body is too large to check the api token scope")
+)
+
+// Generate create a new token, return the token, the hash to store and the prefix to display
+func Generate() (token, hash, prefix string, err error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); nil != err {
+		return "", "", "", err
+	}
+	token = TokenPrefix + hex.EncodeToString(buf)
+	return token, Hash(token), token[:DisplayPrefixLength], nil
+}
+
+// Hash return the hash of the token which is stored and used to find the token
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// FromHeader return the personal api token in the Authorization header, or empty if there is none
+func FromHeader(header http.Header) string {
+	auth := strings.TrimSpace(header.Get("Authorization"))
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		return ""
+	}
+	token := strings.TrimSpace(auth[7:])
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return ""
+	}
+	return token
+}
+
+// Authenticate find the token and check whether it is still valid
+func Authenticate(ctx context.Context, client apimachinery.ClientSetInterface, header http.Header, token string) (*metadata.APIToken, error) {
+	h := make(http.Header)
+	h.Set(common.BKHTTPHeaderUser, common.CCSystemOperatorUserName)
+	h.Set(common.BKHTTPOwnerID, common.BKSuperOwnerID)
+	h.Set(common.BKHTTPCCRequestID, header.Get(common.BKHTTPCCRequestID))
+
+	cond := map[string]interface{}{"token_hash": Hash(token)}
+	result, err := client.HostController().User().SearchUserToken(ctx, h, cond)
+	if nil != err {
+		return nil, fmt.Errorf("search api token failed, err: %v", err)
+	}
+	if !result.Result {
+		return nil, fmt.Errorf("search api token failed, err: %s", result.ErrMsg)
+	}
+	if 1 != len(result.Data) {
+		return nil, ErrInvalidToken
+	}
+	tok := result.Data[0]
+	now := time.Now()
+	if err := Check(&tok, now); nil != err {
+		return nil, err
+	}
+
+	if nil == tok.LastUsedTime || now.Sub(tok.LastUsedTime.Time) > touchInterval {
+		go func() {
+			result, err := client.HostController().User().TouchUserToken(context.Background(), h, tok.ID)
+			if nil != err || !result.Result {
+				blog.Warnf("update the last used time of api token %d failed, err: %v, result: %v", tok.ID, err, result)
+			}
+		}()
+	}
+	return &tok, nil
+}
+
+// Check check whether the token is revoked or expired
+func Check(tok *metadata.APIToken, now time.Time) error {
+	if tok.Revoked || !now.Before(tok.ExpireTime.Time) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// SetHeaders set the user and supplier account of the token to the request header,
+// the headers passed by the caller are overwritten
+func SetHeaders(header http.Header, tok *metadata.APIToken) {
+	header.Set(common.BKHTTPHeaderUser, tok.User)
+	header.Set(common.BKHTTPOwnerID, tok.OwnerID)
+	header.Set(common.BKHTTPAPITokenID, strconv.FormatInt(tok.ID, 10))
+}
+
+// ReadBody read the request body for the scope check and restore it for the later handlers,
+// ErrBodyTooLarge is returned if the body is too large to be parsed
+func ReadBody(req *http.Request) ([]byte, error) {
+	if nil == req.Body {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if nil != err {
+		return nil, err
+	}
+	if len(body) > maxScopeBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	return body, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apitoken
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+)
+
+func TestGenerate(t *testing.T) {
+	token, hash, prefix, err := Generate()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, TokenPrefix))
+	require.Equal(t, Hash(token), hash)
+	require.Equal(t, DisplayPrefixLength, len(prefix))
+	require.True(t, strings.HasPrefix(token, prefix))
+
+	other, _, _, err := Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+}
+
+func TestFromHeader(t *testing.T) {
+	header := make(http.Header)
+	require.Equal(t, "", FromHeader(header))
+	header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	require.Equal(t, "", FromHeader(header))
+	header.Set("Authorization", "Bearer other-token")
+	require.Equal(t, "", FromHeader(header))
+	header.Set("Authorization", "bearer  cctk_0123456789 ")
+	require.Equal(t, "cctk_0123456789", FromHeader(header))
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	tok := &metadata.APIToken{ExpireTime: metadata.Time{Time: now.Add(time.Hour)}}
+	require.NoError(t, Check(tok, now))
+	require.Equal(t, ErrInvalidToken, Check(tok, now.Add(2*time.Hour)))
+	tok.Revoked = true
+	require.Equal(t, ErrInvalidToken, Check(tok, now))
+}
+
+func TestSetHeaders(t *testing.T) {
+	header := make(http.Header)
+	header.Set(common.BKHTTPHeaderUser, "admin")
+	SetHeaders(header, &metadata.APIToken{ID: 3, User: "user", OwnerID: "0"})
+	require.Equal(t, "user", header.Get(common.BKHTTPHeaderUser))
+	require.Equal(t, "0", header.Get(common.BKHTTPOwnerID))
+	require.Equal(t, "3", header.Get(common.BKHTTPAPITokenID))
+}
+
+func TestReadBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/api/v3/hosts/search", bytes.NewBufferString(`{"bk_biz_id":2}`))
+	require.NoError(t, err)
+	body, err := ReadBody(req)
+	require.NoError(t, err)
+	require.Equal(t, `{"bk_biz_id":2}`, string(body))
+	again, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, body, again)
+}
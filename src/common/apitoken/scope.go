@@ -0,0 +1,412 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apitoken
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+)
+
+// managePathSegment the api tokens can not be used to manage the api tokens
+const managePathSegment = "usertoken"
+
+// readRoutes the post apis which only read the data, the read only tokens can call them besides the get apis.
+// the routes are the paths of the api gateway without the version prefix, the "{}" segment matches any value.
+var readRoutes = parseRoutes(
+	// topo server
+	"/find/object",
+	"/findmany/object",
+	"/find/classificationobject",
+	"/find/objectclassification",
+	"/find/objectattr",
+	"/find/objectunique/object/{bk_obj_id}",
+	"/find/objectattgroup/object/{bk_obj_id}",
+	"/find/objecttopo/scope_type/{scope_type}/scope_id/{scope_id}",
+	"/find/topomodelmainline",
+	"/find/topoinst/biz/{bk_biz_id}",
+	"/find/topoassociationtype",
+	"/find/associationtype",
+	"/find/objectassociation",
+	"/find/instassociation",
+	"/find/instassociation/object/{bk_obj_id}",
+	"/find/instassttopo/object/{bk_obj_id}/inst/{inst_id}",
+	"/find/insttopo/object/{bk_obj_id}/inst/{inst_id}",
+	"/find/instance/object/{bk_obj_id}",
+	"/find/instdetail/object/{bk_obj_id}/inst/{inst_id}",
+	"/topo/association/type/action/search",
+	"/topo/association/type/action/search/batch",
+	"/topo/privilege/group/{bk_supplier_account}/search",
+	"/object/association/action/search",
+	"/inst/association/action/search",
+	"/inst/association/search/owner/{owner_id}/object/{bk_obj_id}",
+	"/inst/association/topo/search/owner/{owner_id}/object/{bk_obj_id}/inst/{inst_id}",
+	"/inst/search/topo/owner/{owner_id}/object/{bk_obj_id}/inst/{inst_id}",
+	"/inst/search/{owner_id}/{bk_obj_id}",
+	"/inst/search/{owner_id}/{bk_obj_id}/{inst_id}",
+	"/inst/search/owner/{owner_id}/object/{bk_obj_id}",
+	"/inst/search/owner/{owner_id}/object/{bk_obj_id}/detail",
+	"/audit/search",
+	"/audit/export",
+	"/graphql",
+	"/biz/search/{owner_id}",
+	"/biz/default/{owner_id}/search",
+	"/set/search/{owner_id}/{app_id}",
+	"/module/search/{owner_id}/{app_id}/{set_id}",
+	"/object/attr/search",
+	"/object/classifications",
+	"/object/classification/{owner_id}/objects",
+	"/object/search/batch",
+	"/objects",
+	"/objects/topo",
+	"/objects/topographics/scope_type/{scope_type}/scope_id/{scope_id}/action/search",
+	"/objectatt/group/property/owner/{owner_id}/object/{bk_obj_id}",
+	"/identifier/{obj_type}/search",
+	// host server
+	"/hosts/search",
+	"/hosts/search/asstdetail",
+	"/hosts/modules/read",
+	"/hosts/favorites/search",
+	"/hosts/cloud/search",
+	"/hosts/cloud/searchConfirm",
+	"/hosts/cloud/accountSearch",
+	"/hosts/cloud/syncHistory",
+	"/hosts/cloud/confirmHistory/search",
+	"/host/lock/search",
+	"/userapi/search/{bk_biz_id}",
+	"/usercustom/user/search",
+	"/usercustom/default/search",
+	// proc server
+	"/proc/search/{bk_supplier_account}/{bk_biz_id}",
+	"/proc/template/search/{bk_supplier_account}/{bk_biz_id}",
+	"/proc/template/version/search/{bk_supplier_account}/{bk_biz_id}/{template_id}",
+	"/proc/template/preview/{bk_supplier_account}/{bk_biz_id}/{template_id}",
+	"/proc/template/getremote/{bk_supplier_account}/{bk_biz_id}/{template_id}",
+	"/proc/template/diff/{bk_supplier_account}/{bk_biz_id}/{template_id}",
+	"/proc/openapi/GetProcessPortByApplicationID/{bk_biz_id}",
+	"/proc/openapi/GetProcessPortByIP",
+	// event server
+	"/event/subscribe/search/{ownerID}/{appID}",
+	// data collection
+	"/collector/netcollect/device/action/search",
+	"/collector/netcollect/property/action/search",
+	"/collector/netcollect/summary/action/search",
+	"/collector/netcollect/report/action/search",
+	"/collector/netcollect/history/action/search",
+	"/collector/netcollect/collector/action/search",
+)
+
+// pathObjects the objects operated by the apis which have no object id in the path
+var pathObjects = map[string]string{
+	"host":   common.BKInnerObjIDHost,
+	"hosts":  common.BKInnerObjIDHost,
+	"biz":    common.BKInnerObjIDApp,
+	"set":    common.BKInnerObjIDSet,
+	"module": common.BKInnerObjIDModule,
+	"proc":   common.BKInnerObjIDProc,
+}
+
+// objectKeywords the path segments after "object" which are not object id
+var objectKeywords = map[string]bool{
+	"attr":           true,
+	"classification": true,
+	"association":    true,
+	"search":         true,
+	"batch":          true,
+}
+
+// bizKeywords the path segments after "biz" which are not followed by the business id
+var bizKeywords = map[string]bool{
+	"search":  true,
+	"default": true,
+}
+
+// Authorize check whether the request is in the scope of the token,
+// the body must be the whole request body if the token is limited to some businesses or objects
+func Authorize(scope metadata.APITokenScope, method, path string, query url.Values, body []byte) error {
+	segments := pathSegments(path)
+	for _, seg := range segments {
+		if managePathSegment == seg {
+			return ErrNoPermission
+		}
+	}
+	if scope.ReadOnly && !isReadRequest(method, segments) {
+		return ErrNoPermission
+	}
+	if 0 == len(scope.BizIDs) && 0 == len(scope.ObjectIDs) {
+		return nil
+	}
+
+	bizIDs, objIDs, ok := requestTargets(segments, query, body)
+	if !ok {
+		return ErrNoPermission
+	}
+	if len(scope.BizIDs) > 0 {
+		if 0 == len(bizIDs) {
+			return ErrNoPermission
+		}
+		for _, bizID := range bizIDs {
+			if !containsInt(scope.BizIDs, bizID) {
+				return ErrNoPermission
+			}
+		}
+	}
+	if len(scope.ObjectIDs) > 0 {
+		if 0 == len(objIDs) {
+			return ErrNoPermission
+		}
+		for _, objID := range objIDs {
+			if !containsString(scope.ObjectIDs, objID) {
+				return ErrNoPermission
+			}
+		}
+	}
+	return nil
+}
+
+// pathSegments split the path without the api version prefix
+func pathSegments(path string) []string {
+	path = strings.TrimPrefix(path, "/api/v3")
+	var segments []string
+	for _, seg := range strings.Split(path, "/") {
+		if "" != seg {
+			segments = append(segments, seg)
+		}
+	}
+	return segments
+}
+
+func parseRoutes(routes ...string) [][]string {
+	ret := make([][]string, 0, len(routes))
+	for _, route := range routes {
+		ret = append(ret, pathSegments(route))
+	}
+	return ret
+}
+
+func isReadRequest(method string, segments []string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		for _, route := range readRoutes {
+			if matchRoute(route, segments) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchRoute(route, segments []string) bool {
+	if len(route) != len(segments) {
+		return false
+	}
+	for idx, seg := range route {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			continue
+		}
+		if seg != segments[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// requestTargets return the business ids and object ids the request refers to,
+// which are found in the path, the query parameters and the request body.
+// ok is false if the request may refer to the businesses or objects which can not be found out,
+// such as an unparseable body or a condition with the operators other than $eq and $in on them.
+func requestTargets(segments []string, query url.Values, body []byte) (bizIDs []int64, objIDs []string, ok bool) {
+	addBiz := func(val interface{}) bool {
+		bizID, ok := toInt64(val)
+		if ok && !containsInt(bizIDs, bizID) {
+			bizIDs = append(bizIDs, bizID)
+		}
+		return ok
+	}
+	addObj := func(val interface{}) bool {
+		objID, ok := val.(string)
+		if ok && "" != objID && !containsString(objIDs, objID) {
+			objIDs = append(objIDs, objID)
+		}
+		return ok
+	}
+
+	for idx, seg := range segments {
+		if 0 == idx {
+			if objID, ok := pathObjects[seg]; ok {
+				addObj(objID)
+			}
+		}
+		if idx+1 >= len(segments) {
+			break
+		}
+		next := segments[idx+1]
+		switch seg {
+		case "biz", "app":
+			if bizKeywords[next] {
+				break
+			}
+			// the business apis are like /biz/{bk_supplier_account}/{bk_biz_id}
+			if idx+2 < len(segments) {
+				if _, ok := toInt64(segments[idx+2]); ok {
+					next = segments[idx+2]
+				}
+			}
+			addBiz(next)
+		case common.BKAppIDField:
+			addBiz(next)
+		case "object", common.BKObjIDField:
+			if !objectKeywords[next] {
+				addObj(next)
+			}
+		}
+	}
+
+	for _, val := range query[common.BKAppIDField] {
+		addBiz(val)
+	}
+	for _, val := range query[common.BKObjIDField] {
+		addObj(val)
+	}
+
+	if 0 == len(body) {
+		return bizIDs, objIDs, true
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); nil != err {
+		return nil, nil, false
+	}
+	add := func(field string, val interface{}) bool {
+		if common.BKAppIDField == field {
+			return addBiz(val)
+		}
+		return addObj(val)
+	}
+	if !bodyTargets(data, add) {
+		return nil, nil, false
+	}
+	return bizIDs, objIDs, true
+}
+
+// bodyTargets find the business ids and object ids at any level of the body, both the field values like
+// {"bk_biz_id": 2} and the conditions like {"field": "bk_biz_id", "operator": "$in", "value": [2]}
+func bodyTargets(data interface{}, add func(field string, val interface{}) bool) bool {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if field, ok := v["field"].(string); ok && isTargetField(field) {
+			op, _ := v["operator"].(string)
+			if "" == op {
+				op = common.BKDBEQ
+			}
+			if !conditionTargets(map[string]interface{}{op: v["value"]}, func(val interface{}) bool { return add(field, val) }) {
+				return false
+			}
+		}
+		for key, item := range v {
+			if isTargetField(key) {
+				field := key
+				if !conditionTargets(item, func(val interface{}) bool { return add(field, val) }) {
+					return false
+				}
+				continue
+			}
+			if !bodyTargets(item, add) {
+				return false
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if !bodyTargets(item, add) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// conditionTargets call add with the values the condition selects, only the values, $eq and $in can be resolved
+func conditionTargets(cond interface{}, add func(val interface{}) bool) bool {
+	switch v := cond.(type) {
+	case map[string]interface{}:
+		for op, item := range v {
+			switch op {
+			case common.BKDBEQ:
+				if !add(item) {
+					return false
+				}
+			case common.BKDBIN:
+				arr, ok := item.([]interface{})
+				if !ok {
+					return false
+				}
+				for _, val := range arr {
+					if !add(val) {
+						return false
+					}
+				}
+			default:
+				return false
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if !add(val) {
+				return false
+			}
+		}
+	default:
+		return add(v)
+	}
+	return true
+}
+
+func isTargetField(field string) bool {
+	return common.BKAppIDField == field || common.BKObjIDField == field
+}
+
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return int64(v), float64(int64(v)) == v
+	case string:
+		ret, err := strconv.ParseInt(v, 10, 64)
+		return ret, nil == err
+	default:
+		return 0, false
+	}
+}
+
+func containsInt(arr []int64, val int64) bool {
+	for _, item := range arr {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(arr []string, val string) bool {
+	for _, item := range arr {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apitoken
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"configcenter/src/common/metadata"
+)
+
+func TestAuthorize(t *testing.T) {
+	readOnly := metadata.APITokenScope{ReadOnly: true}
+	require.NoError(t, Authorize(readOnly, http.MethodGet, "/api/v3/biz/0/2", nil, nil))
+	require.NoError(t, Authorize(readOnly, http.MethodPost, "/api/v3/hosts/search", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(readOnly, http.MethodPost, "/api/v3/hosts/add", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(readOnly, http.MethodDelete, "/api/v3/biz/0/2", nil, nil))
+
+	all := metadata.APITokenScope{}
+	require.NoError(t, Authorize(all, http.MethodDelete, "/api/v3/biz/0/2", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(all, http.MethodPost, "/api/v3/usertoken", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(all, http.MethodPost, "/api/v3/usertoken/search", nil, nil))
+
+	biz := metadata.APITokenScope{BizIDs: []int64{2}}
+	require.NoError(t, Authorize(biz, http.MethodPut, "/api/v3/biz/tencent/2", nil, nil))
+	require.NoError(t, Authorize(biz, http.MethodPut, "/api/v3/biz/0/2", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(biz, http.MethodPut, "/api/v3/biz/0/3", nil, nil))
+	require.NoError(t, Authorize(biz, http.MethodPost, "/api/v3/biz/search/0", nil, []byte(`{"condition":{"bk_biz_id":2}}`)))
+	require.Equal(t, ErrNoPermission, Authorize(biz, http.MethodPost, "/api/v3/biz/search/0", nil, []byte(`{"condition":{}}`)))
+	require.NoError(t, Authorize(biz, http.MethodGet, "/api/v3/topo/inst/0/bk_biz_id/2", nil, nil))
+	require.NoError(t, Authorize(biz, http.MethodGet, "/api/v3/hosts/search", url.Values{"bk_biz_id": []string{"2"}}, nil))
+	require.Equal(t, ErrNoPermission, Authorize(biz, http.MethodPost, "/api/v3/hosts/search", nil, []byte(`{"bk_biz_id":2,"condition":{"bk_biz_id":3}}`)))
+
+	obj := metadata.APITokenScope{ObjectIDs: []string{"host", "switch"}}
+	require.NoError(t, Authorize(obj, http.MethodPost, "/api/v3/hosts/search", nil, nil))
+	require.NoError(t, Authorize(obj, http.MethodPost, "/api/v3/inst/0/switch", nil, []byte(`{"bk_obj_id":"switch"}`)))
+	require.NoError(t, Authorize(obj, http.MethodPost, "/api/v3/object/switch", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(obj, http.MethodPost, "/api/v3/object/router", nil, nil))
+	require.Equal(t, ErrNoPermission, Authorize(obj, http.MethodPost, "/api/v3/object/attr/search", nil, nil))
+}
@@ -0,0 +1,96 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditoplog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// the fields which link an operation log into the audit chain
+const (
+	ChainFieldSeq      = "seq"
+	ChainFieldPrevHash = "prev_hash"
+	ChainFieldHash     = "hash"
+)
+
+// FieldImportTime the time the record is re-imported from the archive, it's not a part of the record content
+// so that it's excluded from the chain hash
+const FieldImportTime = "import_time"
+
+// AuditLogHash calculate the chain hash of an operation log row, the hash field itself is excluded.
+// the row is normalized through bson first, so that the hash of a stored record can be recalculated.
+func AuditLogHash(row interface{}) (string, error) {
+	out, err := bson.Marshal(row)
+	if nil != err {
+		return "", err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(out, &doc); nil != err {
+		return "", err
+	}
+	return AuditDocHash(doc)
+}
+
+// AuditDocHash calculate the chain hash of a stored operation log document
+func AuditDocHash(doc bson.M) (string, error) {
+	canonical := make(map[string]interface{}, len(doc))
+	for key, val := range doc {
+		if key == "_id" || key == ChainFieldHash || key == FieldImportTime {
+			continue
+		}
+		canonical[key] = canonicalValue(val)
+	}
+	// encoding/json sort the map keys, so the output is stable
+	out, err := json.Marshal(canonical)
+	if nil != err {
+		return "", err
+	}
+	sum := sha256.Sum256(out)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalValue convert the value decoded from bson into a form which is independent of the time zone
+// of the process and of the go type chosen by the decoder
+func canonicalValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case bson.M:
+		return canonicalMap(v)
+	case map[string]interface{}:
+		return canonicalMap(v)
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for idx, item := range v {
+			arr[idx] = canonicalValue(item)
+		}
+		return arr
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z")
+	case bson.ObjectId:
+		return v.Hex()
+	default:
+		return v
+	}
+}
+
+func canonicalMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, val := range m {
+		result[key] = canonicalValue(val)
+	}
+	return result
+}
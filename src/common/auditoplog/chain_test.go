@@ -0,0 +1,84 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditoplog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type testLog struct {
+	OwnerID    string      `bson:"bk_supplier_account"`
+	Content    interface{} `bson:"content"`
+	CreateTime time.Time   `bson:"op_time"`
+	Seq        int64       `bson:"seq"`
+	PrevHash   string      `bson:"prev_hash"`
+	Hash       string      `bson:"hash"`
+}
+
+func TestAuditLogHashStable(t *testing.T) {
+	row := &testLog{
+		OwnerID: "0",
+		Content: map[string]interface{}{
+			"pre_data": map[string]interface{}{"bk_host_id": 1, "last_time": time.Now()},
+			"cur_data": map[string]interface{}{"bk_host_id": 1, "tags": []interface{}{"a", 2.5}},
+		},
+		CreateTime: time.Now().In(time.FixedZone("UTC+8", 8*3600)),
+		Seq:        1,
+	}
+	hash, err := AuditLogHash(row)
+	require.NoError(t, err)
+
+	// the hash field itself and the mongo _id never take part in the hash
+	row.Hash = hash
+	out, err := bson.Marshal(row)
+	require.NoError(t, err)
+	stored := bson.M{}
+	require.NoError(t, bson.Unmarshal(out, &stored))
+	stored["_id"] = bson.NewObjectId()
+
+	storedHash, err := AuditDocHash(stored)
+	require.NoError(t, err)
+	require.Equal(t, hash, storedHash)
+}
+
+func TestAuditLogHashDetectModify(t *testing.T) {
+	row := &testLog{OwnerID: "0", Content: map[string]interface{}{"bk_host_innerip": "127.0.0.1"}, Seq: 1}
+	hash, err := AuditLogHash(row)
+	require.NoError(t, err)
+
+	row.Content = map[string]interface{}{"bk_host_innerip": "127.0.0.2"}
+	modified, err := AuditLogHash(row)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, modified)
+
+	row.Content = map[string]interface{}{"bk_host_innerip": "127.0.0.1"}
+	row.PrevHash = "forged"
+	relinked, err := AuditLogHash(row)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, relinked)
+}
+
+func TestAuditDocHashIgnoreImportTime(t *testing.T) {
+	doc := bson.M{"bk_supplier_account": "0", ChainFieldSeq: int64(1), ChainFieldPrevHash: ""}
+	hash, err := AuditDocHash(doc)
+	require.NoError(t, err)
+
+	doc[FieldImportTime] = time.Now()
+	imported, err := AuditDocHash(doc)
+	require.NoError(t, err)
+	require.Equal(t, hash, imported)
+}
@@ -17,18 +17,24 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
-	"time"
+
+	"github.com/emicklei/go-restful"
 
 	"configcenter/src/apimachinery"
 	"configcenter/src/apimachinery/discovery"
 	"configcenter/src/apimachinery/util"
 	"configcenter/src/common"
 	cc "configcenter/src/common/backbone/configcenter"
-	"configcenter/src/common/backbone/service_mange/zk"
 	"configcenter/src/common/blog"
+	crd "configcenter/src/common/confregdiscover"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/idempotency"
 	"configcenter/src/common/language"
+	"configcenter/src/common/metric"
+	"configcenter/src/common/openapi"
+	"configcenter/src/common/trace"
 	"configcenter/src/common/types"
+	"configcenter/src/common/version"
 )
 
 // BackboneParameter Used to constrain different services to ensure
@@ -45,18 +51,6 @@ type BackboneParameter struct {
 	SrvInfo *types.ServerInfo
 }
 
-func newManageSrvClient(ctx context.Context, manageSrvAddr string) (*zk.ZkClient, error) {
-	client := zk.NewZkClient(manageSrvAddr, 5*time.Second)
-	if err := client.Start(); err != nil {
-		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
-	}
-	if err := client.Ping(); err != nil {
-		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
-	}
-
-	return client, nil
-}
-
 func newConfig(ctx context.Context, srvInfo *types.ServerInfo, discovery discovery.DiscoveryInterface, apiMachinerConfig *util.APIMachineryConfig) (*Config, error) {
 
 	machinery, err := apimachinery.NewApiMachinery(apiMachinerConfig, discovery)
@@ -101,11 +95,11 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 	if err != nil {
 		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", input.Regdiscv, err)
 	}
-	discoveryInterface, err := discovery.NewDiscoveryInterface(client)
+	discoveryInterface, err := discovery.NewDiscoveryInterface(client.regDiscv)
 	if err != nil {
 		return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", input.Regdiscv, err)
 	}
-	disc, err := NewServcieDiscovery(client)
+	disc, err := NewServcieDiscovery(client.regDiscv)
 	if err != nil {
 		return nil, fmt.Errorf("new service discover failed, err:%v", err)
 	}
@@ -123,19 +117,19 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 	if err != nil {
 		return nil, fmt.Errorf("new engine failed, err: %v", err)
 	}
-	engine.client = client
+	engine.confRegDiscv = client.confRegDiscv
 	engine.apiMachinerConfig = apiMachineryConfig
 	engine.discovery = discoveryInterface
 	engine.ServiceManageInterface = discoveryInterface
 	engine.srvInfo = input.SrvInfo
 
 	handler := &cc.CCHandler{
-		OnProcessUpdate:  input.ConfigUpdate,
+		OnProcessUpdate:  onProcessUpdate(input.ConfigUpdate),
 		OnLanguageUpdate: engine.onLanguageUpdate,
 		OnErrorUpdate:    engine.onErrorUpdate,
 	}
 
-	err = cc.NewConfigCenter(ctx, client, common.GetIdentification(), input.ConfigPath, handler)
+	err = cc.New(ctx, common.GetIdentification(), input.ConfigPath, client.confRegDiscv, handler)
 	if err != nil {
 		return nil, fmt.Errorf("new config center failed, err: %v", err)
 	}
@@ -143,7 +137,36 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 	return engine, nil
 }
 
+// onProcessUpdate set the trace exporter and the idempotency store with the process config before the process handle it
+func onProcessUpdate(handler cc.ProcHandlerFunc) cc.ProcHandlerFunc {
+	return func(previous, current cc.ProcessConfig) {
+		if err := trace.Configure(current.ConfigMap); err != nil {
+			blog.Errorf("configure trace failed, err: %v", err)
+		}
+		if err := idempotency.Configure(current.ConfigMap); err != nil {
+			blog.Errorf("configure idempotency store failed, err: %v", err)
+		}
+		handler(previous, current)
+	}
+}
+
 func StartServer(ctx context.Context, e *Engine, HTTPHandler http.Handler) error {
+	// export the metrics in the prometheus text exposition format, the requests of the routes are recorded
+	if container, ok := HTTPHandler.(*restful.Container); ok {
+		container.Filter(metric.RestfulFilter)
+		container.Handle("/metrics", metric.PrometheusHandler())
+		// describe the routes with their request and response types
+		container.Handle(openapi.DocPath, openapi.ContainerHandler(common.GetIdentification(), version.CCVersion, container))
+	} else {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metric.PrometheusHandler())
+		mux.Handle("/", metric.InstrumentHandler("/", HTTPHandler))
+		HTTPHandler = mux
+	}
+
+	// replay the recorded responses to the retries of the writes with the same idempotency key
+	HTTPHandler = idempotency.Handler(func() errors.CCErrorIf { return e.CCErr }, HTTPHandler)
+
 	e.server = Server{
 		ListenAddr: e.srvInfo.IP,
 		ListenPort: e.srvInfo.Port,
@@ -173,7 +196,7 @@ func New(c *Config, disc ServiceDiscoverInterface) (*Engine, error) {
 }
 
 type Engine struct {
-	client *zk.ZkClient
+	confRegDiscv crd.ConfRegDiscvIf
 	sync.Mutex
 	ServerInfo             types.ServerInfo
 	CoreAPI                apimachinery.ClientSetInterface
@@ -196,8 +219,9 @@ func (e *Engine) ApiMachineryConfig() *util.APIMachineryConfig {
 	return e.apiMachinerConfig
 }
 
-func (e *Engine) ServiceManageClient() *zk.ZkClient {
-	return e.client
+// ConfRegDiscover return the config register and discover of the service manage backend
+func (e *Engine) ConfRegDiscover() crd.ConfRegDiscvIf {
+	return e.confRegDiscv
 }
 
 func (e *Engine) onLanguageUpdate(previous, current map[string]language.LanguageMap) {
@@ -14,6 +14,9 @@ package backbone
 
 import (
 	"context"
+	"net/http"
+
+	"configcenter/src/common/util"
 )
 
 type ccContext struct {
@@ -34,3 +37,13 @@ func newCCContext() CCContextInterface {
 func (c *ccContext) WithCancel() (context.Context, context.CancelFunc) {
 	return context.WithCancel(c.ctx)
 }
+
+// NewContextWithHeader derive the context from the timeout header set by the caller, the parent should be
+// the context of the http request, so that the handler stops working on the request once the caller disconnects.
+func NewContextWithHeader(parent context.Context, header http.Header) (context.Context, context.CancelFunc) {
+	timeout, ok := util.GetHTTPCCRequestTimeout(header)
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
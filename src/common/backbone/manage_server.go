@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backbone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"configcenter/src/common/backbone/service_mange/etcd"
+	"configcenter/src/common/backbone/service_mange/file"
+	"configcenter/src/common/backbone/service_mange/zk"
+	"configcenter/src/common/blog"
+	crd "configcenter/src/common/confregdiscover"
+	"configcenter/src/common/registerdiscover"
+)
+
+const (
+	// the scheme of the regdiscv address which chooses the service manage backend,
+	// the address without scheme is the zookeeper addresses.
+	zkScheme   = "zk://"
+	etcdScheme = "etcd://"
+	fileScheme = "file://"
+)
+
+// manageSrvClient is the service manage backend, the services are registered into and discovered from it,
+// and the configs are read from it.
+type manageSrvClient struct {
+	regDiscv     registerdiscover.RegDiscvServer
+	confRegDiscv crd.ConfRegDiscvIf
+}
+
+// newManageSrvClient connect the service manage backend, it is chosen with the scheme of the address:
+//   - 127.0.0.1:2181,127.0.0.2:2181 or zk://127.0.0.1:2181 for zookeeper
+//   - etcd://127.0.0.1:2379,https://127.0.0.2:2379 for etcd or the etcd compatible server with the v3 json gateway
+//   - file:///data/cc/registry.json for the static registry file, which is hot reloaded
+func newManageSrvClient(ctx context.Context, manageSrvAddr string) (*manageSrvClient, error) {
+	switch {
+	case strings.HasPrefix(manageSrvAddr, etcdScheme):
+		client := etcd.NewEtcdClient(strings.TrimPrefix(manageSrvAddr, etcdScheme), 10*time.Second)
+		if err := client.Start(); err != nil {
+			return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
+		}
+		// deregister the service at once when the server exits
+		go func() {
+			<-ctx.Done()
+			if err := client.Stop(); err != nil {
+				blog.Errorf("stop etcd client failed, err: %v", err)
+			}
+		}()
+		return &manageSrvClient{
+			regDiscv:     registerdiscover.NewEtcdRegDiscv(client),
+			confRegDiscv: crd.NewEtcdRegDiscover(client),
+		}, nil
+
+	case strings.HasPrefix(manageSrvAddr, fileScheme):
+		client := file.NewFileClient(strings.TrimPrefix(manageSrvAddr, fileScheme), 5*time.Second)
+		if err := client.Start(); err != nil {
+			return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
+		}
+		return &manageSrvClient{
+			regDiscv:     registerdiscover.NewFileRegDiscv(client),
+			confRegDiscv: crd.NewFileRegDiscover(client),
+		}, nil
+
+	default:
+		client := zk.NewZkClient(strings.TrimPrefix(manageSrvAddr, zkScheme), 5*time.Second)
+		if err := client.Start(); err != nil {
+			return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
+		}
+		if err := client.Ping(); err != nil {
+			return nil, fmt.Errorf("connect regdiscv [%s] failed: %v", manageSrvAddr, err)
+		}
+		return &manageSrvClient{
+			regDiscv:     registerdiscover.NewZkRegDiscv(client),
+			confRegDiscv: crd.NewZkRegDiscover(client),
+		}, nil
+	}
+}
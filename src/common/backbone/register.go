@@ -15,7 +15,6 @@ package backbone
 import (
 	"github.com/gin-gonic/gin/json"
 
-	"configcenter/src/common/backbone/service_mange/zk"
 	"configcenter/src/common/registerdiscover"
 	"configcenter/src/common/types"
 	"configcenter/src/framework/core/errors"
@@ -28,9 +27,9 @@ type ServiceDiscoverInterface interface {
 	Register(path string, c types.ServerInfo) error
 }
 
-func NewServcieDiscovery(client *zk.ZkClient) (ServiceDiscoverInterface, error) {
+func NewServcieDiscovery(rdServer registerdiscover.RegDiscvServer) (ServiceDiscoverInterface, error) {
 	s := new(serviceDiscovery)
-	s.client = registerdiscover.NewRegDiscoverEx(client)
+	s.client = registerdiscover.NewRegDiscoverWithServer(rdServer)
 	return s, nil
 }
 
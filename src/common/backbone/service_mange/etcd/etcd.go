@@ -0,0 +1,508 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound the key is not exist in etcd
+var ErrKeyNotFound = errors.New("etcd: key not found")
+
+// EtcdClient do register and discover by etcd, it talks to the etcd (or any etcd compatible)
+// cluster with the json gateway of the v3 api, so that no grpc client is needed
+type EtcdClient struct {
+	sync.Mutex
+	endpoints []string
+	index     int
+	// client is used by the unary calls, which are limited by the session time out.
+	// watchClient is used by the watch, which is only stopped by the context.
+	client         *http.Client
+	watchClient    *http.Client
+	cancel         context.CancelFunc
+	rootCxt        context.Context
+	sessionTimeOut time.Duration
+	// leases the granted leases, they are revoked when the client stops
+	leases  map[int64]struct{}
+	stopped bool
+}
+
+// KeyValue is the key value pair stored in etcd
+type KeyValue struct {
+	Key            string
+	Value          []byte
+	CreateRevision int64
+	ModRevision    int64
+}
+
+// NewEtcdClient create a object of EtcdClient, serv is the endpoints separated by comma,
+// the endpoint without scheme uses http.
+func NewEtcdClient(serv string, timeOut time.Duration) *EtcdClient {
+	endpoints := make([]string, 0)
+	for _, ep := range strings.Split(serv, ",") {
+		ep = strings.TrimRight(strings.TrimSpace(ep), "/")
+		if len(ep) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(ep, "http://") && !strings.HasPrefix(ep, "https://") {
+			ep = "http://" + ep
+		}
+		endpoints = append(endpoints, ep)
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   timeOut,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: timeOut,
+		MaxIdleConnsPerHost: 10,
+	}
+	return &EtcdClient{
+		endpoints:      endpoints,
+		client:         &http.Client{Transport: transport, Timeout: timeOut},
+		watchClient:    &http.Client{Transport: transport},
+		sessionTimeOut: timeOut,
+		leases:         make(map[int64]struct{}),
+	}
+}
+
+// Ping to ping server
+func (c *EtcdClient) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.sessionTimeOut)
+	defer cancel()
+	return c.post(ctx, "/v3/maintenance/status", struct{}{}, nil)
+}
+
+// Start used to run register and discover server
+func (c *EtcdClient) Start() error {
+	if len(c.endpoints) == 0 {
+		return errors.New("no etcd endpoint is configured")
+	}
+
+	// create root context
+	c.rootCxt, c.cancel = context.WithCancel(context.Background())
+
+	if err := c.Ping(); err != nil {
+		return fmt.Errorf("fail to connect etcd. err:%s", err.Error())
+	}
+	return nil
+}
+
+// Stop used to stop register and discover server, the loops with the contexts of the client are canceled,
+// and the granted leases are revoked, so that the registered nodes are removed at once.
+func (c *EtcdClient) Stop() error {
+	c.Lock()
+	if c.stopped {
+		c.Unlock()
+		return nil
+	}
+	c.stopped = true
+	leases := make([]int64, 0, len(c.leases))
+	for lease := range c.leases {
+		leases = append(leases, lease)
+	}
+	c.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	var lastErr error
+	for _, lease := range leases {
+		ctx, cancel := context.WithTimeout(context.Background(), c.sessionTimeOut)
+		if err := c.Revoke(ctx, lease); err != nil {
+			lastErr = fmt.Errorf("revoke lease %d failed, err: %v", lease, err)
+		}
+		cancel()
+	}
+	return lastErr
+}
+
+// SessionTimeOut client session time out, it is used as the ttl of the lease of the registered service
+func (c *EtcdClient) SessionTimeOut() time.Duration {
+	return c.sessionTimeOut
+}
+
+// WithCancel context with cancel
+func (c *EtcdClient) WithCancel() (context.Context, context.CancelFunc) {
+	return context.WithCancel(c.rootCxt)
+}
+
+// Get the key value of key
+func (c *EtcdClient) Get(ctx context.Context, key string) (*KeyValue, error) {
+	req := rangeRequest{Key: encode(key)}
+	resp := new(rangeResponse)
+	if err := c.post(ctx, "/v3/kv/range", req, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	return resp.Kvs[0].keyValue()
+}
+
+// List the key values which key has the prefix, ordered by the create revision.
+// the revision of the store is returned too, so that the changes after it can be watched.
+func (c *EtcdClient) List(ctx context.Context, prefix string) ([]KeyValue, int64, error) {
+	req := rangeRequest{
+		Key:        encode(prefix),
+		RangeEnd:   encode(prefixEnd(prefix)),
+		SortOrder:  "ASCEND",
+		SortTarget: "CREATE",
+	}
+	resp := new(rangeResponse)
+	if err := c.post(ctx, "/v3/kv/range", req, resp); err != nil {
+		return nil, 0, err
+	}
+
+	kvs := make([]KeyValue, 0, len(resp.Kvs))
+	for _, item := range resp.Kvs {
+		kv, err := item.keyValue()
+		if err != nil {
+			return nil, 0, err
+		}
+		kvs = append(kvs, *kv)
+	}
+	// keep the order even if the server ignores the sort options
+	sort.SliceStable(kvs, func(i, j int) bool { return kvs[i].CreateRevision < kvs[j].CreateRevision })
+
+	return kvs, int64(resp.Header.Revision), nil
+}
+
+// Put save the value of key, the key is removed with the lease if lease is not 0
+func (c *EtcdClient) Put(ctx context.Context, key string, value []byte, lease int64) error {
+	req := putRequest{
+		Key:   encode(key),
+		Value: base64.StdEncoding.EncodeToString(value),
+		Lease: jsonInt64(lease),
+	}
+	return c.post(ctx, "/v3/kv/put", req, nil)
+}
+
+// Delete remove the key
+func (c *EtcdClient) Delete(ctx context.Context, key string) error {
+	return c.post(ctx, "/v3/kv/deleterange", rangeRequest{Key: encode(key)}, nil)
+}
+
+// Grant create a lease with the ttl
+func (c *EtcdClient) Grant(ctx context.Context, ttl time.Duration) (int64, error) {
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	resp := new(leaseResponse)
+	if err := c.post(ctx, "/v3/lease/grant", leaseRequest{TTL: jsonInt64(seconds)}, resp); err != nil {
+		return 0, err
+	}
+	if resp.Error != "" {
+		return 0, fmt.Errorf("grant lease failed, err: %s", resp.Error)
+	}
+
+	lease := int64(resp.ID)
+	c.Lock()
+	stopped := c.stopped
+	if !stopped {
+		c.leases[lease] = struct{}{}
+	}
+	c.Unlock()
+	// the client is stopped while granting, the lease is not kept
+	if stopped {
+		revokeCtx, cancel := context.WithTimeout(context.Background(), c.sessionTimeOut)
+		defer cancel()
+		c.Revoke(revokeCtx, lease)
+		return 0, errors.New("etcd client is stopped")
+	}
+
+	return lease, nil
+}
+
+// Revoke the lease, the keys attached to the lease are removed
+func (c *EtcdClient) Revoke(ctx context.Context, lease int64) error {
+	c.Lock()
+	delete(c.leases, lease)
+	c.Unlock()
+
+	return c.post(ctx, "/v3/lease/revoke", leaseRequest{ID: jsonInt64(lease)}, nil)
+}
+
+// KeepAlive refresh the lease once, the lease is expired if the returned ttl is not positive
+func (c *EtcdClient) KeepAlive(ctx context.Context, lease int64) (int64, error) {
+	resp := new(streamResponse)
+	if err := c.post(ctx, "/v3/lease/keepalive", leaseRequest{ID: jsonInt64(lease)}, resp); err != nil {
+		return 0, err
+	}
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+
+	return int64(resp.Result.TTL), nil
+}
+
+// Watch block until the key (or the keys with the prefix if prefix is true) changed after the revision,
+// or the context is done.
+func (c *EtcdClient) Watch(ctx context.Context, key string, prefix bool, revision int64) error {
+	create := watchCreateRequest{
+		Key:           encode(key),
+		StartRevision: jsonInt64(revision + 1),
+	}
+	if prefix {
+		create.RangeEnd = encode(prefixEnd(key))
+	}
+
+	body, err := c.do(ctx, c.watchClient, "/v3/watch", watchRequest{CreateRequest: create})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	for {
+		resp := new(streamResponse)
+		if err := decoder.Decode(resp); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("watch %s failed, err: %v", key, err)
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+
+		// the revision has been compacted, the caller should list again.
+		if len(resp.Result.Events) != 0 || resp.Result.CompactRevision != 0 {
+			return nil
+		}
+		if resp.Result.Canceled {
+			return fmt.Errorf("watch %s is canceled by server, reason: %s", key, resp.Result.CancelReason)
+		}
+	}
+}
+
+// post do the unary call, it is limited by the session time out even if the context has no deadline
+func (c *EtcdClient) post(ctx context.Context, path string, req interface{}, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.sessionTimeOut)
+	defer cancel()
+
+	body, err := c.do(ctx, c.client, path, req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if resp == nil {
+		return nil
+	}
+	if err := json.NewDecoder(body).Decode(resp); err != nil {
+		return fmt.Errorf("decode the response of %s failed, err: %v", path, err)
+	}
+	return nil
+}
+
+// do try the endpoints one by one from the last available one, the response body is returned on success
+func (c *EtcdClient) do(ctx context.Context, client *http.Client, path string, req interface{}) (io.ReadCloser, error) {
+	js, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	index := c.index
+	c.Unlock()
+
+	var lastErr error
+	for i := 0; i < len(c.endpoints); i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		current := (index + i) % len(c.endpoints)
+		httpReq, err := http.NewRequest(http.MethodPost, c.endpoints[current]+path, bytes.NewReader(js))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(httpReq.WithContext(ctx))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("%s returns status %d, %s", c.endpoints[current]+path, resp.StatusCode, strings.TrimSpace(string(msg)))
+			// the request reached the server, there is no need to try the others.
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil, lastErr
+			}
+			continue
+		}
+
+		c.Lock()
+		c.index = current
+		c.Unlock()
+		return resp.Body, nil
+	}
+
+	return nil, lastErr
+}
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// prefixEnd return the range end to get all the keys with the prefix
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i] = end[i] + 1
+			return string(end[:i+1])
+		}
+	}
+	// all the keys
+	return "\x00"
+}
+
+// jsonInt64 is the int64 of the json gateway, which is encoded as string
+type jsonInt64 int64
+
+func (i jsonInt64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+func (i *jsonInt64) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = jsonInt64(v)
+	return nil
+}
+
+type responseHeader struct {
+	Revision jsonInt64 `json:"revision"`
+}
+
+type rangeRequest struct {
+	Key        string `json:"key"`
+	RangeEnd   string `json:"range_end,omitempty"`
+	SortOrder  string `json:"sort_order,omitempty"`
+	SortTarget string `json:"sort_target,omitempty"`
+}
+
+type rangeResponse struct {
+	Header responseHeader `json:"header"`
+	Kvs    []kv           `json:"kvs"`
+}
+
+type kv struct {
+	Key            string    `json:"key"`
+	Value          string    `json:"value"`
+	CreateRevision jsonInt64 `json:"create_revision"`
+	ModRevision    jsonInt64 `json:"mod_revision"`
+}
+
+func (k kv) keyValue() (*KeyValue, error) {
+	key, err := base64.StdEncoding.DecodeString(k.Key)
+	if err != nil {
+		return nil, fmt.Errorf("decode key %s failed, err: %v", k.Key, err)
+	}
+	value, err := base64.StdEncoding.DecodeString(k.Value)
+	if err != nil {
+		return nil, fmt.Errorf("decode the value of key %s failed, err: %v", string(key), err)
+	}
+
+	return &KeyValue{
+		Key:            string(key),
+		Value:          value,
+		CreateRevision: int64(k.CreateRevision),
+		ModRevision:    int64(k.ModRevision),
+	}, nil
+}
+
+type putRequest struct {
+	Key   string    `json:"key"`
+	Value string    `json:"value"`
+	Lease jsonInt64 `json:"lease,omitempty"`
+}
+
+type leaseRequest struct {
+	ID  jsonInt64 `json:"ID,omitempty"`
+	TTL jsonInt64 `json:"TTL,omitempty"`
+}
+
+type leaseResponse struct {
+	ID    jsonInt64 `json:"ID"`
+	TTL   jsonInt64 `json:"TTL"`
+	Error string    `json:"error"`
+}
+
+type watchCreateRequest struct {
+	Key           string    `json:"key"`
+	RangeEnd      string    `json:"range_end,omitempty"`
+	StartRevision jsonInt64 `json:"start_revision,omitempty"`
+}
+
+type watchRequest struct {
+	CreateRequest watchCreateRequest `json:"create_request"`
+}
+
+// streamResponse is the message of the streaming apis, such as watch and keepalive
+type streamResponse struct {
+	Result struct {
+		TTL             jsonInt64    `json:"TTL"`
+		Canceled        bool         `json:"canceled"`
+		CancelReason    string       `json:"cancel_reason"`
+		CompactRevision jsonInt64    `json:"compact_revision"`
+		Events          []watchEvent `json:"events"`
+	} `json:"result"`
+	Error *streamError `json:"error"`
+}
+
+type watchEvent struct {
+	Type string `json:"type"`
+	Kv   kv     `json:"kv"`
+}
+
+type streamError struct {
+	Code    int    `json:"grpc_code"`
+	Message string `json:"message"`
+}
+
+func (e *streamError) Error() string {
+	return fmt.Sprintf("etcd error, code: %d, message: %s", e.Code, e.Message)
+}
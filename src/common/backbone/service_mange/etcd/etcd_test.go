@@ -0,0 +1,197 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGateway is a minimal etcd v3 json gateway which keeps the key values in memory
+type fakeGateway struct {
+	kvs      map[string]kv
+	revision int64
+	changed  chan struct{}
+	leases   int64
+	revoked  []string
+}
+
+func (g *fakeGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := make(map[string]json.RawMessage)
+	json.NewDecoder(r.Body).Decode(&body)
+	str := func(raw json.RawMessage) string {
+		var s string
+		json.Unmarshal(raw, &s)
+		return s
+	}
+
+	switch r.URL.Path {
+	case "/v3/maintenance/status":
+		w.Write([]byte(`{"version": "3.3.0"}`))
+	case "/v3/kv/put":
+		g.revision++
+		key := str(body["key"])
+		item := kv{Key: key, Value: str(body["value"]), ModRevision: jsonInt64(g.revision), CreateRevision: jsonInt64(g.revision)}
+		if old, ok := g.kvs[key]; ok {
+			item.CreateRevision = old.CreateRevision
+		}
+		g.kvs[key] = item
+		close(g.changed)
+		g.changed = make(chan struct{})
+		w.Write([]byte(`{}`))
+	case "/v3/kv/range":
+		key, _ := base64.StdEncoding.DecodeString(str(body["key"]))
+		end, _ := base64.StdEncoding.DecodeString(str(body["range_end"]))
+		resp := rangeResponse{Header: responseHeader{Revision: jsonInt64(g.revision)}}
+		for _, item := range g.kvs {
+			k, _ := base64.StdEncoding.DecodeString(item.Key)
+			if string(k) == string(key) || (len(end) != 0 && string(k) >= string(key) && string(k) < string(end)) {
+				resp.Kvs = append(resp.Kvs, item)
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	case "/v3/lease/grant":
+		g.leases++
+		json.NewEncoder(w).Encode(leaseResponse{ID: jsonInt64(g.leases), TTL: jsonInt64(10)})
+	case "/v3/lease/revoke":
+		g.revoked = append(g.revoked, str(body["ID"]))
+		w.Write([]byte(`{}`))
+	case "/v3/watch":
+		changed := g.changed
+		w.Write([]byte(`{"result": {"created": true}}` + "\n"))
+		w.(http.Flusher).Flush()
+		<-changed
+		w.Write([]byte(`{"result": {"events": [{"type": "PUT"}]}}` + "\n"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestEtcdClient(t *testing.T) {
+	gateway := &fakeGateway{kvs: make(map[string]kv), changed: make(chan struct{})}
+	// the handler is not safe for concurrency, it is enough for the sequential requests of the test
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	// the first endpoint is unavailable, the client should fall back to the second one
+	client := NewEtcdClient("127.0.0.1:1,"+strings.TrimPrefix(server.URL, "http://"), time.Second)
+	if err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Stop()
+
+	ctx, cancel := client.WithCancel()
+	defer cancel()
+	if err := client.Put(ctx, "/cc/services/endpoints/apiserver/127.0.0.2", []byte("b"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Put(ctx, "/cc/services/endpoints/apiserver/127.0.0.1", []byte("a"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Put(ctx, "/cc/services/endpoints/apiserverx", []byte("c"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	kv, err := client.Get(ctx, "/cc/services/endpoints/apiserverx")
+	if err != nil || string(kv.Value) != "c" {
+		t.Fatalf("get key, got %v, err: %v", kv, err)
+	}
+	if _, err := client.Get(ctx, "/not/exist"); err != ErrKeyNotFound {
+		t.Fatalf("get not exist key, got err: %v", err)
+	}
+
+	kvs, revision, err := client.List(ctx, "/cc/services/endpoints/apiserver/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision != 3 || len(kvs) != 2 || string(kvs[0].Value) != "b" || string(kvs[1].Value) != "a" {
+		t.Fatalf("list keys ordered by create revision, got %v at revision %d", kvs, revision)
+	}
+
+	watched := make(chan error, 1)
+	go func() {
+		watched <- client.Watch(ctx, "/cc/services/endpoints/apiserver/", true, revision)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-watched:
+		t.Fatalf("watch returned before any change, err: %v", err)
+	default:
+	}
+
+	if err := client.Put(ctx, "/cc/services/endpoints/apiserver/127.0.0.3", []byte("d"), 0); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-watched:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch does not return after the change")
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	if end := prefixEnd("/cc/"); end != "/cc0" {
+		t.Fatalf("unexpected prefix end %q", end)
+	}
+	if end := prefixEnd("a\xff"); end != "b" {
+		t.Fatalf("unexpected prefix end %q", end)
+	}
+}
+
+func TestEtcdClientStop(t *testing.T) {
+	gateway := &fakeGateway{kvs: make(map[string]kv), changed: make(chan struct{})}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	client := NewEtcdClient(server.URL, time.Second)
+	if err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := client.WithCancel()
+	defer cancel()
+
+	first, err := client.Grant(ctx, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Grant(ctx, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Revoke(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("the context of the client is not canceled after stop")
+	}
+	if len(gateway.revoked) != 2 || gateway.revoked[1] != strconv.FormatInt(second, 10) {
+		t.Errorf("the lease granted is not revoked after stop, revoked: %v", gateway.revoked)
+	}
+	if _, err := client.Grant(context.Background(), 10*time.Second); err == nil {
+		t.Errorf("grant lease after stop should be failed")
+	}
+}
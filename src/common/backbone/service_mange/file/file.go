@@ -0,0 +1,301 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+)
+
+// ErrKeyNotFound the key is not exist in the registry file
+var ErrKeyNotFound = errors.New("file registry: key not found")
+
+// FileClient is a static registry stored in a json file, which is an object of the
+// key (the path as the zookeeper node) and its value, such as:
+//
+//	{
+//	  "/cc/services/endpoints/apiserver/127.0.0.1": {"ip": "127.0.0.1", "port": 8080, "scheme": "http"},
+//	  "/cc/services/config/apiserver": "[api-server]\nport=8080"
+//	}
+//
+// the file is reloaded when it is modified, so that the services and the configs can be changed
+// without restart. the services registered by this process are kept in memory only.
+type FileClient struct {
+	sync.RWMutex
+	path     string
+	interval time.Duration
+	modTime  time.Time
+	size     int64
+	loadErr  error
+	data     map[string][]byte
+	local    map[string][]byte
+	changed  chan struct{}
+	cancel   context.CancelFunc
+	rootCxt  context.Context
+}
+
+// KeyValue is the key value pair stored in the registry file
+type KeyValue struct {
+	Key   string
+	Value []byte
+}
+
+// NewFileClient create a object of FileClient, the file is checked with the interval to reload
+func NewFileClient(path string, interval time.Duration) *FileClient {
+	return &FileClient{
+		path:     path,
+		interval: interval,
+		data:     make(map[string][]byte),
+		local:    make(map[string][]byte),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Ping to check the registry file is available
+func (c *FileClient) Ping() error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.loadErr
+}
+
+// Start load the registry file and reload it when it is modified
+func (c *FileClient) Start() error {
+	if err := c.reload(); err != nil {
+		return fmt.Errorf("load registry file %s failed, err: %v", c.path, err)
+	}
+
+	c.rootCxt, c.cancel = context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.rootCxt.Done():
+				return
+			case <-ticker.C:
+				if err := c.reload(); err != nil {
+					blog.Errorf("reload registry file %s failed, keep the previous content, err: %v", c.path, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stop reloading the registry file
+func (c *FileClient) Stop() error {
+	c.cancel()
+	return nil
+}
+
+// WithCancel context with cancel
+func (c *FileClient) WithCancel() (context.Context, context.CancelFunc) {
+	return context.WithCancel(c.rootCxt)
+}
+
+// Changed return a channel which is closed when the content of the registry changed next time
+func (c *FileClient) Changed() <-chan struct{} {
+	c.RLock()
+	defer c.RUnlock()
+	return c.changed
+}
+
+// Get the value of the key
+func (c *FileClient) Get(key string) ([]byte, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if value, ok := c.local[key]; ok {
+		return value, nil
+	}
+	if value, ok := c.data[key]; ok {
+		return value, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Children return the key values which are the direct children of the path, ordered by the key
+func (c *FileClient) Children(path string) []KeyValue {
+	c.RLock()
+	defer c.RUnlock()
+
+	prefix := strings.TrimRight(path, "/") + "/"
+	children := make(map[string][]byte)
+	for _, m := range []map[string][]byte{c.data, c.local} {
+		for key, value := range m {
+			if !strings.HasPrefix(key, prefix) || strings.Contains(key[len(prefix):], "/") || len(key) == len(prefix) {
+				continue
+			}
+			children[key] = value
+		}
+	}
+
+	kvs := make([]KeyValue, 0, len(children))
+	for key, value := range children {
+		kvs = append(kvs, KeyValue{Key: key, Value: value})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+// Register save the key value in memory, it is not written into the registry file
+func (c *FileClient) Register(key string, value []byte) {
+	c.Lock()
+	defer c.Unlock()
+	c.local[key] = value
+	c.notify()
+}
+
+// Put save the value of the key into the registry file
+func (c *FileClient) Put(key string, value []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	data, err := c.read()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if data == nil {
+		data = make(map[string][]byte)
+	}
+	data[key] = value
+
+	content := make(map[string]json.RawMessage)
+	for k, v := range data {
+		content[k] = encode(v)
+	}
+	js, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it, so that the readers never see a partial file
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(js); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+
+	c.data = data
+	if info, err := os.Stat(c.path); err == nil {
+		c.modTime, c.size = info.ModTime(), info.Size()
+	}
+	c.notify()
+	return nil
+}
+
+// reload read the registry file if it is modified since last load
+func (c *FileClient) reload() error {
+	info, err := os.Stat(c.path)
+	if err != nil && !os.IsNotExist(err) {
+		c.setLoadErr(err)
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if os.IsNotExist(err) {
+		// the file is created by the first write
+		c.loadErr = nil
+		return nil
+	}
+	if info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return nil
+	}
+
+	data, err := c.read()
+	c.loadErr = err
+	if err != nil {
+		return err
+	}
+
+	c.data = data
+	c.modTime, c.size = info.ModTime(), info.Size()
+	c.notify()
+	blog.V(3).Infof("registry file %s reloaded, %d keys", c.path, len(data))
+	return nil
+}
+
+func (c *FileClient) setLoadErr(err error) {
+	c.Lock()
+	defer c.Unlock()
+	c.loadErr = err
+}
+
+func (c *FileClient) read() (map[string][]byte, error) {
+	js, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte)
+	if len(bytes.TrimSpace(js)) == 0 {
+		return data, nil
+	}
+
+	content := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(js, &content); err != nil {
+		return nil, err
+	}
+	for key, raw := range content {
+		data[key] = decode(raw)
+	}
+	return data, nil
+}
+
+// notify wake up the watchers, it should be called with the lock held
+func (c *FileClient) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// decode return the string value itself, or the json value of the other types
+func decode(raw json.RawMessage) []byte {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s)
+	}
+	return []byte(raw)
+}
+
+// encode keep the json object and array as it is, so that the file is readable, the others are saved as string
+func encode(value []byte) json.RawMessage {
+	trimed := bytes.TrimSpace(value)
+	if len(trimed) != 0 && (trimed[0] == '{' || trimed[0] == '[') && json.Valid(trimed) {
+		return json.RawMessage(trimed)
+	}
+	js, _ := json.Marshal(string(value))
+	return json.RawMessage(js)
+}
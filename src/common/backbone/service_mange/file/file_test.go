@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "registry.json")
+	content := `{
+  "/cc/services/endpoints/apiserver/127.0.0.1": {"ip": "127.0.0.1", "port": 8080},
+  "/cc/services/endpoints/apiserver/127.0.0.2": {"ip": "127.0.0.2", "port": 8080},
+  "/cc/services/endpoints/apiserver/sub/127.0.0.3": {"ip": "127.0.0.3", "port": 8080},
+  "/cc/services/config/apiserver": "[api-server]\nport=8080"
+}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewFileClient(path, 10*time.Millisecond)
+	if err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Stop()
+
+	conf, err := client.Get("/cc/services/config/apiserver")
+	if err != nil || string(conf) != "[api-server]\nport=8080" {
+		t.Fatalf("get config, got %q, err: %v", conf, err)
+	}
+	if _, err := client.Get("/cc/services/config/hostserver"); err != ErrKeyNotFound {
+		t.Fatalf("get not exist key, got err: %v", err)
+	}
+
+	client.Register("/cc/services/endpoints/apiserver/127.0.0.4", []byte(`{"ip": "127.0.0.4", "port": 8080}`))
+	children := client.Children("/cc/services/endpoints/apiserver")
+	if len(children) != 3 || children[0].Key != "/cc/services/endpoints/apiserver/127.0.0.1" ||
+		children[2].Key != "/cc/services/endpoints/apiserver/127.0.0.4" {
+		t.Fatalf("unexpected children: %v", children)
+	}
+
+	// modify the file and wait for the reload
+	changed := client.Changed()
+	content = `{"/cc/services/endpoints/apiserver/127.0.0.5": {"ip": "127.0.0.5", "port": 8080}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("the registry file is not reloaded")
+	}
+	children = client.Children("/cc/services/endpoints/apiserver")
+	if len(children) != 2 || children[0].Key != "/cc/services/endpoints/apiserver/127.0.0.4" ||
+		children[1].Key != "/cc/services/endpoints/apiserver/127.0.0.5" {
+		t.Fatalf("unexpected children after reload: %v", children)
+	}
+
+	// the written value is persisted and can be read by a new client
+	if err := client.Put("/cc/services/errors", []byte(`{"en": {"1199000": "error"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	another := NewFileClient(path, time.Second)
+	if err := another.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer another.Stop()
+	errs, err := another.Get("/cc/services/errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the json value is kept as json in the file, so it may be indented
+	compact := new(bytes.Buffer)
+	if err := json.Compact(compact, errs); err != nil || compact.String() != `{"en":{"1199000":"error"}}` {
+		t.Fatalf("get written value, got %q, err: %v", errs, err)
+	}
+	if _, err := another.Get("/cc/services/endpoints/apiserver/127.0.0.4"); err != ErrKeyNotFound {
+		t.Fatalf("the registered service should not be persisted, err: %v", err)
+	}
+}
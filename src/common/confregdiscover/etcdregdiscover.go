@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confregdiscover
+
+import (
+	"context"
+	"time"
+
+	"configcenter/src/common/backbone/service_mange/etcd"
+	"configcenter/src/common/blog"
+)
+
+// EtcdRegDiscover config register and discover by etcd
+type EtcdRegDiscover struct {
+	client  *etcd.EtcdClient
+	cancel  context.CancelFunc
+	rootCtx context.Context
+}
+
+// NewEtcdRegDiscover create a object of EtcdRegDiscover
+func NewEtcdRegDiscover(client *etcd.EtcdClient) *EtcdRegDiscover {
+	ctx, ctxCancel := client.WithCancel()
+	return &EtcdRegDiscover{
+		client:  client,
+		rootCtx: ctx,
+		cancel:  ctxCancel,
+	}
+}
+
+// Ping to ping server
+func (etcdRD *EtcdRegDiscover) Ping() error {
+	return etcdRD.client.Ping()
+}
+
+// Write to save config data into etcd
+func (etcdRD *EtcdRegDiscover) Write(path string, data []byte) error {
+	return etcdRD.client.Put(etcdRD.rootCtx, path, data, 0)
+}
+
+func (etcdRD *EtcdRegDiscover) Read(path string) (string, error) {
+	kv, err := etcdRD.client.Get(etcdRD.rootCtx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(kv.Value), nil
+}
+
+func (etcdRD *EtcdRegDiscover) Discover(key string) (<-chan *DiscoverEvent, error) {
+
+	env := make(chan *DiscoverEvent, 1)
+
+	go etcdRD.loopDiscover(etcdRD.rootCtx, key, env)
+
+	return env, nil
+}
+
+func (etcdRD *EtcdRegDiscover) loopDiscover(discvCtx context.Context, path string, env chan *DiscoverEvent) {
+	for {
+		discvEnv := &DiscoverEvent{
+			Err: nil,
+			Key: path,
+		}
+
+		kv, err := etcdRD.client.Get(discvCtx, path)
+		if err != nil {
+			if discvCtx.Err() != nil {
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+			blog.Errorf("fail to get context for path(%s), will watch after 5s, err: %v", path, err)
+			if err != etcd.ErrKeyNotFound {
+				discvEnv.Err = err
+				env <- discvEnv
+			}
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		discvEnv.Data = kv.Value
+
+		// write into discoverEvent channel
+		env <- discvEnv
+
+		if err := etcdRD.client.Watch(discvCtx, path, false, kv.ModRevision); err != nil {
+			if discvCtx.Err() != nil {
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+			blog.Errorf("fail to watch context for path(%s), will watch after 5s, err: %v", path, err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		blog.V(5).Infof("watch found the content of path(%s) changed", path)
+	}
+}
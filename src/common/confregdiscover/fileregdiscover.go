@@ -0,0 +1,93 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confregdiscover
+
+import (
+	"bytes"
+	"context"
+
+	"configcenter/src/common/backbone/service_mange/file"
+	"configcenter/src/common/blog"
+)
+
+// FileRegDiscover config register and discover by a static registry file
+type FileRegDiscover struct {
+	client  *file.FileClient
+	cancel  context.CancelFunc
+	rootCtx context.Context
+}
+
+// NewFileRegDiscover create a object of FileRegDiscover
+func NewFileRegDiscover(client *file.FileClient) *FileRegDiscover {
+	ctx, ctxCancel := client.WithCancel()
+	return &FileRegDiscover{
+		client:  client,
+		rootCtx: ctx,
+		cancel:  ctxCancel,
+	}
+}
+
+// Ping to ping server
+func (fileRD *FileRegDiscover) Ping() error {
+	return fileRD.client.Ping()
+}
+
+// Write to save config data into the registry file
+func (fileRD *FileRegDiscover) Write(path string, data []byte) error {
+	return fileRD.client.Put(path, data)
+}
+
+func (fileRD *FileRegDiscover) Read(path string) (string, error) {
+	data, err := fileRD.client.Get(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (fileRD *FileRegDiscover) Discover(key string) (<-chan *DiscoverEvent, error) {
+
+	env := make(chan *DiscoverEvent, 1)
+
+	go fileRD.loopDiscover(fileRD.rootCtx, key, env)
+
+	return env, nil
+}
+
+func (fileRD *FileRegDiscover) loopDiscover(discvCtx context.Context, path string, env chan *DiscoverEvent) {
+	var previous []byte
+	for {
+		// get the channel before read, so that no change is missed
+		changed := fileRD.client.Changed()
+
+		// the other keys in the file may be changed, only the changes of the path are sent
+		data, err := fileRD.client.Get(path)
+		if err == nil && (previous == nil || !bytes.Equal(previous, data)) {
+			select {
+			case env <- &DiscoverEvent{Key: path, Data: data}:
+				previous = data
+			case <-discvCtx.Done():
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+		}
+
+		select {
+		case <-discvCtx.Done():
+			blog.Infof("discover path(%s) done", path)
+			return
+		case <-changed:
+			blog.V(5).Infof("registry file changed, check the content of path(%s)", path)
+		}
+	}
+}
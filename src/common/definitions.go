@@ -425,6 +425,9 @@ const (
 	// BKOpTargetField the op target field
 	BKOpTargetField = "op_target"
 
+	// BKOpTargetAPIToken the op target of the personal api token operation logs
+	BKOpTargetAPIToken = "api_token"
+
 	// BKOpTimeField the op time field
 	BKOpTimeField = "op_time"
 
@@ -798,6 +801,8 @@ const (
 	EventCacheEventTxnQueuePrefix = BKCacheKeyV3Prefix + "event:inst_txn_queue:"
 	EventCacheEventTxnSet         = BKCacheKeyV3Prefix + "event:txn_set"
 	RedisSnapKeyPrefix            = BKCacheKeyV3Prefix + "snapshot:"
+	// EventCacheModelChannel the channel which the event server publishes the model events to
+	EventCacheModelChannel = BKCacheKeyV3Prefix + "event:model_channel"
 )
 
 const (
@@ -832,6 +837,16 @@ const (
 	BKHTTPOtherRequestID  = "X-Bkapi-Request-Id"
 	BKHTTPCCRequestTime   = "Cc_Request_Time"
 	BKHTTPCCTransactionID = "Cc_Txn_Id"
+	// BKHTTPCCRequestTimeout the milliseconds left before the caller gives up the request
+	BKHTTPCCRequestTimeout = "Cc_Request_Timeout"
+	// BKHTTPIdempotencyKey the key which identify a logical write, the retries of the write carry the same key
+	BKHTTPIdempotencyKey = "Idempotency-Key"
+	// BKHTTPIdempotentReplayed the response is replayed from the recorded response of the idempotency key
+	BKHTTPIdempotentReplayed = "Idempotent-Replayed"
+	// BKHTTPAPITokenID the id of the personal api token which authenticate the request
+	BKHTTPAPITokenID = "Bk_Api_Token_Id"
+	// BKHTTPAppCode the app code of the caller, which is set by the esb
+	BKHTTPAppCode = "Bk_App_Code"
 )
 
 type CCContextKey string
@@ -917,6 +932,8 @@ const (
 
 const (
 	BKDefaultLoginUserPluginVersion = "self"
+	BKOIDCLoginUserPluginVersion    = "oidc"
+	BKLDAPLoginUserPluginVersion    = "ldap"
 	HTTPCookieBKToken               = "bk_token"
 
 	WEBSessionUinKey           = "username"
@@ -930,6 +947,7 @@ const (
 	WEBSessionMultiSupplierKey = "multisupplier"
 	WEBSessionLanguageKey      = "language"
 	WEBSessionSupplierID       = "supplier_id"
+	WEBSessionLoginRedirectKey = "login_redirect_url"
 
 	LoginSystemMultiSupplierTrue  = "1"
 	LoginSystemMultiSupplierFalse = "0"
@@ -948,8 +966,12 @@ const TemplateStatusField = "status"
 const BKStatusField = "status"
 
 const (
-	TemplateStatusDraft   = "draft"
-	TemplateStatusOnline  = "online"
+	TemplateStatusDraft    = "draft"
+	TemplateStatusReview   = "review"
+	TemplateStatusApproved = "approved"
+	TemplateStatusOnline   = "online"
+	TemplateStatusArchived = "archived"
+	// TemplateStatusHistory the legacy status of the offline versions, it is treated as archived
 	TemplateStatusHistory = "history"
 )
 
@@ -977,6 +999,7 @@ const (
 	RedisCloudSyncInstanceStarted             = BKCacheKeyV3Prefix + "cloudsyncinstancestarted:list"
 	RedisCloudSyncInstancePendingStop         = BKCacheKeyV3Prefix + "cloudsyncinstancependingstop:list"
 	RedisCloudSyncStartLockKey                = BKCacheKeyV3Prefix + "lock:cloudsyncstart"
+	RedisProcSrvTemplateVersionLockKeyPrefix  = BKCacheKeyV3Prefix + "lock:proctemplateversion:"
 )
 
 // association fields
@@ -1010,6 +1033,9 @@ const (
 const (
 	MetaDataSynchronizeFlagField    = "metadata_sync_flag"
 	MetaDataSynchronizeVersionField = "metadata_sync_version"
+	// MetaDataSynchronizeLastTimeField the last_time of the source data when it is synchronized,
+	// the data edited on the target side has a different last_time
+	MetaDataSynchronizeLastTimeField = "metadata_sync_last_time"
 
 	// SynchronizeSignPrefix  synchronize sign , Should appear in the configuration file
 	SynchronizeSignPrefix = "sync_blueking"
@@ -148,7 +148,20 @@ const (
 	CCErrCommParamsNeedFloat = 1199042
 	CCErrCommNotAllSuccess   = 1199044
 
+	// CCErrCommIdempotencyKeyInProgress the request with the idempotency key is still in progress
+	CCErrCommIdempotencyKeyInProgress = 1199045
+	// CCErrCommIdempotencyKeyReused the idempotency key was used by another request
+	CCErrCommIdempotencyKeyReused = 1199046
+
 	// apiserver 1100XXX
+	// CCErrAPITokenInvalid the api token is invalid, expired or revoked
+	CCErrAPITokenInvalid = 1100000
+
+	// CCErrAPITokenNoPermission the api token has no permission to do the request
+	CCErrAPITokenNoPermission = 1100001
+
+	// CCErrAPIRateLimited the request rate exceeds the limit %s
+	CCErrAPIRateLimited = 1100002
 
 	// toposerver 1101XXX
 	// CCErrTopoInstCreateFailed unable to create the instance
@@ -359,6 +372,12 @@ const (
 	CCErrorTopoAssociationKindInconsistent = 1101083
 	// CCErrorTopoModleStopped means model have been stopped to use
 	CCErrorTopoModleStopped = 1101084
+	// CCErrTopoAttributeNoWritePermission means the user has no permission to write the attributes
+	CCErrTopoAttributeNoWritePermission = 1101085
+	// CCErrTopoAttributeNoReadPermission means the user has no permission to query by the masked attributes
+	CCErrTopoAttributeNoReadPermission = 1101086
+	// CCErrTopoGraphTooManyRelatedInsts means the related instances of a graphql field are too many to be fetched at once
+	CCErrTopoGraphTooManyRelatedInsts = 1101088
 	// objectcontroller 1102XXX
 
 	// CCErrObjectPropertyGroupInsertFailed failed to save the property group
@@ -470,6 +489,14 @@ const (
 	CCErrProcQueryTaskWaitOPFail        = 1108021
 	CCErrProcQueryTaskOPErrFail         = 1108022
 	CCErrProcCreateTemplateFail         = 1108023
+	CCErrProcTemplateNotFound           = 1108024
+	CCErrProcTemplateVersionNotFound    = 1108025
+	CCErrProcRenderConfigFail           = 1108026
+	CCErrProcConfigDeliveryNotSupported = 1108027
+	CCErrProcTemplateVersionStatusError = 1108028
+	CCErrProcTemplateVersionNotEditable = 1108029
+	CCErrProcTemplateVersionNotApprover = 1108030
+	CCErrProcTemplateVersionLocked      = 1108031
 
 	// auditlog 1109XXX
 	CCErrAuditSaveLogFaile      = 1109001
@@ -556,6 +583,9 @@ const (
 	CCErrWebGetAddNetPropertyResultFail = 1111010
 	CCErrWebGetNetDeviceFail            = 1111011
 	CCErrWebGetNetPropertyFail          = 1111012
+	CCErrWebFileFormatNotSupported      = 1111013
+	CCErrWebExportJobNotFound           = 1111014
+	CCErrWebExportJobNotFinished        = 1111015
 
 	// datacollection 1112xxx
 	CCErrCollectNetDeviceCreateFail            = 1112000
@@ -587,6 +617,10 @@ const (
 	CCErrCoreServiceSyncError = 1113900
 	// CCErrCoreServiceSyncDataClassifyNotExistError %s type data synchronization, data of the same type %sdoes not exist
 	CCErrCoreServiceSyncDataClassifyNotExistError = 1113901
+	// CCErrCoreServiceSyncConflictNotExist synchronize conflict %d does not exist
+	CCErrCoreServiceSyncConflictNotExist = 1113902
+	// CCErrCoreServiceSyncConflictResolved synchronize conflict %d has been resolved
+	CCErrCoreServiceSyncConflictResolved = 1113903
 
 	// CCErrApiServerV2AppNameLenErr app name must be 1-32 len
 	CCErrAPIServerV2APPNameLenErr = 1170001
@@ -612,6 +646,16 @@ const (
 	// synchronize_server 1114xxx
 
 	CCErrSynchronizeError = 1114001
+	// CCErrSynchronizeItemNotExist synchronize config item %s does not exist
+	CCErrSynchronizeItemNotExist = 1114002
+	// CCErrSynchronizeItemRunning synchronize config item %s is running
+	CCErrSynchronizeItemRunning = 1114003
+	// CCErrSynchronizeItemNotRunning synchronize config item %s is not running
+	CCErrSynchronizeItemNotRunning = 1114004
+	// CCErrSynchronizeCanceled the synchronization is canceled
+	CCErrSynchronizeCanceled = 1114005
+	// CCErrSynchronizeNotMaster the synchronization only runs on the master
+	CCErrSynchronizeNotMaster = 1114006
 
 	/** TODO: 以下错误码需要改造 **/
 
@@ -43,6 +43,16 @@ func NewEventWithHeader(header http.Header) *metadata.EventInst {
 	}
 }
 
+// NewModelEvent create the event of the model definitions of the object type changed by the action,
+// the event server publishes it to invalidate the caches of the model definitions
+func NewModelEvent(header http.Header, objType, action string) *metadata.EventInst {
+	event := NewEventWithHeader(header)
+	event.EventType = metadata.EventTypeModel
+	event.ObjType = objType
+	event.Action = action
+	return event
+}
+
 type ClientViaRedis struct {
 	rdb       dal.RDB
 	cache     *redis.Client
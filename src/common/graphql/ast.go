@@ -0,0 +1,111 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+// Document the parsed graphql request document
+type Document struct {
+	Operations []*OperationNode
+	Fragments  map[string]*FragmentNode
+}
+
+// OperationNode the query, mutation or subscription operation
+type OperationNode struct {
+	Type         string
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*DirectiveNode
+	SelectionSet []Selection
+	Location     Location
+}
+
+// VariableDefinition the variable declared by the operation, the type is kept as its text such as [Int!]
+type VariableDefinition struct {
+	Name         string
+	Type         string
+	DefaultValue interface{}
+}
+
+// Selection is one of *FieldNode, *FragmentSpreadNode and *InlineFragmentNode
+type Selection interface {
+	isSelection()
+}
+
+// FieldNode the selected field
+type FieldNode struct {
+	Alias        string
+	Name         string
+	Arguments    []*ArgumentNode
+	Directives   []*DirectiveNode
+	SelectionSet []Selection
+	Location     Location
+}
+
+// ResponseKey the key of the field in the result, it is the alias if exists
+func (f *FieldNode) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpreadNode the ...name spread of a named fragment
+type FragmentSpreadNode struct {
+	Name       string
+	Directives []*DirectiveNode
+	Location   Location
+}
+
+// InlineFragmentNode the ... on Type { } fragment
+type InlineFragmentNode struct {
+	TypeCondition string
+	Directives    []*DirectiveNode
+	SelectionSet  []Selection
+}
+
+// FragmentNode the named fragment definition
+type FragmentNode struct {
+	Name          string
+	TypeCondition string
+	Directives    []*DirectiveNode
+	SelectionSet  []Selection
+	Location      Location
+}
+
+func (*FieldNode) isSelection()          {}
+func (*FragmentSpreadNode) isSelection() {}
+func (*InlineFragmentNode) isSelection() {}
+
+// ArgumentNode the argument of the field or the directive, the value is one of
+// nil, bool, int64, float64, string, EnumValue, Variable, []interface{} and map[string]interface{}
+type ArgumentNode struct {
+	Name  string
+	Value interface{}
+}
+
+// DirectiveNode the directive such as @include(if: $var)
+type DirectiveNode struct {
+	Name      string
+	Arguments []*ArgumentNode
+}
+
+// Variable the reference to the variable in the value
+type Variable string
+
+// EnumValue the enum literal in the value
+type EnumValue string
+
+// Location the position in the request document, starts from 1
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
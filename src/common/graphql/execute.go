@@ -0,0 +1,721 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Request the body of the graphql http request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Params the params to execute the request
+type Params struct {
+	Context context.Context
+	Schema  *Schema
+	Request Request
+	// MaxDepth the max depth of the nested selections, it is not limited if it is 0
+	MaxDepth int
+}
+
+// Result the result of the request, the data is absent if the request is invalid
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error the error of the request or the field
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ResolveParams the params of the resolvers
+type ResolveParams struct {
+	Context context.Context
+	Schema  *Schema
+	// Source the value of the parent object, it is not set for the batch resolvers
+	Source interface{}
+	Args   map[string]interface{}
+	Field  *FieldNode
+	// Path the path of the field in the result, it is the path for the first source for the batch resolvers
+	Path     []interface{}
+	executor *executor
+}
+
+// SelectedFields return the names of the fields selected under the field, or under the sub field in the path,
+// the fragments are expanded regardless of the type conditions.
+func (p ResolveParams) SelectedFields(path ...string) []string {
+	selections := p.Field.SelectionSet
+	for _, name := range path {
+		var sub []Selection
+		_, fields := p.executor.fieldsByName(selections, nil)
+		for _, field := range fields[name] {
+			sub = append(sub, field.SelectionSet...)
+		}
+		selections = sub
+	}
+	names, _ := p.executor.fieldsByName(selections, nil)
+	return names
+}
+
+// Do parse, validate and execute the query request, the mutations and the subscriptions are not supported
+func Do(p Params) *Result {
+	doc, err := Parse(p.Request.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+
+	op, err := selectOperation(doc, p.Request.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+	if op.Type != "query" {
+		return &Result{Errors: []*Error{{Message: fmt.Sprintf("the %s operation is not supported, the api is read-only", op.Type), Locations: []Location{op.Location}}}}
+	}
+
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := &executor{ctx: ctx, schema: p.Schema, fragments: doc.Fragments, maxDepth: p.MaxDepth}
+	if err := e.initVariables(op, p.Request.Variables); err != nil {
+		return &Result{Errors: []*Error{toError(err)}}
+	}
+	if errs := e.validate(p.Schema.Query, op.SelectionSet, 1, nil); len(errs) != 0 {
+		return &Result{Errors: errs}
+	}
+
+	data := e.executeObjects(p.Schema.Query, []interface{}{nil}, op.SelectionSet, [][]interface{}{nil})
+	return &Result{Data: data[0], Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (*OperationNode, error) {
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+func toError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Message: err.Error()}
+}
+
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	fragments map[string]*FragmentNode
+	variables map[string]interface{}
+	maxDepth  int
+	errors    []*Error
+}
+
+func (e *executor) initVariables(op *OperationNode, provided map[string]interface{}) error {
+	e.variables = make(map[string]interface{})
+	for _, def := range op.Variables {
+		if val, ok := provided[def.Name]; ok {
+			e.variables[def.Name] = val
+			continue
+		}
+		if def.DefaultValue != nil {
+			e.variables[def.Name] = e.valueOf(def.DefaultValue)
+			continue
+		}
+		if strings.HasSuffix(def.Type, "!") {
+			return &Error{Message: fmt.Sprintf("Variable \"$%s\" of required type %q was not provided.", def.Name, def.Type), Locations: []Location{op.Location}}
+		}
+	}
+	return nil
+}
+
+// valueOf replace the variables in the literal value with their values
+func (e *executor) valueOf(value interface{}) interface{} {
+	switch val := value.(type) {
+	case Variable:
+		return e.variables[string(val)]
+	case EnumValue:
+		return string(val)
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = e.valueOf(item)
+		}
+		return list
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			obj[key] = e.valueOf(item)
+		}
+		return obj
+	default:
+		return value
+	}
+}
+
+// lookupField get the field of the object, the introspection fields are available on the query type
+func (e *executor) lookupField(typ *Object, name string) (*Field, bool) {
+	if typ == e.schema.Query {
+		switch name {
+		case schemaMetaField.Name:
+			return schemaMetaField, true
+		case typeMetaField.Name:
+			return typeMetaField, true
+		}
+	}
+	return typ.Field(name)
+}
+
+func (e *executor) validate(typ *Object, selections []Selection, depth int, spreads map[string]bool) []*Error {
+	if e.maxDepth > 0 && depth > e.maxDepth {
+		return []*Error{{Message: fmt.Sprintf("the query exceeds the max depth %d", e.maxDepth)}}
+	}
+
+	var errs []*Error
+	for _, sel := range selections {
+		switch node := sel.(type) {
+		case *FieldNode:
+			if node.Name == "__typename" {
+				if len(node.SelectionSet) != 0 {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Field %q must not have a selection since type \"String\" has no subfields.", node.Name), Locations: []Location{node.Location}})
+				}
+				continue
+			}
+			field, ok := e.lookupField(typ, node.Name)
+			if !ok {
+				errs = append(errs, &Error{Message: fmt.Sprintf("Cannot query field %q on type %q.", node.Name, typ.Name), Locations: []Location{node.Location}})
+				continue
+			}
+			for _, arg := range node.Arguments {
+				if findArgument(field.Args, arg.Name) == nil {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Unknown argument %q on field \"%s.%s\".", arg.Name, typ.Name, node.Name), Locations: []Location{node.Location}})
+				}
+			}
+			switch named := namedType(field.Type).(type) {
+			case *Object:
+				if len(node.SelectionSet) == 0 {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Field %q of type %q must have a selection of subfields.", node.Name, field.Type.String()), Locations: []Location{node.Location}})
+					continue
+				}
+				next := depth + 1
+				if strings.HasPrefix(named.Name, "__") {
+					// the introspection queries are deeply nested, they are not limited
+					next = depth
+				}
+				errs = append(errs, e.validate(named, node.SelectionSet, next, spreads)...)
+			case *Scalar:
+				if len(node.SelectionSet) != 0 {
+					errs = append(errs, &Error{Message: fmt.Sprintf("Field %q must not have a selection since type %q has no subfields.", node.Name, field.Type.String()), Locations: []Location{node.Location}})
+				}
+			}
+
+		case *FragmentSpreadNode:
+			frag, ok := e.fragments[node.Name]
+			if !ok {
+				errs = append(errs, &Error{Message: fmt.Sprintf("Unknown fragment %q.", node.Name), Locations: []Location{node.Location}})
+				continue
+			}
+			if spreads[node.Name] {
+				errs = append(errs, &Error{Message: fmt.Sprintf("Cannot spread fragment %q within itself.", node.Name), Locations: []Location{node.Location}})
+				continue
+			}
+			if err := e.checkTypeCondition(frag.TypeCondition); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if frag.TypeCondition != typ.Name {
+				continue
+			}
+			nested := map[string]bool{node.Name: true}
+			for name := range spreads {
+				nested[name] = true
+			}
+			errs = append(errs, e.validate(typ, frag.SelectionSet, depth, nested)...)
+
+		case *InlineFragmentNode:
+			if node.TypeCondition != "" {
+				if err := e.checkTypeCondition(node.TypeCondition); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if node.TypeCondition != typ.Name {
+					continue
+				}
+			}
+			errs = append(errs, e.validate(typ, node.SelectionSet, depth, spreads)...)
+		}
+	}
+	return errs
+}
+
+func (e *executor) checkTypeCondition(name string) *Error {
+	if _, ok := e.schema.Type(name); !ok {
+		return &Error{Message: fmt.Sprintf("Unknown type %q.", name)}
+	}
+	return nil
+}
+
+func findArgument(args []*Argument, name string) *Argument {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+func namedType(t Type) Type {
+	for {
+		list, ok := t.(*List)
+		if !ok {
+			return t
+		}
+		t = list.OfType
+	}
+}
+
+// included evaluate the @skip and the @include directives
+func (e *executor) included(directives []*DirectiveNode) bool {
+	for _, directive := range directives {
+		var cond interface{}
+		for _, arg := range directive.Arguments {
+			if arg.Name == "if" {
+				cond = e.valueOf(arg.Value)
+			}
+		}
+		switch directive.Name {
+		case skipDirective.Name:
+			if cond == true {
+				return false
+			}
+		case includeDirective.Name:
+			if cond != true {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// collectFields group the fields by the response key in the order they are selected, the fragments which
+// do not apply to the type are skipped
+func (e *executor) collectFields(typ *Object, selections []Selection, keys []string, fields map[string][]*FieldNode) ([]string, map[string][]*FieldNode) {
+	if fields == nil {
+		fields = make(map[string][]*FieldNode)
+	}
+	for _, sel := range selections {
+		switch node := sel.(type) {
+		case *FieldNode:
+			if !e.included(node.Directives) {
+				continue
+			}
+			key := node.ResponseKey()
+			if _, ok := fields[key]; !ok {
+				keys = append(keys, key)
+			}
+			fields[key] = append(fields[key], node)
+		case *FragmentSpreadNode:
+			frag, ok := e.fragments[node.Name]
+			if !ok || !e.included(node.Directives) || frag.TypeCondition != typ.Name {
+				continue
+			}
+			keys, fields = e.collectFields(typ, frag.SelectionSet, keys, fields)
+		case *InlineFragmentNode:
+			if !e.included(node.Directives) || (node.TypeCondition != "" && node.TypeCondition != typ.Name) {
+				continue
+			}
+			keys, fields = e.collectFields(typ, node.SelectionSet, keys, fields)
+		}
+	}
+	return keys, fields
+}
+
+// fieldsByName group the fields by the field name regardless of the type conditions of the fragments
+func (e *executor) fieldsByName(selections []Selection, visited map[string]bool) ([]string, map[string][]*FieldNode) {
+	var names []string
+	fields := make(map[string][]*FieldNode)
+	var walk func(selections []Selection)
+	walk = func(selections []Selection) {
+		for _, sel := range selections {
+			switch node := sel.(type) {
+			case *FieldNode:
+				if !e.included(node.Directives) {
+					continue
+				}
+				if _, ok := fields[node.Name]; !ok {
+					names = append(names, node.Name)
+				}
+				fields[node.Name] = append(fields[node.Name], node)
+			case *FragmentSpreadNode:
+				frag, ok := e.fragments[node.Name]
+				if !ok || visited[node.Name] || !e.included(node.Directives) {
+					continue
+				}
+				if visited == nil {
+					visited = make(map[string]bool)
+				}
+				visited[node.Name] = true
+				walk(frag.SelectionSet)
+			case *InlineFragmentNode:
+				if e.included(node.Directives) {
+					walk(node.SelectionSet)
+				}
+			}
+		}
+	}
+	walk(selections)
+	return names, fields
+}
+
+// executeObjects execute the selections on all the sources of the type together, so every field is resolved
+// once for the sources by its batch resolver
+func (e *executor) executeObjects(typ *Object, sources []interface{}, selections []Selection, paths [][]interface{}) []interface{} {
+	keys, fields := e.collectFields(typ, selections, nil, nil)
+	results := make([]*orderedMap, len(sources))
+	for i := range results {
+		results[i] = newOrderedMap()
+	}
+
+	for _, key := range keys {
+		nodes := fields[key]
+		node := nodes[0]
+		fieldPaths := make([][]interface{}, len(sources))
+		for i := range sources {
+			fieldPaths[i] = appendPath(paths[i], key)
+		}
+
+		if node.Name == "__typename" {
+			for _, result := range results {
+				result.Set(key, typ.Name)
+			}
+			continue
+		}
+
+		field, _ := e.lookupField(typ, node.Name)
+		values := e.resolve(field, node, sources, fieldPaths)
+		var selections []Selection
+		for _, n := range nodes {
+			selections = append(selections, n.SelectionSet...)
+		}
+		completed := e.completeValues(field.Type, selections, values, fieldPaths, node)
+		for i, result := range results {
+			result.Set(key, completed[i])
+		}
+	}
+
+	out := make([]interface{}, len(results))
+	for i, result := range results {
+		out[i] = result
+	}
+	return out
+}
+
+func (e *executor) resolve(field *Field, node *FieldNode, sources []interface{}, paths [][]interface{}) []interface{} {
+	values := make([]interface{}, len(sources))
+	args, err := e.coerceArguments(field, node)
+	if err != nil {
+		e.addError(err, node, paths[0])
+		return values
+	}
+
+	params := ResolveParams{Context: e.ctx, Schema: e.schema, Args: args, Field: node, Path: paths[0], executor: e}
+	if field.ResolveBatch != nil {
+		resolved, err := callBatch(field.ResolveBatch, params, sources)
+		if err == nil && len(resolved) != len(sources) {
+			err = fmt.Errorf("the field %s resolves %d values for %d sources", field.Name, len(resolved), len(sources))
+		}
+		if err != nil {
+			e.addError(err, node, paths[0])
+			return values
+		}
+		return resolved
+	}
+
+	for i, source := range sources {
+		if field.Resolve == nil {
+			values[i] = defaultResolve(source, field.Name)
+			continue
+		}
+		params.Source = source
+		params.Path = paths[i]
+		value, err := call(field.Resolve, params)
+		if err != nil {
+			e.addError(err, node, paths[i])
+			continue
+		}
+		values[i] = value
+	}
+	return values
+}
+
+func call(resolve ResolveFunc, p ResolveParams) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("resolve field %s panic: %v", p.Field.Name, r)
+		}
+	}()
+	return resolve(p)
+}
+
+func callBatch(resolve BatchResolveFunc, p ResolveParams, sources []interface{}) (values []interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("resolve field %s panic: %v", p.Field.Name, r)
+		}
+	}()
+	return resolve(p, sources)
+}
+
+// defaultResolve get the value of the field name from the map source
+func defaultResolve(source interface{}, name string) interface{} {
+	if source == nil {
+		return nil
+	}
+	if m, ok := source.(map[string]interface{}); ok {
+		return m[name]
+	}
+	val := reflect.ValueOf(source)
+	if val.Kind() == reflect.Map && val.Type().Key().Kind() == reflect.String {
+		item := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+		if item.IsValid() {
+			return item.Interface()
+		}
+	}
+	return nil
+}
+
+func (e *executor) coerceArguments(field *Field, node *FieldNode) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	for _, def := range field.Args {
+		var argNode *ArgumentNode
+		for _, arg := range node.Arguments {
+			if arg.Name == def.Name {
+				argNode = arg
+			}
+		}
+		if argNode != nil {
+			if variable, ok := argNode.Value.(Variable); ok {
+				if _, provided := e.variables[string(variable)]; !provided {
+					argNode = nil
+				}
+			}
+		}
+		if argNode == nil {
+			if def.DefaultValue != nil {
+				args[def.Name] = def.DefaultValue
+			}
+			continue
+		}
+
+		value := e.valueOf(argNode.Value)
+		if value == nil {
+			args[def.Name] = nil
+			continue
+		}
+		coerced, err := coerceValue(def.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q has invalid value: %v", def.Name, err)
+		}
+		args[def.Name] = coerced
+	}
+	return args, nil
+}
+
+func coerceValue(t Type, value interface{}) (interface{}, error) {
+	switch typ := t.(type) {
+	case *Scalar:
+		return typ.ParseValue(value)
+	case *List:
+		items, ok := toSlice(value)
+		if !ok {
+			items = []interface{}{value}
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			coerced, err := coerceValue(typ.OfType, item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = coerced
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("the type %s can not be used as input", t.String())
+	}
+}
+
+// completeValues convert the resolved values to the result values by the type of the field
+func (e *executor) completeValues(t Type, selections []Selection, values []interface{}, paths [][]interface{}, node *FieldNode) []interface{} {
+	out := make([]interface{}, len(values))
+	switch typ := t.(type) {
+	case *Scalar:
+		for i, value := range values {
+			if isNil(value) {
+				continue
+			}
+			serialized, err := typ.Serialize(value)
+			if err != nil {
+				e.addError(err, node, paths[i])
+				continue
+			}
+			out[i] = serialized
+		}
+
+	case *Object:
+		var index []int
+		var sources []interface{}
+		var sourcePaths [][]interface{}
+		for i, value := range values {
+			if isNil(value) {
+				continue
+			}
+			index = append(index, i)
+			sources = append(sources, value)
+			sourcePaths = append(sourcePaths, paths[i])
+		}
+		if len(sources) != 0 {
+			results := e.executeObjects(typ, sources, selections, sourcePaths)
+			for j, i := range index {
+				out[i] = results[j]
+			}
+		}
+
+	case *List:
+		type position struct{ list, item int }
+		var items []interface{}
+		var itemPaths [][]interface{}
+		var positions []position
+		for i, value := range values {
+			if isNil(value) {
+				continue
+			}
+			list, ok := toSlice(value)
+			if !ok {
+				e.addError(fmt.Errorf("expected a list for the type %s", t.String()), node, paths[i])
+				continue
+			}
+			out[i] = make([]interface{}, len(list))
+			for j, item := range list {
+				items = append(items, item)
+				itemPaths = append(itemPaths, appendPath(paths[i], j))
+				positions = append(positions, position{list: i, item: j})
+			}
+		}
+		completed := e.completeValues(typ.OfType, selections, items, itemPaths, node)
+		for k, pos := range positions {
+			out[pos.list].([]interface{})[pos.item] = completed[k]
+		}
+	}
+	return out
+}
+
+func (e *executor) addError(err error, node *FieldNode, path []interface{}) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Locations: []Location{node.Location}, Path: path})
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	out := make([]interface{}, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, key)
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
+
+func toSlice(value interface{}) ([]interface{}, bool) {
+	if list, ok := value.([]interface{}); ok {
+		return list, true
+	}
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]interface{}, val.Len())
+	for i := range list {
+		list[i] = val.Index(i).Interface()
+	}
+	return list, true
+}
+
+// orderedMap keeps the fields of the result in the order they are selected
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,202 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testTree struct {
+	schema *Schema
+	// batches the count of the batch calls of the children field
+	batches int
+}
+
+func newTestTree(t *testing.T) *testTree {
+	tree := new(testTree)
+	node := NewObject("Node", "")
+	node.AddField(&Field{Name: "id", Type: Int})
+	node.AddField(&Field{Name: "name", Type: String})
+	node.AddField(&Field{Name: "secret", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return nil, errors.New("no permission")
+	}})
+	node.AddField(&Field{
+		Name: "children",
+		Type: NewList(node),
+		Args: []*Argument{{Name: "limit", Type: Int, DefaultValue: int64(10)}},
+		ResolveBatch: func(p ResolveParams, sources []interface{}) ([]interface{}, error) {
+			tree.batches++
+			limit := p.Args["limit"].(int64)
+			values := make([]interface{}, len(sources))
+			for i, source := range sources {
+				id := source.(map[string]interface{})["id"].(int64)
+				var children []map[string]interface{}
+				for c := int64(1); c <= 2 && c <= limit; c++ {
+					children = append(children, map[string]interface{}{"id": id*10 + c, "name": "child"})
+				}
+				values[i] = children
+			}
+			return values, nil
+		},
+	})
+
+	query := NewObject("Query", "")
+	query.AddField(&Field{
+		Name: "node",
+		Type: node,
+		Args: []*Argument{{Name: "id", Type: Int}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			id, _ := p.Args["id"].(int64)
+			return map[string]interface{}{"id": id, "name": "root"}, nil
+		},
+	})
+	query.AddField(&Field{Name: "selected", Type: NewList(String), Args: []*Argument{{Name: "q", Type: JSON}}, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.SelectedFields(), nil
+	}})
+
+	var err error
+	if tree.schema, err = NewSchema(query); err != nil {
+		t.Fatalf("new schema failed, err: %v", err)
+	}
+	return tree
+}
+
+func (tree *testTree) do(query string, variables map[string]interface{}) (string, *Result) {
+	result := Do(Params{Schema: tree.schema, Request: Request{Query: query, Variables: variables}, MaxDepth: 4})
+	out, _ := json.Marshal(result.Data)
+	return string(out), result
+}
+
+func TestExecute(t *testing.T) {
+	tree := newTestTree(t)
+	data, result := tree.do(`
+		query Tree($id: Int = 2, $withName: Boolean!) {
+			root: node(id: $id) {
+				__typename
+				id
+				name @include(if: $withName)
+				children(limit: 2) { ...child }
+			}
+		}
+		fragment child on Node {
+			id
+			children { id, n: name }
+		}`, map[string]interface{}{"withName": false})
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors[0])
+	}
+	expect := `{"root":{"__typename":"Node","id":2,"children":[` +
+		`{"id":21,"children":[{"id":211,"n":"child"},{"id":212,"n":"child"}]},` +
+		`{"id":22,"children":[{"id":221,"n":"child"},{"id":222,"n":"child"}]}]}}`
+	if data != expect {
+		t.Errorf("unexpected data:\n%s\nexpect:\n%s", data, expect)
+	}
+	// the children of all the nodes in the same level are resolved at once
+	if tree.batches != 2 {
+		t.Errorf("expect 2 batch calls, got %d", tree.batches)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	tree := newTestTree(t)
+	data, result := tree.do(`{ node(id: 1) { id secret } }`, nil)
+	if data != `{"node":{"id":1,"secret":null}}` {
+		t.Errorf("unexpected data %s", data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "no permission" {
+		t.Fatalf("unexpected errors %v", result.Errors)
+	}
+	path, _ := json.Marshal(result.Errors[0].Path)
+	if string(path) != `["node","secret"]` {
+		t.Errorf("unexpected error path %s", path)
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	tree := newTestTree(t)
+	cases := map[string]string{
+		`{ node { unknown } }`:                                   `Cannot query field "unknown" on type "Node".`,
+		`{ node }`:                                               `must have a selection of subfields`,
+		`{ node { id { x } } }`:                                  `must not have a selection`,
+		`{ node(foo: 1) { id } }`:                                `Unknown argument "foo"`,
+		`{ node(id: "a") { id } }`:                               `Argument "id" has invalid value`,
+		`{ node { ...missing } }`:                                `Unknown fragment "missing".`,
+		`{ node { ...a } } fragment a on Node { ...a }`:          `Cannot spread fragment "a" within itself.`,
+		`mutation { node { id } }`:                               `not supported`,
+		`{ node { children { children { children { id } } } } }`: `exceeds the max depth`,
+		`{ node { id }`:                                          `Syntax Error`,
+		`query A { node { id } } query B { node { id } }`:        `Must provide operation name`,
+	}
+	for query, message := range cases {
+		_, result := tree.do(query, nil)
+		if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, message) {
+			t.Errorf("query %s: expect error %q, got %v", query, message, result.Errors)
+		}
+		if strings.Contains(message, "invalid value") {
+			continue
+		}
+		if result.Data != nil {
+			t.Errorf("query %s: the invalid request should not be executed", query)
+		}
+	}
+}
+
+func TestSelectedFields(t *testing.T) {
+	tree := newTestTree(t)
+	data, _ := tree.do(`{ selected(q: {a: [1, "b"]}) }`, nil)
+	if data != `{"selected":[]}` && data != `{"selected":null}` {
+		t.Errorf("unexpected data %s", data)
+	}
+
+	query := NewObject("Query", "")
+	query.AddField(&Field{Name: "node", Type: tree.schema.Query.index["node"].Type, Resolve: func(p ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": int64(1), "name": strings.Join(p.SelectedFields("children"), ",")}, nil
+	}})
+	schema, err := NewSchema(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Do(Params{Schema: schema, Request: Request{Query: `{ node { name children { id ... on Node { name } ...f } } } fragment f on Node { children { id } }`}})
+	out, _ := json.Marshal(result.Data)
+	if !strings.Contains(string(out), `"name":"id,name,children"`) {
+		t.Errorf("unexpected selected fields %s", out)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	tree := newTestTree(t)
+	data, result := tree.do(`{
+		__schema { queryType { name } directives { name } }
+		__type(name: "Node") {
+			kind
+			name
+			fields { name args { name defaultValue } type { kind name ofType { name } } }
+		}
+	}`, nil)
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors[0])
+	}
+	for _, expect := range []string{
+		`"queryType":{"name":"Query"}`,
+		`"directives":[{"name":"include"},{"name":"skip"}]`,
+		`"kind":"OBJECT","name":"Node"`,
+		`{"name":"children","args":[{"name":"limit","defaultValue":"10"}],"type":{"kind":"LIST","name":null,"ofType":{"name":"Node"}}}`,
+	} {
+		if !strings.Contains(data, expect) {
+			t.Errorf("expect %s in %s", expect, data)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"encoding/json"
+)
+
+// directive the directives supported by the executor
+type directive struct {
+	Name        string
+	Description string
+	Locations   []string
+	Args        []*Argument
+}
+
+var (
+	includeDirective = &directive{
+		Name:        "include",
+		Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*Argument{{Name: "if", Description: "Included when true.", Type: Boolean}},
+	}
+	skipDirective = &directive{
+		Name:        "skip",
+		Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*Argument{{Name: "if", Description: "Skipped when true.", Type: Boolean}},
+	}
+)
+
+// the introspection types
+var (
+	schemaType     = NewObject("__Schema", "A GraphQL Schema defines the capabilities of a GraphQL server.")
+	typeType       = NewObject("__Type", "The fundamental unit of any GraphQL Schema is the type.")
+	fieldType      = NewObject("__Field", "Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type.")
+	inputValueType = NewObject("__InputValue", "Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values which describe their type and optionally a default value.")
+	enumValueType  = NewObject("__EnumValue", "One possible value for a given Enum.")
+	directiveType  = NewObject("__Directive", "A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.")
+
+	schemaMetaField = &Field{
+		Name:        "__schema",
+		Description: "Access the current type schema of this server.",
+		Type:        schemaType,
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			return p.Schema, nil
+		},
+	}
+	typeMetaField = &Field{
+		Name:        "__type",
+		Description: "Request the type information of a single type.",
+		Type:        typeType,
+		Args:        []*Argument{{Name: "name", Type: String}},
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			name, _ := p.Args["name"].(string)
+			if t, ok := p.Schema.Type(name); ok {
+				return t, nil
+			}
+			return nil, nil
+		},
+	}
+)
+
+func init() {
+	schemaType.AddField(&Field{Name: "description", Type: String})
+	schemaType.AddField(&Field{Name: "types", Type: NewList(typeType), Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Schema).Types(), nil
+	}})
+	schemaType.AddField(&Field{Name: "queryType", Type: typeType, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Schema).Query, nil
+	}})
+	schemaType.AddField(&Field{Name: "mutationType", Type: typeType})
+	schemaType.AddField(&Field{Name: "subscriptionType", Type: typeType})
+	schemaType.AddField(&Field{Name: "directives", Type: NewList(directiveType), Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Schema).directives, nil
+	}})
+
+	includeDeprecated := []*Argument{{Name: "includeDeprecated", Type: Boolean, DefaultValue: false}}
+	typeType.AddField(&Field{Name: "kind", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		switch p.Source.(type) {
+		case *Scalar:
+			return "SCALAR", nil
+		case *Object:
+			return "OBJECT", nil
+		default:
+			return "LIST", nil
+		}
+	}})
+	typeType.AddField(&Field{Name: "name", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		switch t := p.Source.(type) {
+		case *Scalar:
+			return t.Name, nil
+		case *Object:
+			return t.Name, nil
+		}
+		return nil, nil
+	}})
+	typeType.AddField(&Field{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		switch t := p.Source.(type) {
+		case *Scalar:
+			return t.Description, nil
+		case *Object:
+			return t.Description, nil
+		}
+		return nil, nil
+	}})
+	typeType.AddField(&Field{Name: "specifiedByURL", Type: String})
+	typeType.AddField(&Field{Name: "fields", Type: NewList(fieldType), Args: includeDeprecated, Resolve: func(p ResolveParams) (interface{}, error) {
+		if t, ok := p.Source.(*Object); ok {
+			return t.Fields(), nil
+		}
+		return nil, nil
+	}})
+	typeType.AddField(&Field{Name: "interfaces", Type: NewList(typeType), Resolve: func(p ResolveParams) (interface{}, error) {
+		if _, ok := p.Source.(*Object); ok {
+			return []interface{}{}, nil
+		}
+		return nil, nil
+	}})
+	typeType.AddField(&Field{Name: "possibleTypes", Type: NewList(typeType)})
+	typeType.AddField(&Field{Name: "enumValues", Type: NewList(enumValueType), Args: includeDeprecated})
+	typeType.AddField(&Field{Name: "inputFields", Type: NewList(inputValueType)})
+	typeType.AddField(&Field{Name: "ofType", Type: typeType, Resolve: func(p ResolveParams) (interface{}, error) {
+		if t, ok := p.Source.(*List); ok {
+			return t.OfType, nil
+		}
+		return nil, nil
+	}})
+
+	fieldType.AddField(&Field{Name: "name", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Field).Name, nil
+	}})
+	fieldType.AddField(&Field{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Field).Description, nil
+	}})
+	fieldType.AddField(&Field{Name: "args", Type: NewList(inputValueType), Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Field).Args, nil
+	}})
+	fieldType.AddField(&Field{Name: "type", Type: typeType, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Field).Type, nil
+	}})
+	fieldType.AddField(&Field{Name: "isDeprecated", Type: Boolean, Resolve: alwaysFalse})
+	fieldType.AddField(&Field{Name: "deprecationReason", Type: String})
+
+	inputValueType.AddField(&Field{Name: "name", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Argument).Name, nil
+	}})
+	inputValueType.AddField(&Field{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Argument).Description, nil
+	}})
+	inputValueType.AddField(&Field{Name: "type", Type: typeType, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*Argument).Type, nil
+	}})
+	inputValueType.AddField(&Field{Name: "defaultValue", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		arg := p.Source.(*Argument)
+		if arg.DefaultValue == nil {
+			return nil, nil
+		}
+		// the json encoding of the scalars is the same as their graphql literals
+		out, err := json.Marshal(arg.DefaultValue)
+		return string(out), err
+	}})
+	inputValueType.AddField(&Field{Name: "isDeprecated", Type: Boolean, Resolve: alwaysFalse})
+	inputValueType.AddField(&Field{Name: "deprecationReason", Type: String})
+
+	enumValueType.AddField(&Field{Name: "name", Type: String})
+	enumValueType.AddField(&Field{Name: "description", Type: String})
+	enumValueType.AddField(&Field{Name: "isDeprecated", Type: Boolean, Resolve: alwaysFalse})
+	enumValueType.AddField(&Field{Name: "deprecationReason", Type: String})
+
+	directiveType.AddField(&Field{Name: "name", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*directive).Name, nil
+	}})
+	directiveType.AddField(&Field{Name: "description", Type: String, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*directive).Description, nil
+	}})
+	directiveType.AddField(&Field{Name: "locations", Type: NewList(String), Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*directive).Locations, nil
+	}})
+	directiveType.AddField(&Field{Name: "args", Type: NewList(inputValueType), Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source.(*directive).Args, nil
+	}})
+	directiveType.AddField(&Field{Name: "isRepeatable", Type: Boolean, Resolve: alwaysFalse})
+}
+
+func alwaysFalse(p ResolveParams) (interface{}, error) {
+	return false, nil
+}
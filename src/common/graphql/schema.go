@@ -0,0 +1,316 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Type is one of *Scalar, *Object and *List
+type Type interface {
+	// String the type reference such as [Host]
+	String() string
+}
+
+// Scalar the leaf type
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize convert the resolved value to the result value
+	Serialize func(value interface{}) (interface{}, error)
+	// ParseValue convert the argument value to the value used by the resolvers
+	ParseValue func(value interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string {
+	return s.Name
+}
+
+// List the list of the type
+type List struct {
+	OfType Type
+}
+
+// NewList create the list type of the type
+func NewList(ofType Type) *List {
+	return &List{OfType: ofType}
+}
+
+func (l *List) String() string {
+	return "[" + l.OfType.String() + "]"
+}
+
+// Object the object type with the fields
+type Object struct {
+	Name        string
+	Description string
+	fields      []*Field
+	index       map[string]*Field
+}
+
+// NewObject create an object type without fields
+func NewObject(name, description string) *Object {
+	return &Object{Name: name, Description: description, index: make(map[string]*Field)}
+}
+
+func (o *Object) String() string {
+	return o.Name
+}
+
+// AddField add the field to the object, it returns false if the field with the same name exists
+func (o *Object) AddField(field *Field) bool {
+	if _, exists := o.index[field.Name]; exists {
+		return false
+	}
+	o.fields = append(o.fields, field)
+	o.index[field.Name] = field
+	return true
+}
+
+// Field get the field by name
+func (o *Object) Field(name string) (*Field, bool) {
+	field, ok := o.index[name]
+	return field, ok
+}
+
+// Fields the fields in the order they are added
+func (o *Object) Fields() []*Field {
+	return o.fields
+}
+
+// ResolveFunc resolve the value of the field for the source in the params
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// BatchResolveFunc resolve the values of the field for all the sources at once, the values are in the order of the sources
+type BatchResolveFunc func(p ResolveParams, sources []interface{}) ([]interface{}, error)
+
+// Field the field of the object type, the value is the item of the map source with the field name
+// if neither Resolve nor ResolveBatch is set.
+type Field struct {
+	Name         string
+	Description  string
+	Type         Type
+	Args         []*Argument
+	Resolve      ResolveFunc
+	ResolveBatch BatchResolveFunc
+}
+
+// Argument the argument of the field or the directive
+type Argument struct {
+	Name         string
+	Description  string
+	Type         Type
+	DefaultValue interface{}
+}
+
+// Schema the query type and the types reachable from it
+type Schema struct {
+	Query      *Object
+	types      map[string]Type
+	directives []*directive
+}
+
+// NewSchema create the schema of the query type
+func NewSchema(query *Object) (*Schema, error) {
+	s := &Schema{Query: query, types: make(map[string]Type)}
+	for _, scalar := range []*Scalar{String, Int, Float, Boolean, ID} {
+		s.types[scalar.Name] = scalar
+	}
+	if err := s.addType(query); err != nil {
+		return nil, err
+	}
+	if err := s.addType(schemaType); err != nil {
+		return nil, err
+	}
+	s.directives = []*directive{includeDirective, skipDirective}
+	return s, nil
+}
+
+func (s *Schema) addType(t Type) error {
+	switch typ := t.(type) {
+	case *List:
+		return s.addType(typ.OfType)
+	case *Scalar:
+		if exists, ok := s.types[typ.Name]; ok && exists != t {
+			return fmt.Errorf("the type %s is defined more than once", typ.Name)
+		}
+		s.types[typ.Name] = typ
+	case *Object:
+		if exists, ok := s.types[typ.Name]; ok {
+			if exists != t {
+				return fmt.Errorf("the type %s is defined more than once", typ.Name)
+			}
+			return nil
+		}
+		s.types[typ.Name] = typ
+		for _, field := range typ.fields {
+			if err := s.addType(field.Type); err != nil {
+				return err
+			}
+			for _, arg := range field.Args {
+				if err := s.addType(arg.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Type get the named type
+func (s *Schema) Type(name string) (Type, bool) {
+	t, ok := s.types[name]
+	return t, ok
+}
+
+// Types the named types sorted by name
+func (s *Schema) Types() []Type {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	types := make([]Type, 0, len(names))
+	for _, name := range names {
+		types = append(types, s.types[name])
+	}
+	return types
+}
+
+// the built-in scalars
+var (
+	String = &Scalar{
+		Name:        "String",
+		Description: "The `String` scalar type represents textual data.",
+		Serialize:   serializeString,
+		ParseValue:  parseString,
+	}
+	Int = &Scalar{
+		Name:        "Int",
+		Description: "The `Int` scalar type represents non-fractional signed whole numeric values.",
+		Serialize:   toInt,
+		ParseValue:  toInt,
+	}
+	Float = &Scalar{
+		Name:        "Float",
+		Description: "The `Float` scalar type represents signed double-precision fractional values.",
+		Serialize:   toFloat,
+		ParseValue:  toFloat,
+	}
+	Boolean = &Scalar{
+		Name:        "Boolean",
+		Description: "The `Boolean` scalar type represents `true` or `false`.",
+		Serialize:   toBoolean,
+		ParseValue:  toBoolean,
+	}
+	ID = &Scalar{
+		Name:        "ID",
+		Description: "The `ID` scalar type represents a unique identifier.",
+		Serialize:   toID,
+		ParseValue:  toID,
+	}
+	// JSON any json value, it is used for the values without a fixed structure
+	JSON = &Scalar{
+		Name:        "JSON",
+		Description: "The `JSON` scalar type represents any json value.",
+		Serialize:   func(value interface{}) (interface{}, error) { return value, nil },
+		ParseValue:  func(value interface{}) (interface{}, error) { return value, nil },
+	}
+)
+
+func serializeString(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val), nil
+	case float32, float64:
+		return strconv.FormatFloat(reflect.ValueOf(val).Float(), 'f', -1, 64), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	}
+	return nil, fmt.Errorf("String cannot represent value: %v", value)
+}
+
+func parseString(value interface{}) (interface{}, error) {
+	if val, ok := value.(string); ok {
+		return val, nil
+	}
+	return nil, fmt.Errorf("String cannot represent a non string value: %v", value)
+}
+
+func toInt(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(val).Int(), nil
+	case uint, uint8, uint16, uint32, uint64:
+		u := reflect.ValueOf(val).Uint()
+		if u <= math.MaxInt64 {
+			return int64(u), nil
+		}
+	case float32, float64:
+		f := reflect.ValueOf(val).Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f <= math.MaxInt64 {
+			return int64(f), nil
+		}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("Int cannot represent value: %v", value)
+}
+
+func toFloat(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case int, int8, int16, int32, int64:
+		return float64(reflect.ValueOf(val).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return float64(reflect.ValueOf(val).Uint()), nil
+	case float32, float64:
+		return reflect.ValueOf(val).Float(), nil
+	case json.Number:
+		if f, err := val.Float64(); err == nil {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("Float cannot represent value: %v", value)
+}
+
+func toBoolean(value interface{}) (interface{}, error) {
+	if val, ok := value.(bool); ok {
+		return val, nil
+	}
+	return nil, fmt.Errorf("Boolean cannot represent value: %v", value)
+}
+
+func toID(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case string:
+		return value, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(value), nil
+	case float32, float64:
+		if i, err := toInt(value); err == nil {
+			return fmt.Sprint(i), nil
+		}
+	}
+	return nil, fmt.Errorf("ID cannot represent value: %v", value)
+}
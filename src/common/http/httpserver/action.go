@@ -23,6 +23,8 @@ type Action struct {
 	Params        []*restful.Parameter // List of parameters associated with the action.
 	Handler       restful.RouteFunction
 	FilterHandler []restful.FilterFunction
+	Request       interface{} // The sample of the request body which documents the action, optional
+	Response      interface{} // The sample of the response body which documents the action, optional
 }
 
 func NewAction(verb, path string, params []*restful.Parameter, handler restful.RouteFunction, filters []restful.FilterFunction) *Action {
@@ -0,0 +1,327 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	dalredis "configcenter/src/storage/dal/redis"
+)
+
+const (
+	// DefaultWindow how long the response of an idempotency key is recorded by default
+	DefaultWindow = 24 * time.Hour
+	// reserveTTL how long a key is reserved by the request in progress, the reservation of
+	// a crashed process is released after the ttl.
+	reserveTTL = 5 * time.Minute
+	// maxWait how long the retry waits for the request in progress with the same key
+	maxWait       = 30 * time.Second
+	waitInterval  = 50 * time.Millisecond
+	maxRecordSize = 4 << 20
+)
+
+// the config keys of the process config
+const (
+	configStore  = "idempotency.store"
+	configWindow = "idempotency.window"
+)
+
+var (
+	lock       sync.RWMutex
+	store      Store = NewMemoryStore()
+	window           = DefaultWindow
+	configured string
+)
+
+// SetStore replace the store of the responses
+func SetStore(s Store, w time.Duration) {
+	lock.Lock()
+	defer lock.Unlock()
+	store = s
+	window = w
+}
+
+// Shared returns whether the responses are recorded in the store shared by all the instances of the services.
+// only then the writes can be retried on the other instances safely, so the clients should not send the
+// idempotency keys and retry the writes otherwise.
+func Shared() bool {
+	s, _ := current()
+	_, ok := s.(*RedisStore)
+	return ok
+}
+
+func current() (Store, time.Duration) {
+	lock.RLock()
+	defer lock.RUnlock()
+	return store, window
+}
+
+// Configure set the store with the process config, the configs are:
+// idempotency.store: memory or redis, default memory. the redis store use the redis.* configs of the process.
+// the writes are only retried with the idempotency keys by the processes with the redis store.
+// idempotency.window: how long the responses are recorded, such as 12h, default 24h.
+func Configure(config map[string]string) error {
+	key := configKey(config)
+	lock.RLock()
+	unchanged := key == configured
+	lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	w := DefaultWindow
+	if value := config[configWindow]; value != "" {
+		var err error
+		w, err = time.ParseDuration(value)
+		if err != nil || w <= 0 {
+			return fmt.Errorf("invalid %s %q", configWindow, value)
+		}
+	}
+
+	var s Store
+	switch name := config[configStore]; name {
+	case "", "memory":
+		s = NewMemoryStore()
+	case "redis":
+		client, err := dalredis.NewFromConfig(dalredis.ParseConfigFromKV("redis", config))
+		if err != nil {
+			return fmt.Errorf("connect to redis failed, err: %v", err)
+		}
+		s = NewRedisStore(client)
+	default:
+		return fmt.Errorf("unknown %s %q", configStore, name)
+	}
+
+	SetStore(s, w)
+	lock.Lock()
+	configured = key
+	lock.Unlock()
+	blog.Infof("idempotency store is set to %T, window: %v", s, w)
+	return nil
+}
+
+// configKey join the configs the store depends on, it's used to find out whether the config is changed
+func configKey(config map[string]string) string {
+	pairs := make([]string, 0)
+	for key, value := range config {
+		if strings.HasPrefix(key, "idempotency.") || (config[configStore] == "redis" && strings.HasPrefix(key, "redis.")) {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\n")
+}
+
+// Handler replay the recorded response to the non-GET requests whose idempotency key has been handled in the window,
+// so that the client can retry the writes safely. the key is removed from the request header, so that it's not
+// forwarded to the other services by the handler, the requests sent to them carry their own keys.
+// the key is scoped by the supplier account and the user, so the keys of the different users never conflict.
+func Handler(errFunc func() errors.CCErrorIf, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := req.Header.Get(common.BKHTTPIdempotencyKey)
+		if key == "" || req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions {
+			next.ServeHTTP(w, req)
+			return
+		}
+		req.Header.Del(common.BKHTTPIdempotencyKey)
+
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			blog.Errorf("read the body of the request with idempotency key %s failed, err: %v", key, err)
+			writeError(w, req, errFunc, http.StatusBadRequest, common.CCErrCommHTTPReadBodyFailed)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(req, body)
+		key = scopedKey(req.Header, key)
+
+		s, window := current()
+		recorded, err := acquire(req, s, key)
+		if err != nil {
+			blog.Errorf("acquire idempotency key %s failed, err: %v, rid: %s", key, err, util.GetHTTPCCRequestID(req.Header))
+			writeError(w, req, errFunc, http.StatusConflict, common.CCErrCommIdempotencyKeyInProgress, key)
+			return
+		}
+		if recorded != nil {
+			if recorded.Fingerprint != fingerprint {
+				writeError(w, req, errFunc, http.StatusUnprocessableEntity, common.CCErrCommIdempotencyKeyReused, key)
+				return
+			}
+			blog.V(4).Infof("replay the response of idempotency key %s, rid: %s", key, util.GetHTTPCCRequestID(req.Header))
+			replay(w, recorded)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w}
+		handled := false
+		defer func() {
+			// the request is not handled completely, so that the retry should handle it again
+			if !handled {
+				if err := s.Release(key); err != nil {
+					blog.Errorf("release idempotency key %s failed, err: %v", key, err)
+				}
+			}
+		}()
+		next.ServeHTTP(rec, req)
+		handled = true
+
+		if rec.overflow {
+			blog.Warnf("the response of idempotency key %s is too large to be recorded, rid: %s", key, util.GetHTTPCCRequestID(req.Header))
+			handled = false
+			return
+		}
+		resp := &Response{
+			Fingerprint: fingerprint,
+			StatusCode:  rec.statusCode(),
+			Header:      copyHeader(w.Header()),
+			Body:        rec.body.Bytes(),
+		}
+		if err := s.Save(key, resp, window); err != nil {
+			blog.Errorf("record the response of idempotency key %s failed, err: %v", key, err)
+			handled = false
+		}
+	})
+}
+
+// acquire reserve the key for the request, or returns the recorded response of the key. if the key is reserved
+// by the request in progress, it waits until the response is recorded or the reservation is released.
+func acquire(req *http.Request, s Store, key string) (*Response, error) {
+	deadline := time.Now().Add(maxWait)
+	for {
+		reserved, err := s.Reserve(key, reserveTTL)
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		for {
+			resp, exist, err := s.Get(key)
+			if err != nil {
+				return nil, err
+			}
+			if resp != nil {
+				return resp, nil
+			}
+			if !exist {
+				// the reservation is released, try to reserve it again
+				break
+			}
+			if time.Now().After(deadline) {
+				return nil, fmt.Errorf("the request is still in progress after %v", maxWait)
+			}
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(waitInterval):
+			}
+		}
+	}
+}
+
+// scopedKey prefix the key with the supplier account and the user of the request
+func scopedKey(header http.Header, key string) string {
+	return util.GetOwnerID(header) + ":" + util.GetUser(header) + ":" + key
+}
+
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write([]byte(util.GetOwnerID(req.Header) + "\n" + util.GetUser(req.Header) + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, resp *Response) {
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.Header().Set(common.BKHTTPIdempotentReplayed, "true")
+	w.WriteHeader(resp.StatusCode)
+	w.Write(resp.Body)
+}
+
+func writeError(w http.ResponseWriter, req *http.Request, errFunc func() errors.CCErrorIf, status int, code int, args ...interface{}) {
+	defErr := errFunc().CreateDefaultCCErrorIf(util.GetActionLanguageByHTTPHeader(req.Header))
+	body, _ := json.Marshal(metadata.BaseResp{
+		Result: false,
+		Code:   code,
+		ErrMsg: defErr.Errorf(code, args...).Error(),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func copyHeader(header http.Header) http.Header {
+	copied := make(http.Header, len(header))
+	for key, values := range header {
+		copied[key] = append([]string(nil), values...)
+	}
+	return copied
+}
+
+// recorder tee the response to the client and the buffer, the response larger than maxRecordSize
+// is not buffered any more, because it can not be recorded.
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if !r.overflow {
+		if r.body.Len()+len(data) > maxRecordSize {
+			r.overflow = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(data)
+		}
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *recorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
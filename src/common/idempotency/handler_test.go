@@ -0,0 +1,125 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+func TestHandler(t *testing.T) {
+	SetStore(NewMemoryStore(), DefaultWindow)
+	errFunc := func() errors.CCErrorIf {
+		return errors.NewFromCtx(errors.EmptyErrorsSetting)
+	}
+
+	handled := 0
+	handler := Handler(errFunc, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handled++
+		if req.Header.Get(common.BKHTTPIdempotencyKey) != "" {
+			t.Errorf("the idempotency key should not be forwarded to the handler")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"data":{"id":1}}`))
+	}))
+
+	do := func(method, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v3/create/object", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(common.BKHTTPIdempotencyKey, key)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	first := do(http.MethodPost, "key-1", `{"name":"a"}`)
+	retry := do(http.MethodPost, "key-1", `{"name":"a"}`)
+	if handled != 1 {
+		t.Fatalf("the retry should be replayed, but the request is handled %d times", handled)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("unexpected replayed response: %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(common.BKHTTPIdempotentReplayed) != "true" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected replayed header: %v", retry.Header())
+	}
+
+	if resp := do(http.MethodPost, "key-1", `{"name":"b"}`); resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("the key used by another request should be rejected, got %d", resp.Code)
+	}
+
+	other := httptest.NewRequest(http.MethodPost, "/api/v3/create/object", strings.NewReader(`{"name":"a"}`))
+	other.Header.Set(common.BKHTTPIdempotencyKey, "key-1")
+	other.Header.Set(common.BKHTTPHeaderUser, "guest")
+	handler.ServeHTTP(httptest.NewRecorder(), other)
+	if handled != 2 {
+		t.Fatalf("the same key of another user should be handled, handled %d times", handled)
+	}
+
+	do(http.MethodPost, "key-2", `{"name":"a"}`)
+	do(http.MethodPost, "", `{"name":"a"}`)
+	do(http.MethodPost, "", `{"name":"a"}`)
+	if handled != 5 {
+		t.Fatalf("the requests with the other key or without key should be handled, handled %d times", handled)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	if ok, _ := store.Reserve("key", reserveTTL); !ok {
+		t.Fatalf("reserve key failed")
+	}
+	if ok, _ := store.Reserve("key", reserveTTL); ok {
+		t.Fatalf("the reserved key should not be reserved again")
+	}
+	if resp, exist, _ := store.Get("key"); resp != nil || !exist {
+		t.Fatalf("the key should be in progress, got %v %v", resp, exist)
+	}
+	store.Release("key")
+	if _, exist, _ := store.Get("key"); exist {
+		t.Fatalf("the released key should not exist")
+	}
+	store.Save("key", &Response{StatusCode: http.StatusOK}, 0)
+	if _, exist, _ := store.Get("key"); exist {
+		t.Fatalf("the expired key should not exist")
+	}
+}
+
+func TestMemoryStoreLimit(t *testing.T) {
+	store := NewMemoryStore()
+	body := make([]byte, maxRecordSize)
+	for i := 0; i < memoryMaxBytes/maxRecordSize+1; i++ {
+		store.Save(strconv.Itoa(i), &Response{Body: body}, DefaultWindow)
+	}
+	if _, exist, _ := store.Get("0"); exist {
+		t.Fatalf("the oldest response should be removed when the size exceeds the limit")
+	}
+	if _, exist, _ := store.Get("1"); !exist {
+		t.Fatalf("the responses in the limit should be kept")
+	}
+	if store.size > memoryMaxBytes {
+		t.Fatalf("the size %d exceeds the limit", store.size)
+	}
+	if expire := store.entries["1"].Value.(*memoryEntry).expire; time.Until(expire) > memoryMaxWindow {
+		t.Fatalf("the response should not be recorded longer than %v", memoryMaxWindow)
+	}
+}
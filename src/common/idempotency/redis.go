@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"encoding/json"
+	"time"
+
+	redis "gopkg.in/redis.v5"
+)
+
+const redisKeyPrefix = "cc:idempotency:"
+
+// redisInProgress the value of the reserved key whose request is still in progress
+const redisInProgress = "-"
+
+// RedisStore record the responses in redis, so that the retries sent to any instance of the service are replayed
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore create a redis store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve mark the key as in progress for the ttl
+func (r *RedisStore) Reserve(key string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(redisKeyPrefix+key, redisInProgress, ttl).Result()
+}
+
+// Get returns the recorded response of the key
+func (r *RedisStore) Get(key string) (*Response, bool, error) {
+	value, err := r.client.Get(redisKeyPrefix + key).Result()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if value == redisInProgress {
+		return nil, true, nil
+	}
+	resp := new(Response)
+	if err := json.Unmarshal([]byte(value), resp); err != nil {
+		return nil, false, err
+	}
+	return resp, true, nil
+}
+
+// Save record the response of the key for the window
+func (r *RedisStore) Save(key string, resp *Response, window time.Duration) error {
+	value, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(redisKeyPrefix+key, value, window).Err()
+}
+
+// Release remove the reservation of the key
+func (r *RedisStore) Release(key string) error {
+	return r.client.Del(redisKeyPrefix + key).Err()
+}
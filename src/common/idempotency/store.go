@@ -0,0 +1,168 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response the recorded response of the request with an idempotency key
+type Response struct {
+	// Fingerprint identify the request, a key can only be used by the same request
+	Fingerprint string      `json:"fingerprint"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// Store record the responses of the idempotency keys
+type Store interface {
+	// Reserve mark the key as in progress for the ttl, it returns false if the key is already reserved or recorded
+	Reserve(key string, ttl time.Duration) (bool, error)
+	// Get returns the recorded response of the key, the response is nil if the request is still in progress,
+	// exist is false if the key is neither reserved nor recorded.
+	Get(key string) (resp *Response, exist bool, err error)
+	// Save record the response of the key for the window
+	Save(key string, resp *Response, window time.Duration) error
+	// Release remove the reservation of the key, so that the request can be handled again
+	Release(key string) error
+}
+
+const (
+	// memoryMaxBytes the max size of the response bodies recorded by the memory store,
+	// the oldest responses are removed when it's exceeded
+	memoryMaxBytes = 64 << 20
+	// memoryMaxWindow the max time a response is recorded by the memory store, which only
+	// serves the retries sent to the same instance, and they are sent soon after the write.
+	memoryMaxWindow = 10 * time.Minute
+)
+
+type memoryEntry struct {
+	key    string
+	resp   *Response
+	expire time.Time
+}
+
+func (e *memoryEntry) size() int {
+	if e.resp == nil {
+		return 0
+	}
+	return len(e.resp.Body)
+}
+
+// MemoryStore record the responses in the memory of the process, the retries sent to
+// the other instances of the service are not replayed, use RedisStore for that case.
+// the responses are recorded for memoryMaxWindow at most, and memoryMaxBytes in total.
+type MemoryStore struct {
+	sync.Mutex
+	entries map[string]*list.Element
+	// order the entries in the order they are saved, the front one is the oldest
+	order *list.List
+	size  int
+}
+
+// NewMemoryStore create a memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Reserve mark the key as in progress for the ttl
+func (m *MemoryStore) Reserve(key string, ttl time.Duration) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	now := time.Now()
+	if entry := m.get(key, now); entry != nil {
+		return false, nil
+	}
+	m.set(&memoryEntry{key: key, expire: now.Add(ttl)}, now)
+	return true, nil
+}
+
+// Get returns the recorded response of the key
+func (m *MemoryStore) Get(key string) (*Response, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	entry := m.get(key, time.Now())
+	if entry == nil {
+		return nil, false, nil
+	}
+	return entry.resp, true, nil
+}
+
+// Save record the response of the key for the window
+func (m *MemoryStore) Save(key string, resp *Response, window time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+	if window > memoryMaxWindow {
+		window = memoryMaxWindow
+	}
+	now := time.Now()
+	m.set(&memoryEntry{key: key, resp: resp, expire: now.Add(window)}, now)
+	return nil
+}
+
+// Release remove the reservation of the key
+func (m *MemoryStore) Release(key string) error {
+	m.Lock()
+	defer m.Unlock()
+	m.remove(key)
+	return nil
+}
+
+// get returns the entry of the key if it's not expired, the caller should hold the lock
+func (m *MemoryStore) get(key string, now time.Time) *memoryEntry {
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !now.Before(entry.expire) {
+		m.remove(key)
+		return nil
+	}
+	return entry
+}
+
+// set replace the entry of the key, and remove the expired and the oldest entries to keep
+// the size in the limit, the caller should hold the lock
+func (m *MemoryStore) set(entry *memoryEntry, now time.Time) {
+	m.remove(entry.key)
+	m.entries[entry.key] = m.order.PushBack(entry)
+	m.size += entry.size()
+
+	for elem := m.order.Front(); elem != nil; {
+		oldest := elem.Value.(*memoryEntry)
+		elem = elem.Next()
+		if now.Before(oldest.expire) && m.size <= memoryMaxBytes {
+			break
+		}
+		m.remove(oldest.key)
+	}
+}
+
+// remove delete the entry of the key, the caller should hold the lock
+func (m *MemoryStore) remove(key string) {
+	elem, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	m.size -= elem.Value.(*memoryEntry).size()
+	m.order.Remove(elem)
+	delete(m.entries, key)
+}
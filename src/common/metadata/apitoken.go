@@ -0,0 +1,65 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+// APITokenScope limit what the requests authenticated by the personal api token can do,
+// an empty business or object list means no limitation on it
+type APITokenScope struct {
+	ReadOnly  bool     `json:"read_only" bson:"read_only"`
+	BizIDs    []int64  `json:"bk_biz_ids" bson:"bk_biz_ids"`
+	ObjectIDs []string `json:"bk_obj_ids" bson:"bk_obj_ids"`
+}
+
+// APIToken the personal api token of the user, only the hash of the token is stored
+type APIToken struct {
+	ID           int64         `json:"id" bson:"id"`
+	Name         string        `json:"name" bson:"name"`
+	User         string        `json:"bk_user" bson:"bk_user"`
+	OwnerID      string        `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	TokenHash    string        `json:"token_hash,omitempty" bson:"token_hash"`
+	Prefix       string        `json:"prefix" bson:"prefix"`
+	Scope        APITokenScope `json:"scope" bson:"scope"`
+	Revoked      bool          `json:"revoked" bson:"revoked"`
+	ExpireTime   Time          `json:"expire_time" bson:"expire_time"`
+	CreateTime   Time          `json:"create_time" bson:"create_time"`
+	LastUsedTime *Time         `json:"last_used_time" bson:"last_used_time"`
+	RevokeTime   *Time         `json:"revoke_time" bson:"revoke_time"`
+}
+
+// CreateAPITokenInput the parameters to create the personal api token
+type CreateAPITokenInput struct {
+	Name       string        `json:"name"`
+	ExpireDays int64         `json:"expire_days"`
+	Scope      APITokenScope `json:"scope"`
+}
+
+// CreateAPITokenResult the created token, the token is returned only once
+type CreateAPITokenResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		APIToken `json:",inline"`
+		Token    string `json:"token"`
+	} `json:"data"`
+}
+
+// APITokenResult the result of one personal api token
+type APITokenResult struct {
+	BaseResp `json:",inline"`
+	Data     APIToken `json:"data"`
+}
+
+// SearchAPITokenResult the result of searching personal api tokens
+type SearchAPITokenResult struct {
+	BaseResp `json:",inline"`
+	Data     []APIToken `json:"data"`
+}
@@ -87,6 +87,17 @@ type CreateManyModelInstance struct {
 type SetModelInstance CreateModelInstance
 type SetManyModelInstance CreateManyModelInstance
 
+// ValidateModelInstance the instance data to be validated, InstID is zero when the data would create a new instance
+type ValidateModelInstance struct {
+	InstID uint64        `json:"inst_id"`
+	Data   mapstr.MapStr `json:"data"`
+}
+
+// ValidateManyModelInstance validate many instances without writing them
+type ValidateManyModelInstance struct {
+	Datas []ValidateModelInstance `json:"datas"`
+}
+
 type CreateAssociationKind struct {
 	Data AssociationKind `json:"data"`
 }
@@ -16,6 +16,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"configcenter/src/common/mapstr"
 )
@@ -80,6 +81,8 @@ type SynchronizeParameter struct {
 	InfoArray       []*SynchronizeItem `json:"instance_info_array"`
 	Version         int64              `json:"version"`
 	SynchronizeFlag string             `json:"synchronize_flag"`
+	// ConflictPolicy how to handle the instance edited on both sides, default SynchronizeConflictPolicySource
+	ConflictPolicy SynchronizeConflictPolicy `json:"conflict_policy"`
 }
 
 // SynchronizeItem synchronize data information
@@ -137,3 +140,135 @@ func (s *SynchronizeClearDataParameter) Legality(key string) bool {
 func (s *SynchronizeClearDataParameter) signContext(key string) string {
 	return fmt.Sprintf("key-%s-%d-%d", key, s.SynchronizeFlag, s.Tamestamp, s.Version)
 }
+
+// SynchronizeConflictPolicy the policy of the instance edited on both sides
+type SynchronizeConflictPolicy string
+
+const (
+	// SynchronizeConflictPolicySource the source data overwrite the target data
+	SynchronizeConflictPolicySource SynchronizeConflictPolicy = "source"
+	// SynchronizeConflictPolicyTarget the target data is kept
+	SynchronizeConflictPolicyTarget SynchronizeConflictPolicy = "target"
+	// SynchronizeConflictPolicyNewest the data with the newer last_time is kept
+	SynchronizeConflictPolicyNewest SynchronizeConflictPolicy = "newest"
+	// SynchronizeConflictPolicyManual the target data is kept until the conflict is resolved manually
+	SynchronizeConflictPolicyManual SynchronizeConflictPolicy = "manual"
+)
+
+// Valid judge the policy is valid
+func (p SynchronizeConflictPolicy) Valid() bool {
+	switch p {
+	case SynchronizeConflictPolicySource, SynchronizeConflictPolicyTarget,
+		SynchronizeConflictPolicyNewest, SynchronizeConflictPolicyManual:
+		return true
+	}
+	return false
+}
+
+// SynchronizeConflictStatus the status of the synchronize conflict
+type SynchronizeConflictStatus string
+
+const (
+	// SynchronizeConflictStatusPending the conflict waits to be resolved manually
+	SynchronizeConflictStatusPending SynchronizeConflictStatus = "pending"
+	// SynchronizeConflictStatusResolved the conflict has been resolved
+	SynchronizeConflictStatusResolved SynchronizeConflictStatus = "resolved"
+)
+
+// SynchronizeConflict the instance edited on both sides, keep the source and the target version
+type SynchronizeConflict struct {
+	ID              int64                     `json:"id" bson:"id"`
+	SynchronizeFlag string                    `json:"synchronize_flag" bson:"synchronize_flag"`
+	DataClassify    string                    `json:"data_classify" bson:"data_classify"`
+	InstID          int64                     `json:"inst_id" bson:"inst_id"`
+	Source          mapstr.MapStr             `json:"source" bson:"source"`
+	Target          mapstr.MapStr             `json:"target" bson:"target"`
+	Policy          SynchronizeConflictPolicy `json:"policy" bson:"policy"`
+	Status          SynchronizeConflictStatus `json:"status" bson:"status"`
+	// Resolution which side is kept, source or target
+	Resolution SynchronizeConflictPolicy `json:"resolution" bson:"resolution"`
+	Resolver   string                    `json:"resolver" bson:"resolver"`
+	CreateTime time.Time                 `json:"create_time" bson:"create_time"`
+	LastTime   time.Time                 `json:"last_time" bson:"last_time"`
+}
+
+// SynchronizeConflictSearchParameter search synchronize conflict http request parameter
+type SynchronizeConflictSearchParameter struct {
+	SynchronizeFlag string                    `json:"synchronize_flag"`
+	DataClassify    string                    `json:"data_classify"`
+	InstID          int64                     `json:"inst_id"`
+	Status          SynchronizeConflictStatus `json:"status"`
+	Page            BasePage                  `json:"page"`
+}
+
+// SynchronizeConflictResolveParameter resolve synchronize conflict http request parameter
+type SynchronizeConflictResolveParameter struct {
+	// Resolution which side is kept, source or target
+	Resolution SynchronizeConflictPolicy `json:"resolution"`
+}
+
+// SynchronizeConflictSearchResult search synchronize conflict result
+type SynchronizeConflictSearchResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		Count uint64                `json:"count"`
+		Info  []SynchronizeConflict `json:"info"`
+	} `json:"data"`
+}
+
+// SynchronizeItemState the state of the synchronize config item
+type SynchronizeItemState string
+
+const (
+	// SynchronizeItemStateRunning the config item is synchronizing
+	SynchronizeItemStateRunning SynchronizeItemState = "running"
+	// SynchronizeItemStateIdle the config item waits for the next synchronization
+	SynchronizeItemStateIdle SynchronizeItemState = "idle"
+	// SynchronizeItemStateFailed the last synchronization of the config item failed
+	SynchronizeItemStateFailed SynchronizeItemState = "failed"
+)
+
+// SynchronizeRunStatus the result of a synchronization
+type SynchronizeRunStatus string
+
+const (
+	// SynchronizeRunStatusRunning the synchronization is running
+	SynchronizeRunStatusRunning SynchronizeRunStatus = "running"
+	// SynchronizeRunStatusSuccess all the data is synchronized, some data may have exceptions
+	SynchronizeRunStatusSuccess SynchronizeRunStatus = "success"
+	// SynchronizeRunStatusFailed the synchronization is interrupted by an error
+	SynchronizeRunStatusFailed SynchronizeRunStatus = "failed"
+	// SynchronizeRunStatusCanceled the synchronization is canceled by the user
+	SynchronizeRunStatusCanceled SynchronizeRunStatus = "canceled"
+)
+
+// SynchronizeRunRecord a synchronization of the config item
+type SynchronizeRunRecord struct {
+	Version int64 `json:"version"`
+	// Trigger timer or manual
+	Trigger     string               `json:"trigger"`
+	Operator    string               `json:"operator"`
+	Incremental bool                 `json:"incremental"`
+	Status      SynchronizeRunStatus `json:"status"`
+	StartTime   time.Time            `json:"start_time"`
+	EndTime     *time.Time           `json:"end_time,omitempty"`
+	// Counts synchronized data count, map[model|instance|association]map[data classify]count
+	Counts map[string]map[string]int64 `json:"counts"`
+	// ExceptionCounts the exception count of model, instance, association and clear data
+	ExceptionCounts map[string]int64 `json:"exception_counts"`
+	// Exceptions the exceptions of the synchronization, only the first part is kept
+	Exceptions map[string][]ExceptionResult `json:"exceptions"`
+	Error      string                       `json:"error"`
+}
+
+// SynchronizeItemStatus the status of the synchronize config item.
+// the synchronization runs on the master synchronize server, the run history is kept in the local files of it,
+// the other servers and a new master only return the history they have run.
+type SynchronizeItemStatus struct {
+	Name    string                 `json:"name"`
+	State   SynchronizeItemState   `json:"state"`
+	Current *SynchronizeRunRecord  `json:"current,omitempty"`
+	History []SynchronizeRunRecord `json:"history"`
+	// IsMaster whether the status is returned by the master synchronize server
+	IsMaster bool `json:"is_master"`
+}
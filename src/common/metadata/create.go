@@ -23,3 +23,9 @@ type CreatedOneOptionResult struct {
 	BaseResp `json:",inline"`
 	Data     CreateOneDataResult `json:"data"`
 }
+
+// ValidatedManyOptionResult validate many api http response return this result struct
+type ValidatedManyOptionResult struct {
+	BaseResp `json:",inline"`
+	Data     ValidatedManyDataResult `json:"data"`
+}
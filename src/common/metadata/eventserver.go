@@ -162,6 +162,8 @@ const (
 	EventTypeRelation           = "relation"
 	EventTypeAssociation        = "association"
 	EventTypeResourcePoolModule = "resource"
+	// EventTypeModel the changes of the model definitions
+	EventTypeModel = "model"
 )
 
 // Event object type
@@ -170,6 +172,16 @@ const (
 	EventObjTypeModuleTransfer = "moduletransfer"
 )
 
+// Event object types of the model events
+const (
+	EventObjTypeModel               = "model"
+	EventObjTypeModelClassification = "modelclassification"
+	EventObjTypeModelAttributeGroup = "modelattributegroup"
+	EventObjTypeModelAttribute      = "modelattribute"
+	EventObjTypeModelAttrUnique     = "modelattrunique"
+	EventObjTypeModelAssociation    = "modelassociation"
+)
+
 // ConfirmMode define
 type ConfirmMode string
 
@@ -55,6 +55,11 @@ type HostList struct {
 	HostInfo      map[int64]map[string]interface{} `json:"host_info"`
 	SupplierID    int64                            `json:"bk_supplier_id"`
 	InputType     HostInputType                    `json:"input_type"`
+	// ValidateOnly only return the preview of the import, nothing is written
+	ValidateOnly bool `json:"validate_only"`
+	// AllOrNothing validate all the hosts before writing, nothing is written if any host is invalid,
+	// and the written hosts are rolled back if any host fails while writing.
+	AllOrNothing bool `json:"all_or_nothing"`
 }
 
 type AddHostFromAgentHostList struct {
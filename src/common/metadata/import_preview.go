@@ -0,0 +1,170 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+const (
+	// ImportActionCreate the row would create a new instance
+	ImportActionCreate = "create"
+	// ImportActionUpdate the row would update an existing instance
+	ImportActionUpdate = "update"
+	// ImportActionUnchanged the row is the same as the existing instance
+	ImportActionUnchanged = "unchanged"
+	// ImportActionError the row can not be imported
+	ImportActionError = "error"
+)
+
+// ImportFieldDiff the difference of a field between the existing instance and the imported row
+type ImportFieldDiff struct {
+	PropertyID string      `json:"bk_property_id"`
+	Before     interface{} `json:"before"`
+	After      interface{} `json:"after"`
+}
+
+// ImportPreviewRow the preview result of an imported row
+type ImportPreviewRow struct {
+	Row    int64             `json:"row"`
+	Action string            `json:"action"`
+	InstID int64             `json:"inst_id,omitempty"`
+	Diff   []ImportFieldDiff `json:"diff,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// ImportPreview the preview of an import, nothing is written when it is built
+type ImportPreview struct {
+	Create    int                `json:"create"`
+	Update    int                `json:"update"`
+	Unchanged int                `json:"unchanged"`
+	Error     int                `json:"error"`
+	Rows      []ImportPreviewRow `json:"rows"`
+}
+
+// Add append a row to the preview and count its action
+func (p *ImportPreview) Add(row ImportPreviewRow) {
+	switch row.Action {
+	case ImportActionCreate:
+		p.Create++
+	case ImportActionUpdate:
+		p.Update++
+	case ImportActionUnchanged:
+		p.Unchanged++
+	case ImportActionError:
+		p.Error++
+	}
+	p.Rows = append(p.Rows, row)
+}
+
+// SetError mark the row of the preview as error, the first error of a row is kept
+func (p *ImportPreview) SetError(row int64, errMsg string) {
+	for idx := range p.Rows {
+		if p.Rows[idx].Row != row || ImportActionError == p.Rows[idx].Action {
+			continue
+		}
+		p.decrease(p.Rows[idx].Action)
+		p.Rows[idx].Action = ImportActionError
+		p.Rows[idx].Error = errMsg
+		p.Error++
+		return
+	}
+}
+
+func (p *ImportPreview) decrease(action string) {
+	switch action {
+	case ImportActionCreate:
+		p.Create--
+	case ImportActionUpdate:
+		p.Update--
+	case ImportActionUnchanged:
+		p.Unchanged--
+	}
+}
+
+// ErrorMessages return the error message of the error rows
+func (p *ImportPreview) ErrorMessages() []string {
+	errMsgs := make([]string, 0)
+	for _, row := range p.Rows {
+		if ImportActionError == row.Action {
+			errMsgs = append(errMsgs, row.Error)
+		}
+	}
+	return errMsgs
+}
+
+// DiffImportData return the fields of the imported data whose value is different from the origin,
+// the result is sorted by the property id
+func DiffImportData(origin, data mapstr.MapStr, ignoreKeys ...string) []ImportFieldDiff {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if util.InStrArr(ignoreKeys, key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	diffs := make([]ImportFieldDiff, 0)
+	for _, key := range keys {
+		before := origin[key]
+		if isSameImportValue(before, data[key]) {
+			continue
+		}
+		diffs = append(diffs, ImportFieldDiff{PropertyID: key, Before: before, After: data[key]})
+	}
+	return diffs
+}
+
+// isSameImportValue compare the value read from the import file with the stored value,
+// the numbers are compared by value because the file and the db may use different types
+func isSameImportValue(before, after interface{}) bool {
+	if isEmptyImportValue(before) && isEmptyImportValue(after) {
+		return true
+	}
+	if isImportNumber(before) || isImportNumber(after) {
+		beforeVal, beforeErr := util.GetFloat64ByInterface(before)
+		afterVal, afterErr := util.GetFloat64ByInterface(after)
+		if nil == beforeErr && nil == afterErr {
+			return beforeVal == afterVal
+		}
+	}
+	if reflect.DeepEqual(before, after) {
+		return true
+	}
+	return fmt.Sprint(before) == fmt.Sprint(after)
+}
+
+func isEmptyImportValue(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case string:
+		return "" == v
+	}
+	return false
+}
+
+func isImportNumber(val interface{}) bool {
+	switch val.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
+	}
+	return false
+}
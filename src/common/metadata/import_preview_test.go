@@ -0,0 +1,65 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+)
+
+func TestDiffImportData(t *testing.T) {
+	origin := mapstr.MapStr{
+		"bk_inst_name": "inst1",
+		"count":        json.Number("3"),
+		"comment":      nil,
+		"enabled":      true,
+		"import_from":  "1",
+	}
+	tests := []struct {
+		name string
+		data mapstr.MapStr
+		want []ImportFieldDiff
+	}{
+		{"unchanged", mapstr.MapStr{"bk_inst_name": "inst1", "count": int64(3), "comment": "", "enabled": true, "import_from": "2"}, []ImportFieldDiff{}},
+		{"changed", mapstr.MapStr{"bk_inst_name": "inst1", "count": float64(4), "comment": "c"}, []ImportFieldDiff{
+			{PropertyID: "comment", Before: nil, After: "c"},
+			{PropertyID: "count", Before: json.Number("3"), After: float64(4)},
+		}},
+		{"new field", mapstr.MapStr{"owner": "admin"}, []ImportFieldDiff{{PropertyID: "owner", Before: nil, After: "admin"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffImportData(origin, tt.data, "import_from"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffImportData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportPreviewSetError(t *testing.T) {
+	preview := &ImportPreview{}
+	preview.Add(ImportPreviewRow{Row: 1, Action: ImportActionCreate})
+	preview.Add(ImportPreviewRow{Row: 2, Action: ImportActionUpdate})
+	preview.SetError(2, "row 2 error")
+	preview.SetError(2, "row 2 another error")
+
+	if preview.Create != 1 || preview.Update != 0 || preview.Error != 1 {
+		t.Errorf("unexpected preview count %+v", preview)
+	}
+	if msgs := preview.ErrorMessages(); !reflect.DeepEqual(msgs, []string{"row 2 error"}) {
+		t.Errorf("ErrorMessages() = %v", msgs)
+	}
+}
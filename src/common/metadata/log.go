@@ -28,6 +28,9 @@ type OperationLog struct {
 	ExtInfo       string      `bson:"ext_info"            json:"ext_info"`
 	CreateTime    time.Time   `bson:"op_time"         json:"op_time"`
 	InstID        int64       `bson:"inst_id"             json:"inst_id"`
+	Seq           int64       `bson:"seq"                 json:"seq"`
+	PrevHash      string      `bson:"prev_hash"           json:"prev_hash"`
+	Hash          string      `bson:"hash"                json:"hash"`
 }
 
 // TableName return the table name
@@ -35,6 +38,76 @@ func (OperationLog) TableName() string {
 	return "cc_OperationLog"
 }
 
+// AuditChainCheckpoint a signed snapshot of the audit chain head of one supplier account
+type AuditChainCheckpoint struct {
+	OwnerID    string    `bson:"bk_supplier_account" json:"bk_supplier_account"`
+	Seq        int64     `bson:"seq"                 json:"seq"`
+	Hash       string    `bson:"hash"                json:"hash"`
+	Signature  string    `bson:"signature"           json:"signature"`
+	CreateTime time.Time `bson:"create_time"         json:"create_time"`
+}
+
+// AuditChainBrokenLink describe the first record which break the audit chain
+type AuditChainBrokenLink struct {
+	Seq      int64  `json:"seq"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// AuditChainVerifyResult the result of walking through the audit chain of one supplier account
+type AuditChainVerifyResult struct {
+	OwnerID     string                `json:"bk_supplier_account"`
+	Intact      bool                  `json:"intact"`
+	Checked     int64                 `json:"checked"`
+	HeadSeq     int64                 `json:"head_seq"`
+	Checkpoints int64                 `json:"checkpoints"`
+	BrokenLink  *AuditChainBrokenLink `json:"broken_link"`
+}
+
+// AuditChainVerifyResp the response of audit chain verification
+type AuditChainVerifyResp struct {
+	BaseResp `json:",inline"`
+	Data     AuditChainVerifyResult `json:"data"`
+}
+
+// AuditArchivedLog the stub of an archived operation log, it keeps the audit chain verifiable
+// after the record has been moved into the archive file
+type AuditArchivedLog struct {
+	OwnerID    string    `bson:"bk_supplier_account" json:"bk_supplier_account"`
+	Seq        int64     `bson:"seq"                 json:"seq"`
+	PrevHash   string    `bson:"prev_hash"           json:"prev_hash"`
+	Hash       string    `bson:"hash"                json:"hash"`
+	CreateTime time.Time `bson:"op_time"             json:"op_time"`
+	Archive    string    `bson:"archive"             json:"archive"`
+}
+
+// AuditArchiveResult the result of archiving or re-importing operation logs
+type AuditArchiveResult struct {
+	Files    []string `json:"files"`
+	Archived int64    `json:"archived"`
+	Imported int64    `json:"imported"`
+	Skipped  int64    `json:"skipped"`
+}
+
+// the formats supported by the audit log export
+const (
+	AuditExportFormatCSV    = "csv"
+	AuditExportFormatNDJSON = "ndjson"
+)
+
+// AuditExportOption the filter of exporting operation logs
+type AuditExportOption struct {
+	Format    string `json:"format"`
+	StartTime *Time  `json:"start_time"`
+	EndTime   *Time  `json:"end_time"`
+	User      string `json:"operator"`
+	OpTarget  string `json:"op_target"`
+	InstID    int64  `json:"inst_id"`
+	OpType    int    `json:"op_type"`
+	BizID     int64  `json:"bk_biz_id"`
+}
+
 type Content struct {
 	PreData interface{} `json:"pre_data"`
 	CurData interface{} `json:"cur_data"`
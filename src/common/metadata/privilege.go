@@ -13,9 +13,97 @@
 package metadata
 
 import (
+	"sort"
+	"strings"
+
 	"configcenter/src/common/mapstr"
 )
 
+const (
+	// AttributePrivilegeWrite the attribute can be read and written
+	AttributePrivilegeWrite = "write"
+	// AttributePrivilegeRead the attribute can be read but not written
+	AttributePrivilegeRead = "read"
+	// AttributePrivilegeMask the attribute value is masked and can not be written
+	AttributePrivilegeMask = "mask"
+
+	// AttributeMaskedValue the value returned instead of the masked attribute value
+	AttributeMaskedValue = "******"
+)
+
+// AttributePermission the attribute privileges of a user, bk_obj_id -> bk_property_id -> read|mask,
+// the attributes which are not restricted are not included
+type AttributePermission map[string]map[string]string
+
+// Mask replace the values of the masked attributes
+func (a AttributePermission) Mask(objID string, data mapstr.MapStr) {
+	for propertyID, level := range a[objID] {
+		if AttributePrivilegeMask != level {
+			continue
+		}
+		if _, ok := data[propertyID]; ok {
+			data[propertyID] = AttributeMaskedValue
+		}
+	}
+}
+
+// Unwritable return the attributes in the data which can not be written
+func (a AttributePermission) Unwritable(objID string, data mapstr.MapStr) []string {
+	var result []string
+	for propertyID := range a[objID] {
+		if _, ok := data[propertyID]; ok {
+			result = append(result, propertyID)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Unreadable return the masked attributes which the query condition or the sort refers to,
+// the sort is like "-bk_inst_name,bk_inst_id"
+func (a AttributePermission) Unreadable(objID string, cond interface{}, sortStr string) []string {
+	refers := make(map[string]bool)
+	ConditionFields(cond, refers)
+	for _, item := range strings.Split(sortStr, ",") {
+		refers[strings.TrimPrefix(strings.TrimSpace(item), "-")] = true
+	}
+
+	var result []string
+	for propertyID, level := range a[objID] {
+		if AttributePrivilegeMask != level {
+			continue
+		}
+		if refers[propertyID] {
+			result = append(result, propertyID)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ConditionFields collect the fields the condition refers to, including the ones nested in the operators such as $or
+func ConditionFields(cond interface{}, fields map[string]bool) {
+	switch cond := cond.(type) {
+	case mapstr.MapStr:
+		ConditionFields(map[string]interface{}(cond), fields)
+	case map[string]interface{}:
+		for key, value := range cond {
+			if !strings.HasPrefix(key, "$") {
+				fields[key] = true
+			}
+			ConditionFields(value, fields)
+		}
+	case []interface{}:
+		for _, item := range cond {
+			ConditionFields(item, fields)
+		}
+	case []mapstr.MapStr:
+		for _, item := range cond {
+			ConditionFields(item, fields)
+		}
+	}
+}
+
 type PermissionSystemResponse struct {
 	BaseResp `json:",inline"`
 	Data     mapstr.MapStr `json:"data"`
@@ -27,14 +115,16 @@ type PermissionGroupListResult struct {
 }
 
 type Gprivilege struct {
-	ModelConfig    map[string]map[string][]string `json:"model_config" bson:"model_config"`
-	SysConfig      SysConfigStruct                `json:"sys_config,omitempty" bson:"sys_config"`
-	IsHostCrossBiz bool                           `json:"is_host_cross_biz" bson:"is_host_cross_biz"`
+	ModelConfig     map[string]map[string][]string `json:"model_config" bson:"model_config"`
+	SysConfig       SysConfigStruct                `json:"sys_config,omitempty" bson:"sys_config"`
+	IsHostCrossBiz  bool                           `json:"is_host_cross_biz" bson:"is_host_cross_biz"`
+	AttributeConfig AttributePermission            `json:"attribute_config,omitempty" bson:"attribute_config"`
 }
 
 type Privilege struct {
-	ModelConfig map[string]map[string][]string `json:"model_config,omitempty" bson:"model_config"`
-	SysConfig   *SysConfigStruct               `json:"sys_config,omitempty" bson:"sys_config"`
+	ModelConfig     map[string]map[string][]string `json:"model_config,omitempty" bson:"model_config"`
+	SysConfig       *SysConfigStruct               `json:"sys_config,omitempty" bson:"sys_config"`
+	AttributeConfig map[string]map[string]string   `json:"attribute_config,omitempty" bson:"attribute_config"`
 }
 
 type SysConfigStruct struct {
@@ -110,6 +200,8 @@ type PrivilegeUserGroup struct {
 	GroupID         string        `field:"group_id" json:"group_id" bson:"bk_supplier_account"`
 	ModelConfig     mapstr.MapStr `field:"model_config" json:"model_config" bson:"model_config"`
 	SystemConfig    mapstr.MapStr `field:"sys_config" json:"sys_config" bson:"sys_config"`
+	// AttributeConfig the attribute privileges, bk_obj_id -> bk_property_id -> write|read|mask
+	AttributeConfig mapstr.MapStr `field:"attribute_config" json:"attribute_config,omitempty" bson:"attribute_config"`
 }
 
 // Parse load the data from mapstr object into object instance
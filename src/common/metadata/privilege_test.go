@@ -0,0 +1,58 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"reflect"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+)
+
+func TestAttributePermission(t *testing.T) {
+	permission := AttributePermission{"host": {"operator": "mask", "bk_comment": "read"}}
+
+	data := mapstr.MapStr{"bk_host_id": 1, "operator": "admin", "bk_comment": "comment"}
+	permission.Mask("host", data)
+	if !reflect.DeepEqual(mapstr.MapStr{"bk_host_id": 1, "operator": AttributeMaskedValue, "bk_comment": "comment"}, data) {
+		t.Errorf("unexpected masked data %v", data)
+	}
+	permission.Mask("switch", data)
+	if data["operator"] != AttributeMaskedValue {
+		t.Errorf("the other object should not change the data, got %v", data)
+	}
+
+	if fields := permission.Unwritable("host", mapstr.MapStr{"operator": "", "bk_comment": "", "bk_cpu": 1}); !reflect.DeepEqual([]string{"bk_comment", "operator"}, fields) {
+		t.Errorf("unexpected unwritable fields %v", fields)
+	}
+	if fields := permission.Unwritable("switch", mapstr.MapStr{"operator": ""}); len(fields) != 0 {
+		t.Errorf("unexpected unwritable fields %v", fields)
+	}
+
+	for _, item := range []struct {
+		cond interface{}
+		sort string
+		want []string
+	}{
+		{mapstr.MapStr{"operator": "admin", "bk_comment": "x"}, "", []string{"operator"}},
+		{mapstr.MapStr{"$or": []interface{}{map[string]interface{}{"bk_host_innerip": "1"}, map[string]interface{}{"operator": "admin"}}}, "", []string{"operator"}},
+		{map[string]interface{}{"$and": []interface{}{map[string]interface{}{"operator": map[string]interface{}{"$regex": "a"}}}}, "", []string{"operator"}},
+		{nil, "bk_host_id,-operator", []string{"operator"}},
+		{mapstr.MapStr{"bk_comment": "x"}, "bk_host_id", nil},
+		{nil, "", nil},
+	} {
+		if fields := permission.Unreadable("host", item.cond, item.sort); !reflect.DeepEqual(item.want, fields) {
+			t.Errorf("the unreadable fields of %v sort by %q should be %v, got %v", item.cond, item.sort, item.want, fields)
+		}
+	}
+}
@@ -178,6 +178,44 @@ type FilePriviewMap struct {
 	Inst    string `json:"inst"`
 }
 
+// ProcConfigFilter filter the process instances whose config files are handled,
+// the online version of the template is used when VersionID is zero
+type ProcConfigFilter struct {
+	VersionID int64   `json:"version_id"`
+	ProcessID []int64 `json:"bk_process_id"`
+	HostID    []int64 `json:"bk_host_id"`
+}
+
+// ProcConfigFile the config file of a process instance rendered from the template
+type ProcConfigFile struct {
+	TemplateID     int64  `json:"template_id"`
+	VersionID      int64  `json:"version_id"`
+	FileName       string `json:"file_name"`
+	Path           string `json:"path"`
+	AppID          int64  `json:"bk_biz_id"`
+	SetID          int64  `json:"bk_set_id"`
+	ModuleID       int64  `json:"bk_module_id"`
+	ProcessID      int64  `json:"bk_process_id"`
+	FuncID         int64  `json:"bk_func_id"`
+	ProcInstanceID uint64 `json:"proc_instance_id"`
+	HostID         int64  `json:"bk_host_id"`
+	HostProcID     uint64 `json:"host_proc_id"`
+	InnerIP        string `json:"bk_host_innerip"`
+	CloudID        int64  `json:"bk_cloud_id"`
+	Content        string `json:"content"`
+}
+
+// ProcConfigFileResult the result of handling a config file
+type ProcConfigFileResult struct {
+	ProcConfigFile `json:",inline"`
+	RemoteContent  string `json:"remote_content,omitempty"`
+	RemoteExists   bool   `json:"remote_exists"`
+	Changed        bool   `json:"changed"`
+	Diff           string `json:"diff,omitempty"`
+	Success        bool   `json:"success"`
+	ErrMsg         string `json:"errmsg,omitempty"`
+}
+
 // InlineProcInfo process info convert gse proc info
 type InlineProcInfo struct {
 	//Meta    GseProcMeta
@@ -257,3 +295,22 @@ type TemplateVersion struct {
 	Status      string `json:"status" field:"status"`
 	Description string `json:"description" field:"description"`
 }
+
+// TemplateVersionStatusParams change the status of the template version
+type TemplateVersionStatusParams struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+}
+
+// TemplateVersionStatusResult the result of the template version status change
+type TemplateVersionStatusResult struct {
+	VersionID  int64   `json:"version_id"`
+	PreStatus  string  `json:"pre_status"`
+	Status     string  `json:"status"`
+	Comment    string  `json:"comment,omitempty"`
+	Operator   string  `json:"operator"`
+	ArchivedID []int64 `json:"archived_version_id,omitempty"`
+	// OnlineVersionID the online version which the version is compared with, zero means none
+	OnlineVersionID int64  `json:"online_version_id"`
+	Diff            string `json:"diff,omitempty"`
+}
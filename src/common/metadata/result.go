@@ -89,6 +89,11 @@ type CreateManyDataResult struct {
 	CreateManyInfoResult `json:",inline"`
 }
 
+// ValidatedManyDataResult the data struct definition in validate many function result
+type ValidatedManyDataResult struct {
+	Exceptions []ExceptionResult `json:"exception"`
+}
+
 // CreateOneDataResult the data struct definition in create one function result
 type CreateOneDataResult struct {
 	Created CreatedDataResult `json:"created"`
@@ -28,6 +28,7 @@ func newMetricController(conf Config, healthFunc HealthFunc, collectors ...*Coll
 	metricController.Collectors = make(map[CollectorName]CollectInter)
 	for _, c := range collectors {
 		metricController.Collectors[c.Name] = c.Collector
+		DefaultRegistry.RegisterCollector(c)
 	}
 
 	metricHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -71,6 +72,7 @@ func newMetricController(conf Config, healthFunc HealthFunc, collectors ...*Coll
 
 	actions := []Action{
 		{Method: "GET", Path: "/metrics", HandlerFunc: metricHandler},
+		{Method: "GET", Path: "/metrics/prometheus", HandlerFunc: PrometheusHandler().ServeHTTP},
 		{Method: "GET", Path: "/healthz", HandlerFunc: healthHandler},
 	}
 
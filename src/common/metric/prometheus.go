@@ -0,0 +1,396 @@
+package metric
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common/blog"
+)
+
+// PrometheusContentType the content type of the prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets the default buckets of the histogram, in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusMetric the metric rendered in the prometheus text exposition format
+type PrometheusMetric interface {
+	WritePrometheus(w io.Writer)
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// CounterVec a counter partitioned by the labels
+type CounterVec struct {
+	sync.Mutex
+	name       string
+	help       string
+	labelNames []string
+	series     map[string]*counterSeries
+}
+
+// NewCounterVec create a counter partitioned by the labels
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]*counterSeries),
+	}
+}
+
+// Add add the value to the counter with the label values, the value must not be negative
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 || len(labelValues) != len(c.labelNames) {
+		return
+	}
+	key := strings.Join(labelValues, "\xff")
+	c.Lock()
+	defer c.Unlock()
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: labelValues}
+		c.series[key] = series
+	}
+	series.value += value
+}
+
+// Inc increase the counter with the label values by 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// WritePrometheus implements PrometheusMetric
+func (c *CounterVec) WritePrometheus(w io.Writer) {
+	c.Lock()
+	defer c.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		series := c.series[key]
+		writeSample(w, c.name, c.labelNames, series.labelValues, "", "", series.value)
+	}
+}
+
+// GaugeVec a gauge partitioned by the labels
+type GaugeVec struct {
+	sync.Mutex
+	name       string
+	help       string
+	labelNames []string
+	series     map[string]*counterSeries
+}
+
+// NewGaugeVec create a gauge partitioned by the labels
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]*counterSeries),
+	}
+}
+
+// Set set the value of the gauge with the label values
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	if len(labelValues) != len(g.labelNames) {
+		return
+	}
+	key := strings.Join(labelValues, "\xff")
+	g.Lock()
+	defer g.Unlock()
+	series, ok := g.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: labelValues}
+		g.series[key] = series
+	}
+	series.value = value
+}
+
+// Reset remove all the series of the gauge
+func (g *GaugeVec) Reset() {
+	g.Lock()
+	defer g.Unlock()
+	g.series = make(map[string]*counterSeries)
+}
+
+// WritePrometheus implements PrometheusMetric
+func (g *GaugeVec) WritePrometheus(w io.Writer) {
+	g.Lock()
+	defer g.Unlock()
+	writeHeader(w, g.name, g.help, "gauge")
+	for _, key := range sortedKeys(g.series) {
+		series := g.series[key]
+		writeSample(w, g.name, g.labelNames, series.labelValues, "", "", series.value)
+	}
+}
+
+type histogramSeries struct {
+	labelValues []string
+	// bucketCounts the count of the observations not greater than the bucket, not cumulative
+	bucketCounts []uint64
+	count        uint64
+	sum          float64
+}
+
+// HistogramVec a histogram partitioned by the labels
+type HistogramVec struct {
+	sync.Mutex
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+	series     map[string]*histogramSeries
+}
+
+// NewHistogramVec create a histogram partitioned by the labels, the buckets must be sorted in increasing order
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{
+		name:       name,
+		help:       help,
+		buckets:    buckets,
+		labelNames: labelNames,
+		series:     make(map[string]*histogramSeries),
+	}
+}
+
+// Observe add an observation to the histogram with the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labelNames) {
+		return
+	}
+	key := strings.Join(labelValues, "\xff")
+	h.Lock()
+	defer h.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{labelValues: labelValues, bucketCounts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	idx := sort.SearchFloat64s(h.buckets, value)
+	if idx < len(h.buckets) {
+		series.bucketCounts[idx]++
+	}
+	series.count++
+	series.sum += value
+}
+
+// WritePrometheus implements PrometheusMetric
+func (h *HistogramVec) WritePrometheus(w io.Writer) {
+	h.Lock()
+	defer h.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		var cumulative uint64
+		for idx, bucket := range h.buckets {
+			cumulative += series.bucketCounts[idx]
+			writeSample(w, h.name+"_bucket", h.labelNames, series.labelValues, "le", formatFloat(bucket), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", h.labelNames, series.labelValues, "le", "+Inf", float64(series.count))
+		writeSample(w, h.name+"_sum", h.labelNames, series.labelValues, "", "", series.sum)
+		writeSample(w, h.name+"_count", h.labelNames, series.labelValues, "", "", float64(series.count))
+	}
+}
+
+// PrometheusRegistry the metrics and the collectors exported in the prometheus text exposition format
+type PrometheusRegistry struct {
+	sync.RWMutex
+	metrics    []PrometheusMetric
+	collectors map[CollectorName]CollectInter
+}
+
+// NewPrometheusRegistry create a prometheus registry
+func NewPrometheusRegistry() *PrometheusRegistry {
+	return &PrometheusRegistry{
+		metrics:    make([]PrometheusMetric, 0),
+		collectors: make(map[CollectorName]CollectInter),
+	}
+}
+
+// Register register the metric
+func (r *PrometheusRegistry) Register(metric PrometheusMetric) {
+	r.Lock()
+	defer r.Unlock()
+	r.metrics = append(r.metrics, metric)
+}
+
+// RegisterCollector register the collector of the json metrics, the float metrics are exported as gauges
+func (r *PrometheusRegistry) RegisterCollector(collector *Collector) {
+	r.Lock()
+	defer r.Unlock()
+	r.collectors[collector.Name] = collector.Collector
+}
+
+// WritePrometheus write all the metrics in the prometheus text exposition format
+func (r *PrometheusRegistry) WritePrometheus(w io.Writer) {
+	r.RLock()
+	defer r.RUnlock()
+	for _, metric := range r.metrics {
+		metric.WritePrometheus(w)
+	}
+
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	written := make(map[string]bool)
+	for _, name := range names {
+		for _, m := range r.collectors[CollectorName(name)].Collect() {
+			meta := m.GetMeta()
+			if meta == nil || meta.Name == "" {
+				continue
+			}
+			val, err := m.GetValue()
+			if err != nil || val == nil || val.Type != Float {
+				// the string value can not be exported
+				continue
+			}
+			metricName := sanitizeMetricName(meta.Name)
+			if written[metricName] {
+				blog.Warnf("metric %s of collector %s is duplicated, skip it", metricName, name)
+				continue
+			}
+			written[metricName] = true
+			writeHeader(w, metricName, meta.Help, "gauge")
+			writeSample(w, metricName, nil, nil, "", "", val.Float)
+		}
+	}
+}
+
+// DefaultRegistry the registry exported by the /metrics of the services
+var DefaultRegistry = NewPrometheusRegistry()
+
+var (
+	httpRequestsTotal = NewCounterVec("cc_http_requests_total",
+		"Total number of the http requests.", "method", "route", "code")
+	httpRequestDuration = NewHistogramVec("cc_http_request_duration_seconds",
+		"The http request latencies in seconds.", DefaultBuckets, "method", "route")
+)
+
+func init() {
+	DefaultRegistry.Register(httpRequestsTotal)
+	DefaultRegistry.Register(httpRequestDuration)
+	DefaultRegistry.RegisterCollector(newGoMetricCollector())
+}
+
+// ObserveHTTPRequest record the http request of the route
+func ObserveHTTPRequest(method, route string, code int, duration time.Duration) {
+	httpRequestsTotal.Inc(method, route, strconv.Itoa(code))
+	httpRequestDuration.Observe(duration.Seconds(), method, route)
+}
+
+// RestfulFilter record the count and the latency of the http requests per route,
+// it must be a container filter, the route is selected before the container filters
+func RestfulFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	start := time.Now()
+	chain.ProcessFilter(req, resp)
+	ObserveHTTPRequest(req.Request.Method, req.SelectedRoutePath(), resp.StatusCode(), time.Since(start))
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.code = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// InstrumentHandler record the count and the latency of the http requests of the handler,
+// all the requests use the same route, the path is not a label to limit the series count
+func InstrumentHandler(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		handler.ServeHTTP(recorder, req)
+		ObserveHTTPRequest(req.Method, route, recorder.code, time.Since(start))
+	})
+}
+
+// PrometheusHandler export the metrics of the default registry
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		buf := &bytes.Buffer{}
+		DefaultRegistry.WritePrometheus(buf)
+		w.Header().Set("Content-Type", PrometheusContentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+var invalidMetricNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+func sanitizeMetricName(name string) string {
+	name = invalidMetricNameChars.ReplaceAllString(name, "_")
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(strings.TrimSpace(help))
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	labels := make([]string, 0, len(labelNames)+1)
+	for idx, labelName := range labelNames {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, labelName, escapeLabelValue(labelValues[idx])))
+	}
+	if extraName != "" {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	if len(labels) > 0 {
+		fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(labels, ","), formatFloat(value))
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch series := m.(type) {
+	case map[string]*counterSeries:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramSeries:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
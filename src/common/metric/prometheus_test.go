@@ -0,0 +1,80 @@
+package metric
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCollector struct{}
+
+func (testCollector) Collect() []MetricInterf {
+	return []MetricInterf{
+		goMetric{Name: "test.value", Help: "test value", GetFunc: func() float64 { return 3 }},
+	}
+}
+
+func TestPrometheusRegistry(t *testing.T) {
+	registry := NewPrometheusRegistry()
+	counter := NewCounterVec("test_requests_total", "test requests", "code")
+	histogram := NewHistogramVec("test_duration_seconds", "test duration", []float64{0.1, 1}, "route")
+	registry.Register(counter)
+	registry.Register(histogram)
+	registry.RegisterCollector(NewCollector("test", testCollector{}))
+
+	counter.Inc("200")
+	counter.Add(2, "200")
+	counter.Inc("500")
+	histogram.Observe(0.05, "/a")
+	histogram.Observe(0.5, "/a")
+	histogram.Observe(5, "/a")
+
+	buf := &bytes.Buffer{}
+	registry.WritePrometheus(buf)
+	expect := `# HELP test_requests_total test requests
+# TYPE test_requests_total counter
+test_requests_total{code="200"} 3
+test_requests_total{code="500"} 1
+# HELP test_duration_seconds test duration
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/a",le="0.1"} 1
+test_duration_seconds_bucket{route="/a",le="1"} 2
+test_duration_seconds_bucket{route="/a",le="+Inf"} 3
+test_duration_seconds_sum{route="/a"} 5.55
+test_duration_seconds_count{route="/a"} 3
+# HELP test_value test value
+# TYPE test_value gauge
+test_value 3
+`
+	if buf.String() != expect {
+		t.Errorf("expect:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	handler := InstrumentHandler("/test", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test/1", nil))
+	ObserveHTTPRequest(http.MethodPost, "/test", http.StatusOK, time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		`cc_http_requests_total{method="GET",route="/test",code="404"} 1`,
+		`cc_http_requests_total{method="POST",route="/test",code="200"} 1`,
+		`cc_http_request_duration_seconds_count{method="GET",route="/test"} 1`,
+		"# TYPE go_goroutines gauge",
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("%s not found in:\n%s", line, body)
+		}
+	}
+	if recorder.Header().Get("Content-Type") != PrometheusContentType {
+		t.Errorf("unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+	Parent   *node   `json:"-"`
+	secret   string
+}
+
+type searchRequest struct {
+	metadata.BasePage `json:"page"`
+	Condition         mapstr.MapStr `json:"condition"`
+	Fields            []string      `json:"fields"`
+	Root              *node         `json:"root"`
+	Until             time.Time     `json:"until"`
+	Raw               json.RawMessage
+}
+
+type searchResult struct {
+	metadata.BaseResp `json:",inline"`
+	Data              struct {
+		Count int64   `json:"count"`
+		Info  []*node `json:"info"`
+	} `json:"data"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	doc := NewDocument("test", "v1")
+	schema := doc.SchemaOf(searchRequest{})
+	if schema.Ref != componentsRef+"openapi.searchRequest" {
+		t.Fatalf("unexpected ref %q", schema.Ref)
+	}
+
+	req := doc.Components.Schemas["openapi.searchRequest"]
+	if req.Properties["page"].Ref != componentsRef+"metadata.BasePage" {
+		t.Errorf("the named embedded struct should be a property, got %+v", req.Properties["page"])
+	}
+	if req.Properties["condition"].Type != "object" || req.Properties["fields"].Items.Type != "string" {
+		t.Errorf("unexpected condition or fields schema: %+v %+v", req.Properties["condition"], req.Properties["fields"])
+	}
+	if req.Properties["until"].Format != "date-time" {
+		t.Errorf("time should be a date-time string, got %+v", req.Properties["until"])
+	}
+	if _, ok := req.Properties["Raw"]; !ok {
+		t.Errorf("the field without a json name should use the field name")
+	}
+
+	n := doc.Components.Schemas["openapi.node"]
+	if n.Properties["children"].Items.Ref != componentsRef+"openapi.node" {
+		t.Errorf("the recursive type should refer to itself, got %+v", n.Properties["children"].Items)
+	}
+	if _, ok := n.Properties["Parent"]; ok {
+		t.Errorf("the ignored field should not be described")
+	}
+	if _, ok := n.Properties["secret"]; ok {
+		t.Errorf("the unexported field should not be described")
+	}
+
+	doc.SchemaOf(searchResult{})
+	result := doc.Components.Schemas["openapi.searchResult"]
+	for _, name := range []string{"result", "bk_error_code", "bk_error_msg", "data"} {
+		if _, ok := result.Properties[name]; !ok {
+			t.Errorf("the property %s of the inlined base response is missing", name)
+		}
+	}
+	if result.Properties["data"].Properties["count"].Format != "int64" {
+		t.Errorf("the anonymous struct should be inlined, got %+v", result.Properties["data"])
+	}
+}
+
+func TestContainerHandler(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/topo/{version}")
+	ws.Route(ws.POST("/inst/{bk_obj_id}/search").Doc("search instances").Reads(searchRequest{}).Writes(searchResult{}).
+		To(func(*restful.Request, *restful.Response) {}))
+	ws.Route(ws.DELETE("/inst/{id:[0-9]+}").To(func(*restful.Request, *restful.Response) {}))
+	container := restful.NewContainer()
+	container.Add(ws)
+	container.Handle(DocPath, ContainerHandler("topo", "v1", container))
+
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DocPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", recorder.Code)
+	}
+
+	doc := new(Document)
+	if err := json.Unmarshal(recorder.Body.Bytes(), doc); err != nil {
+		t.Fatalf("unmarshal document failed, err: %v", err)
+	}
+	doc.TrimPathParam("version", "v3")
+
+	search, ok := doc.Paths["/topo/v3/inst/{bk_obj_id}/search"]
+	if !ok {
+		t.Fatalf("the search path is missing, paths: %v", doc.Paths)
+	}
+	op := (*search)["post"]
+	if op.Summary != "search instances" || len(op.Parameters) != 1 || op.Parameters[0].Name != "bk_obj_id" {
+		t.Errorf("unexpected operation %+v", op)
+	}
+	if op.RequestBody.Content[jsonMediaType].Schema.Ref != componentsRef+"openapi.searchRequest" {
+		t.Errorf("unexpected request body %+v", op.RequestBody.Content[jsonMediaType].Schema)
+	}
+	if op.Responses["200"].Content[jsonMediaType].Schema.Ref != componentsRef+"openapi.searchResult" {
+		t.Errorf("unexpected response %+v", op.Responses["200"])
+	}
+	if _, ok := doc.Components.Schemas["openapi.node"]; !ok {
+		t.Errorf("the nested schema is missing")
+	}
+
+	del, ok := doc.Paths["/topo/v3/inst/{id}"]
+	if !ok {
+		t.Fatalf("the regex of the path parameter should be removed, paths: %v", doc.Paths)
+	}
+	if op := (*del)["delete"]; op.RequestBody != nil || op.Responses["default"] == nil {
+		t.Errorf("unexpected untyped operation %+v", op)
+	}
+}
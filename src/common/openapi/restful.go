@@ -0,0 +1,168 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+// DocPath the path the services serve the openapi document of their routes
+const DocPath = "/openapi.json"
+
+const jsonMediaType = "application/json"
+
+// pathParamRegexp matches the path parameters such as {name} and {name:regex}
+var pathParamRegexp = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Annotate set the request and the response samples of the route, the nil ones are skipped
+func Annotate(builder *restful.RouteBuilder, request, response interface{}) *restful.RouteBuilder {
+	if request != nil {
+		builder = builder.Reads(request)
+	}
+	if response != nil {
+		builder = builder.Writes(response)
+	}
+	return builder
+}
+
+// FromWebServices create the document of the routes of the web services,
+// the request and the response are described by the read and the write samples of the routes.
+func FromWebServices(title, version string, wss []*restful.WebService) *Document {
+	doc := NewDocument(title, version)
+	for _, ws := range wss {
+		for _, route := range ws.Routes() {
+			doc.AddRoute(ws.RootPath(), route)
+		}
+	}
+	return doc
+}
+
+// AddRoute add the operation of the route, the tag is the root path of its web service
+func (d *Document) AddRoute(rootPath string, route restful.Route) {
+	path := pathParamRegexp.ReplaceAllString(route.Path, "{$1}")
+	op := &Operation{
+		Summary:     route.Doc,
+		OperationID: route.Operation,
+		Responses:   make(map[string]*Response),
+	}
+	if rootPath != "" {
+		op.Tags = []string{rootPath}
+	}
+
+	documented := make(map[string]bool)
+	for _, param := range route.ParameterDocs {
+		data := param.Data()
+		var in string
+		switch data.Kind {
+		case restful.PathParameterKind:
+			in = "path"
+		case restful.QueryParameterKind:
+			in = "query"
+		case restful.HeaderParameterKind:
+			in = "header"
+		default:
+			continue
+		}
+		documented[data.Name] = true
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        data.Name,
+			In:          in,
+			Description: data.Description,
+			Required:    data.Required || in == "path",
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(route.Path, -1) {
+		if documented[match[1]] {
+			continue
+		}
+		documented[match[1]] = true
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if route.ReadSample != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{jsonMediaType: {Schema: d.SchemaOf(route.ReadSample)}},
+		}
+	}
+	if route.WriteSample != nil {
+		op.Responses["200"] = &Response{
+			Description: "OK",
+			Content:     map[string]*MediaType{jsonMediaType: {Schema: d.SchemaOf(route.WriteSample)}},
+		}
+	}
+	// all the apis response the base response on failure, and the untyped routes on success too
+	op.Responses["default"] = &Response{
+		Description: "the result and the error of the request",
+		Content:     map[string]*MediaType{jsonMediaType: {Schema: d.SchemaOf(metadata.Response{})}},
+	}
+
+	d.AddOperation(route.Method, path, op)
+}
+
+// Handler serve the document created by the function
+func Handler(document func() *Document) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			resp.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		doc := document()
+		resp.Header().Set("Content-Type", jsonMediaType)
+		if err := json.NewEncoder(resp).Encode(doc); err != nil {
+			blog.Errorf("write openapi document failed, err: %v", err)
+		}
+	})
+}
+
+// ContainerHandler serve the document of the web services registered in the container
+func ContainerHandler(title, version string, container *restful.Container) http.Handler {
+	return Handler(func() *Document {
+		return FromWebServices(title, version, container.RegisteredWebServices())
+	})
+}
+
+// TrimPathParam remove the path parameter from the path and the parameters of its operations,
+// it is used for the parameters with a fixed value such as {version}.
+func (d *Document) TrimPathParam(name, value string) {
+	placeholder := "{" + name + "}"
+	for path, item := range d.Paths {
+		for _, op := range *item {
+			params := op.Parameters[:0]
+			for _, param := range op.Parameters {
+				if !(param.In == "path" && param.Name == name) {
+					params = append(params, param)
+				}
+			}
+			op.Parameters = params
+		}
+		if strings.Contains(path, placeholder) {
+			delete(d.Paths, path)
+			d.Paths[strings.Replace(path, placeholder, value, -1)] = item
+		}
+	}
+}
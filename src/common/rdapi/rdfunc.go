@@ -17,12 +17,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/trace"
 	"configcenter/src/common/util"
 
 	restful "github.com/emicklei/go-restful"
@@ -45,6 +47,8 @@ func checkHTTPAuth(req *restful.Request, defErr errors.DefaultCCErrorIf) (int, s
 func AllGlobalFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 		generateHttpHeaderRID(req, resp)
+		span := startHTTPSpan(req)
+		defer finishHTTPSpan(span, resp)
 
 		whilteListSuffix := strings.Split(common.URLFilterWhiteListSuffix, common.URLFilterWhiteListSepareteChar)
 		for _, url := range whilteListSuffix {
@@ -78,6 +82,8 @@ func AllGlobalFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request,
 func HTTPRequestIDFilter(errFunc func() errors.CCErrorIf) func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
 		generateHttpHeaderRID(req, resp)
+		span := startHTTPSpan(req)
+		defer finishHTTPSpan(span, resp)
 		if 1 < len(fchain.Filters) {
 			fchain.ProcessFilter(req, resp)
 			return
@@ -119,6 +125,27 @@ func generateHttpHeaderRID(req *restful.Request, resp *restful.Response) {
 	resp.Header().Set(common.BKHTTPCCRequestID, cid)
 }
 
+// startHTTPSpan start a server span of the request, the traceparent header of the request is replaced with
+// the span, so that the requests sent to the other services with the request header are traced as its children.
+func startHTTPSpan(req *restful.Request) *trace.Span {
+	span := trace.StartServerSpan(req.Request.Header, req.Request.Method+" "+req.SelectedRoutePath())
+	span.SetAttribute("http.method", req.Request.Method)
+	span.SetAttribute("http.path", req.Request.URL.Path)
+	span.SetAttribute("rid", util.GetHTTPCCRequestID(req.Request.Header))
+	trace.Inject(req.Request.Header, span)
+	req.Request = req.Request.WithContext(trace.ContextWithSpanContext(req.Request.Context(), span.Context()))
+	return span
+}
+
+func finishHTTPSpan(span *trace.Span, resp *restful.Response) {
+	status := resp.StatusCode()
+	span.SetAttribute("http.status_code", strconv.Itoa(status))
+	if status >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("http status %d", status))
+	}
+	span.Finish()
+}
+
 func ServiceErrorHandler(err restful.ServiceError, req *restful.Request, resp *restful.Response) {
 	blog.Errorf("HTTP ERROR: %v, HTTP MESSAGE: %v, RequestURI: %s %s", err.Code, err.Message, req.Request.Method, req.Request.RequestURI)
 	ret := metadata.BaseResp{
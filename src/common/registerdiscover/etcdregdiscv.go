@@ -0,0 +1,193 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"configcenter/src/common/backbone/service_mange/etcd"
+	"configcenter/src/common/blog"
+)
+
+// EtcdRegDiscv do register and discover by etcd
+type EtcdRegDiscv struct {
+	client         *etcd.EtcdClient
+	cancel         context.CancelFunc
+	rootCxt        context.Context
+	sessionTimeOut time.Duration
+}
+
+// NewEtcdRegDiscv create a object of EtcdRegDiscv
+func NewEtcdRegDiscv(client *etcd.EtcdClient) *EtcdRegDiscv {
+	ctx, ctxCancel := client.WithCancel()
+	return &EtcdRegDiscv{
+		client:         client,
+		sessionTimeOut: client.SessionTimeOut(),
+		cancel:         ctxCancel,
+		rootCxt:        ctx,
+	}
+}
+
+// RegisterAndWatch put the service with a lease and keep the lease alive. if the lease expired, register again
+func (etcdRD *EtcdRegDiscv) RegisterAndWatch(path string, data []byte) error {
+	blog.Infof("register server and watch it. path(%s), data(%s)", path, string(data))
+	go func() {
+		for {
+			err := etcdRD.registerAndKeepAlive(path, data)
+			if etcdRD.rootCxt.Err() != nil {
+				blog.Infof("watch register node(%s) done", path)
+				return
+			}
+			blog.Errorf("register server node(%s) lost, will register again after 5s, err: %v", path, err)
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	return nil
+}
+
+func (etcdRD *EtcdRegDiscv) registerAndKeepAlive(path string, data []byte) error {
+	ctx := etcdRD.rootCxt
+	lease, err := etcdRD.client.Grant(ctx, etcdRD.sessionTimeOut)
+	if err != nil {
+		return err
+	}
+
+	// the node is removed with the lease at once when the keep alive ends, do not wait for the lease expired
+	defer func() {
+		revokeCtx, cancel := context.WithTimeout(context.Background(), etcdRD.sessionTimeOut)
+		etcdRD.client.Revoke(revokeCtx, lease)
+		cancel()
+	}()
+
+	// the node is named with the lease as the zookeeper sequential node, the nodes are ordered by the create revision
+	node := fmt.Sprintf("%s_%016x", path, lease)
+	if err := etcdRD.client.Put(ctx, node, data, lease); err != nil {
+		return err
+	}
+	blog.Infof("finish register server node(%s)", node)
+
+	interval := etcdRD.sessionTimeOut / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	lastAlive := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		ttl, err := etcdRD.client.KeepAlive(ctx, lease)
+		if err != nil {
+			if time.Since(lastAlive) > etcdRD.sessionTimeOut {
+				return err
+			}
+			blog.Warnf("keep alive register node(%s) failed, will retry, err: %v", node, err)
+			continue
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("the lease of register node(%s) is expired", node)
+		}
+		lastAlive = time.Now()
+	}
+}
+
+// GetServNodes get server nodes by path
+func (etcdRD *EtcdRegDiscv) GetServNodes(path string) ([]string, error) {
+	kvs, _, err := etcdRD.client.List(etcdRD.rootCxt, path+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0)
+	for _, kv := range childrenOf(path, kvs) {
+		nodes = append(nodes, kv.Key[len(path)+1:])
+	}
+	return nodes, nil
+}
+
+// Ping to ping server
+func (etcdRD *EtcdRegDiscv) Ping() error {
+	return etcdRD.client.Ping()
+}
+
+// Discover watch the children
+func (etcdRD *EtcdRegDiscv) Discover(path string) (<-chan *DiscoverEvent, error) {
+	blog.Infof("begin to discover by watch children of path(%s)", path)
+	env := make(chan *DiscoverEvent, 1)
+
+	go etcdRD.loopDiscover(etcdRD.rootCxt, path, env)
+
+	return env, nil
+}
+
+func (etcdRD *EtcdRegDiscv) loopDiscover(discvCtx context.Context, path string, env chan *DiscoverEvent) {
+	for {
+		kvs, revision, err := etcdRD.client.List(discvCtx, path+"/")
+		if err != nil {
+			if discvCtx.Err() != nil {
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+			blog.Errorf("fail to get children for path(%s), will watch after 5s, err: %v", path, err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		discvEnv := &DiscoverEvent{
+			Key: path,
+		}
+		for _, kv := range childrenOf(path, kvs) {
+			discvEnv.Nodes = append(discvEnv.Nodes, kv.Key[len(path)+1:])
+			discvEnv.Server = append(discvEnv.Server, string(kv.Value))
+		}
+
+		//write into discoverEvent channel
+		select {
+		case env <- discvEnv:
+		case <-discvCtx.Done():
+			blog.Infof("discover path(%s) done", path)
+			return
+		}
+
+		if err := etcdRD.client.Watch(discvCtx, path+"/", true, revision); err != nil {
+			if discvCtx.Err() != nil {
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+			blog.Errorf("fail to watch children for path(%s), will watch after 5s, err: %v", path, err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		blog.V(5).Infof("watch found the children of path(%s) change", path)
+	}
+}
+
+// childrenOf filter out the descendants which are not the direct children of path
+func childrenOf(path string, kvs []etcd.KeyValue) []etcd.KeyValue {
+	children := make([]etcd.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		if strings.Contains(kv.Key[len(path)+1:], "/") {
+			continue
+		}
+		children = append(children, kv)
+	}
+	return children
+}
@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registerdiscover
+
+import (
+	"context"
+	"reflect"
+
+	"configcenter/src/common/backbone/service_mange/file"
+	"configcenter/src/common/blog"
+)
+
+// FileRegDiscv do register and discover by a static registry file
+type FileRegDiscv struct {
+	client  *file.FileClient
+	cancel  context.CancelFunc
+	rootCxt context.Context
+}
+
+// NewFileRegDiscv create a object of FileRegDiscv
+func NewFileRegDiscv(client *file.FileClient) *FileRegDiscv {
+	ctx, ctxCancel := client.WithCancel()
+	return &FileRegDiscv{
+		client:  client,
+		cancel:  ctxCancel,
+		rootCxt: ctx,
+	}
+}
+
+// RegisterAndWatch register the service into the registry of this process, the registry file is not changed,
+// so the service should be listed in the file to be discovered by the other processes.
+func (fileRD *FileRegDiscv) RegisterAndWatch(path string, data []byte) error {
+	blog.Infof("register server into registry file. path(%s), data(%s)", path, string(data))
+	fileRD.client.Register(path, data)
+	return nil
+}
+
+// GetServNodes get server nodes by path
+func (fileRD *FileRegDiscv) GetServNodes(path string) ([]string, error) {
+	nodes := make([]string, 0)
+	for _, kv := range fileRD.client.Children(path) {
+		nodes = append(nodes, kv.Key[len(path)+1:])
+	}
+	return nodes, nil
+}
+
+// Ping to ping server
+func (fileRD *FileRegDiscv) Ping() error {
+	return fileRD.client.Ping()
+}
+
+// Discover watch the children
+func (fileRD *FileRegDiscv) Discover(path string) (<-chan *DiscoverEvent, error) {
+	blog.Infof("begin to discover by watch children of path(%s) in registry file", path)
+	env := make(chan *DiscoverEvent, 1)
+
+	go fileRD.loopDiscover(fileRD.rootCxt, path, env)
+
+	return env, nil
+}
+
+func (fileRD *FileRegDiscv) loopDiscover(discvCtx context.Context, path string, env chan *DiscoverEvent) {
+	var previous *DiscoverEvent
+	for {
+		// get the channel before read, so that no change is missed
+		changed := fileRD.client.Changed()
+
+		discvEnv := &DiscoverEvent{
+			Key: path,
+		}
+		for _, kv := range fileRD.client.Children(path) {
+			discvEnv.Nodes = append(discvEnv.Nodes, kv.Key[len(path)+1:])
+			discvEnv.Server = append(discvEnv.Server, string(kv.Value))
+		}
+
+		// the other keys in the file may be changed, only the changes of the path are sent
+		if previous == nil || !reflect.DeepEqual(previous, discvEnv) {
+			select {
+			case env <- discvEnv:
+				previous = discvEnv
+			case <-discvCtx.Done():
+				blog.Infof("discover path(%s) done", path)
+				return
+			}
+		}
+
+		select {
+		case <-discvCtx.Done():
+			blog.Infof("discover path(%s) done", path)
+			return
+		case <-changed:
+			blog.V(5).Infof("registry file changed, check the children of path(%s)", path)
+		}
+	}
+}
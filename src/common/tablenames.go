@@ -72,9 +72,12 @@ const (
 	BKTableNameHistory          = "cc_History"
 	BKTableNameHostFavorite     = "cc_HostFavourite"
 	BKTableNameOperationLog     = "cc_OperationLog"
+	BKTableNameAuditCheckpoint  = "cc_AuditCheckpoint"
+	BKTableNameAuditArchived    = "cc_AuditArchived"
 	BKTableNameSubscription     = "cc_Subscription"
 	BKTableNameUserAPI          = "cc_UserAPI"
 	BKTableNameUserCustom       = "cc_UserCustom"
+	BKTableNameUserAPIToken     = "cc_UserAPIToken"
 	BKTableNameObjAsst          = "cc_ObjAsst"
 	BKTableNameTopoGraphics     = "cc_TopoGraphics"
 	BKTableNameTransaction      = "cc_Transaction"
@@ -94,6 +97,9 @@ const (
 	BKTableNameCloudSyncHistory       = "cc_CloudSyncHistory"
 	BKTableNameCloudResourceConfirm   = "cc_CloudResourceConfirm"
 	BKTableNameResourceConfirmHistory = "cc_ResourceConfirmHistory"
+
+	// BKTableNameSynchronizeConflict the table name of the conflict queue of the data synchronization
+	BKTableNameSynchronizeConflict = "cc_SynchronizeConflict"
 )
 
 // AllTables alltables
@@ -123,9 +129,12 @@ var AllTables = []string{
 	BKTableNameHistory,
 	BKTableNameHostFavorite,
 	BKTableNameOperationLog,
+	BKTableNameAuditCheckpoint,
+	BKTableNameAuditArchived,
 	BKTableNameSubscription,
 	BKTableNameUserAPI,
 	BKTableNameUserCustom,
+	BKTableNameUserAPIToken,
 	BKTableNameObjAsst,
 	BKTableNameTopoGraphics,
 	BKTableNameNetcollectConfig,
@@ -142,6 +151,7 @@ var AllTables = []string{
 	BKTableNameResourceConfirmHistory,
 	BKTableNameObjUnique,
 	BKTableNameAsstDes,
+	BKTableNameSynchronizeConflict,
 }
 
 // GetInstTableName returns inst data table name
@@ -0,0 +1,239 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	mrand "math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"configcenter/src/common/blog"
+)
+
+// Exporter send the finished spans to the trace backend
+type Exporter interface {
+	Export(span *Span) error
+	Close() error
+}
+
+// ExporterFactory create the exporter with the process config
+type ExporterFactory func(config map[string]string) (Exporter, error)
+
+const (
+	// ExporterNone the spans are not exported, only the span context is propagated
+	ExporterNone = "none"
+	// ExporterLog the spans are written to the log
+	ExporterLog = "log"
+	// ExporterFile the spans are written to a local file as json lines
+	ExporterFile = "file"
+)
+
+// the config keys of the process config
+const (
+	configExporter   = "trace.exporter"
+	configFile       = "trace.file"
+	configSampleRate = "trace.sampleRate"
+)
+
+var (
+	lock       sync.RWMutex
+	factories  = make(map[string]ExporterFactory)
+	exporter   Exporter
+	sampleRate = 1.0
+	configured string
+)
+
+func init() {
+	RegisterExporter(ExporterNone, func(config map[string]string) (Exporter, error) {
+		return nil, nil
+	})
+	RegisterExporter(ExporterLog, func(config map[string]string) (Exporter, error) {
+		return new(LogExporter), nil
+	})
+	RegisterExporter(ExporterFile, func(config map[string]string) (Exporter, error) {
+		return NewFileExporter(config[configFile])
+	})
+}
+
+// RegisterExporter register a exporter factory, so that it can be chosen by trace.exporter of the process config
+func RegisterExporter(name string, factory ExporterFactory) {
+	lock.Lock()
+	defer lock.Unlock()
+	factories[name] = factory
+}
+
+// SetExporter replace the exporter, the previous one is closed. a nil exporter stop exporting the spans.
+func SetExporter(e Exporter) {
+	lock.Lock()
+	previous := exporter
+	exporter = e
+	lock.Unlock()
+
+	if previous != nil && previous != e {
+		if err := previous.Close(); err != nil {
+			blog.Errorf("close trace exporter failed, err: %v", err)
+		}
+	}
+}
+
+// SetSampleRate set the ratio of the new traces which are sampled, the rate is between 0 and 1
+func SetSampleRate(rate float64) {
+	lock.Lock()
+	defer lock.Unlock()
+	sampleRate = rate
+}
+
+// Configure set the exporter and the sample rate with the process config, the configs are:
+// trace.exporter: the name of the exporter, none, log, file or the registered ones, default none
+// trace.file: the file path of the file exporter
+// trace.sampleRate: the ratio of the new traces which are sampled, default 1
+// the exporter is recreated only when the trace config is changed.
+func Configure(config map[string]string) error {
+	name := config[configExporter]
+	if name == "" {
+		name = ExporterNone
+	}
+
+	rate := 1.0
+	if value := config[configSampleRate]; value != "" {
+		var err error
+		rate, err = strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return fmt.Errorf("invalid %s %q, should be between 0 and 1", configSampleRate, value)
+		}
+	}
+
+	key := traceConfigKey(config)
+	lock.RLock()
+	factory, exist := factories[name]
+	unchanged := key == configured
+	lock.RUnlock()
+	if !exist {
+		return fmt.Errorf("unknown trace exporter %q", name)
+	}
+	if unchanged {
+		return nil
+	}
+
+	e, err := factory(config)
+	if err != nil {
+		return fmt.Errorf("create trace exporter %s failed, err: %v", name, err)
+	}
+	SetExporter(e)
+	SetSampleRate(rate)
+
+	lock.Lock()
+	configured = key
+	lock.Unlock()
+	blog.Infof("trace exporter is set to %s, sample rate: %v", name, rate)
+	return nil
+}
+
+// traceConfigKey join all the trace configs, it's used to find out whether the trace config is changed
+func traceConfigKey(config map[string]string) string {
+	keys := make([]string, 0)
+	for key := range config {
+		if strings.HasPrefix(key, "trace.") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+config[key])
+	}
+	return strings.Join(pairs, "\n")
+}
+
+func shouldSample() bool {
+	lock.RLock()
+	rate := sampleRate
+	lock.RUnlock()
+	return rate >= 1 || mrand.Float64() < rate
+}
+
+func export(span *Span) {
+	lock.RLock()
+	e := exporter
+	lock.RUnlock()
+	if e == nil {
+		return
+	}
+	if err := e.Export(span); err != nil {
+		blog.Errorf("export span %s of trace %s failed, err: %v", span.SpanID, span.TraceID, err)
+	}
+}
+
+// LogExporter write the spans to the log
+type LogExporter struct{}
+
+// Export write the span to the log
+func (LogExporter) Export(span *Span) error {
+	data, err := json.Marshal(span)
+	if err != nil {
+		return err
+	}
+	blog.Infof("[trace] %s", data)
+	return nil
+}
+
+// Close nothing to do
+func (LogExporter) Close() error {
+	return nil
+}
+
+// FileExporter write the spans to a local file, a span a line in json
+type FileExporter struct {
+	sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileExporter create a file exporter, the spans are appended to the file
+func NewFileExporter(path string) (*FileExporter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%s is not set", configFile)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Export append the span to the file
+func (f *FileExporter) Export(span *Span) error {
+	f.Lock()
+	defer f.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.encoder.Encode(span)
+}
+
+// Close close the file
+func (f *FileExporter) Close() error {
+	f.Lock()
+	defer f.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,271 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+)
+
+// TraceParentHeader the w3c trace context header which carries the span context between the services
+const TraceParentHeader = "traceparent"
+
+const (
+	traceParentVersion = "00"
+	sampledFlag        = "01"
+	notSampledFlag     = "00"
+)
+
+// SpanKind describe the role of the span in the request
+type SpanKind string
+
+const (
+	// SpanKindServer the span handle a request from the other service
+	SpanKindServer SpanKind = "server"
+	// SpanKindClient the span send a request to the other service
+	SpanKindClient SpanKind = "client"
+	// SpanKindInternal the span of the operation inside the service
+	SpanKindInternal SpanKind = "internal"
+)
+
+// SpanContext the identity of a span which is propagated between the services
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// IsValid returns whether the span context can be used as a parent
+func (sc SpanContext) IsValid() bool {
+	return isHex(sc.TraceID, 32) && isHex(sc.SpanID, 16) &&
+		strings.Trim(sc.TraceID, "0") != "" && strings.Trim(sc.SpanID, "0") != ""
+}
+
+// TraceParent format the span context as the value of the traceparent header
+func (sc SpanContext) TraceParent() string {
+	flag := notSampledFlag
+	if sc.Sampled {
+		flag = sampledFlag
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", traceParentVersion, sc.TraceID, sc.SpanID, flag)
+}
+
+// ParseTraceParent parse the value of the traceparent header, the format is
+// version-traceid-parentid-flags, such as 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01
+func ParseTraceParent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == traceParentVersion && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	if !isHex(flags, 2) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent flags %q", flags)
+	}
+	flag, _ := strconv.ParseUint(flags, 16, 8)
+	sc := SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flag&1 == 1,
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent id %q", value)
+	}
+	return sc, nil
+}
+
+// isHex check the value is a lower case hex string with the length
+func isHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, c := range value {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func newID(size int) string {
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		mrand.Read(id)
+	}
+	return hex.EncodeToString(id)
+}
+
+// Span a timed operation of a trace
+type Span struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Name       string            `json:"name"`
+	Kind       SpanKind          `json:"kind"`
+	Service    string            `json:"service"`
+	StartTime  time.Time         `json:"start_time"`
+	EndTime    time.Time         `json:"end_time"`
+	Duration   float64           `json:"duration_ms"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+
+	lock     sync.Mutex
+	sampled  bool
+	finished bool
+}
+
+// SetAttribute set a attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = value
+}
+
+// SetError record the error of the operation, a nil error is ignored
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.lock.Lock()
+	s.Error = err.Error()
+	s.lock.Unlock()
+}
+
+// Context returns the span context which is used to propagate the span
+func (s *Span) Context() SpanContext {
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID, Sampled: s.sampled}
+}
+
+// TraceParent returns the value of the traceparent header which take this span as the parent
+func (s *Span) TraceParent() string {
+	return s.Context().TraceParent()
+}
+
+// Finish end the span and export it if the span is sampled, the span can only be finished once
+func (s *Span) Finish() {
+	s.lock.Lock()
+	if s.finished {
+		s.lock.Unlock()
+		return
+	}
+	s.finished = true
+	s.EndTime = time.Now()
+	s.Duration = float64(s.EndTime.Sub(s.StartTime)) / float64(time.Millisecond)
+	s.lock.Unlock()
+
+	if !s.sampled {
+		return
+	}
+	export(s)
+}
+
+// NewSpan create a span, it's the root span of a new trace if the parent is invalid
+func NewSpan(parent SpanContext, name string, kind SpanKind) *Span {
+	span := &Span{
+		SpanID:    newID(8),
+		Name:      name,
+		Kind:      kind,
+		Service:   common.GetIdentification(),
+		StartTime: time.Now(),
+	}
+	if parent.IsValid() {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+		span.sampled = parent.Sampled
+	} else {
+		span.TraceID = newID(16)
+		span.sampled = shouldSample()
+	}
+	return span
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of the parent context which carries the span context
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context carried by the context
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// StartSpan start a span as the child of the span carried by the context, and returns
+// the context which carries the new span, so that the operations it called are traced as its children.
+func StartSpan(ctx context.Context, name string, kind SpanKind) (*Span, context.Context) {
+	parent, _ := SpanContextFromContext(ctx)
+	span := NewSpan(parent, name, kind)
+	return span, ContextWithSpanContext(ctx, span.Context())
+}
+
+// Extract returns the span context carried by the traceparent header
+func Extract(header http.Header) (SpanContext, bool) {
+	if header == nil {
+		return SpanContext{}, false
+	}
+	value := header.Get(TraceParentHeader)
+	if value == "" {
+		return SpanContext{}, false
+	}
+	sc, err := ParseTraceParent(value)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+// Inject set the traceparent header, so that the receiver take the span as the parent
+func Inject(header http.Header, span *Span) {
+	header.Set(TraceParentHeader, span.TraceParent())
+}
+
+// ContextWithHeader returns a copy of the parent context which carries the span context of the header
+func ContextWithHeader(ctx context.Context, header http.Header) context.Context {
+	sc, ok := Extract(header)
+	if !ok {
+		if ctx == nil {
+			return context.Background()
+		}
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// StartServerSpan start a server span as the child of the span carried by the request header
+func StartServerSpan(header http.Header, name string) *Span {
+	parent, _ := Extract(header)
+	return NewSpan(parent, name, SpanKindServer)
+}
@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := ParseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err != nil {
+		t.Fatalf("parse traceparent failed, err: %v", err)
+	}
+	if sc.TraceID != "0af7651916cd43dd8448eb211c80319c" || sc.SpanID != "b7ad6b7169203331" || !sc.Sampled {
+		t.Fatalf("unexpected span context: %+v", sc)
+	}
+	if sc.TraceParent() != "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01" {
+		t.Fatalf("unexpected traceparent: %s", sc.TraceParent())
+	}
+
+	invalids := []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+	}
+	for _, value := range invalids {
+		if _, err := ParseTraceParent(value); err == nil {
+			t.Errorf("traceparent %q should be invalid", value)
+		}
+	}
+}
+
+func TestSpanPropagation(t *testing.T) {
+	header := http.Header{}
+	header.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+
+	server := StartServerSpan(header, "POST /create")
+	if server.TraceID != "0af7651916cd43dd8448eb211c80319c" || server.ParentID != "b7ad6b7169203331" {
+		t.Fatalf("server span should be the child of the header span: %+v", server)
+	}
+	if server.Context().Sampled {
+		t.Fatalf("the sampled flag of the parent should be inherited")
+	}
+
+	Inject(header, server)
+	client, ctx := StartSpan(ContextWithHeader(context.Background(), header), "mongo find", SpanKindClient)
+	if client.TraceID != server.TraceID || client.ParentID != server.SpanID {
+		t.Fatalf("client span should be the child of the server span: %+v", client)
+	}
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok || sc.SpanID != client.SpanID {
+		t.Fatalf("the context should carry the client span, got %+v", sc)
+	}
+
+	root, _ := StartSpan(context.Background(), "root", SpanKindInternal)
+	if root.ParentID != "" || !root.Context().IsValid() {
+		t.Fatalf("unexpected root span: %+v", root)
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.json")
+
+	if err := Configure(map[string]string{"trace.exporter": "unknown"}); err == nil {
+		t.Fatalf("unknown exporter should be rejected")
+	}
+	if err := Configure(map[string]string{"trace.exporter": ExporterFile, "trace.file": path, "trace.sampleRate": "2"}); err == nil {
+		t.Fatalf("invalid sample rate should be rejected")
+	}
+	if err := Configure(map[string]string{"trace.exporter": ExporterFile, "trace.file": path}); err != nil {
+		t.Fatalf("configure file exporter failed, err: %v", err)
+	}
+	defer Configure(map[string]string{})
+
+	parent, ctx := StartSpan(context.Background(), "GET /find", SpanKindServer)
+	child, _ := StartSpan(ctx, "mongo find", SpanKindClient)
+	child.SetAttribute("db.collection", "cc_HostBase")
+	child.SetError(errors.New("not found"))
+	child.Finish()
+	child.Finish()
+	parent.Finish()
+
+	// the exporter is closed, so that all the spans are written to the file
+	if err := Configure(map[string]string{}); err != nil {
+		t.Fatalf("configure none exporter failed, err: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	spans := make([]*Span, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		span := new(Span)
+		if err := json.Unmarshal(scanner.Bytes(), span); err != nil {
+			t.Fatalf("unmarshal span failed, err: %v", err)
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expect 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "mongo find" || spans[0].ParentID != spans[1].SpanID || spans[0].TraceID != spans[1].TraceID {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	if spans[0].Attributes["db.collection"] != "cc_HostBase" || spans[0].Error != "not found" {
+		t.Fatalf("unexpected span attributes: %+v", spans[0])
+	}
+}
@@ -18,11 +18,13 @@ import (
 	"net/http"
 	"reflect"
 	"runtime/debug"
+	"strconv"
 	"sync/atomic"
 	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/trace"
 	"configcenter/src/storage/dal"
 
 	restful "github.com/emicklei/go-restful"
@@ -132,8 +134,33 @@ func GetHTTPCCTransaction(header http.Header) string {
 	return rid
 }
 
+// GetHTTPCCRequestTimeout return the time left before the caller gives up the request from http header,
+// ok is false if the header is not set or invalid
+func GetHTTPCCRequestTimeout(header http.Header) (timeout time.Duration, ok bool) {
+	value := header.Get(common.BKHTTPCCRequestTimeout)
+	if value == "" {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// SetHTTPCCRequestTimeout set the time left before the caller gives up the request to http header
+func SetHTTPCCRequestTimeout(header http.Header, timeout time.Duration) {
+	ms := int64(timeout / time.Millisecond)
+	if ms < 0 {
+		ms = 0
+	}
+	header.Set(common.BKHTTPCCRequestTimeout, strconv.FormatInt(ms, 10))
+}
+
 // GetDBContext returns a new context that contains JoinOption
 func GetDBContext(parent context.Context, header http.Header) context.Context {
+	// carry the span of the request, so that the db operations are traced as its children
+	parent = trace.ContextWithHeader(parent, header)
 	return context.WithValue(parent, common.CCContextKeyJoinOption, dal.JoinOption{
 		RequestID: header.Get(common.BKHTTPCCRequestID),
 		TxnID:     header.Get(common.BKHTTPCCTransactionID),
@@ -12,3 +12,45 @@
  
 package trace
 
+import (
+	"strconv"
+
+	"configcenter/src/common/trace"
+	"configcenter/src/framework/core/httpserver"
+	"configcenter/src/framework/core/log"
+	"github.com/emicklei/go-restful"
+)
+
+type Manager struct{}
+
+var _ Trace = &Manager{}
+
+// NewManager set the trace exporter with the trace.* configs
+func NewManager(conf map[string]string) Trace {
+	if err := trace.Configure(conf); err != nil {
+		log.Errorf("configure trace failed, err: %v", err)
+	}
+	return &Manager{}
+}
+
+// Actions returns the actions whose requests are handled with a server span
+func (m *Manager) Actions(as ...httpserver.Action) []httpserver.Action {
+	var httpactions []httpserver.Action
+	for _, a := range as {
+		httpactions = append(httpactions, httpserver.Action{Method: a.Method, Path: a.Path, Handler: traceHandler(a.Method+" "+a.Path, a.Handler)})
+	}
+	return httpactions
+}
+
+func traceHandler(name string, handler restful.RouteFunction) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		span := trace.StartServerSpan(req.Request.Header, name)
+		trace.Inject(req.Request.Header, span)
+		req.Request = req.Request.WithContext(trace.ContextWithSpanContext(req.Request.Context(), span.Context()))
+
+		handler(req, resp)
+
+		span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode()))
+		span.Finish()
+	}
+}
@@ -12,3 +12,12 @@
  
 package trace
 
+
+import (
+	"configcenter/src/framework/core/httpserver"
+)
+
+// Trace interface
+type Trace interface {
+	Actions(as ...httpserver.Action) []httpserver.Action
+}
@@ -30,6 +30,7 @@ import (
 	"configcenter/src/framework/core/httpserver"
 	"configcenter/src/framework/core/log"
 	"configcenter/src/framework/core/monitor/metric"
+	"configcenter/src/framework/core/monitor/trace"
 	"configcenter/src/framework/core/option"
 	"configcenter/src/framework/core/output/module/client"
 	_ "configcenter/src/framework/plugins"
@@ -108,8 +109,9 @@ func main() {
 	}
 
 	metricManager := metric.NewManager(opt)
+	traceManager := trace.NewManager(config.Get())
 
-	server.RegisterActions(api.Actions()...)
+	server.RegisterActions(traceManager.Actions(api.Actions()...)...)
 	server.RegisterActions(metricManager.Actions()...)
 
 	httpChan := make(chan error, 1)
@@ -15,6 +15,7 @@ package options
 import (
 	"configcenter/src/common/core/cc/config"
 	"configcenter/src/storage/dal/mongo"
+	"configcenter/src/storage/dal/redis"
 
 	"github.com/spf13/pflag"
 )
@@ -41,6 +42,7 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 
 type Config struct {
 	MongoDB       mongo.Config
+	Redis         redis.Config
 	Errors        ErrorConfig
 	Language      LanguageConfig
 	Configures    ConfConfig
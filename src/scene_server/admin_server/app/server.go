@@ -26,6 +26,7 @@ import (
 	"configcenter/src/common/backbone/configcenter"
 	cc "configcenter/src/common/backbone/configcenter"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/eventclient"
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/scene_server/admin_server/app/options"
@@ -33,6 +34,7 @@ import (
 	svc "configcenter/src/scene_server/admin_server/service"
 	"configcenter/src/storage/dal/mongo"
 	"configcenter/src/storage/dal/mongo/local"
+	"configcenter/src/storage/dal/redis"
 )
 
 func Run(ctx context.Context, op *options.ServerOption) error {
@@ -63,7 +65,7 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	service.Engine = engine
 	process.Core = engine
 	process.Service = service
-	process.ConfigCenter = configures.NewConfCenter(ctx, engine.ServiceManageClient())
+	process.ConfigCenter = configures.NewConfCenter(ctx, engine.ConfRegDiscover())
 	for {
 		if process.Config == nil {
 			time.Sleep(time.Second * 2)
@@ -75,6 +77,15 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 			return fmt.Errorf("connect mongo server failed %s", err.Error())
 		}
 		process.Service.SetDB(db)
+		if "" != process.Config.Redis.Address {
+			cache, err := redis.NewFromConfig(process.Config.Redis)
+			if err != nil {
+				return fmt.Errorf("connect redis server failed %s", err.Error())
+			}
+			process.Service.SetEventC(eventclient.NewClientViaRedis(cache, db))
+		} else {
+			blog.Warnf("the redis is not configured, the model events will not be pushed after migrate")
+		}
 		process.Service.SetApiSrvAddr(process.Config.ProcSrvConfig.CCApiSrvAddr)
 		err = process.ConfigCenter.Start(
 			process.Config.Configures.Dir,
@@ -116,6 +127,7 @@ func (h *MigrateServer) onHostConfigUpdate(previous, current cc.ProcessConfig) {
 
 		mongoConf := mongo.ParseConfigFromKV("mongodb", current.ConfigMap)
 		h.Config.MongoDB = mongoConf
+		h.Config.Redis = redis.ParseConfigFromKV("redis", current.ConfigMap)
 
 		h.Config.Errors.Res = current.ConfigMap["errors.res"]
 		h.Config.Language.Res = current.ConfigMap["language.res"]
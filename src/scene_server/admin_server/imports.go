@@ -35,4 +35,9 @@ import (
 	_ "configcenter/src/scene_server/admin_server/upgrader/x18.12.12.03"
 	_ "configcenter/src/scene_server/admin_server/upgrader/x19.01.18.01"
 	_ "configcenter/src/scene_server/admin_server/upgrader/x19.02.15.10"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.03.11.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.03.18.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.03.25.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.04.02.01"
+	_ "configcenter/src/scene_server/admin_server/upgrader/x19.04.02.02"
 )
@@ -41,6 +41,7 @@ func (s *Service) clear(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
+	s.pushModelEvent(pheader)
 	resp.WriteEntity(metadata.NewSuccessResp(nil))
 }
 
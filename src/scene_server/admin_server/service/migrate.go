@@ -42,5 +42,6 @@ func (s *Service) migrate(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
+	s.pushModelEvent(pheader)
 	resp.WriteEntity(metadata.NewSuccessResp("migrate success"))
 }
@@ -14,12 +14,15 @@ package service
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/emicklei/go-restful"
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/eventclient"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
 	"configcenter/src/common/rdapi"
@@ -30,6 +33,7 @@ import (
 type Service struct {
 	*backbone.Engine
 	db           dal.RDB
+	eventC       eventclient.Client
 	ccApiSrvAddr string
 	ctx          context.Context
 }
@@ -44,6 +48,11 @@ func (s *Service) SetDB(db dal.RDB) {
 	s.db = db
 }
 
+// SetEventC set the event client, the model events are pushed with it after the models are changed
+func (s *Service) SetEventC(eventC eventclient.Client) {
+	s.eventC = eventC
+}
+
 func (s *Service) SetApiSrvAddr(ccApiSrvAddr string) {
 	s.ccApiSrvAddr = ccApiSrvAddr
 }
@@ -101,3 +110,15 @@ func (s *Service) Healthz(req *restful.Request, resp *restful.Response) {
 	resp.Header().Set("Content-Type", "application/json")
 	resp.WriteEntity(answer)
 }
+
+// pushModelEvent push the model event after the upgraders change the model definitions in the database directly,
+// the event server publishes it to invalidate the caches of the model definitions
+func (s *Service) pushModelEvent(header http.Header) {
+	if nil == s.eventC {
+		return
+	}
+	event := eventclient.NewModelEvent(header, metadata.EventObjTypeModel, metadata.EventActionUpdate)
+	if err := s.eventC.Push(s.ctx, event); nil != err {
+		blog.Errorf("failed to push the model event, error info is %s", err.Error())
+	}
+}
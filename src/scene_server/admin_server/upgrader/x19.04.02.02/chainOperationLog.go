@@ -0,0 +1,205 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x19_04_02_02
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+const (
+	chainPageSize = 500
+	// chainRetry the max times to relink a record when the auditcontrollers extend the chain at the same time
+	chainRetry = 5
+)
+
+// markOperationLog give the operation logs written before the audit chain was introduced a negative seq in the order
+// of operation time, so that they don't conflict on the unique chain index and stay out of the chain until linked
+func markOperationLog(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	owners, err := getOwners(ctx, db, map[string]interface{}{common.BKDBExists: false})
+	if err != nil {
+		return err
+	}
+
+	for _, ownerID := range owners {
+		// continue after the records marked by the interrupted run, they are not linked yet
+		mark, err := getLowestMark(ctx, db, ownerID)
+		if err != nil {
+			return err
+		}
+		cond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBExists: false},
+		}
+		for {
+			ids := make([]bson.M, 0)
+			// the marked records no longer match the condition, so always read the first page
+			err := db.Table(common.BKTableNameOperationLog).Find(cond).Fields("_id").Sort(common.BKOpTimeField+",_id").Limit(chainPageSize).All(ctx, &ids)
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			for _, id := range ids {
+				mark--
+				data := map[string]interface{}{auditoplog.ChainFieldSeq: mark}
+				if err := db.Table(common.BKTableNameOperationLog).Update(ctx, map[string]interface{}{"_id": id["_id"]}, data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// chainOperationLog link the marked operation logs to the end of the audit chain of their supplier account,
+// the unique chain index makes the record relinked when an auditcontroller takes the same seq at the same time
+func chainOperationLog(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	owners, err := getOwners(ctx, db, map[string]interface{}{common.BKDBLT: 0})
+	if err != nil {
+		return err
+	}
+
+	for _, ownerID := range owners {
+		seq, prevHash, err := getChainHead(ctx, db, ownerID)
+		if err != nil {
+			return err
+		}
+
+		cond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBLT: 0},
+		}
+		for {
+			docs := make([]bson.M, 0)
+			// the chained records no longer match the condition, so always read the first page
+			err := db.Table(common.BKTableNameOperationLog).Find(cond).Sort("-"+auditoplog.ChainFieldSeq).Limit(chainPageSize).All(ctx, &docs)
+			if err != nil {
+				return err
+			}
+			if len(docs) == 0 {
+				break
+			}
+
+			for _, doc := range docs {
+				seq, prevHash, err = linkOperationLog(ctx, db, ownerID, doc, seq, prevHash)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		blog.Infof("chain operation logs of supplier account %s success, chain head at seq %d", ownerID, seq)
+	}
+	return nil
+}
+
+// linkOperationLog link the record after the chain head, and return the new chain head
+func linkOperationLog(ctx context.Context, db dal.RDB, ownerID string, doc bson.M, seq int64, prevHash string) (int64, string, error) {
+	for retry := 0; retry < chainRetry; retry++ {
+		doc[auditoplog.ChainFieldSeq] = seq + 1
+		doc[auditoplog.ChainFieldPrevHash] = prevHash
+		hash, err := auditoplog.AuditDocHash(doc)
+		if err != nil {
+			return 0, "", err
+		}
+		data := map[string]interface{}{
+			auditoplog.ChainFieldSeq:      seq + 1,
+			auditoplog.ChainFieldPrevHash: prevHash,
+			auditoplog.ChainFieldHash:     hash,
+		}
+		err = db.Table(common.BKTableNameOperationLog).Update(ctx, map[string]interface{}{"_id": doc["_id"]}, data)
+		if err == nil {
+			return seq + 1, hash, nil
+		}
+		if !db.IsDuplicatedError(err) {
+			return 0, "", err
+		}
+		// an auditcontroller extended the chain at the same time, link to the new head
+		if seq, prevHash, err = getChainHead(ctx, db, ownerID); err != nil {
+			return 0, "", err
+		}
+	}
+	return 0, "", fmt.Errorf("link operation log %v of supplier account %s failed, the chain is extended too frequently", doc["_id"], ownerID)
+}
+
+// getLowestMark return the lowest negative seq of the marked operation logs of the supplier account, 0 if none
+func getLowestMark(ctx context.Context, db dal.RDB, ownerID string) (int64, error) {
+	marks := make([]bson.M, 0)
+	cond := map[string]interface{}{
+		common.BKOwnerIDField:    ownerID,
+		auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBLT: 0},
+	}
+	err := db.Table(common.BKTableNameOperationLog).Find(cond).Fields(auditoplog.ChainFieldSeq).Sort(auditoplog.ChainFieldSeq).Limit(1).All(ctx, &marks)
+	if err != nil {
+		return 0, err
+	}
+	if len(marks) == 0 {
+		return 0, nil
+	}
+	mark, _ := marks[0][auditoplog.ChainFieldSeq].(int64)
+	return mark, nil
+}
+
+// getOwners return the supplier accounts which have operation logs matching the seq condition
+func getOwners(ctx context.Context, db dal.RDB, seqCond map[string]interface{}) ([]string, error) {
+	owners := make([]struct {
+		OwnerID string `bson:"_id"`
+	}, 0)
+	pipeline := []map[string]interface{}{
+		{common.BKDBMatch: map[string]interface{}{auditoplog.ChainFieldSeq: seqCond}},
+		{"$group": map[string]interface{}{"_id": "$" + common.BKOwnerIDField}},
+	}
+	if err := db.Table(common.BKTableNameOperationLog).AggregateAll(ctx, pipeline, &owners); err != nil {
+		return nil, err
+	}
+	ownerIDs := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		ownerIDs = append(ownerIDs, owner.OwnerID)
+	}
+	return ownerIDs, nil
+}
+
+// getChainHead return the last link of the audit chain of the supplier account, the records chained by
+// the upgraded auditcontrollers may have been archived already
+func getChainHead(ctx context.Context, db dal.RDB, ownerID string) (int64, string, error) {
+	var seq int64
+	var prevHash string
+	cond := map[string]interface{}{
+		common.BKOwnerIDField:    ownerID,
+		auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: 0},
+	}
+	for _, table := range []string{common.BKTableNameOperationLog, common.BKTableNameAuditArchived} {
+		heads := make([]bson.M, 0)
+		err := db.Table(table).Find(cond).Fields(auditoplog.ChainFieldSeq, auditoplog.ChainFieldHash).Sort("-"+auditoplog.ChainFieldSeq).Limit(1).All(ctx, &heads)
+		if err != nil {
+			return 0, "", err
+		}
+		if len(heads) == 0 {
+			continue
+		}
+		if headSeq, ok := heads[0][auditoplog.ChainFieldSeq].(int64); ok && headSeq > seq {
+			seq = headSeq
+			prevHash, _ = heads[0][auditoplog.ChainFieldHash].(string)
+		}
+	}
+	return seq, prevHash, nil
+}
@@ -113,6 +113,10 @@ func (eh *EventHandler) handleInst(event *metadata.EventInstCtx) (err error) {
 		err = eh.SaveEventDone(event)
 	}()
 
+	if event.EventType == metadata.EventTypeModel {
+		eh.publishModelEvent(event)
+	}
+
 	origindists := eh.GetDistInst(&event.EventInst)
 
 	for _, origindist := range origindists {
@@ -176,6 +180,13 @@ func (eh *EventHandler) GetDistInst(e *metadata.EventInst) []metadata.DistInst {
 	return ds
 }
 
+// publishModelEvent publish the model event to the channel watched by the caches of the model definitions
+func (eh *EventHandler) publishModelEvent(event *metadata.EventInstCtx) {
+	if err := eh.cache.Publish(common.EventCacheModelChannel, event.Raw).Err(); err != nil {
+		blog.Errorf("publish model event %d failed: %v", event.ID, err)
+	}
+}
+
 func (eh *EventHandler) pushToQueue(key, value string) (err error) {
 	err = eh.cache.RPush(key, value).Err()
 	blog.Infof("pushed to queue:%v", key)
@@ -31,6 +31,16 @@ import (
 )
 
 func (lgc *Logics) AddHost(ctx context.Context, appID int64, moduleID []int64, ownerID string, hostInfos map[int64]map[string]interface{}, importType metadata.HostInputType) ([]string, []string, []string, error) {
+	return lgc.addHost(ctx, appID, moduleID, ownerID, hostInfos, importType, false)
+}
+
+// AddHostAllOrNothing add the hosts like AddHost, but stop at the first failed host and roll back the written hosts,
+// the created hosts are deleted and the updated hosts are restored to their previous values.
+func (lgc *Logics) AddHostAllOrNothing(ctx context.Context, appID int64, moduleID []int64, ownerID string, hostInfos map[int64]map[string]interface{}, importType metadata.HostInputType) ([]string, []string, []string, error) {
+	return lgc.addHost(ctx, appID, moduleID, ownerID, hostInfos, importType, true)
+}
+
+func (lgc *Logics) addHost(ctx context.Context, appID int64, moduleID []int64, ownerID string, hostInfos map[int64]map[string]interface{}, importType metadata.HostInputType, allOrNothing bool) ([]string, []string, []string, error) {
 
 	instance := NewImportInstance(ctx, ownerID, lgc)
 	var err error
@@ -52,7 +62,12 @@ func (lgc *Logics) AddHost(ctx context.Context, appID int64, moduleID []int64, o
 		return nil, nil, nil, err
 	}
 
+	// the hosts written in the all or nothing mode, they are rolled back if any host fails
+	written := make([]writtenHost, 0)
 	for index, host := range hostInfos {
+		if allOrNothing && (0 < len(errMsg) || 0 < len(updateErrMsg)) {
+			break
+		}
 		if nil == host {
 			continue
 		}
@@ -97,15 +112,25 @@ func (lgc *Logics) AddHost(ctx context.Context, appID int64, moduleID []int64, o
 			}
 			// delete system fields
 			delete(host, common.BKHostIDField)
-			preData, _, _ = lgc.GetHostInstanceDetails(ctx, ownerID, strconv.FormatInt(intHostID, 10))
+			preData, _, err = lgc.GetHostInstanceDetails(ctx, ownerID, strconv.FormatInt(intHostID, 10))
+			if err != nil && allOrNothing {
+				// the host can not be restored without the previous data
+				updateErrMsg = append(updateErrMsg, lgc.ccLang.Languagef("host_import_update_fail", index, innerIP, err.Error()))
+				continue
+			}
 			// update host instance.
 			if err := instance.updateHostInstance(index, host, intHostID); err != nil {
 				updateErrMsg = append(updateErrMsg, err.Error())
 				continue
 			}
+			written = append(written, writtenHost{index: index, hostID: intHostID, preData: preData, fields: host})
 
 		} else {
 			intHostID, err = instance.addHostInstance(int64(common.BKDefaultDirSubArea), index, appID, moduleID, host)
+			if 0 != intHostID {
+				// the host may be created even if it fails to be added to the module
+				written = append(written, writtenHost{index: index, hostID: intHostID})
+			}
 			if err != nil {
 				errMsg = append(errMsg, err.Error())
 				continue
@@ -131,6 +156,11 @@ func (lgc *Logics) AddHost(ctx context.Context, appID int64, moduleID []int64, o
 		})
 	}
 
+	if allOrNothing && (0 < len(errMsg) || 0 < len(updateErrMsg)) {
+		errMsg = append(errMsg, lgc.rollbackAddHost(ctx, appID, instance, written)...)
+		return nil, updateErrMsg, errMsg, errors.New(lgc.ccLang.Language("host_import_err"))
+	}
+
 	if len(logConents) > 0 {
 		log := map[string]interface{}{
 			common.BKContentField: logConents,
@@ -304,11 +334,76 @@ func (h *importInstance) addHostInstance(cloudID, index, appID int64, moduleID [
 	hResult, err := h.CoreAPI.HostController().Module().AddModuleHostConfig(h.ctx, h.pheader, opt)
 	if err != nil {
 		blog.Errorf("add host module by ip:%s  err:%s,input:%+v,rid:%s", ip, err.Error(), opt, h.rid)
-		return 0, fmt.Errorf(h.ccLang.Languagef("host_import_add_fail", index, ip, err.Error()))
+		return hostID, fmt.Errorf(h.ccLang.Languagef("host_import_add_fail", index, ip, err.Error()))
 	} else if err == nil && !hResult.Result {
 		blog.Errorf("add host module by ip:%s  err code:%d,err msg:%s,input:%+v,rid:%s", ip, hResult.Code, hResult.ErrMsg, opt, h.rid)
-		return 0, fmt.Errorf(h.ccLang.Languagef("host_import_add_fail", index, ip, hResult.ErrMsg))
+		return hostID, fmt.Errorf(h.ccLang.Languagef("host_import_add_fail", index, ip, hResult.ErrMsg))
 	}
 
 	return hostID, nil
 }
+
+// writtenHost a host written by the all or nothing import, the fields are nil if the host is created by the row
+type writtenHost struct {
+	index   int64
+	hostID  int64
+	preData map[string]interface{}
+	fields  map[string]interface{}
+}
+
+// rollbackAddHost roll back the written hosts of the all or nothing import in the reverse order,
+// the created hosts are deleted and the updated hosts are restored to their previous values.
+// it returns the error messages of the hosts which can not be rolled back.
+func (lgc *Logics) rollbackAddHost(ctx context.Context, appID int64, h *importInstance, written []writtenHost) []string {
+	errMsg := make([]string, 0)
+	for idx := len(written) - 1; idx >= 0; idx-- {
+		host := written[idx]
+		if nil == host.fields {
+			if err := h.deleteHostInstance(appID, host.hostID); err != nil {
+				errMsg = append(errMsg, lgc.ccLang.Languagef("import_row_int_error_str", host.index, err.Error()))
+			}
+			continue
+		}
+
+		// restore the fields written by the row, the fields which did not exist are cleared
+		data := make(map[string]interface{})
+		for field := range host.fields {
+			data[field] = host.preData[field]
+		}
+		if err := h.updateHostInstance(host.index, data, host.hostID); err != nil {
+			errMsg = append(errMsg, err.Error())
+		}
+	}
+	return errMsg
+}
+
+// deleteHostInstance delete the host created by the import, and its module relations
+func (h *importInstance) deleteHostInstance(appID, hostID int64) error {
+	opt := &metadata.ModuleHostConfigParams{
+		ApplicationID: appID,
+		HostID:        hostID,
+	}
+	hResult, err := h.CoreAPI.HostController().Module().DelModuleHostConfig(h.ctx, h.pheader, opt)
+	if err != nil {
+		blog.Errorf("deleteHostInstance DelModuleHostConfig http do error, err:%s, input:%+v, rid:%s", err.Error(), opt, h.rid)
+		return h.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !hResult.Result {
+		blog.Errorf("deleteHostInstance DelModuleHostConfig http response error, err code:%d, err msg:%s, input:%+v, rid:%s", hResult.Code, hResult.ErrMsg, opt, h.rid)
+		return h.ccErr.New(hResult.Code, hResult.ErrMsg)
+	}
+
+	input := &metadata.DeleteOption{
+		Condition: map[string]interface{}{common.BKHostIDField: hostID},
+	}
+	dResult, err := h.CoreAPI.CoreService().Instance().DeleteInstanceCascade(h.ctx, h.pheader, common.BKInnerObjIDHost, input)
+	if err != nil {
+		blog.Errorf("deleteHostInstance http do error, err:%s, input:%+v, rid:%s", err.Error(), input, h.rid)
+		return h.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !dResult.Result {
+		blog.Errorf("deleteHostInstance http response error, err code:%d, err msg:%s, input:%+v, rid:%s", dResult.Code, dResult.ErrMsg, input, h.rid)
+		return h.ccErr.New(dResult.Code, dResult.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// previewIgnoreHostFields the host fields which are not compared when preview the import
+var previewIgnoreHostFields = []string{
+	"import_from",
+	common.BKHostIDField,
+	common.BKOwnerIDField,
+	common.CreateTimeField,
+	common.LastTimeField,
+}
+
+// PreviewAddHost classify every host row as create, update, unchanged or error the same way as AddHost does,
+// nothing is written.
+func (lgc *Logics) PreviewAddHost(ctx context.Context, ownerID string, hostInfos map[int64]map[string]interface{}) (*metadata.ImportPreview, error) {
+	hostMap, err := lgc.getAddHostIDMap(ctx, hostInfos)
+	if err != nil {
+		blog.Errorf("preview add host, but get hosts failed, err:%s, rid:%s", err.Error(), lgc.rid)
+		return nil, err
+	}
+
+	hostIDs := make([]int64, 0)
+	for _, host := range hostInfos {
+		if hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField]); nil == err {
+			hostIDs = append(hostIDs, hostID)
+		}
+	}
+	idHostMap, err := lgc.getHostMapByID(ctx, hostIDs)
+	if err != nil {
+		blog.Errorf("preview add host, but get hosts by id failed, err:%s, rid:%s", err.Error(), lgc.rid)
+		return nil, err
+	}
+
+	indexes := make([]int64, 0, len(hostInfos))
+	for index := range hostInfos {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	preview := &metadata.ImportPreview{}
+	fileKeys := make(map[string]int64)
+	validItems := make([]metadata.ValidateModelInstance, 0)
+	validRows := make([]int64, 0)
+	for _, index := range indexes {
+		if nil == hostInfos[index] {
+			continue
+		}
+		host := mapstr.NewFromMap(hostInfos[index]).Clone()
+		row := metadata.ImportPreviewRow{Row: index}
+
+		innerIP, isOk := host[common.BKHostInnerIPField].(string)
+		if isOk == false || "" == innerIP {
+			row.Action = metadata.ImportActionError
+			row.Error = lgc.ccLang.Languagef("host_import_innerip_empty", strconv.FormatInt(index, 10))
+			preview.Add(row)
+			continue
+		}
+
+		iSubArea := host[common.BKCloudIDField]
+		if nil == iSubArea {
+			iSubArea = common.BKDefaultDirSubArea
+		}
+		key := lgc.getHostIPCloudKey(innerIP, iSubArea)
+		if _, ok := fileKeys[key]; ok {
+			row.Action = metadata.ImportActionError
+			row.Error = lgc.ccLang.Languagef("host_import_add_fail", index, innerIP, lgc.ccErr.Errorf(common.CCErrCommDuplicateItem, innerIP).Error())
+			preview.Add(row)
+			continue
+		}
+		fileKeys[key] = index
+
+		var origin map[string]interface{}
+		if iHostID, ok := host[common.BKHostIDField]; ok {
+			hostID, err := util.GetInt64ByInterface(iHostID)
+			if err != nil {
+				row.Action = metadata.ImportActionError
+				row.Error = lgc.ccLang.Languagef("host_import_update_fail", index, innerIP, lgc.ccErr.Errorf(common.CCErrCommParamsIsInvalid, common.BKHostIDField).Error())
+				preview.Add(row)
+				continue
+			}
+			origin = idHostMap[hostID]
+			if nil == origin {
+				row.Action = metadata.ImportActionError
+				row.Error = lgc.ccLang.Languagef("host_import_update_fail", index, innerIP, lgc.ccErr.Error(common.CCErrCommNotFound).Error())
+				preview.Add(row)
+				continue
+			}
+		} else {
+			origin = hostMap[key]
+		}
+
+		validItem := metadata.ValidateModelInstance{Data: host}
+		if nil == origin {
+			row.Action = metadata.ImportActionCreate
+			if _, ok := host[common.BKCloudIDField]; !ok {
+				host[common.BKCloudIDField] = common.BKDefaultDirSubArea
+			}
+			row.Diff = metadata.DiffImportData(nil, host, previewIgnoreHostFields...)
+		} else {
+			row.InstID, _ = util.GetInt64ByInterface(origin[common.BKHostIDField])
+			row.Diff = metadata.DiffImportData(origin, host, previewIgnoreHostFields...)
+			if 0 == len(row.Diff) {
+				row.Action = metadata.ImportActionUnchanged
+				preview.Add(row)
+				continue
+			}
+			row.Action = metadata.ImportActionUpdate
+			// the same fields are removed when update the host
+			delete(host, common.BKHostIDField)
+			delete(host, "import_from")
+			delete(host, common.CreateTimeField)
+			validItem.InstID = uint64(row.InstID)
+		}
+		preview.Add(row)
+		validItems = append(validItems, validItem)
+		validRows = append(validRows, index)
+	}
+
+	if 0 == len(validItems) {
+		return preview, nil
+	}
+
+	input := &metadata.ValidateManyModelInstance{Datas: validItems}
+	result, err := lgc.CoreAPI.CoreService().Instance().ValidateManyInstance(ctx, lgc.header, common.BKInnerObjIDHost, input)
+	if err != nil {
+		blog.Errorf("preview add host http do error, err:%s, rid:%s", err.Error(), lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("preview add host http response error, err code:%d, err msg:%s, rid:%s", result.Code, result.ErrMsg, lgc.rid)
+		return nil, lgc.ccErr.New(result.Code, result.ErrMsg)
+	}
+	for _, exception := range result.Data.Exceptions {
+		if exception.OriginIndex < 0 || int(exception.OriginIndex) >= len(validRows) {
+			continue
+		}
+		item := validItems[exception.OriginIndex]
+		index := validRows[exception.OriginIndex]
+		innerIP, _ := item.Data[common.BKHostInnerIPField].(string)
+		langKey := "host_import_add_fail"
+		if 0 != item.InstID {
+			langKey = "host_import_update_fail"
+		}
+		preview.SetError(index, lgc.ccLang.Languagef(langKey, index, innerIP, exception.Message))
+	}
+
+	return preview, nil
+}
+
+func (lgc *Logics) getHostMapByID(ctx context.Context, hostIDs []int64) (map[int64]map[string]interface{}, error) {
+	hostMap := make(map[int64]map[string]interface{})
+	if 0 == len(hostIDs) {
+		return hostMap, nil
+	}
+
+	query := &metadata.QueryInput{
+		Condition: map[string]interface{}{common.BKHostIDField: common.KvMap{common.BKDBIN: hostIDs}},
+		Start:     0,
+		Limit:     common.BKNoLimit,
+		Sort:      common.BKHostIDField,
+	}
+	hResult, err := lgc.CoreAPI.HostController().Host().GetHosts(ctx, lgc.header, query)
+	if err != nil {
+		return nil, errors.New(lgc.ccLang.Languagef("host_search_fail_with_errmsg", err.Error()))
+	}
+	if !hResult.Result {
+		return nil, errors.New(lgc.ccLang.Languagef("host_search_fail_with_errmsg", hResult.ErrMsg))
+	}
+
+	for _, h := range hResult.Data.Info {
+		hostID, err := util.GetInt64ByInterface(h[common.BKHostIDField])
+		if err != nil {
+			continue
+		}
+		hostMap[hostID] = h
+	}
+	return hostMap, nil
+}
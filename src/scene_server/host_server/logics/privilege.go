@@ -0,0 +1,106 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// GetUserAttributePermission get the attribute privileges of the request user, which are merged by the topo server
+func (lgc *Logics) GetUserAttributePermission(ctx context.Context) (metadata.AttributePermission, error) {
+	result, err := lgc.CoreAPI.TopoServer().Privilege().GetUserPrivi(ctx, lgc.ownerID, lgc.user, lgc.header)
+	if nil != err {
+		blog.Errorf("get the privileges of the user(%s) failed, err: %v, rid: %s", lgc.user, err, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("get the privileges of the user(%s) failed, err: %s, rid: %s", lgc.user, result.ErrMsg, lgc.rid)
+		return nil, lgc.ccErr.New(result.Code, result.ErrMsg)
+	}
+
+	privilege := new(metadata.Gprivilege)
+	data, err := json.Marshal(result.Data)
+	if nil == err {
+		err = json.Unmarshal(data, privilege)
+	}
+	if nil != err {
+		blog.Errorf("decode the privileges of the user(%s) failed, err: %v, data: %v, rid: %s", lgc.user, err, result.Data, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)
+	}
+	return privilege.AttributeConfig, nil
+}
+
+// HostSearchUnreadable return the masked attributes which the host search condition or the sort refers to
+func HostSearchUnreadable(permission metadata.AttributePermission, data *metadata.HostCommonSearch) []string {
+	var result []string
+	for _, cond := range data.Condition {
+		fields := mapstr.New()
+		for _, item := range cond.Condition {
+			fields[item.Field] = true
+		}
+		sort := ""
+		if common.BKInnerObjIDHost == cond.ObjectID {
+			sort = data.Page.Sort
+		}
+		result = append(result, permission.Unreadable(cond.ObjectID, fields, sort)...)
+	}
+
+	if 0 != len(data.Ip.Data) {
+		fields := mapstr.New()
+		flag := data.Ip.Flag
+		if "" == flag {
+			flag = common.BKHostInnerIPField
+		}
+		for _, field := range strings.Split(flag, "|") {
+			fields[field] = true
+		}
+		result = append(result, permission.Unreadable(common.BKInnerObjIDHost, fields, "")...)
+	}
+	return result
+}
+
+// MaskSearchHost mask the attributes of the hosts and their topology which the request user can not read
+func MaskSearchHost(permission metadata.AttributePermission, hosts *metadata.SearchHost) {
+	if 0 == len(permission) {
+		return
+	}
+	for _, item := range hosts.Info {
+		for objID, value := range item {
+			maskValue(permission, objID, value)
+		}
+	}
+}
+
+func maskValue(permission metadata.AttributePermission, objID string, value interface{}) {
+	switch value := value.(type) {
+	case mapstr.MapStr:
+		permission.Mask(objID, value)
+	case map[string]interface{}:
+		permission.Mask(objID, value)
+	case []mapstr.MapStr:
+		for _, item := range value {
+			permission.Mask(objID, item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			maskValue(permission, objID, item)
+		}
+	}
+}
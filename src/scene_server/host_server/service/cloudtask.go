@@ -28,7 +28,7 @@ import (
 
 // CloudAddTask create cloud sync task
 func (s *Service) AddCloudTask(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	taskList := new(meta.CloudTaskList)
 	if err := json.NewDecoder(req.Request.Body).Decode(taskList); err != nil {
@@ -49,7 +49,7 @@ func (s *Service) AddCloudTask(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) DeleteCloudTask(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	taskID := req.PathParameter("taskID")
 	_, err := s.CoreAPI.HostController().Cloud().DeleteCloudTask(srvData.ctx, srvData.header, taskID)
@@ -63,7 +63,7 @@ func (s *Service) DeleteCloudTask(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) SearchCloudTask(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -82,7 +82,7 @@ func (s *Service) SearchCloudTask(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) UpdateCloudTask(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	data := make(mapstr.MapStr, 0)
 	if err := json.NewDecoder(req.Request.Body).Decode(&data); err != nil {
@@ -148,7 +148,7 @@ func (s *Service) UpdateCloudTask(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) StartCloudSync(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{}, 0)
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -191,7 +191,7 @@ func (s *Service) StartCloudSync(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) ResourceConfirm(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	resourceIDMap := make(map[string][]int64)
 	if err := json.NewDecoder(req.Request.Body).Decode(&resourceIDMap); err != nil {
@@ -267,7 +267,7 @@ func (s *Service) ResourceConfirm(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) SearchConfirm(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -286,7 +286,7 @@ func (s *Service) SearchConfirm(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) SearchAccount(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -321,7 +321,7 @@ func (s *Service) SearchAccount(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) CloudSyncHistory(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -341,7 +341,7 @@ func (s *Service) CloudSyncHistory(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) AddConfirmHistory(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	resourceIDMap := make(map[string][]int64)
 	if err := json.NewDecoder(req.Request.Body).Decode(&resourceIDMap); err != nil {
@@ -374,7 +374,7 @@ func (s *Service) AddConfirmHistory(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) SearchConfirmHistory(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&opt); err != nil {
@@ -24,7 +24,7 @@ import (
 )
 
 func (s *Service) GetHostFavourites(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	query := new(metadata.QueryInput)
 	if err := json.NewDecoder(req.Request.Body).Decode(query); err != nil {
 		blog.Errorf("get host favourite failed with decode body err: %v,rid:%s", err, srvData.rid)
@@ -49,7 +49,7 @@ func (s *Service) GetHostFavourites(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) AddHostFavourite(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	param := new(metadata.FavouriteParms)
 	if err := json.NewDecoder(req.Request.Body).Decode(param); err != nil {
@@ -82,7 +82,7 @@ func (s *Service) AddHostFavourite(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) UpdateHostFavouriteByID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	ID := req.PathParameter("id")
 
 	if "" == ID || "0" == ID {
@@ -119,7 +119,7 @@ func (s *Service) UpdateHostFavouriteByID(req *restful.Request, resp *restful.Re
 }
 
 func (s *Service) DeleteHostFavouriteByID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	ID := req.PathParameter("id")
 
 	if "" == ID || "0" == ID {
@@ -143,7 +143,7 @@ func (s *Service) DeleteHostFavouriteByID(req *restful.Request, resp *restful.Re
 }
 
 func (s *Service) IncrHostFavouritesCount(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	ID := req.PathParameter("id")
 	if "" == ID || "0" == ID {
 		blog.Errorf("delete host favourite failed, with id  %s, rid:%s", ID, srvData.rid)
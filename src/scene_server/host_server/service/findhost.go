@@ -23,7 +23,7 @@ import (
 )
 
 func (s *Service) FindModuleHost(req *restful.Request, resp *restful.Response) {
-    srvData := s.newSrvComm(req.Request.Header)
+    srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	body := new(meta.HostModuleFind)
@@ -43,7 +43,7 @@ type DataInfo struct {
 }
 
 func (s *Service) DeleteHostBatch(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	opt := new(meta.DeleteHostBatchOpt)
 	if err := json.NewDecoder(req.Request.Body).Decode(opt); err != nil {
@@ -155,7 +155,7 @@ func (s *Service) DeleteHostBatch(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) GetHostInstanceProperties(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	hostID := req.PathParameter("bk_host_id")
 
@@ -192,7 +192,7 @@ func (s *Service) GetHostInstanceProperties(req *restful.Request, resp *restful.
 }
 
 func (s *Service) HostSnapInfo(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	hostID := req.PathParameter(common.BKHostIDField)
 	result, err := s.CoreAPI.HostController().Host().GetHostSnap(srvData.ctx, hostID, srvData.header)
@@ -222,7 +222,7 @@ func (s *Service) HostSnapInfo(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) AddHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	hostList := new(meta.HostList)
 	if err := json.NewDecoder(req.Request.Body).Decode(hostList); err != nil {
@@ -258,14 +258,42 @@ func (s *Service) AddHost(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	succ, updateErrRow, errRow, err := srvData.lgc.AddHost(srvData.ctx, appID, []int64{moduleID}, srvData.ownerID, hostList.HostInfo, hostList.InputType)
 	retData := make(map[string]interface{})
+	if hostList.ValidateOnly || hostList.AllOrNothing {
+		preview, err := srvData.lgc.PreviewAddHost(srvData.ctx, srvData.ownerID, hostList.HostInfo)
+		if err != nil {
+			blog.Errorf("add host, but preview failed, err: %v,input:%+v,rid:%s", err, hostList, srvData.rid)
+			resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+			return
+		}
+		if hostList.ValidateOnly {
+			retData["preview"] = preview
+			resp.WriteEntity(meta.NewSuccessResp(retData))
+			return
+		}
+		if 0 != preview.Error {
+			retData["preview"] = preview
+			blog.Errorf("add host, but %d rows are invalid, nothing is written,input:%+v,rid:%s", preview.Error, hostList, srvData.rid)
+			retData["error"] = preview.ErrorMessages()
+			resp.WriteEntity(meta.Response{
+				BaseResp: meta.BaseResp{Result: false, Code: common.CCErrHostCreateFail, ErrMsg: srvData.ccErr.Error(common.CCErrHostCreateFail).Error()},
+				Data:     retData,
+			})
+			return
+		}
+	}
+
+	addHost := srvData.lgc.AddHost
+	if hostList.AllOrNothing {
+		addHost = srvData.lgc.AddHostAllOrNothing
+	}
+	succ, updateErrRow, errRow, err := addHost(srvData.ctx, appID, []int64{moduleID}, srvData.ownerID, hostList.HostInfo, hostList.InputType)
 	if err != nil {
 		blog.Errorf("add host failed, succ: %v, update: %v, err: %v, %v,input:%+v,rid:%s", succ, updateErrRow, err, errRow, hostList, srvData.rid)
 		retData["error"] = errRow
 		retData["update_error"] = updateErrRow
 		resp.WriteEntity(meta.Response{
-			BaseResp: meta.BaseResp{false, common.CCErrHostCreateFail, srvData.ccErr.Error(common.CCErrHostCreateFail).Error()},
+			BaseResp: meta.BaseResp{Result: false, Code: common.CCErrHostCreateFail, ErrMsg: srvData.ccErr.Error(common.CCErrHostCreateFail).Error()},
 			Data:     retData,
 		})
 		return
@@ -275,7 +303,7 @@ func (s *Service) AddHost(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) AddHostFromAgent(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	agents := new(meta.AddHostFromAgentHostList)
 	if err := json.NewDecoder(req.Request.Body).Decode(&agents); err != nil {
@@ -333,7 +361,7 @@ func (s *Service) AddHostFromAgent(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) SearchHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	body := new(meta.HostCommonSearch)
 	if err := json.NewDecoder(req.Request.Body).Decode(body); err != nil {
@@ -342,12 +370,19 @@ func (s *Service) SearchHost(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
+	permission, err := s.checkHostSearchRead(srvData, body)
+	if err != nil {
+		resp.WriteError(http.StatusForbidden, &meta.RespError{Msg: err})
+		return
+	}
+
 	host, err := srvData.lgc.SearchHost(srvData.ctx, body, false)
 	if err != nil {
 		blog.Errorf("search host failed, err: %v,input:%+v,rid:%s", err, body, srvData.rid)
 		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrHostGetFail)})
 		return
 	}
+	logics.MaskSearchHost(permission, host)
 
 	resp.WriteEntity(meta.SearchHostResult{
 		BaseResp: meta.SuccessBaseResp,
@@ -356,7 +391,7 @@ func (s *Service) SearchHost(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) SearchHostWithAsstDetail(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	body := new(meta.HostCommonSearch)
 	if err := json.NewDecoder(req.Request.Body).Decode(body); err != nil {
@@ -365,12 +400,19 @@ func (s *Service) SearchHostWithAsstDetail(req *restful.Request, resp *restful.R
 		return
 	}
 
+	permission, err := s.checkHostSearchRead(srvData, body)
+	if err != nil {
+		resp.WriteError(http.StatusForbidden, &meta.RespError{Msg: err})
+		return
+	}
+
 	host, err := srvData.lgc.SearchHost(srvData.ctx, body, true)
 	if err != nil {
 		blog.Errorf("search host failed, err: %v,input:%+v,rid:%s", err, body, srvData.rid)
 		resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: err})
 		return
 	}
+	logics.MaskSearchHost(permission, host)
 
 	resp.WriteEntity(meta.SearchHostResult{
 		BaseResp: meta.SuccessBaseResp,
@@ -378,8 +420,22 @@ func (s *Service) SearchHostWithAsstDetail(req *restful.Request, resp *restful.R
 	})
 }
 
+// checkHostSearchRead check whether the request user can search by the attributes in the condition,
+// and return the attribute privileges to mask the search results
+func (s *Service) checkHostSearchRead(srvData *srvComm, body *meta.HostCommonSearch) (meta.AttributePermission, error) {
+	permission, err := srvData.lgc.GetUserAttributePermission(srvData.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fields := logics.HostSearchUnreadable(permission, body); len(fields) != 0 {
+		blog.Errorf("the user(%s) has no permission to search hosts by the attributes(%v), rid: %s", srvData.user, fields, srvData.rid)
+		return nil, srvData.ccErr.Errorf(common.CCErrTopoAttributeNoReadPermission, strings.Join(fields, ","))
+	}
+	return permission, nil
+}
+
 func (s *Service) UpdateHostBatch(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	data := mapstr.New()
 	if err := json.NewDecoder(req.Request.Body).Decode(&data); err != nil {
@@ -491,7 +547,7 @@ func (s *Service) UpdateHostBatch(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) NewHostSyncAppTopo(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	hostList := new(meta.HostSyncList)
 	if err := json.NewDecoder(req.Request.Body).Decode(hostList); err != nil {
@@ -574,7 +630,7 @@ func (s *Service) NewHostSyncAppTopo(req *restful.Request, resp *restful.Respons
 // When the host data is in multiple modules or sets. Disconnect the host from the module or set only
 func (s *Service) MoveSetHost2IdleModule(req *restful.Request, resp *restful.Response) {
 	pheader := req.Request.Header
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	var data meta.SetHostConfigParams
 	if err := json.NewDecoder(req.Request.Body).Decode(&data); err != nil {
@@ -724,7 +780,7 @@ func (s *Service) MoveSetHost2IdleModule(req *restful.Request, resp *restful.Res
 }
 
 func (s *Service) CloneHostProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := &meta.CloneHostPropertyParams{}
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
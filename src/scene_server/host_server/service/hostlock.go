@@ -25,7 +25,7 @@ import (
 
 func (s *Service) LockHost(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	input := &metadata.HostLockRequest{}
 
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -49,7 +49,7 @@ func (s *Service) LockHost(req *restful.Request, resp *restful.Response) {
 
 func (s *Service) UnlockHost(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	input := &metadata.HostLockRequest{}
 
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -73,7 +73,7 @@ func (s *Service) UnlockHost(req *restful.Request, resp *restful.Response) {
 
 func (s *Service) QueryHostLock(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	input := &metadata.QueryHostLockRequest{}
 
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
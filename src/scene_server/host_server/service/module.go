@@ -30,7 +30,7 @@ import (
 )
 
 func (s *Service) AddHostMultiAppModuleRelation(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	result, err := s.CoreAPI.ObjectController().Privilege().GetSystemFlag(srvData.ctx, common.BKDefaultOwnerID, common.HostCrossBizField, srvData.header)
 	if err != nil {
@@ -165,7 +165,7 @@ func (s *Service) AddHostMultiAppModuleRelation(req *restful.Request, resp *rest
 }
 
 func (s *Service) HostModuleRelation(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	config := new(metadata.HostsModuleRelation)
 	if err := json.NewDecoder(req.Request.Body).Decode(config); err != nil {
@@ -263,7 +263,7 @@ func (s *Service) MoveHost2FaultModule(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) MoveHostToResourcePool(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	conf := new(metadata.DefaultModuleHostConfigParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(&conf); err != nil {
@@ -370,7 +370,7 @@ func (s *Service) MoveHostToResourcePool(req *restful.Request, resp *restful.Res
 }
 
 func (s *Service) AssignHostToApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	conf := new(metadata.DefaultModuleHostConfigParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(&conf); err != nil {
@@ -472,7 +472,7 @@ func (s *Service) AssignHostToApp(req *restful.Request, resp *restful.Response)
 }
 
 func (s *Service) AssignHostToAppModule(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	data := new(metadata.HostToAppModule)
 	if err := json.NewDecoder(req.Request.Body).Decode(data); err != nil {
@@ -551,7 +551,7 @@ func (s *Service) AssignHostToAppModule(req *restful.Request, resp *restful.Resp
 // GetHostModuleRelation  query host and module relation,
 // hostID can emtpy
 func (s *Service) GetHostModuleRelation(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	data := new(metadata.HostModuleRelationParameter)
 	if err := json.NewDecoder(req.Request.Body).Decode(data); err != nil {
 		blog.Errorf("Transfer host across business failed with decode body err: %v", err)
@@ -583,7 +583,7 @@ func (s *Service) GetHostModuleRelation(req *restful.Request, resp *restful.Resp
 // TransferHostAcrossBusiness  Transfer host across business,
 // delete old business  host and module reltaion
 func (s *Service) TransferHostAcrossBusiness(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	data := new(metadata.TransferHostAcrossBusinessParameter)
 	if err := json.NewDecoder(req.Request.Body).Decode(data); err != nil {
 		blog.Errorf("Transfer host across business failed with decode body err: %v", err)
@@ -604,7 +604,7 @@ func (s *Service) TransferHostAcrossBusiness(req *restful.Request, resp *restful
 // dangerous operation
 func (s *Service) DeleteHostFromBusiness(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	data := new(metadata.DeleteHostFromBizParameter)
 	if err := json.NewDecoder(req.Request.Body).Decode(data); err != nil {
 		blog.Errorf("DeleteHostFromBizParameter failed with decode body err: %v", err)
@@ -623,7 +623,7 @@ func (s *Service) DeleteHostFromBusiness(req *restful.Request, resp *restful.Res
 
 func (s *Service) moveHostToModuleByName(req *restful.Request, resp *restful.Response, moduleName string) {
     pheader := req.Request.Header
-    srvData := s.newSrvComm(pheader )
+    srvData := s.newSrvComm(req.Request.Context(), pheader)
     defErr := srvData.ccErr
     ctx := srvData.ctx
     rid :=srvData.rid
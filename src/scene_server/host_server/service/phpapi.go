@@ -31,7 +31,7 @@ import (
 
 // updateHostPlat 根据条件更新主机信息
 func (s *Service) UpdateHost(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	blog.V(5).Infof("updateHost start!,rid:%s", srvData.rid)
 
 	appID, err := util.GetInt64ByInterface(req.PathParameter(common.BKAppIDField))
@@ -62,7 +62,7 @@ func (s *Service) UpdateHost(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) UpdateHostByAppID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	blog.V(5).Infof("updateHostByAppID start!,rid:%s", srvData.rid)
 	appID, err := util.GetInt64ByInterface(req.PathParameter("appid"))
 	if nil != err {
@@ -93,7 +93,7 @@ func (s *Service) UpdateHostByAppID(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) HostSearchByIP(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.HostSearchByIPParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -152,7 +152,7 @@ func (s *Service) HostSearchByIP(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) HostSearchByConds(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&input); err != nil {
@@ -193,7 +193,7 @@ func (s *Service) HostSearchByConds(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) HostSearchByModuleID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.HostSearchByModuleIDParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -239,7 +239,7 @@ func (s *Service) HostSearchByModuleID(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) HostSearchBySetID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.HostSearchBySetIDParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -282,7 +282,7 @@ func (s *Service) HostSearchBySetID(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) HostSearchByAppID(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.HostSearchByAppIDParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -323,7 +323,7 @@ func (s *Service) HostSearchByAppID(req *restful.Request, resp *restful.Response
 }
 
 func (s *Service) HostSearchByProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&input); err != nil {
@@ -403,7 +403,7 @@ func (s *Service) HostSearchByProperty(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) GetIPAndProxyByCompany(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.GetIPAndProxyByCompanyParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -445,7 +445,7 @@ func (s *Service) GetIPAndProxyByCompany(req *restful.Request, resp *restful.Res
 }
 
 func (s *Service) UpdateCustomProperty(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&input); err != nil {
@@ -496,7 +496,7 @@ func (s *Service) UpdateCustomProperty(req *restful.Request, resp *restful.Respo
 }
 
 func (s *Service) GetHostAppByCompanyId(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := &meta.GetHostAppByCompanyIDParams{}
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -576,7 +576,7 @@ func (s *Service) GetHostAppByCompanyId(req *restful.Request, resp *restful.Resp
 }
 
 func (s *Service) DelHostInApp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := &meta.DelHostInAppParams
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -658,7 +658,7 @@ func (s *Service) DelHostInApp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) GetGitServerIp(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := new(meta.GitServerIpParams)
 	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
@@ -765,7 +765,7 @@ func (s *Service) GetGitServerIp(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) GetPlat(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	params := new(meta.QueryCondition)
 	res, err := s.CoreAPI.CoreService().Instance().ReadInstance(srvData.ctx, srvData.header, common.BKInnerObjIDPlat, params)
 	if nil != err {
@@ -787,7 +787,7 @@ func (s *Service) GetPlat(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) CreatePlat(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 	input := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&input); nil != err {
 		blog.Errorf("CreatePlat , but decode body failed, err: %s,rid:%s", err.Error(), srvData.rid)
@@ -836,7 +836,7 @@ func (s *Service) CreatePlat(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) DelPlat(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	platID, convErr := util.GetInt64ByInterface(req.PathParameter(common.BKCloudIDField))
 	if nil != convErr || 0 == platID {
@@ -893,7 +893,7 @@ func (s *Service) DelPlat(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) getHostListByAppidAndField(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	// 获取AppID
 	pathParams := req.PathParameters()
@@ -52,9 +52,9 @@ type srvComm struct {
 	lgc           *logics.Logics
 }
 
-func (s *Service) newSrvComm(header http.Header) *srvComm {
+func (s *Service) newSrvComm(parent context.Context, header http.Header) *srvComm {
 	lang := util.GetLanguage(header)
-	ctx, cancel := s.Engine.CCCtx.WithCancel()
+	ctx, cancel := backbone.NewContextWithHeader(parent, header)
 	return &srvComm{
 		header:        header,
 		rid:           util.GetHTTPCCRequestID(header),
@@ -80,7 +80,7 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.DELETE("/hosts/batch").To(s.DeleteHostBatch))
 	ws.Route(ws.GET("/hosts/{bk_supplier_account}/{bk_host_id}").To(s.GetHostInstanceProperties))
 	ws.Route(ws.GET("/hosts/snapshot/{bk_host_id}").To(s.HostSnapInfo))
-	ws.Route(ws.POST("/hosts/add").To(s.AddHost))
+	ws.Route(ws.POST("/hosts/add").Reads(metadata.HostList{}).To(s.AddHost))
 	ws.Route(ws.POST("/host/add/agent").To(s.AddHostFromAgent))
 	ws.Route(ws.POST("/hosts/sync/new/host").To(s.NewHostSyncAppTopo))
 	ws.Route(ws.POST("hosts/favorites/search").To(s.GetHostFavourites))
@@ -89,22 +89,25 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.DELETE("hosts/favorites/{id}").To(s.DeleteHostFavouriteByID))
 	ws.Route(ws.PUT("/hosts/favorites/{id}/incr").To(s.IncrHostFavouritesCount))
 	ws.Route(ws.POST("/hosts/modules/biz/mutiple").To(s.AddHostMultiAppModuleRelation))
-	ws.Route(ws.POST("/hosts/modules").To(s.HostModuleRelation))
+	ws.Route(ws.POST("/hosts/modules").Reads(metadata.HostsModuleRelation{}).Writes(metadata.BaseResp{}).To(s.HostModuleRelation))
 	ws.Route(ws.POST("/hosts/modules/idle").To(s.MoveHost2EmptyModule))
 	ws.Route(ws.POST("/hosts/modules/fault").To(s.MoveHost2FaultModule))
-	ws.Route(ws.POST("/hosts/modules/resource").To(s.MoveHostToResourcePool))
+	ws.Route(ws.POST("/hosts/modules/resource").Reads(metadata.DefaultModuleHostConfigParams{}).Writes(metadata.BaseResp{}).To(s.MoveHostToResourcePool))
 	ws.Route(ws.POST("/hosts/modules/resource/idle").To(s.AssignHostToApp))
 	ws.Route(ws.POST("/host/add/module").To(s.AssignHostToAppModule))
 	ws.Route(ws.POST("/usercustom").To(s.SaveUserCustom))
 	ws.Route(ws.POST("/usercustom/user/search").To(s.GetUserCustom))
 	ws.Route(ws.POST("/usercustom/default/search").To(s.GetDefaultCustom))
-	ws.Route(ws.POST("/hosts/search").To(s.SearchHost))
-	ws.Route(ws.POST("/hosts/search/asstdetail").To(s.SearchHostWithAsstDetail))
+	ws.Route(ws.POST("/usertoken").To(s.CreateUserToken))
+	ws.Route(ws.POST("/usertoken/search").To(s.SearchUserToken))
+	ws.Route(ws.DELETE("/usertoken/{id}").To(s.RevokeUserToken))
+	ws.Route(ws.POST("/hosts/search").Reads(metadata.HostCommonSearch{}).Writes(metadata.SearchHostResult{}).To(s.SearchHost))
+	ws.Route(ws.POST("/hosts/search/asstdetail").Reads(metadata.HostCommonSearch{}).Writes(metadata.SearchHostResult{}).To(s.SearchHostWithAsstDetail))
 	ws.Route(ws.PUT("/hosts/batch").To(s.UpdateHostBatch))
 	ws.Route(ws.PUT("/hosts/property/clone").To(s.CloneHostProperty))
 	ws.Route(ws.POST("/hosts/modules/idle/set").To(s.MoveSetHost2IdleModule))
 	// get host module relation in app
-	ws.Route(ws.POST("/hosts/modules/read").To(s.GetHostModuleRelation))
+	ws.Route(ws.POST("/hosts/modules/read").Reads(metadata.HostModuleRelationParameter{}).To(s.GetHostModuleRelation))
 	// transfer host to other business
 	ws.Route(ws.POST("/hosts/modules/across/biz").To(s.TransferHostAcrossBusiness))
 	//  delete host from business
@@ -140,7 +143,7 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.DELETE("/plat/{bk_cloud_id}").To(s.DelPlat))
 	ws.Route(ws.GET("/healthz").To(s.Healthz))
 
-	ws.Route(ws.POST("/findmany/modulehost").To(s.FindModuleHost))
+	ws.Route(ws.POST("/findmany/modulehost").Reads(metadata.HostModuleFind{}).Writes(metadata.SearchHostResult{}).To(s.FindModuleHost))
 
 	// cloud sync
 	ws.Route(ws.POST("/hosts/cloud/add").To(s.AddCloudTask))
@@ -225,6 +228,6 @@ func (s *Service) InitBackground() {
 		header.Set(common.BKHTTPHeaderUser, common.BKProcInstanceOpUser)
 	}
 
-	srvData := s.newSrvComm(header)
+	srvData := s.newSrvComm(context.Background(), header)
 	go srvData.lgc.TimerTriggerCheckStatus(srvData.ctx)
 }
@@ -28,7 +28,7 @@ import (
 )
 
 func (s *Service) AddUserCustomQuery(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	ucq := new(meta.UserConfig)
 	if err := json.NewDecoder(req.Request.Body).Decode(ucq); nil != err {
@@ -68,7 +68,7 @@ func (s *Service) AddUserCustomQuery(req *restful.Request, resp *restful.Respons
 }
 
 func (s *Service) UpdateUserCustomQuery(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	params := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&params); nil != err {
@@ -101,7 +101,7 @@ func (s *Service) UpdateUserCustomQuery(req *restful.Request, resp *restful.Resp
 
 func (s *Service) DeleteUserCustomQuery(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	ID := req.PathParameter("id")
 	appID := req.PathParameter("bk_biz_id")
@@ -127,7 +127,7 @@ func (s *Service) DeleteUserCustomQuery(req *restful.Request, resp *restful.Resp
 
 func (s *Service) GetUserCustomQuery(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	input := &meta.QueryInput{}
 	if err := json.NewDecoder(req.Request.Body).Decode(input); nil != err {
@@ -177,7 +177,7 @@ func (s *Service) GetUserCustomQuery(req *restful.Request, resp *restful.Respons
 
 func (s *Service) GetUserCustomQueryDetail(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	appID := req.PathParameter("bk_biz_id")
 	ID := req.PathParameter("id")
@@ -203,7 +203,7 @@ func (s *Service) GetUserCustomQueryDetail(req *restful.Request, resp *restful.R
 
 func (s *Service) GetUserCustomQueryResult(req *restful.Request, resp *restful.Response) {
 
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	appID := req.PathParameter("bk_biz_id")
 	ID := req.PathParameter("id")
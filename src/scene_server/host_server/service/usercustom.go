@@ -24,7 +24,7 @@ import (
 )
 
 func (s *Service) SaveUserCustom(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	params := make(map[string]interface{})
 	if err := json.NewDecoder(req.Request.Body).Decode(&params); err != nil {
@@ -78,7 +78,7 @@ func (s *Service) SaveUserCustom(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) GetUserCustom(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	result, err := s.CoreAPI.HostController().User().GetUserCustomByUser(srvData.ctx, srvData.user, srvData.header)
 	if err != nil {
@@ -95,7 +95,7 @@ func (s *Service) GetUserCustom(req *restful.Request, resp *restful.Response) {
 }
 
 func (s *Service) GetDefaultCustom(req *restful.Request, resp *restful.Response) {
-	srvData := s.newSrvComm(req.Request.Header)
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	result, err := s.CoreAPI.HostController().User().GetDefaultUserCustom(srvData.ctx, srvData.user, srvData.header)
 	if err != nil {
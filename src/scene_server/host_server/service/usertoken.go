@@ -0,0 +1,184 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/apitoken"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+const (
+	defaultAPITokenExpireDays = 90
+	maxAPITokenExpireDays     = 365
+)
+
+// CreateUserToken create a personal api token for the current user, the token is returned only once
+func (s *Service) CreateUserToken(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
+	if "" != req.Request.Header.Get(common.BKHTTPAPITokenID) {
+		blog.Errorf("create user token with api token is not allowed, rid: %s", srvData.rid)
+		resp.WriteError(http.StatusForbidden, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrAPITokenNoPermission)})
+		return
+	}
+
+	input := new(metadata.CreateAPITokenInput)
+	if err := json.NewDecoder(req.Request.Body).Decode(input); err != nil {
+		blog.Errorf("create user token failed with decode body err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if "" == input.Name {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsNeedSet, "name")})
+		return
+	}
+	if 0 == input.ExpireDays {
+		input.ExpireDays = defaultAPITokenExpireDays
+	}
+	if input.ExpireDays < 0 || input.ExpireDays > maxAPITokenExpireDays {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsInvalid, "expire_days")})
+		return
+	}
+
+	token, hash, prefix, err := apitoken.Generate()
+	if err != nil {
+		blog.Errorf("create user token failed, generate token err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommResourceInitFailed)})
+		return
+	}
+	data := &metadata.APIToken{
+		Name:       input.Name,
+		User:       srvData.user,
+		TokenHash:  hash,
+		Prefix:     prefix,
+		Scope:      input.Scope,
+		ExpireTime: metadata.Time{Time: time.Now().UTC().Add(time.Duration(input.ExpireDays) * 24 * time.Hour)},
+	}
+	result, err := s.CoreAPI.HostController().User().AddUserToken(srvData.ctx, srvData.header, data)
+	if err != nil {
+		blog.Errorf("create user token http do error, err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !result.Result {
+		blog.Errorf("create user token http response error, err code: %d, err msg: %s, rid: %s", result.Code, result.ErrMsg, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.New(result.Code, result.ErrMsg)})
+		return
+	}
+
+	s.auditUserToken(srvData, auditoplog.AuditOpTypeAdd, nil, &result.Data, "create api token")
+
+	ret := metadata.CreateAPITokenResult{BaseResp: metadata.SuccessBaseResp}
+	ret.Data.APIToken = result.Data
+	ret.Data.Token = token
+	resp.WriteEntity(ret)
+}
+
+// SearchUserToken list the personal api tokens of the current user
+func (s *Service) SearchUserToken(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
+
+	cond := map[string]interface{}{"bk_user": srvData.user}
+	result, err := s.CoreAPI.HostController().User().SearchUserToken(srvData.ctx, srvData.header, cond)
+	if err != nil {
+		blog.Errorf("search user token http do error, err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !result.Result {
+		blog.Errorf("search user token http response error, err code: %d, err msg: %s, rid: %s", result.Code, result.ErrMsg, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.New(result.Code, result.ErrMsg)})
+		return
+	}
+	resp.WriteEntity(result)
+}
+
+// RevokeUserToken revoke the personal api token of the current user
+func (s *Service) RevokeUserToken(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Context(), req.Request.Header)
+	if "" != req.Request.Header.Get(common.BKHTTPAPITokenID) {
+		blog.Errorf("revoke user token with api token is not allowed, rid: %s", srvData.rid)
+		resp.WriteError(http.StatusForbidden, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrAPITokenNoPermission)})
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathParameter("id"), 10, 64)
+	if err != nil {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrCommParamsNeedInt, "id")})
+		return
+	}
+
+	cond := map[string]interface{}{"id": id, "bk_user": srvData.user}
+	result, err := s.CoreAPI.HostController().User().SearchUserToken(srvData.ctx, srvData.header, cond)
+	if err != nil {
+		blog.Errorf("revoke user token http do error, err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !result.Result {
+		blog.Errorf("revoke user token http response error, err code: %d, err msg: %s, rid: %s", result.Code, result.ErrMsg, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.New(result.Code, result.ErrMsg)})
+		return
+	}
+	if 0 == len(result.Data) {
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommNotFound)})
+		return
+	}
+
+	rResult, err := s.CoreAPI.HostController().User().RevokeUserToken(srvData.ctx, srvData.header, id)
+	if err != nil {
+		blog.Errorf("revoke user token http do error, err: %v, rid: %s", err, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
+	}
+	if !rResult.Result {
+		blog.Errorf("revoke user token http response error, err code: %d, err msg: %s, rid: %s", rResult.Code, rResult.ErrMsg, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: srvData.ccErr.New(rResult.Code, rResult.ErrMsg)})
+		return
+	}
+
+	s.auditUserToken(srvData, auditoplog.AuditOpTypeDel, &result.Data[0], nil, "revoke api token")
+	resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
+
+// auditUserToken save the operation log of the personal api token, the token itself is never logged
+func (s *Service) auditUserToken(srvData *srvComm, opType auditoplog.AuditOpType, pre, cur *metadata.APIToken, desc string) {
+	var id int64
+	var preData, curData interface{}
+	if nil != pre {
+		id, preData = pre.ID, pre
+	}
+	if nil != cur {
+		id, curData = cur.ID, cur
+	}
+	log := metadata.AuditObjParams{
+		Content:  metadata.Content{PreData: preData, CurData: curData},
+		OpDesc:   desc,
+		OpType:   opType,
+		OpTarget: common.BKOpTargetAPIToken,
+		InstID:   id,
+	}
+	result, err := s.CoreAPI.AuditController().AddObjectLog(srvData.ctx, srvData.ownerID, "0", srvData.user, srvData.header, log)
+	if err != nil || !result.Result {
+		blog.Errorf("add api token audit log failed, err: %v, result: %v, rid: %s", err, result, srvData.rid)
+	}
+}
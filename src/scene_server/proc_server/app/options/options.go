@@ -43,4 +43,6 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 
 type Config struct {
 	Redis *redis.Config
+	// ProcControl the config of the process control driver, the driver is chosen by the key "driver"
+	ProcControl map[string]string
 }
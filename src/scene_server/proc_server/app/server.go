@@ -26,6 +26,7 @@ import (
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/scene_server/proc_server/app/options"
+	"configcenter/src/scene_server/proc_server/logics"
 	"configcenter/src/scene_server/proc_server/proc_service/service"
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/thirdpartyclient/esbserver"
@@ -78,8 +79,14 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	if err != nil {
 		return fmt.Errorf("create esb api  object failed. err: %v", err)
 	}
+	procCtrlDriver := procSvr.Config.ProcControl["driver"]
+	procCtrl, err := logics.NewProcControl(procCtrlDriver, procSvr.Config.ProcControl, esbSrv)
+	if err != nil {
+		return fmt.Errorf("create process control %s failed. err: %v", procCtrlDriver, err)
+	}
 	procSvr.Engine = engine
 	procSvr.EsbServ = esbSrv
+	procSvr.ProcControl = procCtrl
 	procSvr.Cache = cacheDB
 	go procSvr.InitFunc()
 	if err := backbone.StartServer(ctx, engine, container); err != nil {
@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"configcenter/src/common/metadata"
+)
+
+// ConfigDeliveryLocal the name of the local file system config delivery
+const ConfigDeliveryLocal = "local"
+
+// ConfigDelivery deliver the config files of the process instances to the hosts
+type ConfigDelivery interface {
+	// Push write the content of the config file to the host of the process instance
+	Push(ctx context.Context, file *metadata.ProcConfigFile) error
+	// Fetch read the deployed config file from the host, exists is false when the file is not deployed
+	Fetch(ctx context.Context, file *metadata.ProcConfigFile) (content string, exists bool, err error)
+}
+
+// ConfigDeliveryFactory create a config delivery with the driver config
+type ConfigDeliveryFactory func(config map[string]string) (ConfigDelivery, error)
+
+var (
+	configDeliveryLock      sync.RWMutex
+	configDeliveryFactories = make(map[string]ConfigDeliveryFactory)
+)
+
+func init() {
+	RegisterConfigDelivery(ConfigDeliveryLocal, newLocalConfigDelivery)
+}
+
+// RegisterConfigDelivery register a config delivery driver, the driver registered later with the same name wins
+func RegisterConfigDelivery(name string, factory ConfigDeliveryFactory) {
+	configDeliveryLock.Lock()
+	defer configDeliveryLock.Unlock()
+	configDeliveryFactories[name] = factory
+}
+
+// NewConfigDelivery create the config delivery of the driver
+func NewConfigDelivery(name string, config map[string]string) (ConfigDelivery, error) {
+	configDeliveryLock.RLock()
+	factory, ok := configDeliveryFactories[name]
+	configDeliveryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("config delivery driver %s is not registered", name)
+	}
+	return factory(config)
+}
+
+// localConfigDelivery keep the config files in a local directory, one sub directory for each host.
+// it is a stand-in of the real delivery, used for testing, and is only used when configured explicitly.
+type localConfigDelivery struct {
+	root string
+}
+
+func newLocalConfigDelivery(config map[string]string) (ConfigDelivery, error) {
+	root := config["local.root"]
+	if "" == root {
+		root = filepath.Join(os.TempDir(), "cc_proc_config")
+	}
+	return &localConfigDelivery{root: root}, nil
+}
+
+// filePath the path of the config file, the file can not be out of the root directory
+func (d *localConfigDelivery) filePath(file *metadata.ProcConfigFile) (string, error) {
+	if nil == net.ParseIP(file.InnerIP) {
+		return "", fmt.Errorf("invalid host ip %q", file.InnerIP)
+	}
+	root := filepath.Clean(d.root)
+	hostDir := fmt.Sprintf("%d_%s", file.CloudID, file.InnerIP)
+	target := filepath.Clean(filepath.Join(root, hostDir, filepath.Clean("/"+file.Path)))
+	if !strings.HasPrefix(target, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("config file path %q is out of the root directory", file.Path)
+	}
+	return target, nil
+}
+
+func (d *localConfigDelivery) Push(ctx context.Context, file *metadata.ProcConfigFile) error {
+	target, err := d.filePath(file)
+	if nil != err {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); nil != err {
+		return err
+	}
+
+	// write to a temporary file first, so that the deployed file is never half written
+	tmp := target + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(file.Content), 0644); nil != err {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+func (d *localConfigDelivery) Fetch(ctx context.Context, file *metadata.ProcConfigFile) (string, bool, error) {
+	target, err := d.filePath(file)
+	if nil != err {
+		return "", false, err
+	}
+	content, err := ioutil.ReadFile(target)
+	if nil != err {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return string(content), true, nil
+}
@@ -0,0 +1,183 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext the count of the unchanged lines around the changes
+	diffContext = 3
+	// maxDiffCells the max size of the lcs table, a larger diff replaces all the changed lines
+	maxDiffCells = 4 * 1024 * 1024
+)
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff return the unified diff from the old content to the new content,
+// it is empty when the contents are the same
+func UnifiedDiff(oldName, newName, oldContent, newContent string) string {
+	if oldContent == newContent {
+		return ""
+	}
+	ops := diffLines(splitDiffLines(oldContent), splitDiffLines(newContent))
+
+	// the line numbers before each op
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	for idx, op := range ops {
+		oldLines[idx+1], newLines[idx+1] = oldLines[idx], newLines[idx]
+		if '+' != op.kind {
+			oldLines[idx+1]++
+		}
+		if '-' != op.kind {
+			newLines[idx+1]++
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+	for idx := 0; idx < len(ops); {
+		if ' ' == ops[idx].kind {
+			idx++
+			continue
+		}
+
+		// merge the changes which are close to each other into one hunk
+		start := idx - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := idx
+		for next := idx; next < len(ops); next++ {
+			if ' ' != ops[next].kind {
+				end = next
+				continue
+			}
+			if next-end > 2*diffContext {
+				break
+			}
+		}
+		stop := end + diffContext + 1
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[stop]-oldLines[start]),
+			hunkRange(newLines[start], newLines[stop]-newLines[start]))
+		for _, op := range ops[start:stop] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		idx = stop
+	}
+
+	return buf.String()
+}
+
+func hunkRange(before, count int) string {
+	if 0 == count {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if 1 == count {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitDiffLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if 0 != len(lines) && "" == lines[len(lines)-1] {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	ops = append(ops, diffMiddleLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
+
+// diffMiddleLines diff the lines by the longest common subsequence
+func diffMiddleLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	n, m := len(a), len(b)
+	i, j := 0, 0
+	if n*m <= maxDiffCells {
+		// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+		lcs := make([][]int, n+1)
+		for idx := range lcs {
+			lcs[idx] = make([]int, m+1)
+		}
+		for x := n - 1; x >= 0; x-- {
+			for y := m - 1; y >= 0; y-- {
+				if a[x] == b[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else if lcs[x+1][y] >= lcs[x][y+1] {
+					lcs[x][y] = lcs[x+1][y]
+				} else {
+					lcs[x][y] = lcs[x][y+1]
+				}
+			}
+		}
+
+		for i < n && j < m {
+			switch {
+			case a[i] == b[j]:
+				ops = append(ops, diffOp{kind: ' ', line: a[i]})
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				ops = append(ops, diffOp{kind: '-', line: a[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{kind: '+', line: b[j]})
+				j++
+			}
+		}
+	}
+
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+	return ops
+}
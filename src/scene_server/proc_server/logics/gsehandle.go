@@ -87,7 +87,7 @@ func (lgc *Logics) registerProcInstanceToGse(ctx context.Context, gseproc *metad
 	defErr := lgc.ccErr
 	namespace := getGseProcNameSpace(gseproc.AppID, gseproc.ModuleID)
 	gseproc.Meta.Namespace = namespace
-	ret, err := lgc.esbServ.GseSrv().RegisterProcInfo(ctx, lgc.header, gseproc)
+	ret, err := lgc.procCtrl.Register(ctx, lgc.header, gseproc)
 	if err != nil {
 		blog.Errorf("registerProcInstanceToGse RegisterProcInfo http do error.register process(%s) into gse failed. err: %v,input:%+v,rid:%s", gseproc.Meta.Name, err, gseproc, lgc.rid)
 		return lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -115,7 +115,7 @@ func (lgc *Logics) unregisterProcInstanceToGse(ctx context.Context, gseproc *met
 	gseproc.Meta.Namespace = namespace
 	gseproc.OpType = common.GSEProcOPUnregister
 	defErr := lgc.ccErr
-	ret, err := lgc.esbServ.GseSrv().UnRegisterProcInfo(ctx, lgc.header, gseproc)
+	ret, err := lgc.procCtrl.Unregister(ctx, lgc.header, gseproc)
 	if err != nil {
 		blog.Errorf("unregisterProcInstanceToGse UnRegisterProcInfo http do error.unregister process(%s) into gse failed.  errcode: %d, errmsg: %s,input:%+v,rid:%s", gseproc.Meta.Name, err, gseproc, lgc.rid)
 		return defErr.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -215,6 +215,9 @@ func (lgc *Logics) getOperateProcInstanceData(ctx context.Context, procOp *metad
 		gseprocReq.Meta.Name = procName
 		gseprocReq.Meta.Namespace = getGseProcNameSpace(procOp.ApplicationID, opGseProcInfo.ModuleID)
 		gseprocReq.OpType = procOp.OpType
+		for _, hostInfo := range hostInfoArr {
+			gseprocReq.Hosts = append(gseprocReq.Hosts, *hostInfo)
+		}
 		gseReqArr = append(gseReqArr, gseprocReq)
 	}
 
@@ -236,9 +239,10 @@ func (lgc *Logics) OperateProcInstanceByGse(ctx context.Context, procOp *metadat
 	cacheTaskInfo.TaskID = ccTaskID
 	cacheTaskInfo.Header = mustNeedHeader
 	for _, gseReq := range gseReqArr {
-		gseRsp, err := lgc.esbServ.GseSrv().OperateProcess(ctx, lgc.header, gseReq)
+		gseRsp, err := lgc.procCtrl.Operate(ctx, lgc.header, gseReq)
 		status := metadata.ProcOpTaskStatusWaitOP
 		detail := make(map[string]metadata.ProcessOperateTaskDetail, 0)
+		taskID := ""
 		if nil != err {
 			blog.Errorf("OperateProcInstanceByGse fail to operate process by gse process server. err: %v,input:%+v, rid:%s", err, gseReq, lgc.rid)
 			status = metadata.ProcOpTaskStatusHTTPErr
@@ -246,23 +250,22 @@ func (lgc *Logics) OperateProcInstanceByGse(ctx context.Context, procOp *metadat
 				Errcode: common.CCErrCommHTTPDoRequestFailed,
 				ErrMsg:  err.Error(),
 			}
-		} else if !gseRsp.Result {
+		} else if 0 != gseRsp.Code {
 			blog.Errorf("OperateProcInstanceByGse fail to operate process by gse process server. errcode: %d, errmsg: %s,input:%+v,rid:%s", gseRsp.Code, gseRsp.Message, gseReq, lgc.rid)
 			status = metadata.ProcOpTaskStatusErr
 			detail["gse_error_message"] = metadata.ProcessOperateTaskDetail{
 				Errcode: gseRsp.Code,
 				ErrMsg:  gseRsp.Message,
 			}
-		}
-
-		taskID, ok := gseRsp.Data[common.BKGseTaskIDField].(string)
-		if !ok {
-			blog.Warnf("OperateProcInstanceByGse convert gse process operate taskid to string failed. value: %v,rid:%s", gseRsp.Data, lgc.rid)
+		} else if "" == gseRsp.TaskID {
+			blog.Warnf("OperateProcInstanceByGse gse process operate reply without taskid. reply: %+v,rid:%s", gseRsp, lgc.rid)
 			status = metadata.ProcOpTaskStatusNotTaskIDErr
 			detail["not_foud_gse_task_id"] = metadata.ProcessOperateTaskDetail{
 				Errcode: common.CCErrCommNotFound,
 				ErrMsg:  gseRsp.Message,
 			}
+		} else {
+			taskID = gseRsp.TaskID
 		}
 
 		opProcInsts = append(opProcInsts, &metadata.ProcessOperateTask{
@@ -30,6 +30,7 @@ import (
 type Logics struct {
 	*backbone.Engine
 	esbServ      esbserver.EsbClientInterface
+	procCtrl     ProcControl
 	procHostInst *ProcHostInstConfig
 	ErrHandle    errors.DefaultCCErrorIf
 	cache        *redis.Client
@@ -42,7 +43,7 @@ type Logics struct {
 }
 
 // NewLogics get logic handle
-func NewLogics(b *backbone.Engine, header http.Header, cache *redis.Client, esbServ esbserver.EsbClientInterface, procHostInst *ProcHostInstConfig) *Logics {
+func NewLogics(b *backbone.Engine, header http.Header, cache *redis.Client, esbServ esbserver.EsbClientInterface, procCtrl ProcControl, procHostInst *ProcHostInstConfig) *Logics {
 	lang := util.GetLanguage(header)
 	return &Logics{
 		Engine:       b,
@@ -54,6 +55,7 @@ func NewLogics(b *backbone.Engine, header http.Header, cache *redis.Client, esbS
 		ownerID:      util.GetOwnerID(header),
 		cache:        cache,
 		esbServ:      esbServ,
+		procCtrl:     procCtrl,
 		procHostInst: procHostInst,
 	}
 }
@@ -76,6 +78,7 @@ func (lgc *Logics) NewFromHeader(header http.Header) *Logics {
 		rid:          rid,
 		cache:        lgc.cache,
 		esbServ:      lgc.esbServ,
+		procCtrl:     lgc.procCtrl,
 		procHostInst: lgc.procHostInst,
 		user:         util.GetUser(header),
 		ownerID:      util.GetOwnerID(header),
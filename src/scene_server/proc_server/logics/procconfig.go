@@ -0,0 +1,257 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/flosch/pongo2"
+)
+
+// RenderProcConfigs render the config file of the template for every process instance bound to the template
+func (lgc *Logics) RenderProcConfigs(ctx context.Context, appID, templateID int64, filter *metadata.ProcConfigFilter) ([]metadata.ProcConfigFile, error) {
+	template, err := lgc.getConfigTemplate(ctx, appID, templateID)
+	if nil != err {
+		return nil, err
+	}
+	version, err := lgc.GetConfigTemplateVersion(ctx, appID, templateID, filter.VersionID)
+	if nil != err {
+		return nil, err
+	}
+	versionID, _ := util.GetInt64ByInterface(version[common.BKVersionIDField])
+	content, _ := version[common.BKContentField].(string)
+	fileName, _ := template[common.BKFileNameField].(string)
+
+	tpl, err := pongo2.FromString(content)
+	if nil != err {
+		blog.Errorf("RenderProcConfigs parse template %d version %d error:%s,rid:%s", templateID, versionID, err.Error(), lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcRenderConfigFail, err.Error())
+	}
+
+	procIDs, err := lgc.getTemplateBindProcIDs(ctx, appID, templateID, filter.ProcessID)
+	if nil != err {
+		return nil, err
+	}
+	files := make([]metadata.ProcConfigFile, 0)
+	if 0 == len(procIDs) {
+		return files, nil
+	}
+	instances, err := lgc.getProcInstanceModels(ctx, appID, procIDs, filter.HostID)
+	if nil != err {
+		return nil, err
+	}
+
+	variables := lgc.NewVariables(ctx, appID)
+	for idx := range instances {
+		inst := &instances[idx]
+		vars, err := variables.GetProcInstVariables(ctx, inst)
+		if nil != err {
+			blog.Errorf("RenderProcConfigs get the variables of process instance %+v error:%s,rid:%s", inst, err.Error(), lgc.rid)
+			return nil, err
+		}
+		out, err := tpl.Execute(pongo2.Context(vars))
+		if nil != err {
+			blog.Errorf("RenderProcConfigs render template %d version %d for process instance %+v error:%s,rid:%s", templateID, versionID, inst, err.Error(), lgc.rid)
+			return nil, lgc.ccErr.Errorf(common.CCErrProcRenderConfigFail, err.Error())
+		}
+
+		hostVars, _ := vars[common.BKInnerObjIDHost].(mapstr.MapStr)
+		procVars, _ := vars[common.BKInnerObjIDProc].(mapstr.MapStr)
+		file := metadata.ProcConfigFile{
+			TemplateID:     templateID,
+			VersionID:      versionID,
+			FileName:       fileName,
+			Path:           getProcConfigFilePath(fileName, util.GetStrByInterface(procVars[common.BKWorkPath])),
+			AppID:          appID,
+			SetID:          inst.SetID,
+			ModuleID:       inst.ModuleID,
+			ProcessID:      inst.ProcID,
+			FuncID:         inst.FuncID,
+			ProcInstanceID: inst.ProcInstanceID,
+			HostID:         inst.HostID,
+			HostProcID:     inst.HostProcID,
+			InnerIP:        util.GetStrByInterface(hostVars[common.BKHostInnerIPField]),
+			Content:        out,
+		}
+		file.CloudID, _ = util.GetInt64ByInterface(hostVars[common.BKCloudIDField])
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].ProcessID != files[j].ProcessID {
+			return files[i].ProcessID < files[j].ProcessID
+		}
+		if files[i].HostID != files[j].HostID {
+			return files[i].HostID < files[j].HostID
+		}
+		return files[i].ProcInstanceID < files[j].ProcInstanceID
+	})
+	return files, nil
+}
+
+// PushProcConfigs push the config files through the delivery, a failed file does not stop the others
+func (lgc *Logics) PushProcConfigs(ctx context.Context, delivery ConfigDelivery, files []metadata.ProcConfigFile) []metadata.ProcConfigFileResult {
+	results := make([]metadata.ProcConfigFileResult, 0, len(files))
+	for idx := range files {
+		result := metadata.ProcConfigFileResult{ProcConfigFile: files[idx], Success: true}
+		if err := delivery.Push(ctx, &files[idx]); nil != err {
+			blog.Errorf("PushProcConfigs push %s to host %s error:%s,rid:%s", files[idx].Path, files[idx].InnerIP, err.Error(), lgc.rid)
+			result.Success = false
+			result.ErrMsg = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// GetRemoteProcConfigs fetch the deployed config files through the delivery
+func (lgc *Logics) GetRemoteProcConfigs(ctx context.Context, delivery ConfigDelivery, files []metadata.ProcConfigFile) []metadata.ProcConfigFileResult {
+	results := make([]metadata.ProcConfigFileResult, 0, len(files))
+	for idx := range files {
+		result := metadata.ProcConfigFileResult{ProcConfigFile: files[idx], Success: true}
+		content, exists, err := delivery.Fetch(ctx, &files[idx])
+		if nil != err {
+			blog.Errorf("GetRemoteProcConfigs fetch %s from host %s error:%s,rid:%s", files[idx].Path, files[idx].InnerIP, err.Error(), lgc.rid)
+			result.Success = false
+			result.ErrMsg = err.Error()
+		}
+		result.RemoteContent = content
+		result.RemoteExists = exists
+		results = append(results, result)
+	}
+	return results
+}
+
+// DiffProcConfigs compare the deployed config files with the rendered ones
+func (lgc *Logics) DiffProcConfigs(ctx context.Context, delivery ConfigDelivery, files []metadata.ProcConfigFile) []metadata.ProcConfigFileResult {
+	results := lgc.GetRemoteProcConfigs(ctx, delivery, files)
+	for idx := range results {
+		result := &results[idx]
+		if !result.Success {
+			continue
+		}
+		result.Diff = UnifiedDiff("deployed"+result.Path, "rendered"+result.Path, result.RemoteContent, result.Content)
+		result.Changed = !result.RemoteExists || "" != result.Diff
+	}
+	return results
+}
+
+// getProcConfigFilePath the absolute file name is used directly, otherwise it is under the work path of the process
+func getProcConfigFilePath(fileName, workPath string) string {
+	if path.IsAbs(fileName) || "" == workPath {
+		return path.Clean("/" + fileName)
+	}
+	return path.Join(workPath, fileName)
+}
+
+func (lgc *Logics) getConfigTemplate(ctx context.Context, appID, templateID int64) (mapstr.MapStr, error) {
+	input := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKAppIDField: appID, common.BKTemlateIDField: templateID},
+	}
+	ret, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(ctx, lgc.header, common.BKInnerObjIDConfigTemp, input)
+	if nil != err {
+		blog.Errorf("getConfigTemplate ReadInstance http do error,err:%s,input:%+v,rid:%s", err.Error(), input, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("getConfigTemplate ReadInstance http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, input, lgc.rid)
+		return nil, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	if 0 == len(ret.Data.Info) {
+		blog.Errorf("getConfigTemplate template %d not found,input:%+v,rid:%s", templateID, input, lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateNotFound, strconv.FormatInt(templateID, 10))
+	}
+	return ret.Data.Info[0], nil
+}
+
+// GetConfigTemplateVersion get the version of the template, the online version is used when versionID is zero
+func (lgc *Logics) GetConfigTemplateVersion(ctx context.Context, appID, templateID, versionID int64) (mapstr.MapStr, error) {
+	cond := mapstr.MapStr{common.BKAppIDField: appID, common.BKTemlateIDField: templateID}
+	if 0 == versionID {
+		cond[common.BKStatusField] = common.TemplateStatusOnline
+	} else {
+		cond[common.BKVersionIDField] = versionID
+	}
+	input := &metadata.QueryCondition{Condition: cond}
+	ret, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(ctx, lgc.header, common.BKInnerObjIDTempVersion, input)
+	if nil != err {
+		blog.Errorf("GetConfigTemplateVersion ReadInstance http do error,err:%s,input:%+v,rid:%s", err.Error(), input, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("GetConfigTemplateVersion ReadInstance http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, input, lgc.rid)
+		return nil, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	if 0 == len(ret.Data.Info) {
+		blog.Errorf("GetConfigTemplateVersion version of template %d not found,input:%+v,rid:%s", templateID, input, lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateVersionNotFound, strconv.FormatInt(templateID, 10))
+	}
+	return ret.Data.Info[0], nil
+}
+
+// getTemplateBindProcIDs get the processes bound to the template, only the given processes are kept if any
+func (lgc *Logics) getTemplateBindProcIDs(ctx context.Context, appID, templateID int64, filterProcIDs []int64) ([]int64, error) {
+	cond := mapstr.MapStr{common.BKAppIDField: appID, common.BKTemlateIDField: templateID}
+	ret, err := lgc.CoreAPI.ProcController().SearchProc2Template(ctx, lgc.header, cond)
+	if nil != err {
+		blog.Errorf("getTemplateBindProcIDs SearchProc2Template http do error,err:%s,input:%+v,rid:%s", err.Error(), cond, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("getTemplateBindProcIDs SearchProc2Template http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, cond, lgc.rid)
+		return nil, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+
+	procIDs := make([]int64, 0)
+	for _, item := range ret.Data {
+		procID, err := util.GetInt64ByInterface(item[common.BKProcessIDField])
+		if nil != err {
+			continue
+		}
+		if 0 != len(filterProcIDs) && !util.ContainsInt64(filterProcIDs, procID) {
+			continue
+		}
+		procIDs = append(procIDs, procID)
+	}
+	return procIDs, nil
+}
+
+func (lgc *Logics) getProcInstanceModels(ctx context.Context, appID int64, procIDs, hostIDs []int64) ([]metadata.ProcInstanceModel, error) {
+	cond := common.KvMap{
+		common.BKAppIDField:     appID,
+		common.BKProcessIDField: common.KvMap{common.BKDBIN: procIDs},
+	}
+	if 0 != len(hostIDs) {
+		cond[common.BKHostIDField] = common.KvMap{common.BKDBIN: hostIDs}
+	}
+	dat := &metadata.QueryInput{Condition: cond, Limit: common.BKNoLimit}
+	ret, err := lgc.CoreAPI.ProcController().GetProcInstanceModel(ctx, lgc.header, dat)
+	if nil != err {
+		blog.Errorf("getProcInstanceModels http do error,err:%s,input:%+v,rid:%s", err.Error(), dat, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("getProcInstanceModels http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, dat, lgc.rid)
+		return nil, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	return ret.Data.Info, nil
+}
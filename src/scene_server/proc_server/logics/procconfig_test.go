@@ -0,0 +1,95 @@
+package logics
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"configcenter/src/common/metadata"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	type testData struct {
+		old    string
+		new    string
+		output string
+	}
+
+	td := []testData{
+		testData{
+			old:    "a\nb\n",
+			new:    "a\nb\n",
+			output: "",
+		},
+		testData{
+			old:    "a\nb\nc\n",
+			new:    "a\nx\nc\n",
+			output: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		testData{
+			old:    "",
+			new:    "a\n",
+			output: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		testData{
+			old:    "a\n",
+			new:    "a",
+			output: "--- old\n+++ new\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		testData{
+			old:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:    "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			output: "--- old\n+++ new\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+	}
+
+	for _, item := range td {
+		output := UnifiedDiff("old", "new", item.old, item.new)
+		if output != item.output {
+			t.Errorf("diff %q to %q, expected %q, got %q", item.old, item.new, item.output, output)
+		}
+	}
+}
+
+func TestLocalConfigDelivery(t *testing.T) {
+	root, err := ioutil.TempDir("", "cc_proc_config")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	delivery, err := NewConfigDelivery(ConfigDeliveryLocal, map[string]string{"local.root": root})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if _, err := NewConfigDelivery("unknown", nil); nil == err {
+		t.Errorf("unknown delivery should be failed")
+	}
+
+	ctx := context.Background()
+	file := &metadata.ProcConfigFile{InnerIP: "127.0.0.1", Path: "/data/app/../conf/app.conf", Content: "port=80\n"}
+	if _, exists, err := delivery.Fetch(ctx, file); nil != err || exists {
+		t.Fatalf("fetch file not pushed, exists:%v, err:%v", exists, err)
+	}
+	if err := delivery.Push(ctx, file); nil != err {
+		t.Fatal(err)
+	}
+	content, exists, err := delivery.Fetch(ctx, file)
+	if nil != err || !exists || content != file.Content {
+		t.Errorf("fetch file pushed, content:%q, exists:%v, err:%v", content, exists, err)
+	}
+	if _, err := os.Stat(root + "/0_127.0.0.1/data/conf/app.conf"); nil != err {
+		t.Errorf("file not pushed under the root, err:%v", err)
+	}
+
+	for _, ip := range []string{"../../..", "127.0.0.1/../..", ""} {
+		escape := &metadata.ProcConfigFile{InnerIP: ip, Path: "/etc/app.conf", Content: "port=80\n"}
+		if err := delivery.Push(ctx, escape); nil == err {
+			t.Errorf("push file with host ip %q should be failed", ip)
+		}
+		if _, _, err := delivery.Fetch(ctx, escape); nil == err {
+			t.Errorf("fetch file with host ip %q should be failed", ip)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdpartyclient/esbserver"
+)
+
+const (
+	// ProcControlGse the process control driver which operates the processes through gse
+	ProcControlGse = "gse"
+	// ProcControlAgent the process control driver which dispatches to the agent over http
+	ProcControlAgent = "agent"
+)
+
+// ProcControlReply the reply of the process control backend, zero code means success
+type ProcControlReply struct {
+	Code    int
+	Message string
+	// TaskID the task id of the process operation
+	TaskID string
+	// Detail the operation result of the task, the key is the host and process of the operation
+	Detail map[string]metadata.ProcessOperateTaskDetail
+}
+
+// ProcControl control the processes on the hosts, the error returned means the backend can not be reached,
+// the backend failure is carried by the code of the reply
+type ProcControl interface {
+	// Register register the process to the hosts
+	Register(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error)
+	// Unregister unregister the process from the hosts
+	Unregister(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error)
+	// Operate start, stop, restart or reload the process, the reply carries the task id
+	Operate(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error)
+	// QueryTaskResult query the result of the operation task
+	QueryTaskResult(ctx context.Context, header http.Header, taskID string) (*ProcControlReply, error)
+}
+
+// ProcControlFactory create a process control driver with the driver config
+type ProcControlFactory func(config map[string]string, esbServ esbserver.EsbClientInterface) (ProcControl, error)
+
+var (
+	procControlLock      sync.RWMutex
+	procControlFactories = make(map[string]ProcControlFactory)
+)
+
+func init() {
+	RegisterProcControl(ProcControlGse, newGseProcControl)
+	RegisterProcControl(ProcControlAgent, newAgentProcControl)
+}
+
+// RegisterProcControl register a process control driver, the driver registered later with the same name wins
+func RegisterProcControl(name string, factory ProcControlFactory) {
+	procControlLock.Lock()
+	defer procControlLock.Unlock()
+	procControlFactories[name] = factory
+}
+
+// NewProcControl create the process control of the driver, gse is used when the driver is empty
+func NewProcControl(name string, config map[string]string, esbServ esbserver.EsbClientInterface) (ProcControl, error) {
+	if "" == name {
+		name = ProcControlGse
+	}
+	procControlLock.RLock()
+	factory, ok := procControlFactories[name]
+	procControlLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("process control driver %s is not registered", name)
+	}
+	return factory(config, esbServ)
+}
+
+// gseProcControl operate the processes through the gse process server of esb
+type gseProcControl struct {
+	esbServ esbserver.EsbClientInterface
+}
+
+func newGseProcControl(config map[string]string, esbServ esbserver.EsbClientInterface) (ProcControl, error) {
+	if nil == esbServ {
+		return nil, fmt.Errorf("process control driver %s needs esb", ProcControlGse)
+	}
+	return &gseProcControl{esbServ: esbServ}, nil
+}
+
+func newGseReply(resp metadata.EsbBaseResponse) *ProcControlReply {
+	reply := &ProcControlReply{Code: resp.Code, Message: resp.Message}
+	if !resp.Result && 0 == reply.Code {
+		reply.Code = common.CCErrCommReplyDataFormatError
+	}
+	return reply
+}
+
+func (g *gseProcControl) Register(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	resp, err := g.esbServ.GseSrv().RegisterProcInfo(ctx, header, proc)
+	if nil != err {
+		return nil, err
+	}
+	// gse only sets the code for register
+	return &ProcControlReply{Code: resp.Code, Message: resp.Message}, nil
+}
+
+func (g *gseProcControl) Unregister(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	resp, err := g.esbServ.GseSrv().UnRegisterProcInfo(ctx, header, proc)
+	if nil != err {
+		return nil, err
+	}
+	return &ProcControlReply{Code: resp.Code, Message: resp.Message}, nil
+}
+
+func (g *gseProcControl) Operate(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	resp, err := g.esbServ.GseSrv().OperateProcess(ctx, header, proc)
+	if nil != err {
+		return nil, err
+	}
+	reply := newGseReply(resp.EsbBaseResponse)
+	reply.TaskID, _ = resp.Data[common.BKGseTaskIDField].(string)
+	return reply, nil
+}
+
+func (g *gseProcControl) QueryTaskResult(ctx context.Context, header http.Header, taskID string) (*ProcControlReply, error) {
+	resp, err := g.esbServ.GseSrv().QueryProcOperateResult(ctx, header, taskID)
+	if nil != err {
+		return nil, err
+	}
+	reply := newGseReply(resp.EsbBaseResponse)
+	reply.TaskID = taskID
+	reply.Detail = resp.Data
+	return reply, nil
+}
+
+// agentProcControl dispatches the process control to an agent with a simple http json protocol,
+// POST {addr}/v1/proc/register, /v1/proc/unregister and /v1/proc/operate with the process request as body,
+// GET {addr}/v1/proc/task/{task_id} for the operation result.
+// the agent replies {"code": 0, "message": "", "data": ...}, the data of operate is {"task_id": ""},
+// the data of the task is the operation result of each host process.
+type agentProcControl struct {
+	addr   string
+	client *http.Client
+}
+
+// agentForwardHeaders the headers of the caller forwarded to the agent
+var agentForwardHeaders = []string{common.BKHTTPCCRequestID, common.BKHTTPHeaderUser, common.BKHTTPOwnerID}
+
+type agentReply struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func newAgentProcControl(config map[string]string, esbServ esbserver.EsbClientInterface) (ProcControl, error) {
+	addr := strings.TrimRight(config["agent.addr"], "/")
+	if "" == addr {
+		return nil, fmt.Errorf("process control driver %s needs agent.addr", ProcControlAgent)
+	}
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	timeout := 10 * time.Second
+	if val, ok := config["agent.timeout"]; ok {
+		duration, err := time.ParseDuration(val)
+		if nil != err {
+			return nil, fmt.Errorf("invalid agent.timeout %s, %v", val, err)
+		}
+		timeout = duration
+	}
+	return &agentProcControl{addr: addr, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (a *agentProcControl) do(ctx context.Context, method, subPath string, header http.Header, body interface{}) (*agentReply, error) {
+	var payload []byte
+	if nil != body {
+		var err error
+		if payload, err = json.Marshal(body); nil != err {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, a.addr+subPath, bytes.NewReader(payload))
+	if nil != err {
+		return nil, err
+	}
+	// the agent is an external service, only the headers to trace the request are forwarded,
+	// the cookies and the credentials of the caller are never sent to it
+	for _, key := range agentForwardHeaders {
+		if val := header.Get(key); "" != val {
+			req.Header.Set(key, val)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req.WithContext(ctx))
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if nil != err {
+		return nil, err
+	}
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("agent replied status %d, %s", resp.StatusCode, string(content))
+	}
+	reply := new(agentReply)
+	if err := json.Unmarshal(content, reply); nil != err {
+		return nil, fmt.Errorf("agent replied invalid data, %v", err)
+	}
+	return reply, nil
+}
+
+func (a *agentProcControl) Register(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	reply, err := a.do(ctx, http.MethodPost, "/v1/proc/register", header, proc)
+	if nil != err {
+		return nil, err
+	}
+	return &ProcControlReply{Code: reply.Code, Message: reply.Message}, nil
+}
+
+func (a *agentProcControl) Unregister(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	reply, err := a.do(ctx, http.MethodPost, "/v1/proc/unregister", header, proc)
+	if nil != err {
+		return nil, err
+	}
+	return &ProcControlReply{Code: reply.Code, Message: reply.Message}, nil
+}
+
+func (a *agentProcControl) Operate(ctx context.Context, header http.Header, proc *metadata.GseProcRequest) (*ProcControlReply, error) {
+	reply, err := a.do(ctx, http.MethodPost, "/v1/proc/operate", header, proc)
+	if nil != err {
+		return nil, err
+	}
+	result := &ProcControlReply{Code: reply.Code, Message: reply.Message}
+	if 0 == reply.Code && 0 != len(reply.Data) {
+		data := make(map[string]string)
+		if err := json.Unmarshal(reply.Data, &data); nil != err {
+			return nil, fmt.Errorf("agent replied invalid operate data, %v", err)
+		}
+		result.TaskID = data[common.BKGseTaskIDField]
+	}
+	return result, nil
+}
+
+func (a *agentProcControl) QueryTaskResult(ctx context.Context, header http.Header, taskID string) (*ProcControlReply, error) {
+	reply, err := a.do(ctx, http.MethodGet, "/v1/proc/task/"+url.PathEscape(taskID), header, nil)
+	if nil != err {
+		return nil, err
+	}
+	result := &ProcControlReply{Code: reply.Code, Message: reply.Message, TaskID: taskID}
+	if 0 == reply.Code && 0 != len(reply.Data) {
+		if err := json.Unmarshal(reply.Data, &result.Detail); nil != err {
+			return nil, fmt.Errorf("agent replied invalid task data, %v", err)
+		}
+	}
+	return result, nil
+}
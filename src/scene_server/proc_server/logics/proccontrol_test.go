@@ -0,0 +1,97 @@
+package logics
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+)
+
+func TestAgentProcControl(t *testing.T) {
+	requests := make(map[string]metadata.GseProcRequest)
+	headers := make(map[string]http.Header)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers[r.URL.Path] = r.Header
+		if http.MethodPost == r.Method {
+			body, _ := ioutil.ReadAll(r.Body)
+			proc := metadata.GseProcRequest{}
+			if err := json.Unmarshal(body, &proc); nil != err {
+				t.Errorf("agent got invalid request %s", string(body))
+			}
+			requests[r.URL.Path] = proc
+		}
+		switch r.URL.Path {
+		case "/v1/proc/register":
+			w.Write([]byte(`{"code":0,"message":"success"}`))
+		case "/v1/proc/unregister":
+			w.Write([]byte(`{"code":1,"message":"not registered"}`))
+		case "/v1/proc/operate":
+			w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"task1"}}`))
+		case "/v1/proc/task/task1":
+			w.Write([]byte(`{"code":0,"message":"success","data":{"0:127.0.0.1:nginx":{"errcode":0,"errmsg":"success"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer agent.Close()
+
+	if _, err := NewProcControl(ProcControlAgent, map[string]string{}, nil); nil == err {
+		t.Errorf("agent driver without address should be failed")
+	}
+	if _, err := NewProcControl(ProcControlGse, map[string]string{}, nil); nil == err {
+		t.Errorf("gse driver without esb should be failed")
+	}
+	ctrl, err := NewProcControl(ProcControlAgent, map[string]string{"agent.addr": agent.URL, "agent.timeout": "1s"}, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	proc := &metadata.GseProcRequest{AppID: 1, ProcID: 2, Hosts: []metadata.GseHost{{HostID: 3, Ip: "127.0.0.1"}}}
+	proc.Meta.Name = "nginx"
+
+	header := http.Header{}
+	header.Set(common.BKHTTPCCRequestID, "rid")
+	header.Set(common.BKHTTPHeaderUser, "admin")
+	header.Set(common.BKHTTPOwnerID, "0")
+	header.Set("Cookie", "bk_token=secret")
+	header.Set("Authorization", "Bearer secret")
+	reply, err := ctrl.Register(ctx, header, proc)
+	if nil != err || 0 != reply.Code {
+		t.Errorf("register, reply:%+v, err:%v", reply, err)
+	}
+	if got := requests["/v1/proc/register"]; "nginx" != got.Meta.Name || 1 != len(got.Hosts) {
+		t.Errorf("agent got register request %+v", got)
+	}
+	got := headers["/v1/proc/register"]
+	if "rid" != got.Get(common.BKHTTPCCRequestID) || "admin" != got.Get(common.BKHTTPHeaderUser) || "0" != got.Get(common.BKHTTPOwnerID) {
+		t.Errorf("agent should get the rid, user and supplier account headers, got %v", got)
+	}
+	if "" != got.Get("Cookie") || "" != got.Get("Authorization") {
+		t.Errorf("agent should not get the credentials of the caller, got %v", got)
+	}
+
+	reply, err = ctrl.Unregister(ctx, http.Header{}, proc)
+	if nil != err || 1 != reply.Code || "not registered" != reply.Message {
+		t.Errorf("unregister, reply:%+v, err:%v", reply, err)
+	}
+
+	reply, err = ctrl.Operate(ctx, http.Header{}, proc)
+	if nil != err || 0 != reply.Code || "task1" != reply.TaskID {
+		t.Errorf("operate, reply:%+v, err:%v", reply, err)
+	}
+
+	reply, err = ctrl.QueryTaskResult(ctx, http.Header{}, "task1")
+	if nil != err || 0 != reply.Code || 1 != len(reply.Detail) {
+		t.Errorf("query task, reply:%+v, err:%v", reply, err)
+	}
+
+	if _, err := ctrl.QueryTaskResult(ctx, http.Header{}, "task2"); nil == err {
+		t.Errorf("query unknown task should be failed")
+	}
+}
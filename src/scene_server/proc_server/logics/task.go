@@ -115,18 +115,18 @@ func (lgc *Logics) handleOPProcTask(ctx context.Context, taskID string) (waitExe
 
 	for _, gseTaskID := range gseTaskIDArr {
 
-		gseRet, err := lgc.esbServ.GseSrv().QueryProcOperateResult(ctx, lgc.header, gseTaskID)
+		gseRet, err := lgc.procCtrl.QueryTaskResult(ctx, lgc.header, gseTaskID)
 		if err != nil {
-			requestErr = err
+			requestErr = lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
 			blog.Errorf("handleOPProcTask query task info from gse  error, taskID:%s, gseTaskID:%s, error:%s logID:%s", taskID, gseTaskID, err.Error(), lgc.rid)
 			continue
-		} else if !gseRet.Result {
+		} else if 0 != gseRet.Code {
 			requestErr = lgc.ccErr.New(gseRet.Code, gseRet.Message)
 			blog.Errorf("handleOPProcTask query task info from gse failed,  taskID:%s, gseTaskID:%s, gse return error:%s, error code:%d rid:%s", taskID, gseTaskID, gseRet.Message, gseRet.Code, lgc.rid)
 			continue
 		}
 
-		for key, item := range gseRet.Data {
+		for key, item := range gseRet.Detail {
 			if 0 != item.Errcode {
 				if int(metadata.ProcOpTaskStatusExecuteing) == item.Errcode {
 					waitExecArr = append(waitExecArr, key)
@@ -142,7 +142,7 @@ func (lgc *Logics) handleOPProcTask(ctx context.Context, taskID string) (waitExe
 				common.BKTaskIDField: taskID,
 				common.BKStatusField: mapstr.MapStr{common.BKDBNE: metadata.ProcOpTaskStatusSucc},
 			}
-			data := mapstr.MapStr{common.BKStatusField: metadata.ProcOpTaskStatusErr, common.BKGseOpProcTaskDetailField: gseRet.Data}
+			data := mapstr.MapStr{common.BKStatusField: metadata.ProcOpTaskStatusErr, common.BKGseOpProcTaskDetailField: gseRet.Detail}
 			err := lgc.ModifyTaskInfo(ctx, conds, data)
 			if nil != err {
 				blog.Errorf("handleOPProcTask ModifyTaskStatus task detail  failed,  taskID:%s, gseTaskID:%s, gse return error:%s, error code:%d rid:%s", taskID, gseTaskID, gseRet.Message, gseRet.Code, lgc.rid)
@@ -164,7 +164,7 @@ func (lgc *Logics) handleOPProcTask(ctx context.Context, taskID string) (waitExe
 			common.BKTaskIDField:      taskID,
 			common.BKGseOpTaskIDField: gseTaskID,
 		}
-		data := mapstr.MapStr{common.BKStatusField: metadata.ProcOpTaskStatusSucc, common.BKGseOpProcTaskDetailField: gseRet.Data}
+		data := mapstr.MapStr{common.BKStatusField: metadata.ProcOpTaskStatusSucc, common.BKGseOpProcTaskDetailField: gseRet.Detail}
 		err = lgc.ModifyTaskInfo(ctx, conds, data)
 		if nil != err {
 			requestErr = err
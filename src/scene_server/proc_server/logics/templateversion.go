@@ -0,0 +1,277 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/condition"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// templateVersionTransitions the status which the template version can be changed to,
+// draft -> review -> approved -> online -> archived, review and approved can be sent back to draft
+var templateVersionTransitions = map[string][]string{
+	common.TemplateStatusDraft:    {common.TemplateStatusReview},
+	common.TemplateStatusReview:   {common.TemplateStatusApproved, common.TemplateStatusDraft},
+	common.TemplateStatusApproved: {common.TemplateStatusOnline, common.TemplateStatusDraft},
+	common.TemplateStatusOnline:   {common.TemplateStatusArchived},
+}
+
+// templateVersionLockExpire the expiration of the lock on the versions of a template
+const templateVersionLockExpire = 30 * time.Second
+
+// TemplateVersionConfig the config of the template version workflow
+type TemplateVersionConfig struct {
+	// ApproverGroups the user groups whose users can approve and online the template version
+	ApproverGroups []string
+}
+
+// CanTransitTemplateVersion check whether the template version can be changed from the status to the other one
+func CanTransitTemplateVersion(from, to string) bool {
+	return util.InStrArr(templateVersionTransitions[from], to)
+}
+
+// needTemplateVersionApprover the transitions decided by the review need an approver
+func needTemplateVersionApprover(from, to string) bool {
+	return common.TemplateStatusReview == from || common.TemplateStatusOnline == to
+}
+
+// IsTemplateVersionApprover check whether the user belongs to one of the approver groups
+func (lgc *Logics) IsTemplateVersionApprover(ctx context.Context, ownerID, user string, groups []string) (bool, error) {
+	if 0 == len(groups) || "" == user {
+		return false, nil
+	}
+	cond := condition.CreateCondition()
+	cond.Field(common.BKUserListField).Like(user)
+	ret, err := lgc.CoreAPI.ObjectController().Privilege().SearchUserGroup(ctx, ownerID, lgc.header, cond.ToMapStr())
+	if nil != err {
+		blog.Errorf("IsTemplateVersionApprover SearchUserGroup http do error,err:%s,user:%s,rid:%s", err.Error(), user, lgc.rid)
+		return false, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("IsTemplateVersionApprover SearchUserGroup http response error,err code:%d,err msg:%s,user:%s,rid:%s", ret.Code, ret.ErrMsg, user, lgc.rid)
+		return false, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	for _, group := range ret.Data {
+		// the user list is searched by like, the similar user names are skipped
+		if !util.InStrArr(strings.Split(group.UserList, ";"), user) {
+			continue
+		}
+		if util.InStrArr(groups, group.GroupID) || util.InStrArr(groups, group.GroupName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TransitTemplateVersion change the status of the template version along the workflow,
+// the other online version is archived when the version goes online
+func (lgc *Logics) TransitTemplateVersion(ctx context.Context, ownerID string, appID, templateID, versionID int64, params *metadata.TemplateVersionStatusParams, config *TemplateVersionConfig) (*metadata.TemplateVersionStatusResult, error) {
+	user := util.GetUser(lgc.header)
+	version, err := lgc.GetConfigTemplateVersion(ctx, appID, templateID, versionID)
+	if nil != err {
+		return nil, err
+	}
+	rawFrom := util.GetStrByInterface(version[common.BKStatusField])
+	from := rawFrom
+	if common.TemplateStatusHistory == from {
+		from = common.TemplateStatusArchived
+	}
+	if !CanTransitTemplateVersion(from, params.Status) {
+		blog.Errorf("TransitTemplateVersion version %d of template %d can not be changed from %s to %s,rid:%s", versionID, templateID, from, params.Status, lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateVersionStatusError, from, params.Status)
+	}
+	if needTemplateVersionApprover(from, params.Status) {
+		isApprover, err := lgc.IsTemplateVersionApprover(ctx, ownerID, user, config.ApproverGroups)
+		if nil != err {
+			return nil, err
+		}
+		if !isApprover {
+			blog.Errorf("TransitTemplateVersion user %s is not in the approver groups %v,rid:%s", user, config.ApproverGroups, lgc.rid)
+			return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateVersionNotApprover, user)
+		}
+	}
+
+	result := &metadata.TemplateVersionStatusResult{
+		VersionID: versionID,
+		PreStatus: from,
+		Status:    params.Status,
+		Comment:   params.Comment,
+		Operator:  user,
+	}
+	if common.TemplateStatusReview == params.Status {
+		if err := lgc.diffWithOnlineVersion(ctx, appID, templateID, version, result); nil != err {
+			return nil, err
+		}
+	}
+
+	if common.TemplateStatusOnline != params.Status {
+		if err := lgc.updateTemplateVersionStatus(ctx, appID, templateID, versionID, rawFrom, params.Status); nil != err {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// the versions of the template go online one by one, so that only one version is online
+	unlock, err := lgc.lockTemplateVersions(templateID)
+	if nil != err {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := lgc.updateTemplateVersionStatus(ctx, appID, templateID, versionID, rawFrom, params.Status); nil != err {
+		return nil, err
+	}
+	// the previous online version is archived after the version goes online, so that there is always an online version
+	online, err := lgc.getOnlineTemplateVersions(ctx, appID, templateID, versionID)
+	if nil != err {
+		lgc.rollbackTemplateVersionStatus(ctx, appID, templateID, versionID, params.Status, rawFrom)
+		return nil, err
+	}
+	for _, item := range online {
+		onlineID, err := util.GetInt64ByInterface(item[common.BKVersionIDField])
+		if nil != err {
+			continue
+		}
+		if err := lgc.updateTemplateVersionStatus(ctx, appID, templateID, onlineID, common.TemplateStatusOnline, common.TemplateStatusArchived); nil != err {
+			lgc.rollbackTemplateVersionStatus(ctx, appID, templateID, versionID, params.Status, rawFrom)
+			return nil, err
+		}
+		result.ArchivedID = append(result.ArchivedID, onlineID)
+	}
+	return result, nil
+}
+
+// GetTemplateVersionDiff compare the version with the online version of the template
+func (lgc *Logics) GetTemplateVersionDiff(ctx context.Context, appID, templateID, versionID int64) (*metadata.TemplateVersionStatusResult, error) {
+	version, err := lgc.GetConfigTemplateVersion(ctx, appID, templateID, versionID)
+	if nil != err {
+		return nil, err
+	}
+	result := &metadata.TemplateVersionStatusResult{
+		VersionID: versionID,
+		Status:    util.GetStrByInterface(version[common.BKStatusField]),
+	}
+	if err := lgc.diffWithOnlineVersion(ctx, appID, templateID, version, result); nil != err {
+		return nil, err
+	}
+	return result, nil
+}
+
+// diffWithOnlineVersion set the diff from the online version to the version into the result
+func (lgc *Logics) diffWithOnlineVersion(ctx context.Context, appID, templateID int64, version mapstr.MapStr, result *metadata.TemplateVersionStatusResult) error {
+	online, err := lgc.getOnlineTemplateVersions(ctx, appID, templateID, result.VersionID)
+	if nil != err {
+		return err
+	}
+	onlineContent := ""
+	if 0 != len(online) {
+		result.OnlineVersionID, _ = util.GetInt64ByInterface(online[0][common.BKVersionIDField])
+		onlineContent = util.GetStrByInterface(online[0][common.BKContentField])
+	}
+	result.Diff = UnifiedDiff(fmt.Sprintf("version_%d", result.OnlineVersionID), fmt.Sprintf("version_%d", result.VersionID),
+		onlineContent, util.GetStrByInterface(version[common.BKContentField]))
+	return nil
+}
+
+// getOnlineTemplateVersions get the online versions of the template except the given version
+func (lgc *Logics) getOnlineTemplateVersions(ctx context.Context, appID, templateID, exceptVersionID int64) ([]mapstr.MapStr, error) {
+	input := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:     appID,
+			common.BKTemlateIDField: templateID,
+			common.BKVersionIDField: mapstr.MapStr{common.BKDBNE: exceptVersionID},
+			common.BKStatusField:    common.TemplateStatusOnline,
+		},
+	}
+	input.Limit.Limit = common.BKNoLimit
+	ret, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(ctx, lgc.header, common.BKInnerObjIDTempVersion, input)
+	if nil != err {
+		blog.Errorf("getOnlineTemplateVersions ReadInstance http do error,err:%s,input:%+v,rid:%s", err.Error(), input, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("getOnlineTemplateVersions ReadInstance http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, input, lgc.rid)
+		return nil, lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	return ret.Data.Info, nil
+}
+
+// lockTemplateVersions lock the versions of the template among the proc servers, call the returned function to unlock
+func (lgc *Logics) lockTemplateVersions(templateID int64) (func(), error) {
+	if nil == lgc.cache {
+		return func() {}, nil
+	}
+	key := fmt.Sprintf("%s%d", common.RedisProcSrvTemplateVersionLockKeyPrefix, templateID)
+	locked, err := lgc.cache.SetNX(key, lgc.rid, templateVersionLockExpire).Result()
+	if nil != err {
+		blog.Errorf("lockTemplateVersions lock template %d error:%s,rid:%s", templateID, err.Error(), lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateVersionLocked, templateID)
+	}
+	if !locked {
+		blog.Errorf("lockTemplateVersions template %d is locked by others,rid:%s", templateID, lgc.rid)
+		return nil, lgc.ccErr.Errorf(common.CCErrProcTemplateVersionLocked, templateID)
+	}
+	return func() {
+		// the lock may be expired and got by others
+		if owner, err := lgc.cache.Get(key).Result(); nil == err && owner == lgc.rid {
+			lgc.cache.Del(key)
+		}
+	}, nil
+}
+
+// updateTemplateVersionStatus change the status of the version from the status to the other one,
+// it fails when the status has been changed by others
+func (lgc *Logics) updateTemplateVersionStatus(ctx context.Context, appID, templateID, versionID int64, from, to string) error {
+	input := &metadata.UpdateOption{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:     appID,
+			common.BKTemlateIDField: templateID,
+			common.BKVersionIDField: versionID,
+			common.BKStatusField:    from,
+		},
+		Data: mapstr.MapStr{
+			common.BKStatusField: to,
+			common.LastTimeField: time.Now().UTC(),
+		},
+	}
+	ret, err := lgc.CoreAPI.CoreService().Instance().UpdateInstance(ctx, lgc.header, common.BKInnerObjIDTempVersion, input)
+	if nil != err {
+		blog.Errorf("updateTemplateVersionStatus UpdateInstance http do error,err:%s,input:%+v,rid:%s", err.Error(), input, lgc.rid)
+		return lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !ret.Result {
+		blog.Errorf("updateTemplateVersionStatus UpdateInstance http response error,err code:%d,err msg:%s,input:%+v,rid:%s", ret.Code, ret.ErrMsg, input, lgc.rid)
+		return lgc.ccErr.New(ret.Code, ret.ErrMsg)
+	}
+	if 0 == ret.Data.Count {
+		blog.Errorf("updateTemplateVersionStatus version %d of template %d is not in %s status,rid:%s", versionID, templateID, from, lgc.rid)
+		return lgc.ccErr.Errorf(common.CCErrProcTemplateVersionStatusError, from, to)
+	}
+	return nil
+}
+
+// rollbackTemplateVersionStatus change the status of the version back when the transition fails halfway
+func (lgc *Logics) rollbackTemplateVersionStatus(ctx context.Context, appID, templateID, versionID int64, status, prevStatus string) {
+	if err := lgc.updateTemplateVersionStatus(ctx, appID, templateID, versionID, status, prevStatus); nil != err {
+		blog.Errorf("rollbackTemplateVersionStatus version %d of template %d back to %s failed, err:%s,rid:%s", versionID, templateID, prevStatus, err.Error(), lgc.rid)
+	}
+}
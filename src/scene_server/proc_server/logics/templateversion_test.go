@@ -0,0 +1,46 @@
+package logics
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+)
+
+func TestCanTransitTemplateVersion(t *testing.T) {
+	type testData struct {
+		from string
+		to   string
+		ok   bool
+	}
+
+	td := []testData{
+		testData{from: common.TemplateStatusDraft, to: common.TemplateStatusReview, ok: true},
+		testData{from: common.TemplateStatusReview, to: common.TemplateStatusApproved, ok: true},
+		testData{from: common.TemplateStatusReview, to: common.TemplateStatusDraft, ok: true},
+		testData{from: common.TemplateStatusApproved, to: common.TemplateStatusOnline, ok: true},
+		testData{from: common.TemplateStatusOnline, to: common.TemplateStatusArchived, ok: true},
+		testData{from: common.TemplateStatusDraft, to: common.TemplateStatusOnline, ok: false},
+		testData{from: common.TemplateStatusReview, to: common.TemplateStatusOnline, ok: false},
+		testData{from: common.TemplateStatusArchived, to: common.TemplateStatusOnline, ok: false},
+		testData{from: common.TemplateStatusOnline, to: common.TemplateStatusDraft, ok: false},
+		testData{from: "", to: common.TemplateStatusReview, ok: false},
+	}
+
+	for _, item := range td {
+		if ok := CanTransitTemplateVersion(item.from, item.to); ok != item.ok {
+			t.Errorf("transit from %s to %s, expected %v, got %v", item.from, item.to, item.ok, ok)
+		}
+	}
+
+	for _, to := range []string{common.TemplateStatusApproved, common.TemplateStatusDraft} {
+		if !needTemplateVersionApprover(common.TemplateStatusReview, to) {
+			t.Errorf("transit from review to %s should need an approver", to)
+		}
+	}
+	if !needTemplateVersionApprover(common.TemplateStatusApproved, common.TemplateStatusOnline) {
+		t.Errorf("transit to online should need an approver")
+	}
+	if needTemplateVersionApprover(common.TemplateStatusDraft, common.TemplateStatusReview) {
+		t.Errorf("transit from draft to review should not need an approver")
+	}
+}
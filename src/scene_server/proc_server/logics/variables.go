@@ -14,6 +14,7 @@ package logics
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"configcenter/src/common"
@@ -28,6 +29,8 @@ type Variables struct {
 	header  http.Header
 	ownerID string
 	appID   int64
+	// the instances have been read, keyed by object id and instance id
+	insts map[string]types.MapStr
 }
 
 func (lgc *Logics) NewVariables(ctx context.Context, appID int64) *Variables {
@@ -36,6 +39,7 @@ func (lgc *Logics) NewVariables(ctx context.Context, appID int64) *Variables {
 		header:  lgc.header,
 		ownerID: lgc.ownerID,
 		appID:   appID,
+		insts:   make(map[string]types.MapStr),
 	}
 }
 
@@ -197,3 +201,92 @@ func (v *Variables) GetStandVariables(ctx context.Context, setName, moduleName s
 
 	return allVariables
 }
+
+// GetProcInstVariables get the variables of the process instance. the variables of each scope are kept
+// under the scope name, e.g. {{ host.bk_host_innerip }}, and also merged into the top level the same way
+// as GetStandVariables does.
+func (v *Variables) GetProcInstVariables(ctx context.Context, inst *metadata.ProcInstanceModel) (types.MapStr, error) {
+	appVariables, err := v.getInstVariables(ctx, common.BKInnerObjIDApp, common.BKAppIDField, v.appID)
+	if nil != err {
+		return nil, err
+	}
+	moduleVariables, err := v.getInstVariables(ctx, common.BKInnerObjIDModule, common.BKModuleIDField, inst.ModuleID)
+	if nil != err {
+		return nil, err
+	}
+	setID := inst.SetID
+	if 0 == setID {
+		setID, _ = util.GetInt64ByInterface(moduleVariables[common.BKSetIDField])
+	}
+	setVariables, err := v.getInstVariables(ctx, common.BKInnerObjIDSet, common.BKSetIDField, setID)
+	if nil != err {
+		return nil, err
+	}
+	hostVariables, err := v.getInstVariables(ctx, common.BKInnerObjIDHost, common.BKHostIDField, inst.HostID)
+	if nil != err {
+		return nil, err
+	}
+	procVariables, err := v.getInstVariables(ctx, common.BKInnerObjIDProc, common.BKProcessIDField, inst.ProcID)
+	if nil != err {
+		return nil, err
+	}
+
+	allVariables := types.MapStr{}
+	for _, scopeVariables := range []types.MapStr{procVariables, hostVariables, moduleVariables, setVariables, appVariables} {
+		for key, val := range scopeVariables {
+			allVariables[key] = val
+		}
+	}
+	allVariables[common.BKFuncIDField] = inst.FuncID
+	allVariables["host_proc_id"] = inst.HostProcID
+	allVariables["proc_instance_id"] = inst.ProcInstanceID
+
+	allVariables[common.BKInnerObjIDApp] = appVariables
+	allVariables[common.BKInnerObjIDSet] = setVariables
+	allVariables[common.BKInnerObjIDModule] = moduleVariables
+	allVariables[common.BKInnerObjIDHost] = hostVariables
+	allVariables[common.BKInnerObjIDProc] = procVariables
+	return allVariables, nil
+}
+
+func (v *Variables) getInstVariables(ctx context.Context, objID, instIDField string, instID int64) (types.MapStr, error) {
+	key := fmt.Sprintf("%s:%d", objID, instID)
+	if inst, ok := v.insts[key]; ok {
+		return inst, nil
+	}
+
+	var insts []types.MapStr
+	cond := types.MapStr{instIDField: instID}
+	if common.BKInnerObjIDHost == objID {
+		input := metadata.QueryInput{Condition: cond, Limit: 1}
+		result, err := v.logic.CoreAPI.HostController().Host().GetHosts(ctx, v.header, &input)
+		if err != nil {
+			blog.Errorf("getInstVariables GetHosts http do error,err:%s,query:%+v,rid:%s", err.Error(), input, v.logic.rid)
+			return nil, v.logic.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+		}
+		if !result.Result {
+			blog.Errorf("getInstVariables GetHosts http response error,err code:%d,err msg:%s,query:%+v,rid:%s", result.Code, result.ErrMsg, input, v.logic.rid)
+			return nil, v.logic.ccErr.New(result.Code, result.ErrMsg)
+		}
+		insts = result.Data.Info
+	} else {
+		input := metadata.QueryCondition{Condition: cond}
+		result, err := v.logic.CoreAPI.CoreService().Instance().ReadInstance(ctx, v.header, objID, &input)
+		if err != nil {
+			blog.Errorf("getInstVariables ReadInstance http do error,err:%s,objID:%s,query:%+v,rid:%s", err.Error(), objID, input, v.logic.rid)
+			return nil, v.logic.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+		}
+		if !result.Result {
+			blog.Errorf("getInstVariables ReadInstance http response error,err code:%d,err msg:%s,objID:%s,query:%+v,rid:%s", result.Code, result.ErrMsg, objID, input, v.logic.rid)
+			return nil, v.logic.ccErr.New(result.Code, result.ErrMsg)
+		}
+		insts = result.Data.Info
+	}
+
+	if 0 == len(insts) {
+		blog.Errorf("getInstVariables %s instance %d not found,rid:%s", objID, instID, v.logic.rid)
+		return nil, v.logic.ccErr.Error(common.CCErrCommNotFound)
+	}
+	v.insts[key] = insts[0]
+	return insts[0], nil
+}
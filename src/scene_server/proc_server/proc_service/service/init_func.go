@@ -12,12 +12,13 @@
 package service
 
 import (
+	"context"
 	"net/http"
 )
 
 func (s *ProcServer) InitFunc() {
 	header := make(http.Header, 0)
-	srvData := s.newSrvComm(header)
+	srvData := s.newSrvComm(context.Background(), header)
 
 	go srvData.lgc.InitFunc(srvData.ctx)
 }
@@ -12,6 +12,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -26,7 +27,7 @@ import (
 )
 
 func (ps *ProcServer) GetProcessPortByApplicationID(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	//get appID
@@ -147,7 +148,7 @@ func (ps *ProcServer) GetProcessPortByApplicationID(req *restful.Request, resp *
 
 //根据IP获取进程端口
 func (ps *ProcServer) GetProcessPortByIP(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	reqParam := make(map[string]interface{})
@@ -267,7 +268,7 @@ func (ps *ProcServer) GetProcessPortByIP(req *restful.Request, resp *restful.Res
 
 // 根据模块获取所有关联的进程，建立Map ModuleToProcesses
 func (ps *ProcServer) getProcessesByModuleName(forward http.Header, moduleName string, appID int64) ([]mapstr.MapStr, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 	procData := make([]mapstr.MapStr, 0)
 	params := mapstr.MapStr{
@@ -295,7 +296,7 @@ func (ps *ProcServer) getModuleHostConfigsByAppID(appID int64, forward http.Head
 }
 
 func (ps *ProcServer) getConfigByCond(forward http.Header, cond map[string][]int64) ([]map[string]int64, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 
 	configArr := make([]map[string]int64, 0)
@@ -330,7 +331,7 @@ func (ps *ProcServer) getAppInfoByID(appID int64, forward http.Header) (map[int6
 }
 
 func (ps *ProcServer) getAppMapByCond(forward http.Header, fields []string, cond mapstr.MapStr) (map[int64]mapstr.MapStr, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 
 	appMap := make(map[int64]mapstr.MapStr, 0)
@@ -362,7 +363,7 @@ func (ps *ProcServer) getAppMapByCond(forward http.Header, fields []string, cond
 }
 
 func (ps *ProcServer) getHostMapByAppID(forward http.Header, configData []map[string]int64) (map[int64]map[string]interface{}, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 	hostIDArr := make([]int64, 0)
 	for _, config := range configData {
@@ -403,7 +404,7 @@ func (ps *ProcServer) getHostMapByAppID(forward http.Header, configData []map[st
 }
 
 func (ps *ProcServer) getHostMapByCond(forward http.Header, condition map[string]interface{}) (map[int64]map[string]interface{}, []int64, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 
 	hostMap := make(map[int64]map[string]interface{})
@@ -438,7 +439,7 @@ func (ps *ProcServer) getHostMapByCond(forward http.Header, condition map[string
 }
 
 func (ps *ProcServer) getModuleMapByCond(forward http.Header, field []string, cond mapstr.MapStr) (map[int64]mapstr.MapStr, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 	moduleMap := make(map[int64]mapstr.MapStr)
 	input := new(meta.QueryCondition)
@@ -469,7 +470,7 @@ func (ps *ProcServer) getModuleMapByCond(forward http.Header, field []string, co
 }
 
 func (ps *ProcServer) getProcessMapByAppID(appID int64, forward http.Header) (map[int64]mapstr.MapStr, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 
 	procMap := make(map[int64]mapstr.MapStr)
@@ -503,7 +504,7 @@ func (ps *ProcServer) getProcessMapByAppID(appID int64, forward http.Header) (ma
 }
 
 func (ps *ProcServer) getProcessBindModule(appId, procId int64, forward http.Header) ([]interface{}, error) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 	defErr := srvData.ccErr
 
 	condition := make(map[string]interface{})
@@ -25,7 +25,7 @@ import (
 )
 
 func (ps *ProcServer) BindModuleProcess(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	ownerID := srvData.ownerID
 	defErr := srvData.ccErr
@@ -71,7 +71,7 @@ func (ps *ProcServer) BindModuleProcess(req *restful.Request, resp *restful.Resp
 }
 
 func (ps *ProcServer) DeleteModuleProcessBind(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	pathParams := req.PathParameters()
@@ -111,7 +111,7 @@ func (ps *ProcServer) DeleteModuleProcessBind(req *restful.Request, resp *restfu
 }
 
 func (ps *ProcServer) GetProcessBindModule(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	pathParams := req.PathParameters()
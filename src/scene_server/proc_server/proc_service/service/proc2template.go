@@ -29,7 +29,7 @@ import (
 )
 
 func (ps *ProcServer) GetProcBindTemplate(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	pathParams := req.PathParameters()
@@ -104,7 +104,7 @@ func (ps *ProcServer) GetProcBindTemplate(req *restful.Request, resp *restful.Re
 }
 
 func (ps *ProcServer) BindProc2Template(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	pathParams := req.PathParameters()
@@ -154,7 +154,7 @@ func (ps *ProcServer) BindProc2Template(req *restful.Request, resp *restful.Resp
 }
 
 func (ps *ProcServer) DeleteProc2Template(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	pathParams := req.PathParameters()
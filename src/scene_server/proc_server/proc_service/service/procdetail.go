@@ -12,6 +12,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -25,7 +26,7 @@ import (
 )
 
 func (ps *ProcServer) GetProcessDetailByID(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	ownerID := req.PathParameter(common.BKOwnerIDField)
 	appIDStr := req.PathParameter(common.BKAppIDField)
@@ -54,7 +55,7 @@ func (ps *ProcServer) GetProcessDetailByID(req *restful.Request, resp *restful.R
 }
 
 func (ps *ProcServer) getProcDetail(req *restful.Request, ownerID string, appID, procID int) ([]map[string]interface{}, error) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	// search process
 	procCondition := make(map[string]interface{})
@@ -123,7 +124,7 @@ type instNameAsst struct {
 }
 
 func (ps *ProcServer) getInstAsst(forward http.Header, ownerID, objID string, ids []string, page map[string]interface{}) ([]instNameAsst, int, int) {
-	srvData := ps.newSrvComm(forward)
+	srvData := ps.newSrvComm(context.Background(), forward)
 
 	tmpIDS := make([]int, 0)
 	for _, id := range ids {
@@ -32,7 +32,7 @@ import (
 )
 
 func (ps *ProcServer) CreateProcess(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -88,7 +88,7 @@ func (ps *ProcServer) CreateProcess(req *restful.Request, resp *restful.Response
 }
 
 func (ps *ProcServer) UpdateProcess(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -160,7 +160,7 @@ func (ps *ProcServer) UpdateProcess(req *restful.Request, resp *restful.Response
 
 func (ps *ProcServer) BatchUpdateProcess(req *restful.Request, resp *restful.Response) {
 	//user := util.GetUser(req.Request.Header)
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -295,7 +295,7 @@ func (ps *ProcServer) BatchUpdateProcess(req *restful.Request, resp *restful.Res
 }
 
 func (ps *ProcServer) DeleteProcess(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -356,7 +356,7 @@ func (ps *ProcServer) DeleteProcess(req *restful.Request, resp *restful.Response
 }
 
 func (ps *ProcServer) SearchProcess(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
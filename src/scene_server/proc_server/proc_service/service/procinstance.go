@@ -23,7 +23,7 @@ import (
 )
 
 func (ps *ProcServer) OperateProcessInstance(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	procOpParam := new(meta.ProcessOperate)
@@ -57,7 +57,7 @@ func (ps *ProcServer) OperateProcessInstance(req *restful.Request, resp *restful
 }
 
 func (ps *ProcServer) QueryProcessOperateResult(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	taskID := req.PathParameter("taskID")
@@ -81,7 +81,7 @@ func (ps *ProcServer) QueryProcessOperateResult(req *restful.Request, resp *rest
 }
 
 func (ps *ProcServer) RefreshProcHostInstByEvent(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	input := new(meta.EventInst)
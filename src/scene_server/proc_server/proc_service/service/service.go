@@ -14,6 +14,8 @@ package service
 import (
 	"context"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/emicklei/go-restful"
@@ -22,6 +24,7 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	cfnc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/language"
 	"configcenter/src/common/metadata"
@@ -54,12 +57,18 @@ type ProcServer struct {
 	Config             *options.Config
 	EsbServ            esbserver.EsbClientInterface
 	Cache              *redis.Client
+	ProcControl        logics.ProcControl
 	procHostInstConfig logics.ProcHostInstConfig
+	// configDelivery is replaced when the config is updated, guarded by configDeliveryLock
+	configDelivery     logics.ConfigDelivery
+	configDeliveryLock sync.RWMutex
+
+	templateVersionConfig logics.TemplateVersionConfig
 }
 
-func (s *ProcServer) newSrvComm(header http.Header) *srvComm {
+func (s *ProcServer) newSrvComm(parent context.Context, header http.Header) *srvComm {
 	lang := util.GetLanguage(header)
-	ctx, cancel := s.Engine.CCCtx.WithCancel()
+	ctx, cancel := backbone.NewContextWithHeader(parent, header)
 	return &srvComm{
 		header:        header,
 		rid:           util.GetHTTPCCRequestID(header),
@@ -69,7 +78,7 @@ func (s *ProcServer) newSrvComm(header http.Header) *srvComm {
 		ctxCancelFunc: cancel,
 		user:          util.GetUser(header),
 		ownerID:       util.GetOwnerID(header),
-		lgc:           logics.NewLogics(s.Engine, header, s.Cache, s.EsbServ, &s.procHostInstConfig),
+		lgc:           logics.NewLogics(s.Engine, header, s.Cache, s.EsbServ, s.ProcControl, &s.procHostInstConfig),
 	}
 }
 
@@ -106,6 +115,8 @@ func (ps *ProcServer) WebService() *restful.WebService {
 	ws.Route(ws.POST("/template/version/search/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.SearchTemplateVersion))
 	ws.Route(ws.POST("/template/version/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.CreateTemplateVersion))
 	ws.Route(ws.PUT("/template/vesrion/{bk_supplier_account}/{bk_biz_id}/{template_id}/{version_id}").To(ps.UpdateTemplateVersion))
+	ws.Route(ws.PUT("/template/version/status/{bk_supplier_account}/{bk_biz_id}/{template_id}/{version_id}").To(ps.UpdateTemplateVersionStatus))
+	ws.Route(ws.GET("/template/version/diff/{bk_supplier_account}/{bk_biz_id}/{template_id}/{version_id}").To(ps.GetTemplateVersionDiff))
 	ws.Route(ws.GET("/template/proc/{bk_supplier_account}/{bk_biz_id}/{bk_process_id}").To(ps.GetProcBindTemplate))
 	ws.Route(ws.PUT("/template/proc/{bk_supplier_account}/{bk_biz_id}/{bk_process_id}/{template_id}").To(ps.BindProc2Template))
 	ws.Route(ws.DELETE("/template/proc/{bk_supplier_account}/{bk_biz_id}/{bk_process_id}/{template_id}").To(ps.DeleteProc2Template))
@@ -113,6 +124,7 @@ func (ps *ProcServer) WebService() *restful.WebService {
 	ws.Route(ws.POST("/template/create/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.CreateCfg))
 	ws.Route(ws.POST("/template/push/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.PushCfg))
 	ws.Route(ws.POST("/template/getremote/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.GetRemoteCfg))
+	ws.Route(ws.POST("/template/diff/{bk_supplier_account}/{bk_biz_id}/{template_id}").To(ps.DiffCfg))
 	ws.Route(ws.GET("/template/group/{bk_supplier_account}/{bk_biz_id}").To(ps.GetTemplateGroup))
 
 	//v2
@@ -204,8 +216,16 @@ func (ps *ProcServer) OnProcessConfigUpdate(previous, current cfnc.ProcessConfig
 	}
 
 	cfg := ccRedis.ParseConfigFromKV("redis", current.ConfigMap)
+	procControlPrefix := "proc control."
+	procControlConfig := make(map[string]string)
+	for key, val := range current.ConfigMap {
+		if strings.HasPrefix(key, procControlPrefix) {
+			procControlConfig[strings.TrimPrefix(key, procControlPrefix)] = val
+		}
+	}
 	ps.Config = &options.Config{
-		Redis: &cfg,
+		Redis:       &cfg,
+		ProcControl: procControlConfig,
 	}
 
 	hostInstPrefix := "host instance"
@@ -229,4 +249,36 @@ func (ps *ProcServer) OnProcessConfigUpdate(previous, current cfnc.ProcessConfig
 		}
 	}
 
+	if val, ok := current.ConfigMap["template version.approverGroups"]; ok {
+		groups := make([]string, 0)
+		for _, group := range strings.FieldsFunc(val, func(r rune) bool { return ',' == r || ';' == r }) {
+			if group = strings.TrimSpace(group); "" != group {
+				groups = append(groups, group)
+			}
+		}
+		ps.templateVersionConfig.ApproverGroups = groups
+	}
+
+	deliveryPrefix := "config delivery."
+	deliveryConfig := make(map[string]string)
+	for key, val := range current.ConfigMap {
+		if strings.HasPrefix(key, deliveryPrefix) {
+			deliveryConfig[strings.TrimPrefix(key, deliveryPrefix)] = val
+		}
+	}
+	// the config files are only delivered by the driver configured explicitly,
+	// pushing the config files is not supported without it.
+	driver := deliveryConfig["driver"]
+	if "" == driver {
+		blog.Warnf("config delivery driver is not configured, pushing process config files is not supported")
+		ps.setConfigDelivery(nil)
+		return
+	}
+	delivery, err := logics.NewConfigDelivery(driver, deliveryConfig)
+	if nil != err {
+		blog.Errorf("init config delivery %s failed, err: %v", driver, err)
+		return
+	}
+	ps.setConfigDelivery(delivery)
+
 }
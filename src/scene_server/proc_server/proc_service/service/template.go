@@ -29,7 +29,7 @@ import (
 )
 
 func (ps *ProcServer) CreateTemplate(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	user := srvData.user
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -104,7 +104,7 @@ func (ps *ProcServer) CreateTemplate(req *restful.Request, resp *restful.Respons
 }
 
 func (ps *ProcServer) DeleteTemplate(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	user := srvData.user
 	var logContent auditoplog.AuditLogExt
@@ -170,7 +170,7 @@ func (ps *ProcServer) DeleteTemplate(req *restful.Request, resp *restful.Respons
 }
 
 func (ps *ProcServer) UpdateTemplate(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	user := srvData.user
 	var logContent auditoplog.AuditLogExt
@@ -259,7 +259,7 @@ func (ps *ProcServer) UpdateTemplate(req *restful.Request, resp *restful.Respons
 }
 
 func (ps *ProcServer) SearchTemplate(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	var params meta.SearchParams
@@ -309,7 +309,7 @@ func (ps *ProcServer) SearchTemplate(req *restful.Request, resp *restful.Respons
 }
 
 func (ps *ProcServer) GetTemplateGroup(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	appIDStr := req.PathParameter(common.BKAppIDField)
@@ -13,14 +13,18 @@
 package service
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
 	"configcenter/src/common/blog"
 	types "configcenter/src/common/mapstr"
 	meta "configcenter/src/common/metadata"
+	"configcenter/src/scene_server/proc_server/logics"
 
 	"github.com/emicklei/go-restful"
 	"github.com/flosch/pongo2"
@@ -28,7 +32,7 @@ import (
 )
 
 func (ps *ProcServer) PreviewCfg(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	appIDStr := req.PathParameter(common.BKAppIDField)
@@ -91,19 +95,125 @@ func (ps *ProcServer) PreviewCfg(req *restful.Request, resp *restful.Response) {
 	resp.WriteEntity(meta.NewSuccessResp(result))
 }
 
+// CreateCfg render the config file of the template for the process instances
 func (ps *ProcServer) CreateCfg(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 
+	_, files, ok := ps.renderCfgFiles(srvData, req, resp)
+	if !ok {
+		return
+	}
+	resp.WriteEntity(meta.NewSuccessResp(files))
 }
 
+// PushCfg render the config file of the template and push it to the hosts of the process instances
 func (ps *ProcServer) PushCfg(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
+
+	delivery, ok := ps.getConfigDelivery(srvData, resp)
+	if !ok {
+		return
+	}
+	appIDStr, files, ok := ps.renderCfgFiles(srvData, req, resp)
+	if !ok {
+		return
+	}
+	results := srvData.lgc.PushProcConfigs(srvData.ctx, delivery, files)
+
+	// save operation log
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		log := common.KvMap{
+			common.BKOpDescField: fmt.Sprintf("push config file [%s] of template [%d] version [%d] to host [%s]", result.Path, result.TemplateID, result.VersionID, result.InnerIP),
+			common.BKOpTypeField: auditoplog.AuditOpTypeModify,
+		}
+		ps.CoreAPI.AuditController().AddProcLog(srvData.ctx, srvData.ownerID, appIDStr, srvData.user, srvData.header, log)
+	}
 
+	resp.WriteEntity(meta.NewSuccessResp(results))
 }
 
+// GetRemoteCfg fetch the config file of the template deployed on the hosts of the process instances
 func (ps *ProcServer) GetRemoteCfg(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 
+	delivery, ok := ps.getConfigDelivery(srvData, resp)
+	if !ok {
+		return
+	}
+	_, files, ok := ps.renderCfgFiles(srvData, req, resp)
+	if !ok {
+		return
+	}
+	resp.WriteEntity(meta.NewSuccessResp(srvData.lgc.GetRemoteProcConfigs(srvData.ctx, delivery, files)))
 }
 
-// If it is necessary
+// DiffCfg compare the deployed config file with the rendered one in unified diff format
 func (ps *ProcServer) DiffCfg(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
+
+	delivery, ok := ps.getConfigDelivery(srvData, resp)
+	if !ok {
+		return
+	}
+	_, files, ok := ps.renderCfgFiles(srvData, req, resp)
+	if !ok {
+		return
+	}
+	resp.WriteEntity(meta.NewSuccessResp(srvData.lgc.DiffProcConfigs(srvData.ctx, delivery, files)))
+}
 
+func (ps *ProcServer) getConfigDelivery(srvData *srvComm, resp *restful.Response) (logics.ConfigDelivery, bool) {
+	ps.configDeliveryLock.RLock()
+	delivery := ps.configDelivery
+	ps.configDeliveryLock.RUnlock()
+	if nil == delivery {
+		blog.Errorf("config delivery is not configured,rid:%s", srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Errorf(common.CCErrProcConfigDeliveryNotSupported, "")})
+		return nil, false
+	}
+	return delivery, true
+}
+
+func (ps *ProcServer) setConfigDelivery(delivery logics.ConfigDelivery) {
+	ps.configDeliveryLock.Lock()
+	ps.configDelivery = delivery
+	ps.configDeliveryLock.Unlock()
+}
+
+// renderCfgFiles parse the request and render the config files, the response is written when it fails
+func (ps *ProcServer) renderCfgFiles(srvData *srvComm, req *restful.Request, resp *restful.Response) (string, []meta.ProcConfigFile, bool) {
+	defErr := srvData.ccErr
+
+	pathParams := req.PathParameters()
+	appIDStr := pathParams[common.BKAppIDField]
+	appID, err := strconv.ParseInt(appIDStr, 10, 64)
+	if nil != err {
+		blog.Errorf("params error :%v,input:%+v,rid:%s", err, pathParams, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommHTTPInputInvalid)})
+		return "", nil, false
+	}
+	templateID, err := strconv.ParseInt(pathParams[common.BKTemlateIDField], 10, 64)
+	if nil != err {
+		blog.Errorf("params error :%v,input:%+v,rid:%s", err, pathParams, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommHTTPInputInvalid)})
+		return "", nil, false
+	}
+
+	filter := new(meta.ProcConfigFilter)
+	if err := json.NewDecoder(req.Request.Body).Decode(filter); nil != err && io.EOF != err {
+		blog.Errorf("decode request body err: %v,rid:%s", err, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return "", nil, false
+	}
+
+	files, err := srvData.lgc.RenderProcConfigs(srvData.ctx, appID, templateID, filter)
+	if nil != err {
+		blog.Errorf("render config files of template %d failed, err: %v,input:%+v,rid:%s", templateID, err, filter, srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: err})
+		return "", nil, false
+	}
+	return appIDStr, files, true
 }
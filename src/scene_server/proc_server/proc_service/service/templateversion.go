@@ -13,14 +13,17 @@
 package service
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
 	"configcenter/src/common/blog"
 	types "configcenter/src/common/mapstr"
 	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 	"configcenter/src/scene_server/validator"
 
 	"github.com/emicklei/go-restful"
@@ -28,7 +31,7 @@ import (
 )
 
 func (ps *ProcServer) SearchTemplateVersion(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 
 	ownerID := req.PathParameter(common.BKOwnerIDField)
@@ -80,7 +83,7 @@ func (ps *ProcServer) SearchTemplateVersion(req *restful.Request, resp *restful.
 }
 
 func (ps *ProcServer) CreateTemplateVersion(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 	defErr := srvData.ccErr
 	user := srvData.user
 
@@ -109,6 +112,14 @@ func (ps *ProcServer) CreateTemplateVersion(req *restful.Request, resp *restful.
 		return
 	}
 
+	// the version always begins with draft, the status is changed by the workflow
+	if "" != params.Status && common.TemplateStatusDraft != params.Status {
+		blog.Errorf("create config version failed! the status %s is not draft,rid:%s", params.Status, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrProcTemplateVersionStatusError, "", params.Status)})
+		return
+	}
+	params.Status = common.TemplateStatusDraft
+
 	input := types.MapStr{common.BKAppIDField: appID,
 		common.BKOperatorField:    user,
 		common.BKTemlateIDField:   templateID,
@@ -135,36 +146,13 @@ func (ps *ProcServer) CreateTemplateVersion(req *restful.Request, resp *restful.
 		return
 	}
 
-	versionID := ret.Data.Created.ID
-
-	//only one online status
-	if params.Status == common.TemplateStatusOnline {
-		data := types.MapStr{common.BKStatusField: common.TemplateStatusHistory}
-		condition := types.MapStr{
-			common.BKTemlateIDField: types.MapStr{common.BKDBEQ: versionID},
-			common.BKOwnerIDField:   ownerID,
-			common.BKStatusField:    common.TemplateStatusOnline}
-		input := &meta.UpdateOption{Condition: condition, Data: data}
-		ret, err := ps.CoreAPI.CoreService().Instance().UpdateInstance(srvData.ctx, srvData.header, common.BKInnerObjIDTempVersion, input)
-		if err != nil {
-			blog.Errorf("CreateTemplateVersion UpdateObject http do error,err:%s,input:%+v,query:%+v,rid:%s", err.Error(), params, input, srvData.rid)
-			resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
-		}
-		if !ret.Result {
-			blog.Errorf("CreateTemplateVersion  UpdateObject http response error,err code:%d,err msg:%s,input:%+v,query:%+v,rid:%s", ret.Code, ret.ErrMsg, params, input, srvData.rid)
-			resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.New(ret.Code, ret.ErrMsg)})
-			return
-		}
-	}
-
 	resp.WriteEntity(meta.NewSuccessResp(nil))
 }
 
 func (ps *ProcServer) UpdateTemplateVersion(req *restful.Request, resp *restful.Response) {
-	srvData := ps.newSrvComm(req.Request.Header)
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
 
 	defErr := srvData.ccErr
-	ownerID := srvData.ownerID
 
 	appIDStr := req.PathParameter(common.BKAppIDField)
 	appID, err := strconv.ParseInt(appIDStr, 10, 64)
@@ -196,11 +184,34 @@ func (ps *ProcServer) UpdateTemplateVersion(req *restful.Request, resp *restful.
 		return
 	}
 
+	// only the draft version can be edited, the status is changed by the workflow
+	version, err := srvData.lgc.GetConfigTemplateVersion(srvData.ctx, appID, templateID, versionID)
+	if nil != err {
+		blog.Errorf("update config version failed! get version %d err: %v,rid:%s", versionID, err, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+		return
+	}
+	status := util.GetStrByInterface(version[common.BKStatusField])
+	if "" != params.Status && status != params.Status {
+		blog.Errorf("update config version failed! the status can not be changed from %s to %s,rid:%s", status, params.Status, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrProcTemplateVersionStatusError, status, params.Status)})
+		return
+	}
+	if common.TemplateStatusDraft != status {
+		blog.Errorf("update config version failed! the version %d is %s,rid:%s", versionID, status, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrProcTemplateVersionNotEditable, status)})
+		return
+	}
+
 	condition := types.MapStr{
 		common.BKAppIDField:     appID,
 		common.BKTemlateIDField: templateID,
 		common.BKVersionIDField: versionID}
-	data := types.NewFromStruct(params, "field")
+	data := types.MapStr{
+		common.BKContentField:     params.Content,
+		common.BKDescriptionField: params.Description,
+		common.LastTimeField:      time.Now().UTC(),
+	}
 
 	input := &meta.UpdateOption{Condition: condition, Data: data}
 
@@ -208,6 +219,7 @@ func (ps *ProcServer) UpdateTemplateVersion(req *restful.Request, resp *restful.
 	if err != nil {
 		blog.Errorf("UpdateTemplateVersion UpdateObject http do error,err:%s,input:%+v,query:%+v,rid:%s", err.Error(), params, input, srvData.rid)
 		resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
+		return
 	}
 	if !ret.Result {
 		blog.Errorf("UpdateTemplateVersion  UpdateObject http response error,err code:%d,err msg:%s,input:%+v,query:%+v,rid:%s", ret.Code, ret.ErrMsg, params, input, srvData.rid)
@@ -215,27 +227,88 @@ func (ps *ProcServer) UpdateTemplateVersion(req *restful.Request, resp *restful.
 		return
 	}
 
-	//only one online status
-	if params.Status == common.TemplateStatusOnline {
-		data := types.MapStr{common.BKStatusField: common.TemplateStatusHistory}
-		condition := types.MapStr{
-			common.BKTemlateIDField: templateID,
-			common.BKVersionIDField: types.MapStr{common.BKDBNE: versionID},
-			common.BKOwnerIDField:   ownerID,
-			common.BKStatusField:    common.TemplateStatusOnline}
+	resp.WriteEntity(meta.NewSuccessResp(nil))
+}
 
-		input := &meta.UpdateOption{Condition: condition, Data: data}
-		ret, err := ps.CoreAPI.CoreService().Instance().UpdateInstance(srvData.ctx, srvData.header, common.BKInnerObjIDTempVersion, input)
-		if err != nil {
-			blog.Errorf("UpdateTemplateVersion UpdateObject http do error,err:%s,input:%+v,query:%+v,rid:%s", err.Error(), params, input, srvData.rid)
-			resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)})
-		}
-		if !ret.Result {
-			blog.Errorf("UpdateTemplateVersion  UpdateObject http response error,err code:%d,err msg:%s,input:%+v,query:%+v,rid:%s", ret.Code, ret.ErrMsg, params, input, srvData.rid)
-			resp.WriteError(http.StatusInternalServerError, &meta.RespError{Msg: srvData.ccErr.New(ret.Code, ret.ErrMsg)})
-			return
+// UpdateTemplateVersionStatus move the template version along the workflow draft -> review -> approved -> online -> archived
+func (ps *ProcServer) UpdateTemplateVersionStatus(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
+	defErr := srvData.ccErr
+
+	appIDStr := req.PathParameter(common.BKAppIDField)
+	appID, templateID, versionID, err := parseTemplateVersionPath(req)
+	if nil != err {
+		blog.Errorf("update template version status failed! derr: %v,input:%+v,rid:%s", err, req.PathParameters(), srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommHTTPInputInvalid)})
+		return
+	}
+
+	params := new(meta.TemplateVersionStatusParams)
+	if err := json.NewDecoder(req.Request.Body).Decode(params); err != nil {
+		blog.Errorf("update template version status failed! decode request body err: %v,rid:%s", err, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+
+	config := ps.templateVersionConfig
+	result, err := srvData.lgc.TransitTemplateVersion(srvData.ctx, srvData.ownerID, appID, templateID, versionID, params, &config)
+	if nil != err {
+		blog.Errorf("update template version status failed! err: %v,input:%+v,rid:%s", err, params, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+		return
+	}
+
+	// save operation log
+	for _, archivedID := range result.ArchivedID {
+		log := types.MapStr{
+			common.BKOpDescField: fmt.Sprintf("change the status of template [%d] version [%d] from [%s] to [%s]", templateID, archivedID, common.TemplateStatusOnline, common.TemplateStatusArchived),
+			common.BKOpTypeField: auditoplog.AuditOpTypeModify,
 		}
+		ps.CoreAPI.AuditController().AddProcLog(srvData.ctx, srvData.ownerID, appIDStr, srvData.user, srvData.header, log)
 	}
+	log := types.MapStr{
+		common.BKOpDescField: fmt.Sprintf("change the status of template [%d] version [%d] from [%s] to [%s]", templateID, versionID, result.PreStatus, result.Status),
+		common.BKOpTypeField: auditoplog.AuditOpTypeModify,
+		common.BKContentField: meta.Content{
+			PreData: types.MapStr{common.BKStatusField: result.PreStatus},
+			CurData: types.MapStr{common.BKStatusField: result.Status, "comment": result.Comment},
+		},
+	}
+	ps.CoreAPI.AuditController().AddProcLog(srvData.ctx, srvData.ownerID, appIDStr, srvData.user, srvData.header, log)
 
-	resp.WriteEntity(meta.NewSuccessResp(nil))
+	resp.WriteEntity(meta.NewSuccessResp(result))
+}
+
+// GetTemplateVersionDiff compare the template version with the online version of the template
+func (ps *ProcServer) GetTemplateVersionDiff(req *restful.Request, resp *restful.Response) {
+	srvData := ps.newSrvComm(req.Request.Context(), req.Request.Header)
+	defErr := srvData.ccErr
+
+	appID, templateID, versionID, err := parseTemplateVersionPath(req)
+	if nil != err {
+		blog.Errorf("get template version diff failed! derr: %v,input:%+v,rid:%s", err, req.PathParameters(), srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommHTTPInputInvalid)})
+		return
+	}
+
+	result, err := srvData.lgc.GetTemplateVersionDiff(srvData.ctx, appID, templateID, versionID)
+	if nil != err {
+		blog.Errorf("get template version diff failed! err: %v,rid:%s", err, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: err})
+		return
+	}
+	resp.WriteEntity(meta.NewSuccessResp(result))
+}
+
+func parseTemplateVersionPath(req *restful.Request) (appID, templateID, versionID int64, err error) {
+	appID, err = strconv.ParseInt(req.PathParameter(common.BKAppIDField), 10, 64)
+	if nil != err {
+		return
+	}
+	templateID, err = strconv.ParseInt(req.PathParameter(common.BKTemlateIDField), 10, 64)
+	if nil != err {
+		return
+	}
+	versionID, err = strconv.ParseInt(req.PathParameter(common.BKVersionIDField), 10, 64)
+	return
 }
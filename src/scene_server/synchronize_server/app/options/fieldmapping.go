@@ -0,0 +1,165 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package options
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldMappingAllObject the field mapping applied to all the objects
+const FieldMappingAllObject = "*"
+
+// FieldMapping the transformation of the instance fields before pushing to the target
+type FieldMapping struct {
+	// Rename map[source field]target field
+	Rename map[string]string
+	// ValueMap map[source field]map[source value]target value, such as the enum fields
+	ValueMap map[string]map[string]string
+	// Drop the fields not synchronized
+	Drop []string
+	// Constant map[field]value, the fields injected to every instance
+	Constant map[string]interface{}
+}
+
+func newFieldMapping() *FieldMapping {
+	return &FieldMapping{
+		Rename:   make(map[string]string),
+		ValueMap: make(map[string]map[string]string),
+		Drop:     make([]string, 0),
+		Constant: make(map[string]interface{}),
+	}
+}
+
+// GetFieldMapping get the field mappings of the object, the mapping of all objects is in front
+func (c *ConfigItem) GetFieldMapping(objID string) []*FieldMapping {
+	mappings := make([]*FieldMapping, 0)
+	if mapping, ok := c.FieldMapping[FieldMappingAllObject]; ok {
+		mappings = append(mappings, mapping)
+	}
+	if mapping, ok := c.FieldMapping[objID]; ok && objID != FieldMappingAllObject {
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+// ParseFieldMapping parse the field mapping config, the field is objID.field, objID * means all objects.
+// rename: host.bk_os_name:os_name,set.bk_set_desc:description
+// valueMap: host.bk_os_type:1=linux|2=windows
+// drop: host.bk_comment,*.operator
+// constant: host.bk_source:cmdb,host.bk_cloud_id:0,host.bk_sn:"0001", the number and bool values are typed, quote them to be strings
+func ParseFieldMapping(rename, valueMap, drop, constant string) (map[string]*FieldMapping, []error) {
+	mappings := make(map[string]*FieldMapping)
+	errs := make([]error, 0)
+	getMapping := func(objID string) *FieldMapping {
+		if _, ok := mappings[objID]; !ok {
+			mappings[objID] = newFieldMapping()
+		}
+		return mappings[objID]
+	}
+
+	for _, item := range splitFieldMappingConfig(rename) {
+		objID, field, target, err := parseFieldMappingItem(item)
+		if err != nil || target == "" {
+			errs = append(errs, fmt.Errorf("field rename %s invalid", item))
+			continue
+		}
+		getMapping(objID).Rename[field] = target
+	}
+
+	for _, item := range splitFieldMappingConfig(valueMap) {
+		objID, field, values, err := parseFieldMappingItem(item)
+		if err != nil || values == "" {
+			errs = append(errs, fmt.Errorf("field value map %s invalid", item))
+			continue
+		}
+		// the value map is dropped entirely if any value is invalid, a partial map changes the values unexpectedly
+		fieldValueMap := make(map[string]string)
+		valid := true
+		for _, value := range strings.Split(values, "|") {
+			parts := strings.SplitN(value, "=", 2)
+			if len(parts) != 2 {
+				valid = false
+				break
+			}
+			fieldValueMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+		if !valid {
+			errs = append(errs, fmt.Errorf("field value map %s invalid", item))
+			continue
+		}
+		getMapping(objID).ValueMap[field] = fieldValueMap
+	}
+
+	for _, item := range splitFieldMappingConfig(drop) {
+		parts := strings.SplitN(item, ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("field drop %s invalid", item))
+			continue
+		}
+		mapping := getMapping(parts[0])
+		mapping.Drop = append(mapping.Drop, parts[1])
+	}
+
+	for _, item := range splitFieldMappingConfig(constant) {
+		objID, field, value, err := parseFieldMappingItem(item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field constant %s invalid", item))
+			continue
+		}
+		getMapping(objID).Constant[field] = parseConstantValue(value)
+	}
+
+	return mappings, errs
+}
+
+func splitFieldMappingConfig(config string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(config, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseFieldMappingItem parse objID.field:value
+func parseFieldMappingItem(item string) (objID, field, value string, err error) {
+	parts := strings.SplitN(item, ":", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf("%s not objID.field:value", item)
+	}
+	keys := strings.SplitN(strings.TrimSpace(parts[0]), ".", 2)
+	if len(keys) != 2 || keys[0] == "" || keys[1] == "" {
+		return "", "", "", fmt.Errorf("%s not objID.field:value", item)
+	}
+	return keys[0], keys[1], strings.TrimSpace(parts[1]), nil
+}
+
+// parseConstantValue parse the constant as the int, float or bool value, the quoted value is always the string
+func parseConstantValue(value string) interface{} {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	if val, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return val
+	}
+	if val, err := strconv.ParseFloat(value, 64); err == nil {
+		return val
+	}
+	if val, err := strconv.ParseBool(value); err == nil {
+		return val
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldMapping(t *testing.T) {
+	mappings, errs := ParseFieldMapping(
+		"host.bk_os_name:os_name, *.bk_comment:comment, host:bad",
+		"host.bk_os_type:1=linux|2=windows,host.bk_state:1=on|bad",
+		"host.operator,*.bk_bak_operator,bad",
+		`host.bk_source:cmdb,host.bk_cloud_id:0,host.bk_sn:"0001",host.bk_enabled:true`,
+	)
+	if len(errs) != 3 {
+		t.Errorf("expect 3 errors, got %v", errs)
+	}
+
+	host := mappings["host"]
+	if host == nil || host.Rename["bk_os_name"] != "os_name" || host.Constant["bk_source"] != "cmdb" ||
+		host.Constant["bk_cloud_id"] != int64(0) || host.Constant["bk_sn"] != "0001" || host.Constant["bk_enabled"] != true ||
+		!reflect.DeepEqual(host.Drop, []string{"operator"}) ||
+		!reflect.DeepEqual(host.ValueMap["bk_os_type"], map[string]string{"1": "linux", "2": "windows"}) {
+		t.Errorf("unexpected host field mapping %#v", host)
+	}
+	if _, ok := host.ValueMap["bk_state"]; ok {
+		t.Errorf("the invalid value map should be dropped, got %#v", host.ValueMap)
+	}
+
+	item := &ConfigItem{FieldMapping: mappings}
+	if hostMappings := item.GetFieldMapping("host"); len(hostMappings) != 2 || hostMappings[0] != mappings[FieldMappingAllObject] {
+		t.Errorf("expect the mapping of all objects in front, got %#v", hostMappings)
+	}
+	if setMappings := item.GetFieldMapping("set"); len(setMappings) != 1 || setMappings[0].Rename["bk_comment"] != "comment" {
+		t.Errorf("unexpected set field mapping %#v", setMappings)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/metadata"
 )
 
 //ServerOption define option of server in flags
@@ -97,4 +98,29 @@ type ConfigItem struct {
 
 	// Retry error max retry count
 	ExceptionFileCount int
+
+	// Incremental only synchronize the instances changed since the last synchronization
+	Incremental bool
+	// FullSyncInterval the minutes between two full synchronizations when Incremental is true,
+	// the full synchronization removes the data deleted from the source
+	FullSyncInterval int64
+
+	// ConflictPolicy the policy of the instance edited on both sides, default source
+	ConflictPolicy metadata.SynchronizeConflictPolicy
+	// ObjectConflictPolicy the policy of the special object, overwrite ConflictPolicy
+	ObjectConflictPolicy map[string]metadata.SynchronizeConflictPolicy
+
+	// FieldMapping map[objID]field mapping, transform the instances before pushing to the target
+	FieldMapping map[string]*FieldMapping
+}
+
+// GetConflictPolicy get the conflict policy of the object
+func (c *ConfigItem) GetConflictPolicy(objID string) metadata.SynchronizeConflictPolicy {
+	if policy, ok := c.ObjectConflictPolicy[objID]; ok {
+		return policy
+	}
+	if c.ConflictPolicy == "" {
+		return metadata.SynchronizeConflictPolicySource
+	}
+	return c.ConflictPolicy
 }
@@ -16,6 +16,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,7 +25,8 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
-	//"configcenter/src/common/blog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/common/version"
 	"configcenter/src/scene_server/synchronize_server/app/options"
@@ -112,6 +114,15 @@ func (s *SynchronizeServer) onSynchronizeServerConfigUpdate(previous, current cc
 		supplerAccount := current.ConfigMap[name+".SupplerAccount"]
 		witeList := current.ConfigMap[name+".WiteList"]
 		objectIDs := current.ConfigMap[name+".ObjectID"]
+		incremental := current.ConfigMap[name+".Incremental"]
+		fullSyncInterval := current.ConfigMap[name+".FullSyncInterval"]
+		conflictPolicy := current.ConfigMap[name+".ConflictPolicy"]
+		// eg: host:manual,set:target
+		objectConflictPolicy := current.ConfigMap[name+".ObjectConflictPolicy"]
+		fieldRename := current.ConfigMap[name+".FieldRename"]
+		fieldValueMap := current.ConfigMap[name+".FieldValueMap"]
+		fieldDrop := current.ConfigMap[name+".FieldDrop"]
+		fieldConstant := current.ConfigMap[name+".FieldConstant"]
 
 		configItem.AppNames = strings.Split(appNames, ",")
 		if syncResource == "1" {
@@ -120,6 +131,40 @@ func (s *SynchronizeServer) onSynchronizeServerConfigUpdate(previous, current cc
 		if witeList == "1" {
 			configItem.WiteList = true
 		}
+		if incremental == "1" {
+			configItem.Incremental = true
+		}
+		if fullSyncInterval != "" {
+			interval, err := strconv.ParseInt(fullSyncInterval, 10, 64)
+			if err != nil {
+				blog.Warnf("%s.FullSyncInterval %s not integer, err:%s", name, fullSyncInterval, err.Error())
+			}
+			configItem.FullSyncInterval = interval
+		}
+		if conflictPolicy != "" {
+			if !metadata.SynchronizeConflictPolicy(conflictPolicy).Valid() {
+				blog.Warnf("%s.ConflictPolicy %s invalid, use source", name, conflictPolicy)
+			} else {
+				configItem.ConflictPolicy = metadata.SynchronizeConflictPolicy(conflictPolicy)
+			}
+		}
+		configItem.ObjectConflictPolicy = make(map[string]metadata.SynchronizeConflictPolicy)
+		for _, item := range strings.Split(objectConflictPolicy, ",") {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			parts := strings.SplitN(item, ":", 2)
+			if len(parts) != 2 || !metadata.SynchronizeConflictPolicy(strings.TrimSpace(parts[1])).Valid() {
+				blog.Warnf("%s.ObjectConflictPolicy %s invalid, ignore it", name, item)
+				continue
+			}
+			configItem.ObjectConflictPolicy[strings.TrimSpace(parts[0])] = metadata.SynchronizeConflictPolicy(strings.TrimSpace(parts[1]))
+		}
+		fieldMapping, errs := options.ParseFieldMapping(fieldRename, fieldValueMap, fieldDrop, fieldConstant)
+		for _, err := range errs {
+			blog.Warnf("%s field mapping error, ignore it, err:%s", name, err.Error())
+		}
+		configItem.FieldMapping = fieldMapping
 		configItem.ObjectIDArr = strings.Split(objectIDs, ",")
 		configItem.Name = name
 		configItem.TargetHost = targetHost
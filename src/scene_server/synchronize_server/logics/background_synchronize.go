@@ -36,6 +36,8 @@ type synchronizeItemInterface interface {
 	synchronizeModelTask(ctx context.Context) ([]metadata.ExceptionResult, errors.CCError)
 	synchronizeAssociationTask(ctx context.Context) ([]metadata.ExceptionResult, errors.CCError)
 	synchronizeItemClearData(ctx context.Context) (map[string][]metadata.ExceptionResult, errors.CCError)
+	setChangedSince(changedSince int64)
+	setRun(run *synchronizeRun)
 }
 
 type synchronizeItem struct {
@@ -46,6 +48,10 @@ type synchronizeItem struct {
 	objIDMap map[string]bool
 	appIDArr []int64
 	version  int64
+	// changedSince only synchronize the instances changed since the unix time, zero means full synchronization
+	changedSince int64
+	// run record the synchronized data count
+	run *synchronizeRun
 }
 
 func (lgc *Logics) NewSynchronizeItem(version int64, syncConfig *options.ConfigItem) synchronizeItemInterface {
@@ -70,6 +76,14 @@ func (s *synchronizeItem) configPretreatment() {
 	return
 }
 
+func (s *synchronizeItem) setChangedSince(changedSince int64) {
+	s.changedSince = changedSince
+}
+
+func (s *synchronizeItem) setRun(run *synchronizeRun) {
+	s.run = run
+}
+
 func (s *synchronizeItem) synchronizeItemClearData(ctx context.Context) (map[string][]metadata.ExceptionResult, errors.CCError) {
 	errorInfoArr := make(map[string][]metadata.ExceptionResult)
 
@@ -125,6 +139,7 @@ func (s *synchronizeItem) synchronizeItemException(ctx context.Context, exceptio
 func (s *synchronizeItem) synchronizeInstanceTask(ctx context.Context) (errorInfoArr []metadata.ExceptionResult, err errors.CCError) {
 
 	inst := s.lgc.NewFetchInst(s.config, s.baseCondition)
+	inst.SetChangedSince(s.changedSince)
 	var partErrorInfoArr []metadata.ExceptionResult
 
 	// must first business.  get synchronize app information,
@@ -166,10 +181,14 @@ func (s *synchronizeItem) synchronizeInstance(ctx context.Context, objID string,
 	var errorInfoArr []metadata.ExceptionResult
 
 	for {
+		if ctx.Err() != nil {
+			return nil, s.lgc.ccErr.Error(common.CCErrSynchronizeCanceled)
+		}
 		info, err := inst.Fetch(ctx, objID, start, limit)
 		if err != nil {
 			return nil, err
 		}
+		s.run.addCount("instance", objID, int64(len(info.Info)))
 
 		input := &metadata.SynchronizeDataInfo{}
 		input.OperateDataType = metadata.SynchronizeOperateDataTypeInstance
@@ -207,6 +226,7 @@ func (s *synchronizeItem) sycnhronizePartInstance(ctx context.Context, input *me
 		DataClassify:    input.DataClassify,
 		Version:         input.Version,
 		SynchronizeFlag: input.SynchronizeFlag,
+		ConflictPolicy:  s.config.GetConflictPolicy(input.DataClassify),
 	}
 	if len(input.InfoArray) == 0 {
 		return errorInfoArr, nil
@@ -291,11 +311,14 @@ func (s *synchronizeItem) synchronizeModel(ctx context.Context, model *FetchMode
 	var errorInfoArr []metadata.ExceptionResult
 
 	for {
-
+		if ctx.Err() != nil {
+			return nil, s.lgc.ccErr.Error(common.CCErrSynchronizeCanceled)
+		}
 		info, err := model.Fetch(ctx, dataClassify, start, limit)
 		if err != nil {
 			return nil, err
 		}
+		s.run.addCount("model", dataClassify, int64(len(info.Info)))
 
 		input := &metadata.SynchronizeDataInfo{}
 		input.OperateDataType = metadata.SynchronizeOperateDataTypeModel
@@ -399,11 +422,14 @@ func (s *synchronizeItem) sycnhronizeAssociation(ctx context.Context, associatio
 	var errorInfoArr []metadata.ExceptionResult
 
 	for {
-
+		if ctx.Err() != nil {
+			return nil, s.lgc.ccErr.Error(common.CCErrSynchronizeCanceled)
+		}
 		info, err := association.Fetch(ctx, dataClassify, start, limit)
 		if err != nil {
 			return nil, err
 		}
+		s.run.addCount("association", dataClassify, int64(len(info.Info)))
 
 		input := &metadata.SynchronizeDataInfo{}
 		input.OperateDataType = metadata.SynchronizeOperateDataTypeAssociation
@@ -0,0 +1,89 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"configcenter/src/scene_server/synchronize_server/app/options"
+	"configcenter/src/scene_server/synchronize_server/logics/exception/file"
+)
+
+const (
+	// defaultFullSyncInterval the default minutes between two full synchronizations of the incremental config item
+	defaultFullSyncInterval int64 = 24 * 60
+	// checkpointOverlap the seconds fetched again before the checkpoint, tolerate the clock difference between the servers
+	checkpointOverlap int64 = 60
+)
+
+// synchronizeCheckpoint the progress of the synchronization of a config item
+type synchronizeCheckpoint struct {
+	Name string `json:"name"`
+	// LastSyncTime the start time of the last successful synchronization,
+	// the incremental synchronization fetches the instances changed since it
+	LastSyncTime int64 `json:"last_sync_time"`
+	// LastFullSyncTime the start time of the last successful full synchronization
+	LastFullSyncTime int64 `json:"last_full_sync_time"`
+}
+
+// checkpointFile the checkpoint is kept with the exception files, a new master without it begins with a full synchronization
+func checkpointFile(name string) string {
+	return filepath.Join(file.Dir, name+".checkpoint")
+}
+
+func loadSynchronizeCheckpoint(name string) *synchronizeCheckpoint {
+	checkpoint := &synchronizeCheckpoint{Name: name}
+	content, err := ioutil.ReadFile(checkpointFile(name))
+	if err != nil {
+		return checkpoint
+	}
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return &synchronizeCheckpoint{Name: name}
+	}
+	return checkpoint
+}
+
+func (c *synchronizeCheckpoint) save() error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp := checkpointFile(c.Name) + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, checkpointFile(c.Name))
+}
+
+// changedSince the time since which the changed instances are synchronized, zero means full synchronization
+func (c *synchronizeCheckpoint) changedSince(config *options.ConfigItem, now time.Time) int64 {
+	if !config.Incremental || 0 == c.LastSyncTime || 0 == c.LastFullSyncTime {
+		return 0
+	}
+	interval := config.FullSyncInterval
+	if interval <= 0 {
+		interval = defaultFullSyncInterval
+	}
+	if now.Unix()-c.LastFullSyncTime >= interval*60 {
+		return 0
+	}
+	since := c.LastSyncTime - checkpointOverlap
+	if since <= 0 {
+		return 0
+	}
+	return since
+}
@@ -0,0 +1,103 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/synchronize_server/app/options"
+	"configcenter/src/scene_server/synchronize_server/logics/exception/file"
+)
+
+func TestCheckpointChangedSince(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	config := &options.ConfigItem{Name: "test", Incremental: true, FullSyncInterval: 60}
+
+	checkpoint := &synchronizeCheckpoint{Name: "test"}
+	if since := checkpoint.changedSince(config, now); since != 0 {
+		t.Errorf("no checkpoint, expect full synchronization, got %d", since)
+	}
+
+	checkpoint.LastSyncTime = now.Unix() - 300
+	checkpoint.LastFullSyncTime = now.Unix() - 600
+	if since := checkpoint.changedSince(config, now); since != checkpoint.LastSyncTime-checkpointOverlap {
+		t.Errorf("expect incremental synchronization since %d, got %d", checkpoint.LastSyncTime-checkpointOverlap, since)
+	}
+
+	checkpoint.LastFullSyncTime = now.Unix() - 3600
+	if since := checkpoint.changedSince(config, now); since != 0 {
+		t.Errorf("full synchronization interval elapsed, expect full synchronization, got %d", since)
+	}
+
+	config.Incremental = false
+	checkpoint.LastFullSyncTime = now.Unix() - 600
+	if since := checkpoint.changedSince(config, now); since != 0 {
+		t.Errorf("not incremental, expect full synchronization, got %d", since)
+	}
+}
+
+func TestCheckpointSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldDir := file.Dir
+	file.Dir = dir
+	defer func() { file.Dir = oldDir }()
+
+	checkpoint := loadSynchronizeCheckpoint("test")
+	if checkpoint.LastSyncTime != 0 || checkpoint.LastFullSyncTime != 0 {
+		t.Fatalf("expect empty checkpoint, got %#v", checkpoint)
+	}
+	checkpoint.LastSyncTime = 200
+	checkpoint.LastFullSyncTime = 100
+	if err := checkpoint.save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := loadSynchronizeCheckpoint("test")
+	if *loaded != *checkpoint {
+		t.Errorf("expect %#v, got %#v", checkpoint, loaded)
+	}
+}
+
+func TestIncrementalTime(t *testing.T) {
+	// the source converts the time string in the condition decoded from json to the time
+	input := metadata.SynchronizeFindInfoParameter{
+		Condition: mapstr.MapStr{common.LastTimeField: mapstr.MapStr{common.BKDBGTE: incrementalTime(1000000)}},
+	}
+	content, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := metadata.SynchronizeFindInfoParameter{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	converted, ok := util.ConvParamsTime(map[string]interface{}(decoded.Condition)).(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected converted condition %#v", converted)
+	}
+	cond, _ := converted[common.LastTimeField].(map[string]interface{})
+	if since, ok := cond[common.BKDBGTE].(time.Time); !ok || !since.Equal(time.Unix(1000000, 0)) {
+		t.Errorf("expect the time %v, got %#v", time.Unix(1000000, 0), converted)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+// SearchConflict search the conflict queue of the synchronization in the target
+func (lgc *Logics) SearchConflict(ctx context.Context, input *metadata.SynchronizeConflictSearchParameter) (*metadata.SynchronizeConflictSearchResult, error) {
+	result, err := lgc.CoreAPI.CoreService().Synchronize().SearchSynchronizeConflict(ctx, lgc.header, input)
+	if err != nil {
+		blog.Errorf("SearchConflict http do error, err:%s,input:%#v,rid:%s", err.Error(), input, lgc.rid)
+		return nil, lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("SearchConflict http reply error, err code:%d,err msg:%s,input:%#v,rid:%s", result.Code, result.ErrMsg, input, lgc.rid)
+		return nil, lgc.ccErr.New(result.Code, result.ErrMsg)
+	}
+	return result, nil
+}
+
+// ResolveConflict resolve the pending conflict, keep the source or the target data
+func (lgc *Logics) ResolveConflict(ctx context.Context, id int64, input *metadata.SynchronizeConflictResolveParameter) error {
+	result, err := lgc.CoreAPI.CoreService().Synchronize().ResolveSynchronizeConflict(ctx, lgc.header, id, input)
+	if err != nil {
+		blog.Errorf("ResolveConflict http do error, err:%s,id:%d,input:%#v,rid:%s", err.Error(), id, input, lgc.rid)
+		return lgc.ccErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !result.Result {
+		blog.Errorf("ResolveConflict http reply error, err code:%d,err msg:%s,id:%d,input:%#v,rid:%s", result.Code, result.ErrMsg, id, input, lgc.rid)
+		return lgc.ccErr.New(result.Code, result.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/synchronize_server/app/options"
+)
+
+// isProtectedField the fields used by the synchronization can not be transformed
+func isProtectedField(objID, field string) bool {
+	switch field {
+	case common.GetInstIDField(objID), common.BKObjIDField, common.MetadataField, common.LastTimeField:
+		return true
+	}
+	return false
+}
+
+// applyFieldMapping transform the instance, drop the fields, map the values, rename the fields and inject the constants in order
+func applyFieldMapping(objID string, info mapstr.MapStr, mappings []*options.FieldMapping) {
+	for _, mapping := range mappings {
+		for _, field := range mapping.Drop {
+			if !isProtectedField(objID, field) {
+				info.Remove(field)
+			}
+		}
+		for field, valueMap := range mapping.ValueMap {
+			val, ok := info.Get(field)
+			if !ok || val == nil || isProtectedField(objID, field) {
+				continue
+			}
+			if target, ok := valueMap[fmt.Sprintf("%v", val)]; ok {
+				info.Set(field, target)
+			}
+		}
+		renamed := mapstr.New()
+		for field, target := range mapping.Rename {
+			val, ok := info.Get(field)
+			if !ok || isProtectedField(objID, field) || isProtectedField(objID, target) {
+				continue
+			}
+			info.Remove(field)
+			renamed.Set(target, val)
+		}
+		info.Merge(renamed)
+		for field, val := range mapping.Constant {
+			if !isProtectedField(objID, field) {
+				info.Set(field, val)
+			}
+		}
+	}
+}
+
+// applyAttributeFieldMapping transform the attribute definition as the instances, so that the synchronized
+// instances match the synchronized model. the attribute is not synchronized if false is returned.
+// the constant fields are not defined in the source model, they should be defined in the target model.
+func applyAttributeFieldMapping(attr mapstr.MapStr, mappings []*options.FieldMapping) bool {
+	objID, _ := attr.String(common.BKObjIDField)
+	propertyID, _ := attr.String(common.BKPropertyIDField)
+	if isProtectedField(objID, propertyID) {
+		return true
+	}
+	for _, mapping := range mappings {
+		if util.InStrArr(mapping.Drop, propertyID) {
+			return false
+		}
+		if valueMap, ok := mapping.ValueMap[propertyID]; ok {
+			if options, ok := attr[common.BKOptionField].([]interface{}); ok {
+				for _, option := range options {
+					item, ok := option.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					if target, ok := valueMap[fmt.Sprintf("%v", item["id"])]; ok {
+						item["id"] = target
+					}
+				}
+			}
+		}
+		if target, ok := mapping.Rename[propertyID]; ok && !isProtectedField(objID, target) {
+			propertyID = target
+			attr.Set(common.BKPropertyIDField, target)
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"reflect"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/scene_server/synchronize_server/app/options"
+)
+
+func TestApplyFieldMapping(t *testing.T) {
+	mappings := []*options.FieldMapping{
+		{
+			Rename:   map[string]string{"bk_os_type": "os_type", "bk_host_id": "host_id"},
+			ValueMap: map[string]map[string]string{"bk_os_type": {"1": "linux"}},
+			Drop:     []string{"operator", "bk_host_id"},
+			Constant: map[string]interface{}{"bk_source": "cmdb", "bk_cloud_id": int64(0)},
+		},
+	}
+	info := mapstr.MapStr{
+		"bk_host_id":      int64(1),
+		"bk_host_innerip": "127.0.0.1",
+		"bk_os_type":      "1",
+		"operator":        "admin",
+	}
+	applyFieldMapping("host", info, mappings)
+
+	expect := mapstr.MapStr{
+		"bk_host_id":      int64(1),
+		"bk_host_innerip": "127.0.0.1",
+		"os_type":         "linux",
+		"bk_source":       "cmdb",
+		"bk_cloud_id":     int64(0),
+	}
+	if !reflect.DeepEqual(info, expect) {
+		t.Errorf("expect %#v, got %#v", expect, info)
+	}
+}
+
+func TestApplyAttributeFieldMapping(t *testing.T) {
+	mappings := []*options.FieldMapping{
+		{
+			Rename:   map[string]string{"bk_os_type": "os_type", "bk_host_id": "host_id"},
+			ValueMap: map[string]map[string]string{"bk_os_type": {"1": "linux"}},
+			Drop:     []string{"operator", "bk_host_id"},
+		},
+	}
+
+	osType := mapstr.MapStr{
+		"bk_obj_id":      "host",
+		"bk_property_id": "bk_os_type",
+		"option":         []interface{}{map[string]interface{}{"id": "1", "name": "Linux"}, map[string]interface{}{"id": "2", "name": "Windows"}},
+	}
+	if !applyAttributeFieldMapping(osType, mappings) {
+		t.Fatalf("the renamed attribute should be synchronized")
+	}
+	expect := mapstr.MapStr{
+		"bk_obj_id":      "host",
+		"bk_property_id": "os_type",
+		"option":         []interface{}{map[string]interface{}{"id": "linux", "name": "Linux"}, map[string]interface{}{"id": "2", "name": "Windows"}},
+	}
+	if !reflect.DeepEqual(osType, expect) {
+		t.Errorf("expect %#v, got %#v", expect, osType)
+	}
+
+	if applyAttributeFieldMapping(mapstr.MapStr{"bk_obj_id": "host", "bk_property_id": "operator"}, mappings) {
+		t.Errorf("the dropped attribute should not be synchronized")
+	}
+	hostID := mapstr.MapStr{"bk_obj_id": "host", "bk_property_id": "bk_host_id"}
+	if !applyAttributeFieldMapping(hostID, mappings) || hostID["bk_property_id"] != "bk_host_id" {
+		t.Errorf("the protected attribute should not be changed, got %#v", hostID)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 )
 
 const (
@@ -55,6 +56,10 @@ func (lgc *Logics) find(ctx context.Context, input *metadata.SynchronizeFindInfo
 func (lgc *Logics) Find(ctx context.Context, input *metadata.SynchronizeFindInfoParameter) (*metadata.InstDataInfo, errors.CCError) {
 	switch input.DataType {
 	case metadata.SynchronizeOperateDataTypeInstance:
+		// the time in the condition is the string after json, such as the incremental synchronization condition
+		if convCond, ok := util.ConvParamsTime(map[string]interface{}(input.Condition)).(map[string]interface{}); ok {
+			input.Condition = convCond
+		}
 		return lgc.findInstance(ctx, input.DataClassify, SynchronizeFindInfoParameterToQuerycondition(input))
 	case metadata.SynchronizeOperateDataTypeAssociation:
 		return lgc.find(ctx, input)
@@ -64,5 +64,16 @@ func (fm *FetchModel) Fetch(ctx context.Context, dataClassify string, start, lim
 		blog.Errorf("Fetch http reply error. err code:%d,err msg:%s,rid:%s", result.Code, result.ErrMsg, fm.lgc.rid)
 		return nil, fm.lgc.ccErr.New(result.Code, result.ErrMsg)
 	}
+	if common.SynchronizeModelTypeAttribute == dataClassify {
+		// the attributes are transformed as the instances, the dropped ones are not synchronized
+		infos := make([]mapstr.MapStr, 0, len(result.Data.Info))
+		for _, info := range result.Data.Info {
+			objID, _ := info.String(common.BKObjIDField)
+			if applyAttributeFieldMapping(info, fm.syncConfig.GetFieldMapping(objID)) {
+				infos = append(infos, info)
+			}
+		}
+		result.Data.Info = infos
+	}
 	return &result.Data, nil
 }
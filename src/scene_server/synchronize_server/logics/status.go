@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/synchronize_server/logics/exception/file"
+)
+
+const (
+	// maxRunHistory the run records kept of every config item
+	maxRunHistory = 20
+	// maxRunExceptions the exceptions kept of every type in a run record
+	maxRunExceptions = 100
+
+	synchronizeTriggerTimer  = "timer"
+	synchronizeTriggerManual = "manual"
+)
+
+// synchronizeRun the running synchronization of a config item, the data count is recorded while synchronizing
+type synchronizeRun struct {
+	sync.RWMutex
+	name   string
+	record metadata.SynchronizeRunRecord
+	cancel context.CancelFunc
+}
+
+func (r *synchronizeRun) addCount(kind, dataClassify string, count int64) {
+	if r == nil || count == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.record.Counts[kind]; !ok {
+		r.record.Counts[kind] = make(map[string]int64)
+	}
+	r.record.Counts[kind][dataClassify] += count
+}
+
+func (r *synchronizeRun) snapshot() metadata.SynchronizeRunRecord {
+	r.RLock()
+	defer r.RUnlock()
+	record := r.record
+	record.Counts = make(map[string]map[string]int64, len(r.record.Counts))
+	for kind, counts := range r.record.Counts {
+		record.Counts[kind] = make(map[string]int64, len(counts))
+		for dataClassify, count := range counts {
+			record.Counts[kind][dataClassify] = count
+		}
+	}
+	return record
+}
+
+// synchronizeStatus the running synchronization and the run history of the config items,
+// the history is kept with the exception files on the local disk of the master, it is not shared with the other servers
+type synchronizeStatus struct {
+	sync.Mutex
+	running map[string]*synchronizeRun
+	history map[string][]metadata.SynchronizeRunRecord
+}
+
+var itemStatus = &synchronizeStatus{
+	running: make(map[string]*synchronizeRun),
+	history: make(map[string][]metadata.SynchronizeRunRecord),
+}
+
+func historyFile(name string) string {
+	return filepath.Join(file.Dir, name+".history")
+}
+
+// getHistory must be called with the lock
+func (s *synchronizeStatus) getHistory(name string) []metadata.SynchronizeRunRecord {
+	history, ok := s.history[name]
+	if ok {
+		return history
+	}
+	history = make([]metadata.SynchronizeRunRecord, 0)
+	if content, err := ioutil.ReadFile(historyFile(name)); err == nil {
+		if err := json.Unmarshal(content, &history); err != nil {
+			blog.Warnf("load synchronize history of %s error, err:%s", name, err.Error())
+			history = make([]metadata.SynchronizeRunRecord, 0)
+		}
+	}
+	s.history[name] = history
+	return history
+}
+
+// start register the synchronization of the config item, return nil when the config item is running
+func (s *synchronizeStatus) start(name string, version int64, trigger, operator string, cancel context.CancelFunc) *synchronizeRun {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.running[name]; ok {
+		return nil
+	}
+	run := &synchronizeRun{
+		name:   name,
+		cancel: cancel,
+		record: metadata.SynchronizeRunRecord{
+			Version:   version,
+			Trigger:   trigger,
+			Operator:  operator,
+			Status:    metadata.SynchronizeRunStatusRunning,
+			StartTime: time.Now(),
+			Counts:    make(map[string]map[string]int64),
+		},
+	}
+	s.running[name] = run
+	return run
+}
+
+// finish move the synchronization to the history
+func (s *synchronizeStatus) finish(run *synchronizeRun, status metadata.SynchronizeRunStatus, runErr error, exceptionMap map[string][]metadata.ExceptionResult) {
+	record := run.snapshot()
+	now := time.Now()
+	record.EndTime = &now
+	record.Status = status
+	if runErr != nil {
+		record.Error = runErr.Error()
+	}
+	record.ExceptionCounts = make(map[string]int64)
+	record.Exceptions = make(map[string][]metadata.ExceptionResult)
+	for exceptionType, exceptions := range exceptionMap {
+		if len(exceptions) == 0 {
+			continue
+		}
+		record.ExceptionCounts[exceptionType] = int64(len(exceptions))
+		if len(exceptions) > maxRunExceptions {
+			exceptions = exceptions[:maxRunExceptions]
+		}
+		record.Exceptions[exceptionType] = exceptions
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	delete(s.running, run.name)
+	history := append([]metadata.SynchronizeRunRecord{record}, s.getHistory(run.name)...)
+	if len(history) > maxRunHistory {
+		history = history[:maxRunHistory]
+	}
+	s.history[run.name] = history
+
+	content, err := json.Marshal(history)
+	if err != nil {
+		blog.Errorf("save synchronize history of %s error, err:%s", run.name, err.Error())
+		return
+	}
+	tmp := historyFile(run.name) + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		blog.Errorf("save synchronize history of %s error, err:%s", run.name, err.Error())
+		return
+	}
+	if err := os.Rename(tmp, historyFile(run.name)); err != nil {
+		blog.Errorf("save synchronize history of %s error, err:%s", run.name, err.Error())
+	}
+}
+
+// cancel cancel the running synchronization, return false when the config item is not running
+func (s *synchronizeStatus) cancel(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+	run, ok := s.running[name]
+	if !ok {
+		return false
+	}
+	run.cancel()
+	return true
+}
+
+func (s *synchronizeStatus) status(name string) metadata.SynchronizeItemStatus {
+	s.Lock()
+	defer s.Unlock()
+	history := s.getHistory(name)
+	status := metadata.SynchronizeItemStatus{
+		Name:    name,
+		State:   metadata.SynchronizeItemStateIdle,
+		History: make([]metadata.SynchronizeRunRecord, len(history)),
+	}
+	copy(status.History, history)
+	if run, ok := s.running[name]; ok {
+		current := run.snapshot()
+		status.State = metadata.SynchronizeItemStateRunning
+		status.Current = &current
+	} else if len(history) > 0 && history[0].Status == metadata.SynchronizeRunStatusFailed {
+		status.State = metadata.SynchronizeItemStateFailed
+	}
+	return status
+}
@@ -0,0 +1,75 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/synchronize_server/logics/exception/file"
+)
+
+func TestSynchronizeStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldDir := file.Dir
+	file.Dir = dir
+	defer func() { file.Dir = oldDir }()
+
+	status := &synchronizeStatus{
+		running: make(map[string]*synchronizeRun),
+		history: make(map[string][]metadata.SynchronizeRunRecord),
+	}
+	canceled := false
+	run := status.start("test", 100, synchronizeTriggerManual, "admin", func() { canceled = true })
+	if run == nil {
+		t.Fatal("start synchronization failed")
+	}
+	if status.start("test", 101, synchronizeTriggerTimer, "", func() {}) != nil {
+		t.Fatal("the running config item is started again")
+	}
+	run.addCount("instance", "host", 10)
+	run.addCount("instance", "host", 5)
+
+	current := status.status("test")
+	if current.State != metadata.SynchronizeItemStateRunning || current.Current == nil || current.Current.Counts["instance"]["host"] != 15 {
+		t.Fatalf("unexpected running status %#v", current)
+	}
+	if !status.cancel("test") || !canceled {
+		t.Fatal("cancel the running synchronization failed")
+	}
+
+	exceptions := map[string][]metadata.ExceptionResult{"instance": make([]metadata.ExceptionResult, maxRunExceptions+1)}
+	status.finish(run, metadata.SynchronizeRunStatusFailed, errors.New("failed"), exceptions)
+	if status.cancel("test") {
+		t.Fatal("cancel the finished synchronization")
+	}
+
+	// load the history from the file
+	status.history = make(map[string][]metadata.SynchronizeRunRecord)
+	current = status.status("test")
+	if current.State != metadata.SynchronizeItemStateFailed || current.Current != nil || len(current.History) != 1 {
+		t.Fatalf("unexpected finished status %#v", current)
+	}
+	record := current.History[0]
+	if record.Version != 100 || record.Error != "failed" || record.ExceptionCounts["instance"] != maxRunExceptions+1 ||
+		len(record.Exceptions["instance"]) != maxRunExceptions || record.Counts["instance"]["host"] != 15 {
+		t.Errorf("unexpected run record %#v", record)
+	}
+}
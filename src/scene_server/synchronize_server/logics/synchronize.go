@@ -16,7 +16,9 @@ import (
 	"context"
 	"time"
 
+	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	"configcenter/src/scene_server/synchronize_server/app/options"
@@ -89,36 +91,135 @@ func (lgc *Logics) Synchronize(ctx context.Context, config *options.Config) {
 
 // SynchronizeItem  synchronize data
 func (lgc *Logics) SynchronizeItem(ctx context.Context, syncConfig *options.ConfigItem) {
-	version := getVersion()
+	run, runCtx := lgc.startSynchronizeItem(ctx, syncConfig, synchronizeTriggerTimer)
+	if run == nil {
+		blog.Warnf("synchonrize config:%s is running, skip this time, rid:%s", syncConfig.Name, lgc.rid)
+		return
+	}
+	lgc.synchronizeItem(runCtx, syncConfig, run)
+}
+
+// TriggerSynchronizeItem synchronize the config item on demand, return the version of the synchronization
+func (lgc *Logics) TriggerSynchronizeItem(ctx context.Context, syncConfig *options.ConfigItem) (int64, errors.CCError) {
+	if !lgc.Engine.ServiceManageInterface.IsMaster() {
+		return 0, lgc.ccErr.Error(common.CCErrSynchronizeNotMaster)
+	}
+	run, runCtx := lgc.startSynchronizeItem(ctx, syncConfig, synchronizeTriggerManual)
+	if run == nil {
+		return 0, lgc.ccErr.Errorf(common.CCErrSynchronizeItemRunning, syncConfig.Name)
+	}
+	go lgc.synchronizeItem(runCtx, syncConfig, run)
+	return run.record.Version, nil
+}
+
+// CancelSynchronizeItem cancel the running synchronization of the config item
+func (lgc *Logics) CancelSynchronizeItem(ctx context.Context, name string) errors.CCError {
+	if !itemStatus.cancel(name) {
+		return lgc.ccErr.Errorf(common.CCErrSynchronizeItemNotRunning, name)
+	}
+	blog.Infof("cancel synchonrize config:%s, user:%s, rid:%s", name, lgc.user, lgc.rid)
+	return nil
+}
+
+// GetSynchronizeItemStatus get the state and the run history of the config item, the history is local to the server
+func (lgc *Logics) GetSynchronizeItemStatus(ctx context.Context, name string) metadata.SynchronizeItemStatus {
+	status := itemStatus.status(name)
+	status.IsMaster = lgc.Engine.ServiceManageInterface.IsMaster()
+	return status
+}
+
+func (lgc *Logics) startSynchronizeItem(ctx context.Context, syncConfig *options.ConfigItem, trigger string) (*synchronizeRun, context.Context) {
+	runCtx, cancel := context.WithCancel(ctx)
+	run := itemStatus.start(syncConfig.Name, getVersion(), trigger, lgc.user, cancel)
+	if run == nil {
+		cancel()
+		return nil, nil
+	}
+	return run, runCtx
+}
+
+func (lgc *Logics) synchronizeItem(ctx context.Context, syncConfig *options.ConfigItem, run *synchronizeRun) {
+	version := run.record.Version
 
 	blog.InfoJSON("start synchonrize config:%s, verison:%s", syncConfig, version)
 	// syncConfig can modify
 	synchronizeItem := lgc.NewSynchronizeItem(version, syncConfig)
+	synchronizeItem.setRun(run)
+
+	// incremental synchronization only ships the instances changed since the checkpoint,
+	// the full synchronization runs periodically to remove the data deleted from the source
+	checkpoint := loadSynchronizeCheckpoint(syncConfig.Name)
+	changedSince := checkpoint.changedSince(syncConfig, time.Unix(version, 0))
+	if changedSince > 0 && !lgc.supportIncrementalSynchronize(ctx, syncConfig) {
+		blog.Warnf("synchonrize config:%s, the source does not support incremental synchronization, synchronize all, rid:%s", syncConfig.Name, lgc.rid)
+		changedSince = 0
+	}
+	synchronizeItem.setChangedSince(changedSince)
+	run.Lock()
+	run.record.Incremental = changedSince > 0
+	run.Unlock()
+	blog.Infof("synchonrize config:%s, verison:%d, changed since:%d, rid:%s", syncConfig.Name, version, changedSince, lgc.rid)
 
 	exceptionMap := make(map[string][]metadata.ExceptionResult)
-	var err error
+	var err, runErr error
+	succ := true
 	exceptionMap["model"], err = synchronizeItem.synchronizeModelTask(ctx) //lgc.synchronizeModelTask(ctx, syncConfig, version, nil)
 	if err != nil {
+		succ = false
+		runErr = err
 		blog.Errorf("SynchronizeItem model error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
 	}
 
 	exceptionMap["instance"], err = synchronizeItem.synchronizeInstanceTask(ctx) //(ctx, syncConfig, version, nil)
 	if err != nil {
+		succ = false
+		runErr = err
 		blog.Errorf("SynchronizeItem instance error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
 	}
 
 	exceptionMap["association"], err = synchronizeItem.synchronizeAssociationTask(ctx) //(ctx, syncConfig, version, nil)
 	if err != nil {
+		succ = false
+		runErr = err
 		blog.Errorf("SynchronizeItem association error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
 	}
-	exceptionMapClear, err := synchronizeItem.synchronizeItemClearData(ctx)
-	if err != nil {
-		blog.Errorf("SynchronizeItem synchronizeItemClearData error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
+	// the unchanged data is not synchronized in incremental synchronization, it must not be cleared.
+	// the canceled synchronization does not synchronize all the data too.
+	if 0 == changedSince && nil == ctx.Err() {
+		exceptionMapClear, err := synchronizeItem.synchronizeItemClearData(ctx)
+		if err != nil {
+			blog.Errorf("SynchronizeItem synchronizeItemClearData error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
+		}
+		for key, val := range exceptionMapClear {
+			exceptionMap[key] = val
+		}
 	}
-	for key, val := range exceptionMapClear {
-		exceptionMap[key] = val
+	go synchronizeItem.synchronizeItemException(context.Background(), exceptionMap)
+
+	// the synchronization canceled after the last task does not return error, but it is not finished either
+	if nil != ctx.Err() {
+		succ = false
+	}
+	// the checkpoint is kept when the synchronization failed or any instance failed,
+	// so that the failed instances are synchronized again next time.
+	if succ && 0 == len(exceptionMap["instance"]) && syncConfig.Incremental {
+		checkpoint.LastSyncTime = version
+		if 0 == changedSince {
+			checkpoint.LastFullSyncTime = version
+		}
+		if err := checkpoint.save(); err != nil {
+			blog.Errorf("SynchronizeItem save checkpoint error, config:%#v,err:%s,version:%d,rid:%s", syncConfig, err.Error(), version, lgc.rid)
+		}
+	}
+
+	status := metadata.SynchronizeRunStatusSuccess
+	if nil != ctx.Err() {
+		status = metadata.SynchronizeRunStatusCanceled
+	} else if !succ {
+		status = metadata.SynchronizeRunStatusFailed
 	}
-	go synchronizeItem.synchronizeItemException(ctx, exceptionMap)
+	itemStatus.finish(run, status, runErr, exceptionMap)
+	run.cancel()
 
 	blog.InfoJSON("end synchonrize config:%s, verison:%s", syncConfig, version)
 
@@ -80,6 +80,12 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Path("/synchronize/{version}").Filter(rdapi.HTTPRequestIDFilter(getErrFunc)).Produces(restful.MIME_JSON)
 
 	ws.Route(ws.POST("/search").To(s.Find))
+	ws.Route(ws.POST("/conflict/search").To(s.SearchConflict))
+	ws.Route(ws.PUT("/conflict/resolve/{id}").To(s.ResolveConflict))
+	ws.Route(ws.GET("/status").To(s.ListSynchronizeStatus))
+	ws.Route(ws.GET("/status/{name}").To(s.GetSynchronizeStatus))
+	ws.Route(ws.POST("/trigger/{name}").To(s.TriggerSynchronize))
+	ws.Route(ws.POST("/cancel/{name}").To(s.CancelSynchronize))
 
 	return ws
 }
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/synchronize_server/app/options"
+)
+
+func (s *Service) getConfigItem(name string) *options.ConfigItem {
+	for _, item := range s.Config.ConifgItemArray {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// ListSynchronizeStatus get the state and the run history of all the config items,
+// the history is kept on the master which runs the synchronization, is_master tells whether it is the master
+func (s *Service) ListSynchronizeStatus(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+	statusArr := make([]metadata.SynchronizeItemStatus, 0)
+	for _, item := range s.Config.ConifgItemArray {
+		statusArr = append(statusArr, srvData.lgc.GetSynchronizeItemStatus(srvData.ctx, item.Name))
+	}
+	resp.WriteEntity(metadata.NewSuccessResp(statusArr))
+}
+
+// GetSynchronizeStatus get the state and the run history of the config item
+func (s *Service) GetSynchronizeStatus(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+	name := req.PathParameter("name")
+	if s.getConfigItem(name) == nil {
+		blog.Errorf("GetSynchronizeStatus, but config item %s not found,rid:%s", name, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrSynchronizeItemNotExist, name)})
+		return
+	}
+	resp.WriteEntity(metadata.NewSuccessResp(srvData.lgc.GetSynchronizeItemStatus(srvData.ctx, name)))
+}
+
+// TriggerSynchronize synchronize the config item on demand
+func (s *Service) TriggerSynchronize(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+	name := req.PathParameter("name")
+	item := s.getConfigItem(name)
+	if item == nil {
+		blog.Errorf("TriggerSynchronize, but config item %s not found,rid:%s", name, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrSynchronizeItemNotExist, name)})
+		return
+	}
+
+	version, err := srvData.lgc.TriggerSynchronizeItem(srvData.ctx, item)
+	if err != nil {
+		blog.Errorf("TriggerSynchronize %s error. error: %s,rid:%s", name, err.Error(), srvData.rid)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: err})
+		return
+	}
+	resp.WriteEntity(metadata.NewSuccessResp(mapstr.MapStr{"version": version}))
+}
+
+// CancelSynchronize cancel the running synchronization of the config item
+func (s *Service) CancelSynchronize(req *restful.Request, resp *restful.Response) {
+	srvData := s.newSrvComm(req.Request.Header)
+	name := req.PathParameter("name")
+	if s.getConfigItem(name) == nil {
+		blog.Errorf("CancelSynchronize, but config item %s not found,rid:%s", name, srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: srvData.ccErr.Errorf(common.CCErrSynchronizeItemNotExist, name)})
+		return
+	}
+
+	if err := srvData.lgc.CancelSynchronizeItem(srvData.ctx, name); err != nil {
+		blog.Errorf("CancelSynchronize %s error. error: %s,rid:%s", name, err.Error(), srvData.rid)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: err})
+		return
+	}
+	resp.WriteEntity(metadata.NewSuccessResp(nil))
+}
@@ -13,8 +13,6 @@
 package compatiblev2
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -48,7 +46,7 @@ func (b *business) SearchAllApp(fields string, cond mapstr.MapStr) (*metadata.In
 	query.Condition = cond
 	query.Fields = fields
 
-	rsp, err := b.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDApp, b.params.Header, query)
+	rsp, err := b.client.ObjectController().Instance().SearchObjects(b.params.Context, common.BKInnerObjIDApp, b.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-biz] failed to request object controller, error info is %s", err.Error())
 		return nil, err
@@ -13,8 +13,6 @@
 package compatiblev2
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -52,7 +50,7 @@ func (m *module) hasHost(bizID int64, moduleIDS []int64) (bool, error) {
 		"ModuleID":      moduleIDS,
 	}
 
-	rsp, err := m.client.HostController().Module().GetModulesHostConfig(context.Background(), m.params.Header, cond)
+	rsp, err := m.client.HostController().Module().GetModulesHostConfig(m.params.Context, m.params.Header, cond)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request the object controller, err: %s", err.Error())
 		return false, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -74,7 +72,7 @@ func (m *module) isValidSet(bizID, setID int64) (bool, error) {
 	query := &metadata.QueryInput{}
 	query.Condition = cond.ToMapStr()
 
-	rsp, err := m.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDSet, m.params.Header, query)
+	rsp, err := m.client.ObjectController().Instance().SearchObjects(m.params.Context, common.BKInnerObjIDSet, m.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module]failed to request object controller, err: %s", err.Error())
 		return false, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -97,7 +95,7 @@ func (m *module) isRepeated(moduleName string, excludeModuleIDS interface{}) (bo
 	query := &metadata.QueryInput{}
 	query.Condition = cond.ToMapStr()
 
-	rsp, err := m.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDModule, m.params.Header, query)
+	rsp, err := m.client.ObjectController().Instance().SearchObjects(m.params.Context, common.BKInnerObjIDModule, m.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return false, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -138,7 +136,7 @@ func (m *module) UpdateMultiModule(bizID int64, moduleIDS interface{}, innerData
 	updateData := mapstr.New()
 	updateData.Set("condition", cond.ToMapStr())
 	updateData.Set("data", innerData)
-	rsp, err := m.client.ObjectController().Instance().UpdateObject(context.Background(), common.BKInnerObjIDModule, m.params.Header, updateData)
+	rsp, err := m.client.ObjectController().Instance().UpdateObject(m.params.Context, common.BKInnerObjIDModule, m.params.Header, updateData)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -153,7 +151,7 @@ func (m *module) UpdateMultiModule(bizID int64, moduleIDS interface{}, innerData
 }
 func (m *module) SearchModuleByApp(query *metadata.QueryInput) (*metadata.InstResult, error) {
 
-	rsp, err := m.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDModule, m.params.Header, query)
+	rsp, err := m.client.ObjectController().Instance().SearchObjects(m.params.Context, common.BKInnerObjIDModule, m.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return nil, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -173,7 +171,7 @@ func (m *module) SearchModuleBySetProperty(bizID int64, cond condition.Condition
 	query.Limit = common.BKNoLimit
 	//fmt.Println("cond:", cond.ToMapStr())
 	// search sets
-	rsp, err := m.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDSet, m.params.Header, query)
+	rsp, err := m.client.ObjectController().Instance().SearchObjects(m.params.Context, common.BKInnerObjIDSet, m.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return nil, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -202,7 +200,7 @@ func (m *module) SearchModuleBySetProperty(bizID int64, cond condition.Condition
 	cond.Field(common.BKSetIDField).In(setIDS)
 	query.Condition = cond.ToMapStr()
 
-	rspModule, err := m.client.ObjectController().Instance().SearchObjects(context.Background(), common.BKInnerObjIDModule, m.params.Header, query)
+	rspModule, err := m.client.ObjectController().Instance().SearchObjects(m.params.Context, common.BKInnerObjIDModule, m.params.Header, query)
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return nil, m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -250,7 +248,7 @@ func (m *module) AddMultiModule(bizID, setID int64, moduleNames []string, data m
 		data.Set(common.BKDefaultField, 0)
 		data.Set(common.BKInstParentStr, setID)
 
-		rsp, err := m.client.ObjectController().Instance().CreateObject(context.Background(), common.BKInnerObjIDModule, m.params.Header, data)
+		rsp, err := m.client.ObjectController().Instance().CreateObject(m.params.Context, common.BKInnerObjIDModule, m.params.Header, data)
 		if nil != err {
 			blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 			return m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -281,7 +279,7 @@ func (m *module) DeleteMultiModule(bizID int64, moduleIDS []int64) error {
 	cond.Field(common.BKAppIDField).Eq(bizID)
 	cond.Field(common.BKModuleIDField).In(moduleIDS)
 
-	rsp, err := m.client.ObjectController().Instance().DelObject(context.Background(), common.BKInnerObjIDModule, m.params.Header, cond.ToMapStr())
+	rsp, err := m.client.ObjectController().Instance().DelObject(m.params.Context, common.BKInnerObjIDModule, m.params.Header, cond.ToMapStr())
 	if nil != err {
 		blog.Errorf("[compatiblev2-module] failed to request object controller, err: %s", err.Error())
 		return m.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
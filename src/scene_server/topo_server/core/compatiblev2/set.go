@@ -13,8 +13,6 @@
 package compatiblev2
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -49,7 +47,7 @@ func (s *set) hasHost(bizID int64, setIDS []int64) (bool, error) {
 		"SetID":         setIDS,
 	}
 
-	rsp, err := s.client.HostController().Module().GetModulesHostConfig(context.Background(), s.params.Header, cond)
+	rsp, err := s.client.HostController().Module().GetModulesHostConfig(s.params.Context, s.params.Header, cond)
 	if nil != err {
 		blog.Errorf("[compatiblev2-set] failed to request the object controller, err: %s", err.Error())
 		return false, s.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -69,7 +67,7 @@ func (s *set) deleteModules(bizID int64, setIDS []int64) error {
 	cond.Field(common.BKSetIDField).In(setIDS)
 	cond.Field(common.BKAppIDField).Eq(bizID)
 
-	rsp, err := s.client.ObjectController().Instance().DelObject(context.Background(), common.BKInnerObjIDModule, s.params.Header, cond.ToMapStr())
+	rsp, err := s.client.ObjectController().Instance().DelObject(s.params.Context, common.BKInnerObjIDModule, s.params.Header, cond.ToMapStr())
 
 	if nil != err {
 		blog.Errorf("[compatiblev2-set] failed to request the object controller, err: %s", err.Error())
@@ -90,7 +88,7 @@ func (s *set) UpdateMultiSet(bizID int64, data mapstr.MapStr, cond condition.Con
 	input.Set("data", data)
 	input.Set("condition", cond.ToMapStr())
 
-	rsp, err := s.client.ObjectController().Instance().UpdateObject(context.Background(), common.BKInnerObjIDSet, s.params.Header, input)
+	rsp, err := s.client.ObjectController().Instance().UpdateObject(s.params.Context, common.BKInnerObjIDSet, s.params.Header, input)
 	if nil != err {
 		blog.Errorf("[compatiblev2-set] failed to request the object controller, err: %s", rsp.ErrMsg)
 		return s.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -128,7 +126,7 @@ func (s *set) DeleteMultiSet(bizID int64, setIDS []int64) error {
 	cond.Field(common.BKAppIDField).Eq(bizID)
 	cond.Field(common.BKSetIDField).In(setIDS)
 
-	rsp, err := s.client.ObjectController().Instance().DelObject(context.Background(), common.BKInnerObjIDSet, s.params.Header, cond.ToMapStr())
+	rsp, err := s.client.ObjectController().Instance().DelObject(s.params.Context, common.BKInnerObjIDSet, s.params.Header, cond.ToMapStr())
 	if nil != err {
 		blog.Errorf("[compatiblev2-set] faield to check host, err: %s", err.Error())
 		return err
@@ -143,7 +141,7 @@ func (s *set) DeleteMultiSet(bizID int64, setIDS []int64) error {
 }
 func (s *set) DeleteSetHost(bizID int64, cond condition.Condition) error {
 
-	rsp, err := s.client.ObjectController().OpenAPI().DeleteSetHost(context.Background(), s.params.Header, cond.ToMapStr())
+	rsp, err := s.client.ObjectController().OpenAPI().DeleteSetHost(s.params.Context, s.params.Header, cond.ToMapStr())
 
 	if nil != err {
 		blog.Errorf("[compatiblev2-set] failed to delete the set hosts, err: %s", err.Error())
@@ -0,0 +1,336 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package graph builds the read only graphql schema of the models and the instances,
+// the schema is generated from the models, the attributes and the model associations in coreservice.
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/graphql"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+const (
+	// DefaultLimit the default count of the instances of a query field or a relation field
+	DefaultLimit = 20
+	// MaxLimit the max count of the instances of a query field or a relation field
+	MaxLimit = 200
+	// MaxDepth the max depth of the selections of a query
+	MaxDepth = 10
+	// FetchLimit the max count of the related instances read for all the instances of a relation field
+	FetchLimit = 5000
+)
+
+// Reader read the models and the instances for the schema
+type Reader interface {
+	// ReadModels read the models with their attributes
+	ReadModels(ctx context.Context) ([]metadata.SearchModelInfo, error)
+	// ReadModelAssociations read all the model associations
+	ReadModelAssociations(ctx context.Context) ([]metadata.Association, error)
+	// ReadInstances read the instances of the model, it returns the total count of the matched instances
+	ReadInstances(ctx context.Context, objID string, cond *metadata.QueryCondition) (int64, []mapstr.MapStr, error)
+	// ReadInstAssociations read the instance associations matched the condition
+	ReadInstAssociations(ctx context.Context, cond mapstr.MapStr) ([]metadata.InstAsst, error)
+	// ReadModuleHosts read the host module relations, the keys are bk_biz_id, bk_set_id, bk_module_id or bk_host_id
+	ReadModuleHosts(ctx context.Context, cond map[string][]int64) ([]metadata.ModuleHost, error)
+}
+
+// model the graphql type of a model
+type model struct {
+	objID   string
+	idField string
+	typ     *graphql.Object
+	attrs   map[string]bool
+	// parent the mainline parent model
+	parent *model
+}
+
+type builder struct {
+	reader     Reader
+	permission metadata.AttributePermission
+	ccErr      errors.DefaultCCErrorIf
+	models     map[string]*model
+	typeNames  map[string]bool
+}
+
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// NewSchema build the schema for the user, the masked attributes are masked in the result and can not be used in the filters
+func NewSchema(ctx context.Context, reader Reader, permission metadata.AttributePermission, ccErr errors.DefaultCCErrorIf) (*graphql.Schema, error) {
+	models, err := reader.ReadModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	asstDes, err := reader.ReadModelAssociations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		reader:     reader,
+		permission: permission,
+		ccErr:      ccErr,
+		models:     make(map[string]*model),
+		typeNames:  map[string]bool{"Query": true},
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Spec.ObjectID < models[j].Spec.ObjectID })
+	query := graphql.NewObject("Query", "the models and the instances")
+	for _, info := range models {
+		if info.Spec.IsPaused || !namePattern.MatchString(info.Spec.ObjectID) || strings.HasPrefix(info.Spec.ObjectID, "__") {
+			continue
+		}
+		m := b.addModel(info)
+		query.AddField(b.queryField(m, info.Spec.ObjectName))
+	}
+
+	for _, asst := range asstDes {
+		if asst.AsstKindID == common.AssociationKindMainline {
+			b.addMainline(asst)
+			continue
+		}
+		b.addAssociation(asst)
+	}
+	b.addHostRelations()
+
+	return graphql.NewSchema(query)
+}
+
+// typeName convert the object id to a unique type name, such as bk_switch to BkSwitch
+func (b *builder) typeName(objID string) string {
+	name := ""
+	for _, part := range strings.Split(objID, "_") {
+		if part != "" {
+			name += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "M" + name
+	}
+	unique := name
+	for i := 2; b.typeNames[unique] || b.typeNames[unique+"Connection"]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	b.typeNames[unique] = true
+	b.typeNames[unique+"Connection"] = true
+	return unique
+}
+
+func (b *builder) addModel(info metadata.SearchModelInfo) *model {
+	m := &model{
+		objID:   info.Spec.ObjectID,
+		idField: common.GetInstIDField(info.Spec.ObjectID),
+		attrs:   make(map[string]bool),
+	}
+	m.typ = graphql.NewObject(b.typeName(m.objID), info.Spec.ObjectName)
+	m.typ.AddField(&graphql.Field{Name: m.idField, Description: "the instance id", Type: graphql.Int})
+	m.attrs[m.idField] = true
+
+	attrs := info.Attributes
+	sort.SliceStable(attrs, func(i, j int) bool { return attrs[i].PropertyIndex < attrs[j].PropertyIndex })
+	for _, attr := range attrs {
+		if !namePattern.MatchString(attr.PropertyID) || strings.HasPrefix(attr.PropertyID, "__") {
+			continue
+		}
+		field := &graphql.Field{Name: attr.PropertyID, Description: attr.PropertyName, Type: attributeType(attr.PropertyType)}
+		if b.permission[m.objID][attr.PropertyID] == metadata.AttributePrivilegeMask {
+			// the masked values are replaced by the mask string like the instance search api
+			field.Type = graphql.String
+		}
+		if m.typ.AddField(field) {
+			m.attrs[attr.PropertyID] = true
+		}
+	}
+	b.models[m.objID] = m
+	return m
+}
+
+// attributeType the graphql type of the attribute values
+func attributeType(propertyType string) graphql.Type {
+	switch propertyType {
+	case common.FieldTypeInt:
+		return graphql.Int
+	case common.FieldTypeFloat:
+		return graphql.Float
+	case common.FieldTypeBool:
+		return graphql.Boolean
+	case common.FieldTypeSingleChar, common.FieldTypeLongChar, common.FieldTypeEnum, common.FieldTypeDate,
+		common.FieldTypeTime, common.FieldTypeUser, common.FieldTypeTimeZone:
+		return graphql.String
+	default:
+		return graphql.JSON
+	}
+}
+
+// queryField the root field which searches the instances of the model
+func (b *builder) queryField(m *model, objName string) *graphql.Field {
+	conn := graphql.NewObject(m.typ.Name+"Connection", "the paged instances of "+objName)
+	conn.AddField(&graphql.Field{Name: "count", Description: "the total count of the matched instances", Type: graphql.Int})
+	conn.AddField(&graphql.Field{Name: "info", Description: "the instances in the page", Type: graphql.NewList(m.typ)})
+
+	return &graphql.Field{
+		Name:        m.objID,
+		Description: "search the instances of " + objName,
+		Type:        conn,
+		Args:        pageArgs(),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			pg, err := b.parsePage(m, p.Args)
+			if err != nil {
+				return nil, err
+			}
+			cond := &metadata.QueryCondition{
+				Condition: pg.filter,
+				Limit:     metadata.SearchLimit{Offset: pg.start, Limit: pg.limit},
+				SortArr:   pg.sort,
+				Fields:    b.fields(m, p.SelectedFields("info")),
+			}
+			withInfo := contains(p.SelectedFields(), "info")
+			if !withInfo {
+				// only the count is needed
+				cond.Limit.Limit = 1
+				cond.Fields = []string{m.idField}
+			}
+			count, insts, err := b.readInstances(p.Context, m, cond)
+			if err != nil {
+				return nil, err
+			}
+			if !withInfo {
+				insts = nil
+			}
+			return mapstr.MapStr{"count": count, "info": insts}, nil
+		},
+	}
+}
+
+func pageArgs() []*graphql.Argument {
+	return []*graphql.Argument{
+		{Name: "filter", Description: `the condition of the instances as an object or a json string, such as {"bk_biz_name": "demo"}`, Type: graphql.JSON},
+		{Name: "start", Description: "the offset of the instances", Type: graphql.Int, DefaultValue: int64(0)},
+		{Name: "limit", Description: fmt.Sprintf("the max count of the instances, at most %d", MaxLimit), Type: graphql.Int, DefaultValue: int64(DefaultLimit)},
+		{Name: "sort", Description: "the fields to sort by separated by comma, such as bk_host_id:-1,bk_host_innerip", Type: graphql.String},
+	}
+}
+
+type page struct {
+	filter mapstr.MapStr
+	start  int64
+	limit  int64
+	sort   []metadata.SearchSort
+}
+
+// parsePage parse the page arguments, the masked attributes can not be used in the filter or the sort
+func (b *builder) parsePage(m *model, args map[string]interface{}) (*page, error) {
+	pg := &page{filter: mapstr.New()}
+	switch filter := args["filter"].(type) {
+	case nil:
+	case map[string]interface{}:
+		pg.filter = mapstr.NewFromMap(filter)
+	case string:
+		// the operators such as $in are not valid names in the graphql literals, the filter can be a json string
+		if err := json.Unmarshal([]byte(filter), &pg.filter); err != nil || pg.filter == nil {
+			return nil, b.ccErr.Errorf(common.CCErrCommParamsInvalid, "filter")
+		}
+	default:
+		return nil, b.ccErr.Errorf(common.CCErrCommParamsInvalid, "filter")
+	}
+
+	var ok bool
+	if pg.start, ok = args["start"].(int64); !ok || pg.start < 0 {
+		return nil, b.ccErr.Errorf(common.CCErrCommParamsInvalid, "start")
+	}
+	if pg.limit, ok = args["limit"].(int64); !ok || pg.limit <= 0 || pg.limit > MaxLimit {
+		return nil, b.ccErr.Errorf(common.CCErrCommParamsInvalid, "limit")
+	}
+
+	sortStr, _ := args["sort"].(string)
+	if sortStr != "" {
+		pg.sort = metadata.NewSearchSortParse().String(sortStr).ToSearchSortArr()
+		for _, item := range pg.sort {
+			if item.Field == "" {
+				return nil, b.ccErr.Errorf(common.CCErrCommParamsInvalid, "sort")
+			}
+		}
+	}
+	if unreadable := b.permission.Unreadable(m.objID, pg.filter, sortStr); len(unreadable) > 0 {
+		return nil, b.ccErr.Errorf(common.CCErrTopoAttributeNoReadPermission, strings.Join(unreadable, ","))
+	}
+	return pg, nil
+}
+
+// readInstances read the instances of the model and mask the attributes which the user can not read
+func (b *builder) readInstances(ctx context.Context, m *model, cond *metadata.QueryCondition) (int64, []mapstr.MapStr, error) {
+	count, insts, err := b.reader.ReadInstances(ctx, m.objID, cond)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, inst := range insts {
+		b.permission.Mask(m.objID, inst)
+	}
+	return count, insts, nil
+}
+
+// fields the fields to read for the selected fields, the relation fields are replaced by the fields they depend on
+func (b *builder) fields(m *model, selected []string) []string {
+	fields := []string{m.idField}
+	for _, name := range selected {
+		if name != m.idField && m.attrs[name] {
+			fields = append(fields, name)
+		}
+	}
+	if m.parent != nil {
+		fields = append(fields, common.BKInstParentStr)
+	}
+	return fields
+}
+
+func contains(items []string, item string) bool {
+	for _, one := range items {
+		if one == item {
+			return true
+		}
+	}
+	return false
+}
+
+// instIDs the instance ids of the sources in order
+func instIDs(field string, sources []interface{}) []int64 {
+	ids := make([]int64, len(sources))
+	for i, source := range sources {
+		ids[i] = instID(field, source)
+	}
+	return ids
+}
+
+func instID(field string, source interface{}) int64 {
+	var value interface{}
+	switch inst := source.(type) {
+	case mapstr.MapStr:
+		value = inst[field]
+	case map[string]interface{}:
+		value = inst[field]
+	}
+	id, err := util.GetInt64ByInterface(value)
+	if err != nil {
+		return 0
+	}
+	return id
+}
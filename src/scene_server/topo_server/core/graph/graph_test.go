@@ -0,0 +1,251 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/graphql"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+type fakeReader struct {
+	insts     map[string][]mapstr.MapStr
+	instReads int
+}
+
+func newFakeReader() *fakeReader {
+	return &fakeReader{insts: map[string][]mapstr.MapStr{
+		"biz": {
+			{"bk_biz_id": 1, "bk_biz_name": "demo"},
+			{"bk_biz_id": 2, "bk_biz_name": "other"},
+		},
+		"set": {
+			{"bk_set_id": 10, "bk_set_name": "s1", "bk_parent_id": 1},
+			{"bk_set_id": 11, "bk_set_name": "s2", "bk_parent_id": 1},
+			{"bk_set_id": 20, "bk_set_name": "s3", "bk_parent_id": 2},
+		},
+		"module": {
+			{"bk_module_id": 100, "bk_module_name": "m1", "bk_parent_id": 10},
+			{"bk_module_id": 101, "bk_module_name": "m2", "bk_parent_id": 10},
+			{"bk_module_id": 110, "bk_module_name": "m3", "bk_parent_id": 11},
+		},
+		"host": {
+			{"bk_host_id": 1000, "bk_host_innerip": "10.0.0.1", "operator": "admin"},
+			{"bk_host_id": 1001, "bk_host_innerip": "10.0.0.2", "operator": "admin"},
+		},
+		"bk_switch": {
+			{"bk_inst_id": 5, "bk_inst_name": "sw1"},
+		},
+	}}
+}
+
+func (r *fakeReader) ReadModels(ctx context.Context) ([]metadata.SearchModelInfo, error) {
+	model := func(objID string, attrs ...string) metadata.SearchModelInfo {
+		info := metadata.SearchModelInfo{Spec: metadata.Object{ObjectID: objID, ObjectName: objID}}
+		for _, attr := range attrs {
+			info.Attributes = append(info.Attributes, metadata.Attribute{ObjectID: objID, PropertyID: attr, PropertyType: common.FieldTypeSingleChar})
+		}
+		return info
+	}
+	paused := model("bk_router", "bk_inst_name")
+	paused.Spec.IsPaused = true
+	return []metadata.SearchModelInfo{
+		model("biz", "bk_biz_name"),
+		model("set", "bk_set_name"),
+		model("module", "bk_module_name"),
+		model("host", "bk_host_innerip", "operator"),
+		model("bk_switch", "bk_inst_name"),
+		paused,
+	}, nil
+}
+
+func (r *fakeReader) ReadModelAssociations(ctx context.Context) ([]metadata.Association, error) {
+	return []metadata.Association{
+		{ObjectID: "set", AsstObjID: "biz", AsstKindID: common.AssociationKindMainline, AssociationName: "set_bk_mainline_biz"},
+		{ObjectID: "module", AsstObjID: "set", AsstKindID: common.AssociationKindMainline, AssociationName: "module_bk_mainline_set"},
+		{ObjectID: "host", AsstObjID: "module", AsstKindID: common.AssociationKindMainline, AssociationName: "host_bk_mainline_module"},
+		{ObjectID: "bk_switch", AsstObjID: "host", AsstKindID: "connect", AssociationName: "bk_switch_connect_host"},
+	}, nil
+}
+
+func (r *fakeReader) ReadInstances(ctx context.Context, objID string, cond *metadata.QueryCondition) (int64, []mapstr.MapStr, error) {
+	r.instReads++
+	var matched []mapstr.MapStr
+	for _, inst := range r.insts[objID] {
+		if match(inst, cond.Condition) {
+			matched = append(matched, inst)
+		}
+	}
+	var result []mapstr.MapStr
+	for i := cond.Limit.Offset; i < int64(len(matched)) && i < cond.Limit.Offset+cond.Limit.Limit; i++ {
+		inst := mapstr.New()
+		for _, field := range cond.Fields {
+			if value, ok := matched[i][field]; ok {
+				inst[field] = value
+			}
+		}
+		result = append(result, inst)
+	}
+	return int64(len(matched)), result, nil
+}
+
+func match(inst mapstr.MapStr, cond mapstr.MapStr) bool {
+	for key, value := range cond {
+		if key == common.BKDBAND {
+			for _, sub := range value.([]interface{}) {
+				if !match(inst, sub.(mapstr.MapStr)) {
+					return false
+				}
+			}
+			continue
+		}
+		if in, ok := value.(mapstr.MapStr); ok {
+			found := false
+			for _, id := range in[common.BKDBIN].([]int64) {
+				instID, _ := util.GetInt64ByInterface(inst[key])
+				found = found || instID == id
+			}
+			if !found {
+				return false
+			}
+			continue
+		}
+		if inst[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *fakeReader) ReadInstAssociations(ctx context.Context, cond mapstr.MapStr) ([]metadata.InstAsst, error) {
+	asst := metadata.InstAsst{InstID: 5, ObjectID: "bk_switch", AsstInstID: 1000, AsstObjectID: "host", ObjectAsstID: "bk_switch_connect_host"}
+	if !match(mapstr.MapStr{"bk_obj_asst_id": asst.ObjectAsstID, "bk_inst_id": asst.InstID, "bk_asst_inst_id": asst.AsstInstID}, cond) {
+		return nil, nil
+	}
+	return []metadata.InstAsst{asst}, nil
+}
+
+func (r *fakeReader) ReadModuleHosts(ctx context.Context, cond map[string][]int64) ([]metadata.ModuleHost, error) {
+	relations := []metadata.ModuleHost{
+		{AppID: 1, SetID: 10, ModuleID: 100, HostID: 1000},
+		{AppID: 1, SetID: 10, ModuleID: 101, HostID: 1001},
+		{AppID: 1, SetID: 11, ModuleID: 110, HostID: 1001},
+	}
+	var result []metadata.ModuleHost
+	for _, relation := range relations {
+		data := mapstr.MapStr{"bk_biz_id": relation.AppID, "bk_set_id": relation.SetID, "bk_module_id": relation.ModuleID, "bk_host_id": relation.HostID}
+		matched := true
+		for key, ids := range cond {
+			matched = matched && match(data, mapstr.MapStr{key: mapstr.MapStr{common.BKDBIN: ids}})
+		}
+		if matched {
+			result = append(result, relation)
+		}
+	}
+	return result, nil
+}
+
+func execute(t *testing.T, reader Reader, permission metadata.AttributePermission, query string) string {
+	ccErr := errors.NewFromCtx(errors.EmptyErrorsSetting).CreateDefaultCCErrorIf("en")
+	schema, err := NewSchema(context.Background(), reader, permission, ccErr)
+	require.NoError(t, err)
+	result := graphql.Do(graphql.Params{
+		Context:  context.Background(),
+		Schema:   schema,
+		Request:  graphql.Request{Query: query},
+		MaxDepth: MaxDepth,
+	})
+	out, err := json.Marshal(result)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestTopology(t *testing.T) {
+	reader := newFakeReader()
+	out := execute(t, reader, nil, `{
+		biz(filter: {bk_biz_name: "demo"}) {
+			count
+			info {
+				bk_biz_name
+				set(limit: 1) { bk_set_name module { bk_module_name host { bk_host_innerip } } }
+			}
+		}
+	}`)
+	require.JSONEq(t, `{"data": {"biz": {"count": 1, "info": [{"bk_biz_name": "demo", "set": [
+		{"bk_set_name": "s1", "module": [
+			{"bk_module_name": "m1", "host": [{"bk_host_innerip": "10.0.0.1"}]},
+			{"bk_module_name": "m2", "host": [{"bk_host_innerip": "10.0.0.2"}]}
+		]}
+	]}]}}}`, out)
+	// the instances of each level are read once
+	require.Equal(t, 4, reader.instReads)
+
+	out = execute(t, newFakeReader(), nil, `{
+		host(sort: "bk_host_id") {
+			info {
+				bk_host_id
+				biz { bk_biz_name }
+				module { bk_module_name set { bk_set_name } }
+				bk_switch_connect_host { bk_inst_name }
+			}
+		}
+		bk_switch { count }
+	}`)
+	require.JSONEq(t, `{"data": {"host": {"info": [
+		{"bk_host_id": 1000, "biz": {"bk_biz_name": "demo"}, "module": [{"bk_module_name": "m1", "set": {"bk_set_name": "s1"}}],
+			"bk_switch_connect_host": [{"bk_inst_name": "sw1"}]},
+		{"bk_host_id": 1001, "biz": {"bk_biz_name": "demo"},
+			"module": [{"bk_module_name": "m2", "set": {"bk_set_name": "s1"}}, {"bk_module_name": "m3", "set": {"bk_set_name": "s2"}}],
+			"bk_switch_connect_host": []}
+	]}, "bk_switch": {"count": 1}}}`, out)
+}
+
+func TestSchema(t *testing.T) {
+	out := execute(t, newFakeReader(), nil, `{ bk_router { count } }`)
+	require.Contains(t, out, `Cannot query field \"bk_router\" on type \"Query\"`)
+
+	out = execute(t, newFakeReader(), nil, `{ __type(name: "BkSwitch") { fields { name } } }`)
+	require.JSONEq(t, `{"data": {"__type": {"fields": [{"name": "bk_inst_id"}, {"name": "bk_inst_name"}, {"name": "bk_switch_connect_host"}]}}}`, out)
+
+	out = execute(t, newFakeReader(), nil, `{ biz(limit: 1000) { count } }`)
+	require.Contains(t, out, `"errors"`)
+	require.Contains(t, out, `"path":["biz"]`)
+}
+
+func TestMaskedAttribute(t *testing.T) {
+	permission := metadata.AttributePermission{"host": {"operator": metadata.AttributePrivilegeMask}}
+
+	out := execute(t, newFakeReader(), permission, `{ host { info { bk_host_innerip operator } } }`)
+	require.Contains(t, out, `"bk_host_innerip":"10.0.0.1","operator":"`+metadata.AttributeMaskedValue+`"`)
+	require.NotContains(t, out, `"errors"`)
+
+	out = execute(t, newFakeReader(), permission, `{ biz { info { host { operator } } } }`)
+	require.Contains(t, out, `"operator":"`+metadata.AttributeMaskedValue+`"`)
+	require.NotContains(t, out, `"admin"`)
+
+	out = execute(t, newFakeReader(), permission, `{ biz { info { host(filter: {operator: "admin"}) { bk_host_id } } } }`)
+	require.Contains(t, out, `"host":null`)
+	require.Contains(t, out, `"errors"`)
+
+	out = execute(t, newFakeReader(), permission, `{ host(filter: "{\"$or\": [{\"operator\": \"admin\"}]}") { count } }`)
+	require.Contains(t, out, `"data":{"host":null}`)
+}
@@ -0,0 +1,325 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graph
+
+import (
+	"context"
+
+	"configcenter/src/common"
+	"configcenter/src/common/graphql"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// relatedFunc return the ids of the related instances of each source
+type relatedFunc func(ctx context.Context, sources []interface{}) ([][]int64, error)
+
+// addMainline add the children field to the parent model and the parent field to the child model,
+// the relations between the hosts and the modules are added by addHostRelations
+func (b *builder) addMainline(asst metadata.Association) {
+	child, parent := b.models[asst.ObjectID], b.models[asst.AsstObjID]
+	if child == nil || parent == nil || child.objID == common.BKInnerObjIDHost {
+		return
+	}
+	child.parent = parent
+
+	parent.typ.AddField(&graphql.Field{
+		Name:         child.objID,
+		Description:  "the mainline child instances",
+		Type:         graphql.NewList(child.typ),
+		Args:         pageArgs(),
+		ResolveBatch: b.childrenResolver(parent, child),
+	})
+	child.typ.AddField(&graphql.Field{
+		Name:        parent.objID,
+		Description: "the mainline parent instance",
+		Type:        parent.typ,
+		ResolveBatch: b.objectResolver(parent, func(ctx context.Context, sources []interface{}) ([][]int64, error) {
+			related := make([][]int64, len(sources))
+			for i, id := range instIDs(common.BKInstParentStr, sources) {
+				related[i] = []int64{id}
+			}
+			return related, nil
+		}),
+	})
+}
+
+// addAssociation add the association field to both the models, the field is named by the association id,
+// the field of the reverse side has the _reverse suffix if the model is associated with itself.
+func (b *builder) addAssociation(asst metadata.Association) {
+	src, dst := b.models[asst.ObjectID], b.models[asst.AsstObjID]
+	if src == nil || dst == nil || !namePattern.MatchString(asst.AssociationName) {
+		return
+	}
+
+	src.typ.AddField(&graphql.Field{
+		Name:         asst.AssociationName,
+		Description:  "the associated " + dst.objID + " instances, the association kind is " + asst.AsstKindID,
+		Type:         graphql.NewList(dst.typ),
+		Args:         pageArgs(),
+		ResolveBatch: b.listResolver(dst, b.instAsstRelated(asst.AssociationName, src, false)),
+	})
+	reverse := asst.AssociationName
+	if src == dst {
+		reverse += "_reverse"
+	}
+	dst.typ.AddField(&graphql.Field{
+		Name:         reverse,
+		Description:  "the " + src.objID + " instances associated with it, the association kind is " + asst.AsstKindID,
+		Type:         graphql.NewList(src.typ),
+		Args:         pageArgs(),
+		ResolveBatch: b.listResolver(src, b.instAsstRelated(asst.AssociationName, dst, true)),
+	})
+}
+
+// instAsstRelated the related instance ids by the instance associations, the reverse means the sources are the targets
+func (b *builder) instAsstRelated(asstID string, m *model, reverse bool) relatedFunc {
+	return func(ctx context.Context, sources []interface{}) ([][]int64, error) {
+		ids := instIDs(m.idField, sources)
+		key := common.BKInstIDField
+		if reverse {
+			key = common.BKAsstInstIDField
+		}
+		assts, err := b.reader.ReadInstAssociations(ctx, mapstr.MapStr{
+			common.AssociationObjAsstIDField: asstID,
+			key:                              mapstr.MapStr{common.BKDBIN: ids},
+		})
+		if err != nil {
+			return nil, err
+		}
+		relations := make(map[int64][]int64)
+		for _, asst := range assts {
+			if reverse {
+				relations[asst.AsstInstID] = append(relations[asst.AsstInstID], asst.InstID)
+			} else {
+				relations[asst.InstID] = append(relations[asst.InstID], asst.AsstInstID)
+			}
+		}
+		return relatedOf(ids, relations), nil
+	}
+}
+
+// addHostRelations add the host field to the business, the set and the module models,
+// and add the biz, the set and the module fields to the host model
+func (b *builder) addHostRelations() {
+	host := b.models[common.BKInnerObjIDHost]
+	if host == nil {
+		return
+	}
+	for _, objID := range []string{common.BKInnerObjIDApp, common.BKInnerObjIDSet, common.BKInnerObjIDModule} {
+		m := b.models[objID]
+		if m == nil {
+			continue
+		}
+		m.typ.AddField(&graphql.Field{
+			Name:         common.BKInnerObjIDHost,
+			Description:  "the hosts in the " + objID,
+			Type:         graphql.NewList(host.typ),
+			Args:         pageArgs(),
+			ResolveBatch: b.listResolver(host, b.moduleHostRelated(m, host)),
+		})
+
+		field := &graphql.Field{Name: objID, Description: "the " + objID + " which the host belongs to"}
+		if objID == common.BKInnerObjIDApp {
+			field.Type = m.typ
+			field.ResolveBatch = b.objectResolver(m, b.moduleHostRelated(host, m))
+		} else {
+			field.Type = graphql.NewList(m.typ)
+			field.Args = pageArgs()
+			field.ResolveBatch = b.listResolver(m, b.moduleHostRelated(host, m))
+		}
+		host.typ.AddField(field)
+	}
+}
+
+// moduleHostRelated the related instance ids by the host module relations
+func (b *builder) moduleHostRelated(from, to *model) relatedFunc {
+	return func(ctx context.Context, sources []interface{}) ([][]int64, error) {
+		ids := instIDs(from.idField, sources)
+		relations, err := b.reader.ReadModuleHosts(ctx, map[string][]int64{from.idField: ids})
+		if err != nil {
+			return nil, err
+		}
+		related := make(map[int64][]int64)
+		for _, relation := range relations {
+			fromID, toID := moduleHostID(from.objID, relation), moduleHostID(to.objID, relation)
+			related[fromID] = append(related[fromID], toID)
+		}
+		return relatedOf(ids, related), nil
+	}
+}
+
+func moduleHostID(objID string, relation metadata.ModuleHost) int64 {
+	switch objID {
+	case common.BKInnerObjIDApp:
+		return relation.AppID
+	case common.BKInnerObjIDSet:
+		return relation.SetID
+	case common.BKInnerObjIDModule:
+		return relation.ModuleID
+	default:
+		return relation.HostID
+	}
+}
+
+// relatedOf the related ids of each id without duplicates
+func relatedOf(ids []int64, relations map[int64][]int64) [][]int64 {
+	related := make([][]int64, len(ids))
+	for i, id := range ids {
+		exists := make(map[int64]bool)
+		for _, relatedID := range relations[id] {
+			if !exists[relatedID] {
+				exists[relatedID] = true
+				related[i] = append(related[i], relatedID)
+			}
+		}
+	}
+	return related
+}
+
+// childrenResolver resolve the mainline children of the parents
+func (b *builder) childrenResolver(parent, child *model) graphql.BatchResolveFunc {
+	return func(p graphql.ResolveParams, sources []interface{}) ([]interface{}, error) {
+		pg, err := b.parsePage(child, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		ids := instIDs(parent.idField, sources)
+		insts, err := b.readRelated(p, child, common.BKInstParentStr, ids, pg)
+		if err != nil {
+			return nil, err
+		}
+		children := make(map[int64][]mapstr.MapStr)
+		for _, inst := range insts {
+			parentID := instID(common.BKInstParentStr, inst)
+			children[parentID] = append(children[parentID], inst)
+		}
+		values := make([]interface{}, len(sources))
+		for i, id := range ids {
+			values[i] = paginate(children[id], pg)
+		}
+		return values, nil
+	}
+}
+
+// listResolver resolve the related instances of the sources with the page arguments
+func (b *builder) listResolver(target *model, related relatedFunc) graphql.BatchResolveFunc {
+	return func(p graphql.ResolveParams, sources []interface{}) ([]interface{}, error) {
+		pg, err := b.parsePage(target, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		relatedIDs, err := related(p.Context, sources)
+		if err != nil {
+			return nil, err
+		}
+		insts, err := b.readRelated(p, target, target.idField, flatten(relatedIDs), pg)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(sources))
+		for i, ids := range relatedIDs {
+			idSet := make(map[int64]bool, len(ids))
+			for _, id := range ids {
+				idSet[id] = true
+			}
+			// keep the order of the sorted instances
+			list := make([]mapstr.MapStr, 0)
+			for _, inst := range insts {
+				if idSet[instID(target.idField, inst)] {
+					list = append(list, inst)
+				}
+			}
+			values[i] = paginate(list, pg)
+		}
+		return values, nil
+	}
+}
+
+// objectResolver resolve the only related instance of the sources
+func (b *builder) objectResolver(target *model, related relatedFunc) graphql.BatchResolveFunc {
+	return func(p graphql.ResolveParams, sources []interface{}) ([]interface{}, error) {
+		relatedIDs, err := related(p.Context, sources)
+		if err != nil {
+			return nil, err
+		}
+		insts, err := b.readRelated(p, target, target.idField, flatten(relatedIDs), &page{filter: mapstr.New()})
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int64]mapstr.MapStr, len(insts))
+		for _, inst := range insts {
+			byID[instID(target.idField, inst)] = inst
+		}
+		values := make([]interface{}, len(sources))
+		for i, ids := range relatedIDs {
+			if len(ids) == 0 {
+				continue
+			}
+			if inst, ok := byID[ids[0]]; ok {
+				values[i] = inst
+			}
+		}
+		return values, nil
+	}
+}
+
+// readRelated read the instances whose key field is in the keys, all of them are read at once
+// so that they can be paged for each source.
+func (b *builder) readRelated(p graphql.ResolveParams, target *model, key string, keys []int64, pg *page) ([]mapstr.MapStr, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cond := mapstr.MapStr{key: mapstr.MapStr{common.BKDBIN: keys}}
+	if len(pg.filter) > 0 {
+		cond = mapstr.MapStr{common.BKDBAND: []interface{}{pg.filter, cond}}
+	}
+	count, insts, err := b.readInstances(p.Context, target, &metadata.QueryCondition{
+		Condition: cond,
+		Limit:     metadata.SearchLimit{Limit: FetchLimit},
+		SortArr:   pg.sort,
+		Fields:    b.fields(target, p.SelectedFields()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count > int64(len(insts)) {
+		return nil, b.ccErr.Errorf(common.CCErrTopoGraphTooManyRelatedInsts, target.objID)
+	}
+	return insts, nil
+}
+
+func flatten(relatedIDs [][]int64) []int64 {
+	exists := make(map[int64]bool)
+	ids := make([]int64, 0)
+	for _, related := range relatedIDs {
+		for _, id := range related {
+			if !exists[id] {
+				exists[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func paginate(insts []mapstr.MapStr, pg *page) []mapstr.MapStr {
+	if pg.start >= int64(len(insts)) {
+		return make([]mapstr.MapStr, 0)
+	}
+	end := pg.start + pg.limit
+	if end > int64(len(insts)) {
+		end = int64(len(insts))
+	}
+	return insts[pg.start:end]
+}
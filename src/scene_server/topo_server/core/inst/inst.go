@@ -13,7 +13,6 @@
 package inst
 
 import (
-	"context"
 	"encoding/json"
 
 	"configcenter/src/apimachinery"
@@ -55,6 +54,9 @@ type Inst interface {
 
 	ToMapStr() mapstr.MapStr
 
+	// GetExisting return the stored instance which the current values point to, nil if it does not exist
+	GetExisting() (mapstr.MapStr, error)
+
 	IsDefault() bool
 }
 
@@ -87,7 +89,7 @@ func (cli *inst) searchInsts(targetModel model.Object, cond condition.Condition)
 	queryInput.Condition = cond.ToMapStr()
 
 	if targetModel.Object().ObjectID != common.BKInnerObjIDHost {
-		rsp, err := cli.clientSet.CoreService().Instance().ReadInstance(context.Background(), cli.params.Header, targetModel.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
+		rsp, err := cli.clientSet.CoreService().Instance().ReadInstance(cli.params.Context, cli.params.Header, targetModel.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[inst-inst] failed to request the object controller , error info is %s", err.Error())
 			return nil, cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -102,7 +104,7 @@ func (cli *inst) searchInsts(targetModel model.Object, cond condition.Condition)
 	}
 
 	// search hosts
-	rsp, err := cli.clientSet.HostController().Host().GetHosts(context.Background(), cli.params.Header, queryInput)
+	rsp, err := cli.clientSet.HostController().Host().GetHosts(cli.params.Context, cli.params.Header, queryInput)
 	if nil != err {
 		blog.Errorf("[inst-inst] failed to request the object controller , error info is %s", err.Error())
 		return nil, cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -127,7 +129,7 @@ func (cli *inst) Create() error {
 
 	cli.datas.Set(common.BKOwnerIDField, cli.params.SupplierAccount)
 
-    rsp, err := cli.clientSet.CoreService().Instance().CreateInstance(context.Background(), cli.params.Header, cli.target.GetObjectID(), &metadata.CreateModelInstance{Data: cli.datas})
+    rsp, err := cli.clientSet.CoreService().Instance().CreateInstance(cli.params.Context, cli.params.Header, cli.target.GetObjectID(), &metadata.CreateModelInstance{Data: cli.datas})
 	if nil != err {
 		blog.Errorf("failed to create object instance, error info is %s", err.Error())
 		return err
@@ -186,7 +188,7 @@ func (cli *inst) Update(data mapstr.MapStr) error {
 	updateCond := metadata.UpdateOption{}
 	updateCond.Data = data
 	updateCond.Condition = cond.ToMapStr()
-	rsp, err := cli.clientSet.CoreService().Instance().UpdateInstance(context.Background(), cli.params.Header, cli.target.GetObjectID(), &updateCond)
+	rsp, err := cli.clientSet.CoreService().Instance().UpdateInstance(cli.params.Context, cli.params.Header, cli.target.GetObjectID(), &updateCond)
 	if nil != err {
 		blog.Errorf("failed to update the object(%s) instances, error info is %s", tObj.ObjectID, err.Error())
 		return cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -211,13 +213,13 @@ func (cli *inst) Update(data mapstr.MapStr) error {
 	return nil
 }
 
-func (cli *inst) IsExists() (bool, error) {
+func (cli *inst) searchExisting() ([]mapstr.MapStr, error) {
 
 	tObj := cli.target.Object()
 	attrs, err := cli.target.GetAttributesExceptInnerFields()
 	if nil != err {
 		blog.Errorf("failed to get attributes for the object(%s), error info is is %s", tObj.ObjectID, err.Error())
-		return false, err
+		return nil, err
 	}
 
 	cond := condition.CreateCondition()
@@ -242,7 +244,7 @@ func (cli *inst) IsExists() (bool, error) {
 
 				val, exists := cli.datas.Get(attr.PropertyID)
 				if !exists {
-					return false, cli.params.Err.Errorf(common.CCErrCommParamsLostField, attr.PropertyID)
+					return nil, cli.params.Err.Errorf(common.CCErrCommParamsLostField, attr.PropertyID)
 				}
 				cond.Field(attr.PropertyID).Eq(val)
 			}
@@ -254,20 +256,39 @@ func (cli *inst) IsExists() (bool, error) {
 	queryCond.Condition = cond.ToMapStr()
 
 	rsp, err := cli.clientSet.CoreService().Instance().ReadInstance(
-		context.Background(), cli.params.Header, cli.target.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()},
+		cli.params.Context, cli.params.Header, cli.target.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()},
 	)
 	if nil != err {
 		blog.Errorf("failed to search object(%s) instances  , error info is %s", tObj.ObjectID, err.Error())
-		return false, cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+		return nil, cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
 	}
 
 	if !rsp.Result {
 		blog.Errorf("failed to search the object (%s) instances, error info is %s", tObj.ObjectID, rsp.ErrMsg)
-		return false, cli.params.Err.Error(common.CCErrTopoInstSelectFailed)
+		return nil, cli.params.Err.Error(common.CCErrTopoInstSelectFailed)
 	}
 
-	return 0 != rsp.Data.Count, nil
+	return rsp.Data.Info, nil
 }
+func (cli *inst) IsExists() (bool, error) {
+	existing, err := cli.searchExisting()
+	if nil != err {
+		return false, err
+	}
+	return 0 != len(existing), nil
+}
+
+func (cli *inst) GetExisting() (mapstr.MapStr, error) {
+	existing, err := cli.searchExisting()
+	if nil != err {
+		return nil, err
+	}
+	if 0 == len(existing) {
+		return nil, nil
+	}
+	return existing[0], nil
+}
+
 func (cli *inst) Save(data mapstr.MapStr) error {
 
 	if nil != data {
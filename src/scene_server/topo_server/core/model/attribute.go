@@ -13,7 +13,6 @@
 package model
 
 import (
-	"context"
 	"encoding/json"
 
 	"configcenter/src/apimachinery"
@@ -65,7 +64,7 @@ func (a *attribute) searchObjects(objID string) ([]metadata.Object, error) {
 	input := metadata.QueryCondition{
 		Condition: cond.ToMapStr(),
 	}
-	rsp, err := a.clientSet.CoreService().Model().ReadModel(context.Background(), a.params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Model().ReadModel(a.params.Context, a.params.Header, &input)
 	if nil != err {
 		blog.Errorf("failed to request the object controller, err: %s", err.Error())
 		return nil, a.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -170,7 +169,7 @@ func (a *attribute) Create() error {
 
 	// create a new record
 	input := metadata.CreateModelAttributes{Attributes: []metadata.Attribute{a.attr}}
-	rsp, err := a.clientSet.CoreService().Model().CreateModelAttrs(context.Background(), a.params.Header, a.attr.ObjectID, &input)
+	rsp, err := a.clientSet.CoreService().Model().CreateModelAttrs(a.params.Context, a.params.Header, a.attr.ObjectID, &input)
 	if nil != err {
 		blog.Errorf("faield to request the object controller, the err: %s", err.Error())
 		return err
@@ -211,7 +210,7 @@ func (a *attribute) Update(data mapstr.MapStr) error {
 		Condition: condition.CreateCondition().Field(common.BKFieldID).Eq(a.attr.ID).ToMapStr(),
 		Data:      data,
 	}
-	rsp, err := a.clientSet.CoreService().Model().UpdateModelAttrs(context.Background(), a.params.Header, a.attr.ObjectID, &input)
+	rsp, err := a.clientSet.CoreService().Model().UpdateModelAttrs(a.params.Context, a.params.Header, a.attr.ObjectID, &input)
 	if nil != err {
 		blog.Errorf("failed to request object controller, err: %s", err.Error())
 		return err
@@ -226,7 +225,7 @@ func (a *attribute) Update(data mapstr.MapStr) error {
 }
 func (a *attribute) search(cond condition.Condition) ([]metadata.Attribute, error) {
 
-	rsp, err := a.clientSet.CoreService().Model().ReadModelAttr(context.Background(), a.params.Header, a.attr.ObjectID, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Model().ReadModelAttr(a.params.Context, a.params.Header, a.attr.ObjectID, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("failed to request to object controller, err: %s", err.Error())
 		return nil, err
@@ -303,7 +302,7 @@ func (a *attribute) GetGroup() (GroupInterface, error) {
 	cond.Field(metadata.GroupFieldGroupID).Eq(a.attr.PropertyGroup)
 	cond.Field(metadata.GroupFieldObjectID).Eq(a.attr.ObjectID)
 
-	rsp, err := a.clientSet.CoreService().Model().ReadAttributeGroup(context.Background(), a.params.Header, a.attr.ObjectID, metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Model().ReadAttributeGroup(a.params.Context, a.params.Header, a.attr.ObjectID, metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[model-grp] failed to request the object controller, err: %s", err.Error())
 		return nil, a.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
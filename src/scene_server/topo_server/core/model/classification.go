@@ -13,7 +13,6 @@
 package model
 
 import (
-	"context"
 	"encoding/json"
 
 	"configcenter/src/apimachinery"
@@ -66,7 +65,7 @@ func (cli *classification) GetObjects() ([]Object, error) {
 	cond := condition.CreateCondition()
 	cond.Field(metadata.ModelFieldObjCls).Eq(cli.cls.ClassificationID)
 
-	rsp, err := cli.clientSet.CoreService().Model().ReadModel(context.Background(), cli.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := cli.clientSet.CoreService().Model().ReadModel(cli.params.Context, cli.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("failed to request the object controller, err: %s", err.Error())
 		return nil, cli.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -119,7 +118,7 @@ func (cli *classification) Create() error {
 	}
 
 	input := metadata.CreateOneModelClassification{Data: cli.cls}
-	rsp, err := cli.clientSet.CoreService().Model().CreateModelClassification(context.Background(), cli.params.Header, &input)
+	rsp, err := cli.clientSet.CoreService().Model().CreateModelClassification(cli.params.Context, cli.params.Header, &input)
 	if nil != err {
 		blog.Errorf("failed to request object controller, err: %s", err.Error())
 		return err
@@ -171,7 +170,7 @@ func (cli *classification) Update(data mapstr.MapStr) error {
 			Condition: cond.ToMapStr(),
 			Data:      data,
 		}
-		rsp, err := cli.clientSet.CoreService().Model().UpdateModelClassification(context.Background(), cli.params.Header, &input)
+		rsp, err := cli.clientSet.CoreService().Model().UpdateModelClassification(cli.params.Context, cli.params.Header, &input)
 		if nil != err {
 			blog.Errorf("failed to request object controller, err: %s", err.Error())
 			return err
@@ -192,7 +191,7 @@ func (cli *classification) search(cond condition.Condition) ([]metadata.Classifi
 	if nil != cli.params.MetaData {
 		cond.Field(metadata.BKMetadata).Eq(*cli.params.MetaData)
 	}
-	rsp, err := cli.clientSet.CoreService().Model().ReadModelClassification(context.Background(), cli.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := cli.clientSet.CoreService().Model().ReadModelClassification(cli.params.Context, cli.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("failed to request the object controller, err: %s", err.Error())
 		return nil, err
@@ -13,7 +13,6 @@
 package model
 
 import (
-	"context"
 	"encoding/json"
 
 	"configcenter/src/apimachinery"
@@ -109,7 +108,7 @@ func (g *group) Create() error {
 		return err
 	}
 
-	rsp, err := g.clientSet.CoreService().Model().CreateAttributeGroup(context.Background(), g.params.Header, g.GetObjectID(), metadata.CreateModelAttributeGroup{Data: g.grp})
+	rsp, err := g.clientSet.CoreService().Model().CreateAttributeGroup(g.params.Context, g.params.Header, g.GetObjectID(), metadata.CreateModelAttributeGroup{Data: g.grp})
 	if nil != err {
 		blog.Errorf("[model-grp] failed to request object controller, err: %s", err.Error())
 		return g.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -157,7 +156,7 @@ func (g *group) Update(data mapstr.MapStr) error {
 			},
 		}
 
-		rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroup(context.Background(), g.params.Header, g.GetObjectID(), input)
+		rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroup(g.params.Context, g.params.Header, g.GetObjectID(), input)
 		if nil != err {
 			blog.Errorf("[model-grp]failed to request object controller, err: %s", err.Error())
 			return err
@@ -222,7 +221,7 @@ func (g *group) GetAttributes() ([]AttributeInterface, error) {
 		Field(metadata.AttributeFieldPropertyGroup).Eq(g.grp.GroupID).
 		Field(metadata.AttributeFieldSupplierAccount).Eq(g.params.SupplierAccount)
 
-	rsp, err := g.clientSet.CoreService().Model().ReadModelAttr(context.Background(), g.params.Header, g.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := g.clientSet.CoreService().Model().ReadModelAttr(g.params.Context, g.params.Header, g.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("failed to request the object controller, err: %s", err.Error())
 		return nil, g.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -252,7 +251,7 @@ func (g *group) search(cond condition.Condition) ([]metadata.Group, error) {
 	if nil != g.params.MetaData {
 		cond.Field(metadata.BKMetadata).Eq(*g.params.MetaData)
 	}
-	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroup(context.Background(), g.params.Header, g.GetObjectID(), metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroup(g.params.Context, g.params.Header, g.GetObjectID(), metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("failed to request the object controller, err: %s", err.Error())
 		return nil, err
@@ -109,7 +109,7 @@ func (a *association) SearchObjectAssociation(params types.ContextParams, objID
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
 
-	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: fCond})
+	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return nil, params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -125,7 +125,7 @@ func (a *association) SearchObjectAssociation(params types.ContextParams, objID
 
 func (a *association) SearchInstAssociation(params types.ContextParams, query *metadata.QueryInput) ([]metadata.InstAsst, error) {
 	intput, err := mapstr.NewFromInterface(query.Condition)
-	rsp, err := a.clientSet.CoreService().Association().ReadInstAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: intput})
+	rsp, err := a.clientSet.CoreService().Association().ReadInstAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: intput})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return nil, params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -160,7 +160,7 @@ func (a *association) CreateCommonAssociation(params types.ContextParams, data *
 	cond.Field(common.BKObjIDField).Eq(data.ObjectID)
 	cond.Field(common.AssociationKindIDField).Eq(data.AsstKindID)
 
-	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return nil, params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -188,7 +188,7 @@ func (a *association) CreateCommonAssociation(params types.ContextParams, data *
 	}
 
 	// create a new
-	rspAsst, err := a.clientSet.CoreService().Association().CreateModelAssociation(context.Background(), params.Header, &metadata.CreateModelAssociation{Spec: *data})
+	rspAsst, err := a.clientSet.CoreService().Association().CreateModelAssociation(params.Context, params.Header, &metadata.CreateModelAssociation{Spec: *data})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return nil, params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -204,7 +204,7 @@ func (a *association) CreateCommonAssociation(params types.ContextParams, data *
 
 func (a *association) DeleteInstAssociation(params types.ContextParams, cond condition.Condition) error {
 
-	rsp, err := a.clientSet.CoreService().Association().DeleteInstAssociation(context.Background(), params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Association().DeleteInstAssociation(params.Context, params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -220,7 +220,7 @@ func (a *association) DeleteInstAssociation(params types.ContextParams, cond con
 
 func (a *association) CreateCommonInstAssociation(params types.ContextParams, data *metadata.InstAsst) error {
 	// create a new
-	rspAsst, err := a.clientSet.CoreService().Association().CreateInstAssociation(context.Background(), params.Header, &metadata.CreateOneInstanceAssociation{Data: *data})
+	rspAsst, err := a.clientSet.CoreService().Association().CreateInstAssociation(params.Context, params.Header, &metadata.CreateOneInstanceAssociation{Data: *data})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -239,7 +239,7 @@ func (a *association) DeleteAssociationWithPreCheck(params types.ContextParams,
 	// get the association with id at first.
 	cond := condition.CreateCondition()
 	cond.Field(metadata.AssociationFieldAssociationId).Eq(associationID)
-	result, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	result, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if err != nil {
 		blog.Errorf("[operation-asst] delete association with id[%d], but get this association for pre check failed, err: %v", associationID, err)
 		return params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
@@ -288,7 +288,7 @@ func (a *association) DeleteAssociationWithPreCheck(params types.ContextParams,
 }
 
 func (a *association) DeleteAssociation(params types.ContextParams, cond condition.Condition) error {
-	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("delete object association, but get association with cond[%v] failed, err: %v", cond.ToMapStr(), err)
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -312,7 +312,7 @@ func (a *association) DeleteAssociation(params types.ContextParams, cond conditi
 	}
 
 	// delete the object association
-	result, err := a.clientSet.CoreService().Association().DeleteModelAssociation(context.Background(), params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+	result, err := a.clientSet.CoreService().Association().DeleteModelAssociation(params.Context, params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -343,7 +343,7 @@ func (a *association) UpdateAssociation(params types.ContextParams, data mapstr.
 	cond.Field(metadata.AssociationFieldAssociationId).Eq(assoID)
 	cond.Field(metadata.AssociationFieldSupplierAccount).Eq(params.SupplierAccount)
 
-	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -380,7 +380,7 @@ func (a *association) UpdateAssociation(params types.ContextParams, data mapstr.
 		Condition: condition.CreateCondition().Field(common.BKFieldID).Eq(assoID).ToMapStr(),
 		Data:      data,
 	}
-	rspAsst, err := a.clientSet.CoreService().Association().UpdateModelAssociation(context.Background(), params.Header, &updateopt)
+	rspAsst, err := a.clientSet.CoreService().Association().UpdateModelAssociation(params.Context, params.Header, &updateopt)
 	if nil != err {
 		blog.Errorf("[operation-asst] failed to request object controller, err: %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -404,7 +404,7 @@ func (a *association) CheckBeAssociation(params types.ContextParams, obj model.O
 	if len(exists) > 0 {
 		beAsstObject := []string{}
 		for _, asst := range exists {
-			instRsp, err := a.clientSet.CoreService().Instance().ReadInstance(context.Background(), params.Header, asst.ObjectID,
+			instRsp, err := a.clientSet.CoreService().Instance().ReadInstance(params.Context, params.Header, asst.ObjectID,
 				&metadata.QueryCondition{Condition: mapstr.MapStr{common.BKInstIDField: asst.InstID}})
 			if err != nil {
 				return params.Err.Error(common.CCErrObjectSelectInstFailed)
@@ -440,7 +440,7 @@ func (a *association) SearchObjectAssoWithAssoKindList(params types.ContextParam
 		cond := condition.CreateCondition()
 		cond.Field(common.AssociationKindIDField).Eq(id)
 
-		r, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+		r, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 		if err != nil {
 			blog.Errorf("get object association list with association kind[%s] failed, err: %v", id, err)
 			return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -479,12 +479,12 @@ func (a *association) SearchType(params types.ContextParams, request *metadata.S
 		input.SortArr = append(input.SortArr, metadata.SearchSort{IsDsc: isDesc, Field: key})
 	}
 
-	return a.clientSet.CoreService().Association().ReadAssociation(context.Background(), params.Header, &input)
+	return a.clientSet.CoreService().Association().ReadAssociation(params.Context, params.Header, &input)
 
 }
 
 func (a *association) CreateType(params types.ContextParams, request *metadata.AssociationKind) (resp *metadata.CreateAssociationTypeResult, err error) {
-	rsp, err := a.clientSet.CoreService().Association().CreateAssociation(context.Background(), params.Header, &metadata.CreateAssociationKind{Data: *request})
+	rsp, err := a.clientSet.CoreService().Association().CreateAssociation(params.Context, params.Header, &metadata.CreateAssociationKind{Data: *request})
 	resp = &metadata.CreateAssociationTypeResult{BaseResp: rsp.BaseResp}
 	resp.Data.ID = int64(rsp.Data.Created.ID)
 	return resp, err
@@ -497,7 +497,7 @@ func (a *association) UpdateType(params types.ContextParams, asstTypeID int, req
 		Data:      mapstr.NewFromStruct(request, "json"),
 	}
 
-	rsp, err := a.clientSet.CoreService().Association().UpdateAssociation(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Association().UpdateAssociation(params.Context, params.Header, &input)
 	resp = &metadata.UpdateAssociationTypeResult{BaseResp: rsp.BaseResp}
 	return resp, err
 }
@@ -538,7 +538,7 @@ func (a *association) DeleteType(params types.ContextParams, asstTypeID int) (re
 	// a already used association kind can not be deleted.
 	cond = condition.CreateCondition()
 	cond.Field(common.AssociationKindIDField).Eq(result.Data.Info[0].AssociationKindID)
-	asso, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	asso, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if err != nil {
 		blog.Errorf("delete association kind[%d], but get objects that used this asso kind failed, err: %v", asstTypeID, err)
 		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -555,7 +555,7 @@ func (a *association) DeleteType(params types.ContextParams, asstTypeID int) (re
 	}
 
 	rsp, err := a.clientSet.CoreService().Association().DeleteAssociation(
-		context.Background(), params.Header, &metadata.DeleteOption{
+		params.Context, params.Header, &metadata.DeleteOption{
 			Condition: condition.CreateCondition().Field(common.BKFieldID).Eq(asstTypeID).ToMapStr(),
 		},
 	)
@@ -564,7 +564,7 @@ func (a *association) DeleteType(params types.ContextParams, asstTypeID int) (re
 }
 
 func (a *association) SearchObject(params types.ContextParams, request *metadata.SearchAssociationObjectRequest) (resp *metadata.SearchAssociationObjectResult, err error) {
-	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: request.Condition})
+	rsp, err := a.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: request.Condition})
 
 	resp = &metadata.SearchAssociationObjectResult{BaseResp: rsp.BaseResp, Data: []*metadata.Association{}}
 	for index := range rsp.Data.Info {
@@ -575,7 +575,7 @@ func (a *association) SearchObject(params types.ContextParams, request *metadata
 }
 
 func (a *association) CreateObject(params types.ContextParams, request *metadata.Association) (resp *metadata.CreateAssociationObjectResult, err error) {
-	rsp, err := a.clientSet.CoreService().Association().CreateModelAssociation(context.Background(), params.Header, &metadata.CreateModelAssociation{Spec: *request})
+	rsp, err := a.clientSet.CoreService().Association().CreateModelAssociation(params.Context, params.Header, &metadata.CreateModelAssociation{Spec: *request})
 
 	resp = &metadata.CreateAssociationObjectResult{
 		BaseResp: rsp.BaseResp,
@@ -590,7 +590,7 @@ func (a *association) UpdateObject(params types.ContextParams, asstID int, reque
 		Data:      mapstr.NewFromStruct(request, "json"),
 	}
 
-	rsp, err := a.clientSet.CoreService().Association().UpdateModelAssociation(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Association().UpdateModelAssociation(params.Context, params.Header, &input)
 	resp = &metadata.UpdateAssociationObjectResult{
 		BaseResp: rsp.BaseResp,
 	}
@@ -602,13 +602,13 @@ func (a *association) DeleteObject(params types.ContextParams, asstID int) (resp
 	input := metadata.DeleteOption{
 		Condition: condition.CreateCondition().Field(common.BKFieldID).Eq(asstID).ToMapStr(),
 	}
-	rsp, err := a.clientSet.CoreService().Association().DeleteModelAssociation(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Association().DeleteModelAssociation(params.Context, params.Header, &input)
 	return &metadata.DeleteAssociationObjectResult{BaseResp: rsp.BaseResp}, err
 
 }
 
 func (a *association) SearchInst(params types.ContextParams, request *metadata.SearchAssociationInstRequest) (resp *metadata.SearchAssociationInstResult, err error) {
-	rsp, err := a.clientSet.CoreService().Association().ReadInstAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: request.Condition})
+	rsp, err := a.clientSet.CoreService().Association().ReadInstAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: request.Condition})
 
 	resp = &metadata.SearchAssociationInstResult{BaseResp: rsp.BaseResp, Data: []*metadata.InstAsst{}}
 	for index := range rsp.Data.Info {
@@ -694,7 +694,7 @@ func (a *association) CreateInst(params types.ContextParams, request *metadata.C
 			AssociationKindID: objectAsst.AsstKindID,
 		},
 	}
-	rsp, err := a.clientSet.CoreService().Association().CreateInstAssociation(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Association().CreateInstAssociation(params.Context, params.Header, &input)
 
 	resp = &metadata.CreateAssociationInstResult{BaseResp: rsp.BaseResp}
 	resp.Data.ID = int64(rsp.Data.Created.ID)
@@ -705,7 +705,7 @@ func (a *association) DeleteInst(params types.ContextParams, assoID int64) (resp
 	input := metadata.DeleteOption{
 		Condition: condition.CreateCondition().Field(common.BKFieldID).Eq(assoID).ToMapStr(),
 	}
-	rsp, err := a.clientSet.CoreService().Association().DeleteInstAssociation(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Association().DeleteInstAssociation(params.Context, params.Header, &input)
 	resp = &metadata.DeleteAssociationInstResult{
 		BaseResp: rsp.BaseResp,
 	}
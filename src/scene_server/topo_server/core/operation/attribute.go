@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -129,7 +127,7 @@ func (a *attribute) DeleteObjectAttribute(params types.ContextParams, cond condi
 
 	for _, attrItem := range attrItems {
 		// delete the attribute
-		rsp, err := a.clientSet.CoreService().Model().DeleteModelAttr(context.Background(), params.Header, attrItem.Attribute().ObjectID, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+		rsp, err := a.clientSet.CoreService().Model().DeleteModelAttr(params.Context, params.Header, attrItem.Attribute().ObjectID, &metadata.DeleteOption{Condition: cond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[operation-attr] delete object attribute failed, request object controller with err: %v", err)
 			return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -182,7 +180,7 @@ func (a *attribute) FindObjectAttribute(params types.ContextParams, cond conditi
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
 
-	rsp, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(context.Background(), params.Header, &metadata.QueryCondition{Condition: fCond})
+	rsp, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(params.Context, params.Header, &metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-attr] failed to request object controller, error info is %s", err.Error())
 		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -202,7 +200,7 @@ func (a *attribute) UpdateObjectAttribute(params types.ContextParams, data mapst
 		Data:      data,
 	}
 
-	rsp, err := a.clientSet.CoreService().Model().UpdateModelAttrsByCondition(context.Background(), params.Header, &input)
+	rsp, err := a.clientSet.CoreService().Model().UpdateModelAttrsByCondition(params.Context, params.Header, &input)
 	if nil != err {
 		blog.Errorf("[operation-attr] failed to request object controller, error info is %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -98,7 +96,7 @@ func (a *audit) Query(params types.ContextParams, data mapstr.MapStr) (interface
 		query.Limit = common.BKDefaultLimit
 	}
 
-	rsp, err := a.clientSet.AuditController().GetAuditLog(context.Background(), params.Header, query)
+	rsp, err := a.clientSet.AuditController().GetAuditLog(params.Context, params.Header, query)
 	if nil != err {
 		blog.Errorf("[audit] failed request audit conroller, error info is %s", err.Error())
 		return nil, params.Err.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
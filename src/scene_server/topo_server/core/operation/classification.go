@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -128,7 +126,7 @@ func (c *classification) DeleteClassification(params types.ContextParams, id int
 		}
 	}
 
-	rsp, err := c.clientSet.CoreService().Model().DeleteModelClassification(context.Background(), params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+	rsp, err := c.clientSet.CoreService().Model().DeleteModelClassification(params.Context, params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-cls]failed to request the object controller, error info is %s", err.Error())
 		return err
@@ -151,7 +149,7 @@ func (c *classification) FindClassificationWithObjects(params types.ContextParam
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
 
-	rsp, err := c.clientSet.CoreService().Model().ReadModelClassification(context.Background(), params.Header, &metadata.QueryCondition{Condition: fCond})
+	rsp, err := c.clientSet.CoreService().Model().ReadModelClassification(params.Context, params.Header, &metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-cls]failed to request the object controller, error info is %s", err.Error())
 		return nil, err
@@ -170,7 +168,7 @@ func (c *classification) FindClassificationWithObjects(params types.ContextParam
 			AsstObjects:    map[string][]metadata.Object{},
 		}
 		queryObjectCond := condition.CreateCondition().Field(common.BKClassificationIDField).Eq(cls.ClassificationID)
-		queryObjectResp, err := c.clientSet.CoreService().Model().ReadModel(context.Background(), params.Header, &metadata.QueryCondition{Condition: queryObjectCond.ToMapStr()})
+		queryObjectResp, err := c.clientSet.CoreService().Model().ReadModel(params.Context, params.Header, &metadata.QueryCondition{Condition: queryObjectCond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[operation-cls]failed to request the object controller, error info is %s", err.Error())
 			return nil, err
@@ -224,7 +222,7 @@ func (c *classification) FindClassification(params types.ContextParams, cond con
 	} else {
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
-	rsp, err := c.clientSet.CoreService().Model().ReadModelClassification(context.Background(), params.Header, &metadata.QueryCondition{Condition: fCond})
+	rsp, err := c.clientSet.CoreService().Model().ReadModelClassification(params.Context, params.Header, &metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-cls]failed to request the object controller, error info is %s", err.Error())
 		return nil, err
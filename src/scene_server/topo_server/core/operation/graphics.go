@@ -13,7 +13,6 @@
 package operation
 
 import (
-	"context"
 	"strconv"
 
 	"configcenter/src/apimachinery"
@@ -54,7 +53,7 @@ func (g *graphics) SelectObjectTopoGraphics(params types.ContextParams, scopeTyp
 	if nil != params.MetaData {
 		graphcondition.SetMetaData(*params.MetaData)
 	}
-	rsp, err := g.clientSet.ObjectController().Meta().SearchTopoGraphics(context.Background(), params.Header, graphcondition)
+	rsp, err := g.clientSet.ObjectController().Meta().SearchTopoGraphics(params.Context, params.Header, graphcondition)
 	if nil != err {
 		return nil, err
 	}
@@ -162,7 +161,7 @@ func (g *graphics) UpdateObjectTopoGraphics(params types.ContextParams, scopeTyp
 			datas[index].SetMetaData(*params.MetaData)
 		}
 	}
-	rsp, err := g.clientSet.ObjectController().Meta().UpdateTopoGraphics(context.Background(), params.Header, datas)
+	rsp, err := g.clientSet.ObjectController().Meta().UpdateTopoGraphics(params.Context, params.Header, datas)
 	if err != nil {
 		blog.Errorf("UpdateGraphics failed %v", err.Error())
 		return params.Err.New(common.CCErrTopoGraphicsUpdateFailed, err.Error())
@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -86,7 +84,7 @@ func (g *group) CreateObjectGroup(params types.ContextParams, data mapstr.MapStr
 
 func (g *group) DeleteObjectGroup(params types.ContextParams, groupID int64) error {
 	cond := condition.CreateCondition().Field(common.BKFieldID).Eq(groupID)
-	rsp, err := g.clientSet.CoreService().Model().DeleteAttributeGroupByCondition(context.Background(), params.Header, metadata.DeleteOption{Condition: cond.ToMapStr()})
+	rsp, err := g.clientSet.CoreService().Model().DeleteAttributeGroupByCondition(params.Context, params.Header, metadata.DeleteOption{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-grp]failed to request object controller, error info is %s", err.Error())
 		return err
@@ -108,7 +106,7 @@ func (g *group) FindObjectGroup(params types.ContextParams, cond condition.Condi
 	} else {
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
-	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroupByCondition(context.Background(), params.Header, metadata.QueryCondition{Condition: fCond})
+	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroupByCondition(params.Context, params.Header, metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-grp] failed to request the object controller, error info is %s", err.Error())
 		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -132,7 +130,7 @@ func (g *group) FindGroupByObject(params types.ContextParams, objID string, cond
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
 
-	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroup(context.Background(), params.Header, objID, metadata.QueryCondition{Condition: fCond})
+	rsp, err := g.clientSet.CoreService().Model().ReadAttributeGroup(params.Context, params.Header, objID, metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-grp] failed to request the object controller, error info is %s", err.Error())
 		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -153,7 +151,7 @@ func (g *group) UpdateObjectAttributeGroup(params types.ContextParams, conds []m
 			Data:      mapstr.NewFromStruct(cond.Data, "json"),
 		}
 
-		rsp, err := g.clientSet.CoreService().Model().UpdateModelAttrsByCondition(context.Background(), params.Header, &input)
+		rsp, err := g.clientSet.CoreService().Model().UpdateModelAttrsByCondition(params.Context, params.Header, &input)
 		if nil != err {
 			blog.Errorf("[operation-grp] failed to set the group  by the condition (%#v), error info is %s ", cond, err.Error())
 			return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -180,7 +178,7 @@ func (g *group) DeleteObjectAttributeGroup(params types.ContextParams, objID, pr
 		},
 	}
 
-	rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroup(context.Background(), params.Header, objID, input)
+	rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroup(params.Context, params.Header, objID, input)
 	if nil != err {
 		blog.Errorf("[operation-grp] failed to set the group , error info is %s ", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -200,7 +198,7 @@ func (g *group) UpdateObjectGroup(params types.ContextParams, cond *metadata.Upd
 		Condition: mapstr.NewFromStruct(cond.Condition, "json"),
 		Data:      mapstr.NewFromStruct(cond.Data, "json"),
 	}
-	rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroupByCondition(context.Background(), params.Header, input)
+	rsp, err := g.clientSet.CoreService().Model().UpdateAttributeGroupByCondition(params.Context, params.Header, input)
 	if nil != err {
 		blog.Errorf("[operation-grp] failed to set the group to the new data (%#v) by the condition (%#v), error info is %s ", cond.Data, cond.Condition, err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -35,7 +33,7 @@ type identifier struct {
 }
 
 func (g *identifier) SearchIdentifier(params types.ContextParams, objType string, param *metadata.SearchIdentifierParam) (*metadata.SearchHostIdentifierResult, error) {
-	rsp, err := g.clientSet.ObjectController().Identifier().SearchIdentifier(context.Background(), params.Header, objType, param)
+	rsp, err := g.clientSet.ObjectController().Identifier().SearchIdentifier(params.Context, params.Header, objType, param)
 	if nil != err {
 		return nil, err
 	}
@@ -221,7 +221,7 @@ func (ia *importAssociation) getAssociationObjProperty() error {
 	cond.Field(common.BKObjIDField).In(objIDArr)
 	cond.Field(common.BKIsOnlyField).Eq(true)
 
-	rsp, err := ia.cli.clientSet.CoreService().Model().ReadModelAttrByCondition(context.Background(), ia.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := ia.cli.clientSet.CoreService().Model().ReadModelAttrByCondition(ia.params.Context, ia.params.Header, &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[getAssociationInfo] failed to  search attribute , error info is %s, input:%+v, rid:%s", err.Error(), cond, ia.rid)
 		return ia.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -13,7 +13,6 @@
 package operation
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -65,6 +64,8 @@ type BatchResult struct {
 	Errors       []string `json:"error"`
 	Success      []string `json:"success"`
 	UpdateErrors []string `json:"update_error"`
+	// Preview the preview of the rows, set when validate only or the all or nothing import is rejected
+	Preview *metadata.ImportPreview `json:"preview,omitempty"`
 }
 
 type commonInst struct {
@@ -123,7 +124,25 @@ func (c *commonInst) CreateInstBatch(params types.ContextParams, obj model.Objec
 		instNameMap[name] = struct{}{}
 	}
 
+	if batchInfo.ValidateOnly || batchInfo.AllOrNothing {
+		preview, err := c.previewInstBatch(params, obj, batchInfo)
+		if nil != err {
+			blog.Errorf("create object[%s] instance batch, but preview failed, err: %s", object.ObjectID, err.Error())
+			return nil, err
+		}
+		if batchInfo.ValidateOnly || 0 != preview.Error {
+			results.Preview = preview
+			results.Errors = append(results.Errors, preview.ErrorMessages()...)
+			return results, nil
+		}
+	}
+
+	// the rows written in the all or nothing mode, they are rolled back if any row fails
+	written := make([]writtenInst, 0)
 	for colIdx, colInput := range *batchInfo.BatchInfo {
+		if batchInfo.AllOrNothing && 0 != len(results.Errors) {
+			break
+		}
 		if colInput == nil {
 			// this is a empty excel line.
 			continue
@@ -168,6 +187,17 @@ func (c *commonInst) CreateInstBatch(params types.ContextParams, obj model.Objec
 			}
 		}
 
+		var row writtenInst
+		if batchInfo.AllOrNothing {
+			origin, err := item.GetExisting()
+			if nil != err {
+				blog.Errorf("[operation-inst] failed to get the existing object(%s) inst (%#v), err: %s", object.ObjectID, colInput, err.Error())
+				results.Errors = append(results.Errors, params.Lang.Languagef("import_row_int_error_str", colIdx, err.Error()))
+				continue
+			}
+			row = writtenInst{row: colIdx, item: item, origin: origin, fields: mapstr.NewFromMap(colInput).Clone()}
+		}
+
 		// set data
 		err := item.Save(colInput)
 		if nil != err {
@@ -176,9 +206,24 @@ func (c *commonInst) CreateInstBatch(params types.ContextParams, obj model.Objec
 			continue
 		}
 		results.Success = append(results.Success, strconv.FormatInt(colIdx, 10))
+		if batchInfo.AllOrNothing {
+			written = append(written, row)
+			continue
+		}
 		NewSupplementary().Audit(params, c.clientSet, item.GetObject(), c).CommitCreateLog(nil, nil, item)
 	}
 
+	if batchInfo.AllOrNothing {
+		if 0 != len(results.Errors) {
+			results.Errors = append(results.Errors, c.rollbackInstBatch(params, obj, written)...)
+			results.Success = nil
+			return results, nil
+		}
+		for _, row := range written {
+			NewSupplementary().Audit(params, c.clientSet, row.item.GetObject(), c).CommitCreateLog(nil, nil, row.item)
+		}
+	}
+
 	return results, nil
 }
 
@@ -194,7 +239,7 @@ func (c *commonInst) isValidInstID(params types.ContextParams, obj metadata.Obje
 	query.Condition = cond.ToMapStr()
 	query.Limit = common.BKNoLimit
 
-	rsp, err := c.clientSet.CoreService().Instance().ReadInstance(context.Background(), params.Header, obj.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
+	rsp, err := c.clientSet.CoreService().Instance().ReadInstance(params.Context, params.Header, obj.GetObjectID(), &metadata.QueryCondition{Condition: cond.ToMapStr()})
 	if nil != err {
 		blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -238,7 +283,7 @@ func (c *commonInst) innerHasHost(params types.ContextParams, moduleIDS []int64)
 		common.BKModuleIDField: moduleIDS,
 	}
 
-	rsp, err := c.clientSet.HostController().Module().GetModulesHostConfig(context.Background(), params.Header, cond)
+	rsp, err := c.clientSet.HostController().Module().GetModulesHostConfig(params.Context, params.Header, cond)
 	if nil != err {
 		blog.Errorf("[operation-module] failed to request the object controller, err: %s", err.Error())
 		return false, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -352,7 +397,7 @@ func (c *commonInst) DeleteInstByInstID(params types.ContextParams, obj model.Ob
 			delCond.Field(common.BKObjIDField).Eq(object.ObjectID)
 		}
 		// clear association
-		rsp, err := c.clientSet.CoreService().Instance().DeleteInstance(context.Background(), params.Header, obj.GetObjectID(), &metadata.DeleteOption{Condition: delCond.ToMapStr()})
+		rsp, err := c.clientSet.CoreService().Instance().DeleteInstance(params.Context, params.Header, obj.GetObjectID(), &metadata.DeleteOption{Condition: delCond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
 			return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -426,7 +471,7 @@ func (c *commonInst) convertInstIDIntoStruct(params types.ContextParams, asstObj
 
 	query := &metadata.QueryCondition{}
 	query.Condition = cond.ToMapStr()
-	rsp, err := c.clientSet.CoreService().Instance().ReadInstance(context.Background(), params.Header, obj.GetObjectID(), query)
+	rsp, err := c.clientSet.CoreService().Instance().ReadInstance(params.Context, params.Header, obj.GetObjectID(), query)
 
 	if nil != err {
 		blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
@@ -821,7 +866,7 @@ func (c *commonInst) FindInstByAssociationInst(params types.ContextParams, obj m
 func (c *commonInst) FindOriginInst(params types.ContextParams, obj model.Object, cond *metadata.QueryInput) (*metadata.InstResult, error) {
 	switch obj.Object().ObjectID {
 	case common.BKInnerObjIDHost:
-		rsp, err := c.clientSet.HostController().Host().GetHosts(context.Background(), params.Header, cond)
+		rsp, err := c.clientSet.HostController().Host().GetHosts(params.Context, params.Header, cond)
 		if nil != err {
 			blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
 			return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -837,8 +882,17 @@ func (c *commonInst) FindOriginInst(params types.ContextParams, obj model.Object
 
 	default:
 		queryCond, err := mapstr.NewFromInterface(cond.Condition)
-		input := &metadata.QueryCondition{Condition: queryCond}
-		rsp, err := c.clientSet.CoreService().Instance().ReadInstance(context.Background(), params.Header, obj.GetObjectID(), input)
+		input := &metadata.QueryCondition{
+			Condition: queryCond,
+			Limit:     metadata.SearchLimit{Offset: int64(cond.Start), Limit: int64(cond.Limit)},
+		}
+		if "" != cond.Sort {
+			input.SortArr = metadata.NewSearchSortParse().String(cond.Sort).ToSearchSortArr()
+		}
+		if "" != cond.Fields {
+			input.Fields = strings.Split(cond.Fields, ",")
+		}
+		rsp, err := c.clientSet.CoreService().Instance().ReadInstance(params.Context, params.Header, obj.GetObjectID(), input)
 		if nil != err {
 			blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
 			return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -890,7 +944,7 @@ func (c *commonInst) UpdateInst(params types.ContextParams, data mapstr.MapStr,
 	}
 	blog.Infof("aaaaaaaaaaaaaaaa %#v", inputParams)
 	preAuditLog := NewSupplementary().Audit(params, c.clientSet, obj, c).CreateSnapshot(-1, fCond)
-	rsp, err := c.clientSet.CoreService().Instance().UpdateInstance(context.Background(), params.Header, obj.GetObjectID(), &inputParams)
+	rsp, err := c.clientSet.CoreService().Instance().UpdateInstance(params.Context, params.Header, obj.GetObjectID(), &inputParams)
 	if nil != err {
 		blog.Errorf("[operation-inst] failed to request object controller, err: %s", err.Error())
 		return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -13,7 +13,6 @@
 package operation
 
 import (
-	"context"
 	"strings"
 
 	"configcenter/src/apimachinery"
@@ -75,7 +74,7 @@ func (b *business) CreateBusiness(params types.ContextParams, obj model.Object,
 		defaultOwnerHeader := util.CopyHeader(params.Header)
 		defaultOwnerHeader.Set(common.BKHTTPOwnerID, common.BKDefaultOwnerID)
 
-		asstRsp, err := b.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), defaultOwnerHeader, &metadata.QueryCondition{Condition: asstQuery})
+		asstRsp, err := b.clientSet.CoreService().Association().ReadModelAssociation(params.Context, defaultOwnerHeader, &metadata.QueryCondition{Condition: asstQuery})
 		if nil != err {
 			blog.Errorf("[operation-biz] failed to get default assts, error info is %s", err.Error())
 			return nil, params.Err.New(common.CCErrTopoAppCreateFailed, err.Error())
@@ -86,7 +85,7 @@ func (b *business) CreateBusiness(params types.ContextParams, obj model.Object,
 		expectAssts := asstRsp.Data.Info
 		blog.Infof("copy asst for %s, %+v", params.SupplierAccount, expectAssts)
 
-		existAsstRsp, err := b.clientSet.CoreService().Association().ReadModelAssociation(context.Background(), params.Header, &metadata.QueryCondition{Condition: asstQuery})
+		existAsstRsp, err := b.clientSet.CoreService().Association().ReadModelAssociation(params.Context, params.Header, &metadata.QueryCondition{Condition: asstQuery})
 		if nil != err {
 			blog.Errorf("[operation-biz] failed to get default assts, error info is %s", err.Error())
 			return nil, params.Err.New(common.CCErrTopoAppCreateFailed, err.Error())
@@ -111,9 +110,9 @@ func (b *business) CreateBusiness(params types.ContextParams, obj model.Object,
 			var err error
 			if asst.AsstKindID == common.AssociationKindMainline {
 				// bk_maineline is a inner association type that can only create in special case, so we separate bk_mainline association type creation with a independent method,
-				createAsstRsp, err = b.clientSet.CoreService().Association().CreateMainlineModelAssociation(context.Background(), params.Header, &metadata.CreateModelAssociation{Spec: asst})
+				createAsstRsp, err = b.clientSet.CoreService().Association().CreateMainlineModelAssociation(params.Context, params.Header, &metadata.CreateModelAssociation{Spec: asst})
 			} else {
-				createAsstRsp, err = b.clientSet.CoreService().Association().CreateModelAssociation(context.Background(), params.Header, &metadata.CreateModelAssociation{Spec: asst})
+				createAsstRsp, err = b.clientSet.CoreService().Association().CreateModelAssociation(params.Context, params.Header, &metadata.CreateModelAssociation{Spec: asst})
 			}
 			if nil != err {
 				blog.Errorf("[operation-biz] failed to copy default assts, error info is %s", err.Error())
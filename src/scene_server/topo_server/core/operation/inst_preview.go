@@ -0,0 +1,214 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operation
+
+import (
+	"sort"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/condition"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/topo_server/core/inst"
+	"configcenter/src/scene_server/topo_server/core/model"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// previewIgnoreFields the fields which are not compared when preview the import
+var previewIgnoreFields = []string{
+	"import_from",
+	common.BKObjIDField,
+	common.BKOwnerIDField,
+	common.BKDefaultField,
+	common.CreateTimeField,
+	common.LastTimeField,
+	metadata.BKMetadata,
+}
+
+// previewInstBatch classify every row of the batch as create, update, unchanged or error without writing anything
+func (c *commonInst) previewInstBatch(params types.ContextParams, obj model.Object, batchInfo *InstBatchInfo) (*metadata.ImportPreview, error) {
+	object := obj.Object()
+	attrs, err := obj.GetAttributesExceptInnerFields()
+	if nil != err {
+		blog.Errorf("[operation-inst] failed to get the object(%s) attributes, err: %s", object.ObjectID, err.Error())
+		return nil, err
+	}
+	onlyFields := make([]string, 0)
+	for _, attr := range attrs {
+		if attr.Attribute().IsOnly && attr.Attribute().PropertyID != obj.GetInstNameFieldName() {
+			onlyFields = append(onlyFields, attr.Attribute().PropertyID)
+		}
+	}
+
+	rowIdxs := make([]int64, 0, len(*batchInfo.BatchInfo))
+	for rowIdx := range *batchInfo.BatchInfo {
+		rowIdxs = append(rowIdxs, rowIdx)
+	}
+	sort.Slice(rowIdxs, func(i, j int) bool { return rowIdxs[i] < rowIdxs[j] })
+
+	ignoreFields := append([]string{obj.GetInstIDFieldName()}, previewIgnoreFields...)
+	preview := &metadata.ImportPreview{}
+	onlyValues := make(map[string]int64)
+	validItems := make([]metadata.ValidateModelInstance, 0)
+	validRows := make([]int64, 0)
+	for _, rowIdx := range rowIdxs {
+		colInput := (*batchInfo.BatchInfo)[rowIdx]
+		if colInput == nil {
+			// this is a empty excel line.
+			continue
+		}
+
+		data := mapstr.NewFromMap(colInput).Clone()
+		delete(data, "import_from")
+		row := metadata.ImportPreviewRow{Row: rowIdx}
+
+		// the unique fields should not be duplicated in the same file
+		if 0 != len(onlyFields) {
+			values := make([]string, 0, len(onlyFields))
+			for _, field := range onlyFields {
+				values = append(values, util.GetStrByInterface(data[field]))
+			}
+			key := strings.Join(values, "\x00")
+			if _, ok := onlyValues[key]; ok {
+				row.Action = metadata.ImportActionError
+				row.Error = params.Lang.Languagef("import_row_int_error_str", rowIdx, params.Err.Errorf(common.CCErrCommDuplicateItem, strings.Join(onlyFields, ",")).Error())
+				preview.Add(row)
+				continue
+			}
+			onlyValues[key] = rowIdx
+		}
+
+		item := c.instFactory.CreateInst(params, obj)
+		item.SetValues(data.Clone())
+		origin, err := item.GetExisting()
+		if nil != err {
+			row.Action = metadata.ImportActionError
+			row.Error = params.Lang.Languagef("import_row_int_error_str", rowIdx, err.Error())
+			preview.Add(row)
+			continue
+		}
+
+		validItem := metadata.ValidateModelInstance{Data: data}
+		if nil == origin {
+			row.Action = metadata.ImportActionCreate
+			row.Diff = metadata.DiffImportData(nil, data, ignoreFields...)
+			if obj.IsCommon() {
+				validItem.Data = data.Clone()
+				validItem.Data.Set(common.BKObjIDField, object.ObjectID)
+			}
+		} else {
+			row.InstID, _ = util.GetInt64ByInterface(origin[obj.GetInstIDFieldName()])
+			row.Diff = metadata.DiffImportData(origin, data, ignoreFields...)
+			if 0 == len(row.Diff) {
+				row.Action = metadata.ImportActionUnchanged
+				preview.Add(row)
+				continue
+			}
+			row.Action = metadata.ImportActionUpdate
+			validItem.InstID = uint64(row.InstID)
+		}
+		preview.Add(row)
+		validItems = append(validItems, validItem)
+		validRows = append(validRows, rowIdx)
+	}
+
+	if 0 == len(validItems) {
+		return preview, nil
+	}
+
+	rsp, err := c.clientSet.CoreService().Instance().ValidateManyInstance(params.Context, params.Header, object.ObjectID, &metadata.ValidateManyModelInstance{Datas: validItems})
+	if nil != err {
+		blog.Errorf("[operation-inst] failed to validate the object(%s) instances, err: %s", object.ObjectID, err.Error())
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[operation-inst] failed to validate the object(%s) instances, err: %s", object.ObjectID, rsp.ErrMsg)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	for _, exception := range rsp.Data.Exceptions {
+		if exception.OriginIndex < 0 || int(exception.OriginIndex) >= len(validRows) {
+			continue
+		}
+		rowIdx := validRows[exception.OriginIndex]
+		preview.SetError(rowIdx, params.Lang.Languagef("import_row_int_error_str", rowIdx, exception.Message))
+	}
+
+	return preview, nil
+}
+
+// writtenInst a row written by the all or nothing import, the origin is nil if the inst is created by the row
+type writtenInst struct {
+	row    int64
+	item   inst.Inst
+	origin mapstr.MapStr
+	fields mapstr.MapStr
+}
+
+// rollbackInstBatch roll back the written rows of the all or nothing import in the reverse order,
+// the created insts are deleted and the updated insts are restored to their origin values.
+// it returns the error messages of the rows which can not be rolled back.
+func (c *commonInst) rollbackInstBatch(params types.ContextParams, obj model.Object, written []writtenInst) []string {
+	object := obj.Object()
+	errs := make([]string, 0)
+	for idx := len(written) - 1; idx >= 0; idx-- {
+		row := written[idx]
+		instID, err := row.item.GetInstID()
+		if nil != err {
+			blog.Errorf("[operation-inst] failed to roll back the object(%s) inst of the row(%d), err: %s", object.ObjectID, row.row, err.Error())
+			errs = append(errs, params.Lang.Languagef("import_row_int_error_str", row.row, err.Error()))
+			continue
+		}
+
+		cond := condition.CreateCondition()
+		cond.Field(obj.GetInstIDFieldName()).Eq(instID)
+		if obj.IsCommon() {
+			cond.Field(common.BKObjIDField).Eq(object.ObjectID)
+		}
+
+		var rsp metadata.BaseResp
+		if nil == row.origin {
+			var deleted *metadata.DeletedOptionResult
+			deleted, err = c.clientSet.CoreService().Instance().DeleteInstance(params.Context, params.Header, object.ObjectID, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+			if nil == err {
+				rsp = deleted.BaseResp
+			}
+		} else {
+			// restore the fields written by the row, the fields which did not exist are cleared
+			data := mapstr.New()
+			for field := range row.fields {
+				if field == obj.GetInstIDFieldName() || field == common.BKObjIDField || field == common.BKOwnerIDField {
+					continue
+				}
+				data[field] = row.origin[field]
+			}
+			var updated *metadata.UpdatedOptionResult
+			updated, err = c.clientSet.CoreService().Instance().UpdateInstance(params.Context, params.Header, object.ObjectID, &metadata.UpdateOption{Data: data, Condition: cond.ToMapStr()})
+			if nil == err {
+				rsp = updated.BaseResp
+			}
+		}
+		if nil != err {
+			blog.Errorf("[operation-inst] failed to roll back the object(%s) inst(%d), err: %s", object.ObjectID, instID, err.Error())
+			errs = append(errs, params.Lang.Languagef("import_row_int_error_str", row.row, params.Err.Error(common.CCErrCommHTTPDoRequestFailed).Error()))
+			continue
+		}
+		if !rsp.Result {
+			blog.Errorf("[operation-inst] failed to roll back the object(%s) inst(%d), err: %s", object.ObjectID, instID, rsp.ErrMsg)
+			errs = append(errs, params.Lang.Languagef("import_row_int_error_str", row.row, rsp.ErrMsg))
+		}
+	}
+	return errs
+}
@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -62,7 +60,7 @@ func (s *set) hasHost(params types.ContextParams, bizID int64, setIDS []int64) (
 		common.BKSetIDField: setIDS,
 	}
 
-	rsp, err := s.clientSet.HostController().Module().GetModulesHostConfig(context.Background(), params.Header, cond)
+	rsp, err := s.clientSet.HostController().Module().GetModulesHostConfig(params.Context, params.Header, cond)
 	if nil != err {
 		blog.Errorf("[operation-set] failed to request the object controller, error info is %s", err.Error())
 		return false, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -13,7 +13,6 @@
 package operation
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -543,7 +542,7 @@ func (o *object) DeleteObject(params types.ContextParams, id int64, cond conditi
 			}
 		}
 
-		rsp, err := o.clientSet.CoreService().Model().DeleteModel(context.Background(), params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
+		rsp, err := o.clientSet.CoreService().Model().DeleteModel(params.Context, params.Header, &metadata.DeleteOption{Condition: cond.ToMapStr()})
 		if nil != err {
 			blog.Errorf("[operation-obj] failed to request the object controller, err: %s", err.Error())
 			return params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -674,7 +673,7 @@ func (o *object) FindObject(params types.ContextParams, cond condition.Condition
 	} else {
 		fCond.Merge(metadata.BizLabelNotExist)
 	}
-	rsp, err := o.clientSet.CoreService().Model().ReadModel(context.Background(), params.Header, &metadata.QueryCondition{Condition: fCond})
+	rsp, err := o.clientSet.CoreService().Model().ReadModel(params.Context, params.Header, &metadata.QueryCondition{Condition: fCond})
 	if nil != err {
 		blog.Errorf("[operation-obj] failed to request the object controller, err: %s", err.Error())
 		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
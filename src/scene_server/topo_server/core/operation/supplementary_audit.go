@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/auditoplog"
@@ -148,7 +146,7 @@ func (a *auditLog) commitSnapshot(preData, currData *WrapperResult, action audit
 		switch a.obj.GetObjectType() {
 		default:
 
-			rsp, err := a.client.AuditController().AddObjectLog(context.Background(), a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
+			rsp, err := a.client.AuditController().AddObjectLog(a.params.Context, a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
 			if nil != err {
 				blog.Errorf("[audit] failed to add audit log, error info is %s", err.Error())
 				return
@@ -158,7 +156,7 @@ func (a *auditLog) commitSnapshot(preData, currData *WrapperResult, action audit
 				return
 			}
 		case common.BKInnerObjIDApp, common.BKInnerObjIDObject:
-			rsp, err := a.client.AuditController().AddObjectLog(context.Background(), a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
+			rsp, err := a.client.AuditController().AddObjectLog(a.params.Context, a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
 			if nil != err {
 				blog.Errorf("[audit] failed to add audit log, error info is %s", err.Error())
 				return
@@ -168,7 +166,7 @@ func (a *auditLog) commitSnapshot(preData, currData *WrapperResult, action audit
 				return
 			}
 		case common.BKInnerObjIDModule:
-			rsp, err := a.client.AuditController().AddModuleLog(context.Background(), a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
+			rsp, err := a.client.AuditController().AddModuleLog(a.params.Context, a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
 			if nil != err {
 				blog.Errorf("[audit] failed to add audit log, error info is %s", err.Error())
 				return
@@ -178,7 +176,7 @@ func (a *auditLog) commitSnapshot(preData, currData *WrapperResult, action audit
 				return
 			}
 		case common.BKInnerObjIDSet:
-			rsp, err := a.client.AuditController().AddSetLog(context.Background(), a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
+			rsp, err := a.client.AuditController().AddSetLog(a.params.Context, a.params.SupplierAccount, bizID, a.params.User, a.params.Header, data)
 			if nil != err {
 				blog.Errorf("[audit] failed to add audit log, error info is %s", err.Error())
 				return
@@ -45,6 +45,11 @@ type InstBatchInfo struct {
 	// map[rownumber]map[property_id][date]
 	BatchInfo *map[int64]map[string]interface{} `json:"BatchInfo"`
 	InputType string                            `json:"input_type"`
+	// ValidateOnly only return the preview of the import, nothing is written
+	ValidateOnly bool `json:"validate_only"`
+	// AllOrNothing validate all the rows before writing, nothing is written if any row is invalid,
+	// and the written rows are rolled back if any row fails while writing.
+	AllOrNothing bool `json:"all_or_nothing"`
 }
 
 // ConditionItem subcondition
@@ -13,8 +13,6 @@
 package operation
 
 import (
-	"context"
-
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -52,7 +50,7 @@ func (a *unique) Create(params types.ContextParams, objectID string, request *me
 	if nil != params.MetaData {
 		unique.Metadata = *params.MetaData
 	}
-	resp, err := a.clientSet.CoreService().Model().CreateModelAttrUnique(context.Background(), params.Header, objectID, metadata.CreateModelAttrUnique{Data: unique})
+	resp, err := a.clientSet.CoreService().Model().CreateModelAttrUnique(params.Context, params.Header, objectID, metadata.CreateModelAttrUnique{Data: unique})
 	if err != nil {
 		blog.Errorf("[UniqueOperation] create for %s, %#v failed %v", objectID, request, err)
 		return nil, params.Err.Error(common.CCErrTopoObjectUniqueCreateFailed)
@@ -67,7 +65,7 @@ func (a *unique) Update(params types.ContextParams, objectID string, id uint64,
 	update := metadata.UpdateModelAttrUnique{
 		Data: *request,
 	}
-	resp, err := a.clientSet.CoreService().Model().UpdateModelAttrUnique(context.Background(), params.Header, objectID, id, update)
+	resp, err := a.clientSet.CoreService().Model().UpdateModelAttrUnique(params.Context, params.Header, objectID, id, update)
 	if err != nil {
 		blog.Errorf("[UniqueOperation] update for %s, %d, %#v failed %v", objectID, id, request, err)
 		return params.Err.Error(common.CCErrTopoObjectUniqueUpdateFailed)
@@ -79,7 +77,7 @@ func (a *unique) Update(params types.ContextParams, objectID string, id uint64,
 }
 
 func (a *unique) Delete(params types.ContextParams, objectID string, id uint64) (err error) {
-	resp, err := a.clientSet.CoreService().Model().DeleteModelAttrUnique(context.Background(), params.Header, objectID, id)
+	resp, err := a.clientSet.CoreService().Model().DeleteModelAttrUnique(params.Context, params.Header, objectID, id)
 	if err != nil {
 		blog.Errorf("[UniqueOperation] delete for %s, %d failed %v", objectID, id, err)
 		return params.Err.Error(common.CCErrTopoObjectUniqueDeleteFailed)
@@ -102,7 +100,7 @@ func (a *unique) Search(params types.ContextParams, objectID string) (objectUniq
 	cond := metadata.QueryCondition{
 		Condition: fCond,
 	}
-	resp, err := a.clientSet.CoreService().Model().ReadModelAttrUnique(context.Background(), params.Header, cond)
+	resp, err := a.clientSet.CoreService().Model().ReadModelAttrUnique(params.Context, params.Header, cond)
 	if err != nil {
 		blog.Errorf("[UniqueOperation] search for %s, failed %v", objectID, err)
 		return nil, params.Err.Error(common.CCErrTopoObjectUniqueSearchFailed)
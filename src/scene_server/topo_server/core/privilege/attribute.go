@@ -0,0 +1,107 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package privilege
+
+import (
+	"fmt"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// attributePrivilegeRank the rank of the attribute privileges, the higher the more permissive
+var attributePrivilegeRank = map[string]int{
+	metadata.AttributePrivilegeMask:  0,
+	metadata.AttributePrivilegeRead:  1,
+	metadata.AttributePrivilegeWrite: 2,
+}
+
+// ParseAttributeConfig parse and validate the attribute privileges of a user group
+func ParseAttributeConfig(data mapstr.MapStr) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string)
+	for objID, val := range data {
+		properties, err := mapstr.NewFromInterface(val)
+		if nil != err {
+			return nil, fmt.Errorf("the attribute privileges of the object(%s) is not a map", objID)
+		}
+		result[objID] = make(map[string]string)
+		for propertyID, privi := range properties {
+			level, ok := privi.(string)
+			if _, exists := attributePrivilegeRank[level]; !ok || !exists {
+				return nil, fmt.Errorf("the privilege(%v) of the attribute(%s.%s) is invalid", privi, objID, propertyID)
+			}
+			result[objID][propertyID] = level
+		}
+	}
+	return result, nil
+}
+
+// MergeAttributePermission merge the privileges of the user groups which the user belongs to.
+// the privileges are additive: only the groups which grant the object or configure its attributes are considered,
+// an attribute is restricted only if all of them restrict it, and the most permissive privilege wins.
+func MergeAttributePermission(privileges []metadata.Privilege) metadata.AttributePermission {
+	// collect the object ids referred by each group
+	objects := make(map[string]bool)
+	for _, privi := range privileges {
+		for objID := range privi.AttributeConfig {
+			objects[objID] = true
+		}
+	}
+
+	result := make(metadata.AttributePermission)
+	for objID := range objects {
+		var merged map[string]string
+		for _, privi := range privileges {
+			config, configured := privi.AttributeConfig[objID]
+			if !configured && !grantsObject(privi, objID) {
+				continue
+			}
+			if nil == merged {
+				merged = make(map[string]string)
+				for propertyID, level := range config {
+					merged[propertyID] = level
+				}
+				continue
+			}
+			for propertyID, level := range merged {
+				other, ok := config[propertyID]
+				if !ok {
+					delete(merged, propertyID)
+					continue
+				}
+				if attributePrivilegeRank[other] > attributePrivilegeRank[level] {
+					merged[propertyID] = other
+				}
+			}
+		}
+
+		for propertyID, level := range merged {
+			if metadata.AttributePrivilegeWrite == level {
+				delete(merged, propertyID)
+			}
+		}
+		if 0 != len(merged) {
+			result[objID] = merged
+		}
+	}
+	return result
+}
+
+func grantsObject(privi metadata.Privilege, objID string) bool {
+	for _, objects := range privi.ModelConfig {
+		if _, ok := objects[objID]; ok {
+			return true
+		}
+	}
+	return false
+}
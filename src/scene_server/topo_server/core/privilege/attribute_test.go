@@ -0,0 +1,79 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package privilege
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+func TestParseAttributeConfig(t *testing.T) {
+	config, err := ParseAttributeConfig(mapstr.MapStr{
+		"host": map[string]interface{}{"bk_comment": "read", "operator": "mask"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]string{"host": {"bk_comment": "read", "operator": "mask"}}, config)
+
+	_, err = ParseAttributeConfig(mapstr.MapStr{"host": map[string]interface{}{"operator": "none"}})
+	require.Error(t, err)
+	_, err = ParseAttributeConfig(mapstr.MapStr{"host": map[string]interface{}{"operator": 1}})
+	require.Error(t, err)
+	_, err = ParseAttributeConfig(mapstr.MapStr{"host": "mask"})
+	require.Error(t, err)
+}
+
+func TestMergeAttributePermission(t *testing.T) {
+	restricted := metadata.Privilege{
+		ModelConfig: map[string]map[string][]string{"bk_host_manage": {"host": {"search"}}},
+		AttributeConfig: map[string]map[string]string{
+			"host":   {"operator": "mask", "bk_comment": "read", "bk_cpu": "write"},
+			"switch": {"password": "mask"},
+		},
+	}
+	require.Equal(t, metadata.AttributePermission{
+		"host":   {"operator": "mask", "bk_comment": "read"},
+		"switch": {"password": "mask"},
+	}, MergeAttributePermission([]metadata.Privilege{restricted}))
+
+	// the group which grants the object without restriction makes the attributes writable
+	granted := metadata.Privilege{
+		ModelConfig: map[string]map[string][]string{"bk_host_manage": {"host": {"search", "update"}}},
+	}
+	require.Equal(t, metadata.AttributePermission{
+		"switch": {"password": "mask"},
+	}, MergeAttributePermission([]metadata.Privilege{restricted, granted}))
+
+	// the group which has nothing to do with the object is ignored
+	other := metadata.Privilege{
+		ModelConfig: map[string]map[string][]string{"bk_network": {"router": {"search"}}},
+	}
+	require.Equal(t, metadata.AttributePermission{
+		"host":   {"operator": "mask", "bk_comment": "read"},
+		"switch": {"password": "mask"},
+	}, MergeAttributePermission([]metadata.Privilege{other, restricted}))
+
+	// the most permissive privilege wins
+	readable := metadata.Privilege{
+		AttributeConfig: map[string]map[string]string{"host": {"operator": "read", "bk_comment": "mask"}},
+	}
+	require.Equal(t, metadata.AttributePermission{
+		"host":   {"operator": "read", "bk_comment": "read"},
+		"switch": {"password": "mask"},
+	}, MergeAttributePermission([]metadata.Privilege{restricted, readable}))
+
+	require.Equal(t, metadata.AttributePermission{}, MergeAttributePermission(nil))
+}
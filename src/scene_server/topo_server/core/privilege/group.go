@@ -13,7 +13,6 @@
 package privilege
 
 import (
-	"context"
 	"encoding/json"
 
 	"configcenter/src/apimachinery"
@@ -65,7 +64,7 @@ func (u *userGroup) checkGroupNameRepeat(supplierAccount, groupID, groupName str
 	}
 	cond.Field("group_name").Eq(groupName)
 
-	rsp, err := u.client.ObjectController().Privilege().SearchUserGroup(context.Background(), supplierAccount, u.params.Header, cond.ToMapStr())
+	rsp, err := u.client.ObjectController().Privilege().SearchUserGroup(u.params.Context, supplierAccount, u.params.Header, cond.ToMapStr())
 	if nil != err {
 		blog.Errorf("[permission] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -90,7 +89,7 @@ func (u *userGroup) CreateUserGroup(supplierAccount string, userGroup *metadata.
 		return err
 	}
 
-	rspCreate, err := u.client.ObjectController().Privilege().CreateUserGroup(context.Background(), supplierAccount, u.params.Header, userGroup.ToMapStr())
+	rspCreate, err := u.client.ObjectController().Privilege().CreateUserGroup(u.params.Context, supplierAccount, u.params.Header, userGroup.ToMapStr())
 	if nil != err {
 		blog.Errorf("[permission] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -106,7 +105,7 @@ func (u *userGroup) CreateUserGroup(supplierAccount string, userGroup *metadata.
 
 func (u *userGroup) DeleteUserGroup(supplierAccount, groupID string) error {
 
-	rsp, err := u.client.ObjectController().Privilege().DeleteUserGroup(context.Background(), supplierAccount, groupID, u.params.Header)
+	rsp, err := u.client.ObjectController().Privilege().DeleteUserGroup(u.params.Context, supplierAccount, groupID, u.params.Header)
 	if nil != err {
 		blog.Errorf("[permission] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -132,7 +131,7 @@ func (u *userGroup) UpdateUserGroup(supplierAccount, groupID string, data mapstr
 		return err
 	}
 
-	rsp, err := u.client.ObjectController().Privilege().UpdateUserGroup(context.Background(), supplierAccount, groupID, u.params.Header, data)
+	rsp, err := u.client.ObjectController().Privilege().UpdateUserGroup(u.params.Context, supplierAccount, groupID, u.params.Header, data)
 	if nil != err {
 		blog.Errorf("[permission] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -148,7 +147,7 @@ func (u *userGroup) UpdateUserGroup(supplierAccount, groupID string, data mapstr
 
 func (u *userGroup) SearchUserGroup(supplierAccount string, cond condition.Condition) ([]metadata.UserGroup, error) {
 
-	rsp, err := u.client.ObjectController().Privilege().SearchUserGroup(context.Background(), supplierAccount, u.params.Header, cond.ToMapStr())
+	rsp, err := u.client.ObjectController().Privilege().SearchUserGroup(u.params.Context, supplierAccount, u.params.Header, cond.ToMapStr())
 	if nil != err {
 		blog.Errorf("[permission] failed to request object controller, error info is %s", err.Error())
 		return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
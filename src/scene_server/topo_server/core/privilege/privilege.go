@@ -13,13 +13,14 @@
 package privilege
 
 import (
-	"context"
 	"encoding/json"
+	"strings"
 
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/condition"
+	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	"configcenter/src/scene_server/topo_server/core/types"
@@ -30,6 +31,7 @@ type PermissionInterface interface {
 	SetUserGroupPermission(supplierAccount, gourpID string, permission *metadata.PrivilegeUserGroup) error
 	GetUserGroupPermission(supplierAccount, groupID string) (*metadata.GroupPrivilege, error)
 	GetUserPermission(supplierAccount, userName string) (*metadata.Gprivilege, error)
+	GetUserAttributePermission(supplierAccount, userName string) (metadata.AttributePermission, error)
 }
 
 // NewPermission create a new permission instance
@@ -56,7 +58,7 @@ func (u *userGroupPermission) MarshalJSON() ([]byte, error) {
 
 func (u *userGroupPermission) SetUserGroupPermission(supplierAccount, groupID string, permission *metadata.PrivilegeUserGroup) error {
 
-	rsp, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(context.Background(), supplierAccount, groupID, u.params.Header)
+	rsp, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(u.params.Context, supplierAccount, groupID, u.params.Header)
 	if nil != err {
 		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -69,7 +71,7 @@ func (u *userGroupPermission) SetUserGroupPermission(supplierAccount, groupID st
 	}
 
 	if nil == rsp.Data.Privilege || (0 == len(rsp.Data.Privilege.ModelConfig) && nil == rsp.Data.Privilege.SysConfig) {
-		rsp, err := u.client.ObjectController().Privilege().CreateUserGroupPrivi(context.Background(), supplierAccount, groupID, u.params.Header, permission)
+		rsp, err := u.client.ObjectController().Privilege().CreateUserGroupPrivi(u.params.Context, supplierAccount, groupID, u.params.Header, permission)
 		if nil != err {
 			blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
 			return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -80,8 +82,16 @@ func (u *userGroupPermission) SetUserGroupPermission(supplierAccount, groupID st
 		return nil
 	}
 
+	// keep the attribute privileges if they are not set, so that the model privileges can be saved alone
+	if nil == permission.AttributeConfig && 0 != len(rsp.Data.Privilege.AttributeConfig) {
+		permission.AttributeConfig = mapstr.New()
+		for objID, properties := range rsp.Data.Privilege.AttributeConfig {
+			permission.AttributeConfig.Set(objID, properties)
+		}
+	}
+
 	// update privilege
-	rspUpdate, err := u.client.ObjectController().Privilege().UpdateUserGroupPrivi(context.Background(), supplierAccount, groupID, u.params.Header, permission)
+	rspUpdate, err := u.client.ObjectController().Privilege().UpdateUserGroupPrivi(u.params.Context, supplierAccount, groupID, u.params.Header, permission)
 	if nil != err {
 		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
 		return u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -95,7 +105,7 @@ func (u *userGroupPermission) SetUserGroupPermission(supplierAccount, groupID st
 }
 func (u *userGroupPermission) GetUserGroupPermission(supplierAccount, groupID string) (*metadata.GroupPrivilege, error) {
 
-	rsp, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(context.Background(), supplierAccount, groupID, u.params.Header)
+	rsp, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(u.params.Context, supplierAccount, groupID, u.params.Header)
 	if nil != err {
 		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
 		return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -116,7 +126,7 @@ func (u *userGroupPermission) GetUserPermission(supplierAccount, userName string
 	}
 
 	// get cross biz permission
-	rsp, err := u.client.ObjectController().Privilege().GetSystemFlag(context.Background(), supplierAccount, common.HostCrossBizField, u.params.Header)
+	rsp, err := u.client.ObjectController().Privilege().GetSystemFlag(u.params.Context, supplierAccount, common.HostCrossBizField, u.params.Header)
 	if nil != err {
 		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
 		//		return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
@@ -124,18 +134,9 @@ func (u *userGroupPermission) GetUserPermission(supplierAccount, userName string
 		gPrivilege.IsHostCrossBiz = rsp.Result
 	}
 
-	// search user group permission
-	cond := condition.CreateCondition()
-	cond.Field(common.BKUserListField).Like(userName)
-	rspSearchGroup, err := u.client.ObjectController().Privilege().SearchUserGroup(context.Background(), supplierAccount, u.params.Header, cond.ToMapStr())
+	privileges, err := u.searchUserPrivileges(supplierAccount, userName)
 	if nil != err {
-		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
-		return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
-	}
-
-	if !rspSearchGroup.Result {
-		blog.Errorf("[privilege] failed to search group permission, error info is %s", rspSearchGroup.ErrMsg)
-		return nil, u.params.Err.New(rspSearchGroup.Code, rspSearchGroup.ErrMsg)
+		return nil, err
 	}
 
 	var gglconfig []string
@@ -144,27 +145,10 @@ func (u *userGroupPermission) GetUserPermission(supplierAccount, userName string
 	modelPrivi := make(map[string][]string)
 	modelClsConfig := make(map[string]string)
 	// construct the result
-	for _, item := range rspSearchGroup.Data {
-		//item.GroupID
-
-		grpPrivilege, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(context.Background(), supplierAccount, item.GroupID, u.params.Header)
-		if nil != err {
-			blog.Errorf("[privilege] failed to get the user group(%s) privilege, error info is %s", item.GroupID, err.Error())
-			continue
-		}
+	for _, privilege := range privileges {
 
-		if !grpPrivilege.Result {
-			blog.Errorf("[privige] failed to search the user group, error info is %s", grpPrivilege.ErrMsg)
-			continue
-		}
-
-		if nil == grpPrivilege.Data.Privilege {
-			grpPrivilege.Data.Privilege = &metadata.Privilege{}
-			continue
-		}
-
-		if nil != grpPrivilege.Data.Privilege.SysConfig {
-			sysConfig := *grpPrivilege.Data.Privilege.SysConfig
+		if nil != privilege.SysConfig {
+			sysConfig := *privilege.SysConfig
 			for _, i := range sysConfig.Globalbusi {
 				gglconfig = append(gglconfig, i)
 			}
@@ -173,7 +157,7 @@ func (u *userGroupPermission) GetUserPermission(supplierAccount, userName string
 			}
 		}
 
-		for key, val := range grpPrivilege.Data.Privilege.ModelConfig {
+		for key, val := range privilege.ModelConfig {
 			for subKey, subVal := range val {
 				for _, data := range subVal {
 					modelPrivi[subKey] = append(modelPrivi[subKey], data)
@@ -201,6 +185,64 @@ func (u *userGroupPermission) GetUserPermission(supplierAccount, userName string
 	gPrivilege.SysConfig.BackConfig = util.RemoveDuplicatesAndEmpty(gbkconfig)
 	gPrivilege.SysConfig.Globalbusi = util.RemoveDuplicatesAndEmpty(gglconfig)
 	gPrivilege.ModelConfig = cls
+	gPrivilege.AttributeConfig = MergeAttributePermission(privileges)
 	return &gPrivilege, nil
 
 }
+
+func (u *userGroupPermission) GetUserAttributePermission(supplierAccount, userName string) (metadata.AttributePermission, error) {
+
+	privileges, err := u.searchUserPrivileges(supplierAccount, userName)
+	if nil != err {
+		return nil, err
+	}
+
+	return MergeAttributePermission(privileges), nil
+}
+
+// searchUserPrivileges search the privileges of the user groups which the user belongs to
+func (u *userGroupPermission) searchUserPrivileges(supplierAccount, userName string) ([]metadata.Privilege, error) {
+
+	// search user group permission
+	cond := condition.CreateCondition()
+	cond.Field(common.BKUserListField).Like(userName)
+	rspSearchGroup, err := u.client.ObjectController().Privilege().SearchUserGroup(u.params.Context, supplierAccount, u.params.Header, cond.ToMapStr())
+	if nil != err {
+		blog.Errorf("[privilege] failed to request object controller, error info is %s", err.Error())
+		return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+
+	if !rspSearchGroup.Result {
+		blog.Errorf("[privilege] failed to search group permission, error info is %s", rspSearchGroup.ErrMsg)
+		return nil, u.params.Err.New(rspSearchGroup.Code, rspSearchGroup.ErrMsg)
+	}
+
+	privileges := make([]metadata.Privilege, 0)
+	for _, item := range rspSearchGroup.Data {
+
+		// the user list is searched by like, skip the groups which only contain the similar user names
+		if !util.InStrArr(strings.Split(item.UserList, ";"), userName) {
+			continue
+		}
+
+		grpPrivilege, err := u.client.ObjectController().Privilege().GetUserGroupPrivi(u.params.Context, supplierAccount, item.GroupID, u.params.Header)
+		if nil != err {
+			blog.Errorf("[privilege] failed to get the user group(%s) privilege, error info is %s", item.GroupID, err.Error())
+			return nil, u.params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+		}
+
+		// the restrictions of every group must be known, otherwise the restricted attributes would be exposed
+		if !grpPrivilege.Result {
+			blog.Errorf("[privilege] failed to search the user group(%s) privilege, error info is %s", item.GroupID, grpPrivilege.ErrMsg)
+			return nil, u.params.Err.New(grpPrivilege.Code, grpPrivilege.ErrMsg)
+		}
+
+		if nil == grpPrivilege.Data.Privilege {
+			continue
+		}
+
+		privileges = append(privileges, *grpPrivilege.Data.Privilege)
+	}
+
+	return privileges, nil
+}
@@ -13,6 +13,7 @@
 package types
 
 import (
+	"context"
 	"net/http"
 
 	"configcenter/src/common/backbone"
@@ -23,6 +24,8 @@ import (
 
 // ContextParams the logic function params
 type ContextParams struct {
+	// Context is done when the caller gives up the request, or the timeout carried by the header expires
+	Context         context.Context
 	Engin           *backbone.Engine
 	Header          http.Header
 	MaxTopoLevel    int
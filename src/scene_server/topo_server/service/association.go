@@ -25,7 +25,7 @@ import (
 
 // CreateMainLineObject create a new object in the main line topo
 func (s *topoService) CreateMainLineObject(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
-	tx, err := s.tx.StartTransaction(context.Background())
+	tx, err := s.tx.StartTransaction(params.Context)
 	if err != nil {
 		return nil, params.Err.Error(common.CCErrObjectDBOpErrno)
 	}
@@ -40,12 +40,13 @@ func (s *topoService) CreateMainLineObject(params types.ContextParams, pathParam
 	ret, err := s.core.AssociationOperation().CreateMainlineAssociation(params, mainLineAssociation)
 
 	if err != nil {
+		// the transaction is aborted even if the caller has given up the request
 		if txerr := tx.Abort(context.Background()); txerr != nil {
 			blog.Errorf("[api-asst] abort transaction failed; %v", err)
 			return ret, params.Err.Error(common.CCErrObjectDBOpErrno)
 		}
 	} else {
-		if txerr := tx.Commit(context.Background()); txerr != nil {
+		if txerr := tx.Commit(params.Context); txerr != nil {
 			return ret, params.Err.Error(common.CCErrObjectDBOpErrno)
 		}
 	}
@@ -55,7 +56,7 @@ func (s *topoService) CreateMainLineObject(params types.ContextParams, pathParam
 
 // DeleteMainLineObject delete a object int the main line topo
 func (s *topoService) DeleteMainLineObject(params types.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
-	tx, err := s.tx.StartTransaction(context.Background())
+	tx, err := s.tx.StartTransaction(params.Context)
 	if err != nil {
 		return nil, params.Err.Error(common.CCErrObjectDBOpErrno)
 	}
@@ -65,12 +66,13 @@ func (s *topoService) DeleteMainLineObject(params types.ContextParams, pathParam
 	err = s.core.AssociationOperation().DeleteMainlineAssociaton(params, objID)
 
 	if err != nil {
+		// the transaction is aborted even if the caller has given up the request
 		if txerr := tx.Abort(context.Background()); txerr != nil {
 			blog.Errorf("[api-asst] abort transaction failed; %v", err)
 			return nil, params.Err.Error(common.CCErrObjectDBOpErrno)
 		}
 	} else {
-		if txerr := tx.Commit(context.Background()); txerr != nil {
+		if txerr := tx.Commit(params.Context); txerr != nil {
 			return nil, params.Err.Error(common.CCErrObjectDBOpErrno)
 		}
 	}
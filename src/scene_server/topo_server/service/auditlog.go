@@ -13,7 +13,18 @@
 package service
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 	"configcenter/src/scene_server/topo_server/core/types"
 )
 
@@ -22,3 +33,55 @@ func (s *topoService) AuditQuery(params types.ContextParams, pathParams, queryPa
 
 	return s.core.AuditOperation().Query(params, data)
 }
+
+// AuditExport stream the audit logs exported by the audit controller to the client
+func (s *topoService) AuditExport(req *restful.Request, resp *restful.Response) {
+	language := util.GetActionLanguage(req)
+	defErr := s.err.CreateDefaultCCErrorIf(language)
+
+	opt := new(meta.AuditExportOption)
+	if err := json.NewDecoder(req.Request.Body).Decode(opt); nil != err {
+		blog.Errorf("[audit] failed to parse the export option, error info is %s", err.Error())
+		s.sendResponse(resp, common.CCErrCommJSONUnmarshalFailed, defErr.Error(common.CCErrCommJSONUnmarshalFailed).Error())
+		return
+	}
+
+	// the export is stopped when the client disconnects
+	ctx, cancel := backbone.NewContextWithHeader(req.Request.Context(), req.Request.Header)
+	defer cancel()
+	rsp, err := s.engin.CoreAPI.AuditController().ExportAuditLog(ctx, req.Request.Header, opt)
+	if nil != err {
+		blog.Errorf("[audit] failed request audit controller to export, error info is %s", err.Error())
+		s.sendResponse(resp, common.CCErrCommHTTPDoRequestFailed, defErr.Error(common.CCErrCommHTTPDoRequestFailed).Error())
+		return
+	}
+	defer rsp.Body.Close()
+
+	for _, key := range []string{"Content-Type", "Content-Disposition"} {
+		if val := rsp.Header.Get(key); "" != val {
+			resp.Header().Set(key, val)
+		}
+	}
+	resp.WriteHeader(rsp.StatusCode)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := rsp.Body.Read(buf)
+		if n > 0 {
+			if _, err := resp.Write(buf[:n]); nil != err {
+				blog.Errorf("[audit] failed to write the exported audit logs, error info is %s", err.Error())
+				return
+			}
+			if flusher, ok := resp.ResponseWriter.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		if io.EOF == readErr {
+			return
+		}
+		if nil != readErr {
+			blog.Errorf("[audit] failed to read the exported audit logs, error info is %s", readErr.Error())
+			return
+		}
+	}
+}
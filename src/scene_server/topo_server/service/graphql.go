@@ -0,0 +1,197 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/graphql"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/scene_server/topo_server/core"
+	"configcenter/src/scene_server/topo_server/core/graph"
+	"configcenter/src/scene_server/topo_server/core/model"
+	"configcenter/src/scene_server/topo_server/core/types"
+)
+
+// GraphQL execute the read only graphql query over the models and the instances,
+// the result is in the graphql format instead of the common response
+func (s *topoService) GraphQL(req *restful.Request, resp *restful.Response) {
+	language := util.GetActionLanguage(req)
+	defErr := s.err.CreateDefaultCCErrorIf(language)
+	ctx, cancel := backbone.NewContextWithHeader(req.Request.Context(), req.Request.Header)
+	defer cancel()
+	params := types.ContextParams{
+		Context:         ctx,
+		Err:             defErr,
+		Lang:            s.language.CreateDefaultCCLanguageIf(language),
+		MaxTopoLevel:    s.cfg.BusinessTopoLevelMax,
+		Header:          req.Request.Header,
+		SupplierAccount: util.GetActionOnwerID(req),
+		User:            util.GetActionUser(req),
+		Engin:           s.engin,
+	}
+
+	request := graphql.Request{}
+	if err := json.NewDecoder(req.Request.Body).Decode(&request); nil != err {
+		blog.Errorf("[graphql] failed to parse the request, error info is %s", err.Error())
+		s.sendGraphQLResult(resp, graphqlError(defErr.Error(common.CCErrCommJSONUnmarshalFailed)))
+		return
+	}
+
+	permission, err := s.core.PermissionOperation().Permission(params).GetUserAttributePermission(params.SupplierAccount, params.User)
+	if nil != err {
+		blog.Errorf("[graphql] failed to get the attribute privileges of the user(%s), error info is %s", params.User, err.Error())
+		s.sendGraphQLResult(resp, graphqlError(err))
+		return
+	}
+
+	reader := &graphReader{core: s.core, params: params, objects: make(map[string]model.Object)}
+	schema, err := graph.NewSchema(params.Context, reader, permission, defErr)
+	if nil != err {
+		blog.Errorf("[graphql] failed to build the schema, error info is %s", err.Error())
+		s.sendGraphQLResult(resp, graphqlError(err))
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Context:  params.Context,
+		Schema:   schema,
+		Request:  request,
+		MaxDepth: graph.MaxDepth,
+	})
+	for _, item := range result.Errors {
+		blog.V(3).Infof("[graphql] the query of the user(%s) failed, error info is %s", params.User, item.Error())
+	}
+	s.sendGraphQLResult(resp, result)
+}
+
+func graphqlError(err error) *graphql.Result {
+	return &graphql.Result{Errors: []*graphql.Error{{Message: err.Error()}}}
+}
+
+func (s *topoService) sendGraphQLResult(resp *restful.Response, result *graphql.Result) {
+	resp.Header().Set("Content-Type", restful.MIME_JSON)
+	resp.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(resp).Encode(result); nil != err {
+		blog.Errorf("[graphql] failed to send the result, error info is %s", err.Error())
+	}
+}
+
+// graphReader read the models and the instances for the graphql schema through the topo operations,
+// so that the instances and the associations are searched in the same scope as the search apis
+type graphReader struct {
+	core    core.Core
+	params  types.ContextParams
+	objects map[string]model.Object
+}
+
+func (r *graphReader) withContext(ctx context.Context) types.ContextParams {
+	params := r.params
+	params.Context = ctx
+	return params
+}
+
+func (r *graphReader) ReadModels(ctx context.Context) ([]meta.SearchModelInfo, error) {
+	params := r.withContext(ctx)
+	// the models are scoped like the object operation does
+	cond := mapstr.New()
+	if nil != params.MetaData {
+		cond.Merge(meta.PublicAndBizCondition(*params.MetaData))
+		cond.Remove(meta.BKMetadata)
+	} else {
+		cond.Merge(meta.BizLabelNotExist)
+	}
+	rsp, err := params.Engin.CoreAPI.CoreService().Model().ReadModel(ctx, params.Header, &meta.QueryCondition{Condition: cond})
+	if nil != err {
+		blog.Errorf("[graphql] failed to request the core service, error info is %s", err.Error())
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[graphql] failed to search the models, error info is %s", rsp.ErrMsg)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data.Info, nil
+}
+
+func (r *graphReader) ReadModelAssociations(ctx context.Context) ([]meta.Association, error) {
+	return r.core.AssociationOperation().SearchObjectAssociation(r.withContext(ctx), "")
+}
+
+func (r *graphReader) ReadInstances(ctx context.Context, objID string, cond *meta.QueryCondition) (int64, []mapstr.MapStr, error) {
+	params := r.withContext(ctx)
+	obj, ok := r.objects[objID]
+	if !ok {
+		var err error
+		obj, err = r.core.ObjectOperation().FindSingleObject(params, objID)
+		if nil != err {
+			blog.Errorf("[graphql] failed to find the object(%s), error info is %s", objID, err.Error())
+			return 0, nil, err
+		}
+		r.objects[objID] = obj
+	}
+
+	// the sort of the query input is in the mongo style, such as bk_inst_name,-bk_inst_id
+	sorts := make([]string, 0, len(cond.SortArr))
+	for _, item := range cond.SortArr {
+		if item.IsDsc {
+			sorts = append(sorts, "-"+item.Field)
+			continue
+		}
+		sorts = append(sorts, item.Field)
+	}
+	query := &meta.QueryInput{
+		Condition: cond.Condition,
+		Fields:    strings.Join(cond.Fields, ","),
+		Start:     int(cond.Limit.Offset),
+		Limit:     int(cond.Limit.Limit),
+		Sort:      strings.Join(sorts, ","),
+	}
+	cnt, items, err := r.core.InstOperation().FindInst(params, obj, query, false)
+	if nil != err {
+		blog.Errorf("[graphql] failed to search the instances of the object(%s), error info is %s", objID, err.Error())
+		return 0, nil, err
+	}
+	insts := make([]mapstr.MapStr, 0, len(items))
+	for _, item := range items {
+		insts = append(insts, item.GetValues())
+	}
+	return int64(cnt), insts, nil
+}
+
+func (r *graphReader) ReadInstAssociations(ctx context.Context, cond mapstr.MapStr) ([]meta.InstAsst, error) {
+	return r.core.AssociationOperation().SearchInstAssociation(r.withContext(ctx), &meta.QueryInput{Condition: cond})
+}
+
+func (r *graphReader) ReadModuleHosts(ctx context.Context, cond map[string][]int64) ([]meta.ModuleHost, error) {
+	params := r.withContext(ctx)
+	rsp, err := params.Engin.CoreAPI.HostController().Module().GetModulesHostConfig(ctx, params.Header, cond)
+	if nil != err {
+		blog.Errorf("[graphql] failed to request the host controller, error info is %s", err.Error())
+		return nil, params.Err.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if !rsp.Result {
+		blog.Errorf("[graphql] failed to search the host module relations, error info is %s", rsp.ErrMsg)
+		return nil, params.Err.New(rsp.Code, rsp.ErrMsg)
+	}
+	return rsp.Data, nil
+}
@@ -49,7 +49,9 @@ func (s *topoService) CreateInst(params types.ContextParams, pathParams, queryPa
 		          "bk_version": "121",
 		          "import_from": "1"
 		        },
-		      "input_type": "excel"
+		      "input_type": "excel",
+		      "validate_only": false, // only return the preview of the rows
+		      "all_or_nothing": false // write nothing if any row is invalid or fails
 		    }
 		*/
 		batchInfo := new(operation.InstBatchInfo)
@@ -57,6 +59,15 @@ func (s *topoService) CreateInst(params types.ContextParams, pathParams, queryPa
 			blog.Errorf("import object[%s] instance batch, but got invalid BatchInfo:[%v] ", objID, batchInfo)
 			return nil, params.Err.Error(common.CCErrCommParamsIsInvalid)
 		}
+		if nil != batchInfo.BatchInfo {
+			rows := make([]mapstr.MapStr, 0)
+			for _, row := range *batchInfo.BatchInfo {
+				rows = append(rows, row)
+			}
+			if err := s.checkAttributeWrite(params, objID, rows...); nil != err {
+				return nil, err
+			}
+		}
 		setInst, createErr := s.core.InstOperation().CreateInstBatch(params, obj, batchInfo)
 		if nil != createErr {
 			blog.Errorf("failed to create new object %s, %s", objID, createErr.Error())
@@ -65,6 +76,10 @@ func (s *topoService) CreateInst(params types.ContextParams, pathParams, queryPa
 		return setInst, nil
 	}
 
+	if err := s.checkAttributeWrite(params, objID, data); nil != err {
+		return nil, err
+	}
+
 	setInst, err := s.core.InstOperation().CreateInst(params, obj, data)
 	if nil != err {
 		blog.Errorf("failed to create a new %s, %s", objID, err.Error())
@@ -139,6 +154,14 @@ func (s *topoService) UpdateInsts(params types.ContextParams, pathParams, queryP
 		return nil, err
 	}
 
+	updateDatas := make([]mapstr.MapStr, 0)
+	for _, item := range updateCondition.Update {
+		updateDatas = append(updateDatas, item.InstInfo)
+	}
+	if err := s.checkAttributeWrite(params, objID, updateDatas...); nil != err {
+		return nil, err
+	}
+
 	for _, item := range updateCondition.Update {
 
 		cond := condition.CreateCondition()
@@ -173,6 +196,10 @@ func (s *topoService) UpdateInst(params types.ContextParams, pathParams, queryPa
 		return nil, err
 	}
 
+	if err := s.checkAttributeWrite(params, objID, data); nil != err {
+		return nil, err
+	}
+
 	cond := condition.CreateCondition()
 	cond.Field(obj.GetInstIDFieldName()).Eq(instID)
 	err = s.core.InstOperation().UpdateInst(params, data, obj, cond, instID)
@@ -213,12 +240,19 @@ func (s *topoService) SearchInsts(params types.ContextParams, pathParams, queryP
 	query.Sort = page.Sort
 	query.Start = page.Start
 
+	permission, err := s.checkAttributeRead(params, objID, queryCond.Condition, page.Sort)
+	if nil != err {
+		return nil, err
+	}
+
 	cnt, instItems, err := s.core.InstOperation().FindInst(params, obj, query, false)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s", pathParams("obj_id"), err.Error())
 		return nil, err
 	}
 
+	maskInsts(permission, obj.Object().ObjectID, instItems)
+
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
@@ -250,12 +284,19 @@ func (s *topoService) SearchInstAndAssociationDetail(params types.ContextParams,
 	query.Sort = page.Sort
 	query.Start = page.Start
 
+	permission, err := s.checkAttributeRead(params, objID, queryCond.Condition, page.Sort)
+	if nil != err {
+		return nil, err
+	}
+
 	cnt, instItems, err := s.core.InstOperation().FindInst(params, obj, query, true)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s", pathParams("bk_obj_id"), err.Error())
 		return nil, err
 	}
 
+	maskInsts(permission, obj.Object().ObjectID, instItems)
+
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
@@ -286,12 +327,19 @@ func (s *topoService) SearchInstByObject(params types.ContextParams, pathParams,
 	query.Limit = page.Limit
 	query.Sort = page.Sort
 	query.Start = page.Start
+
+	permission, err := s.checkAttributeRead(params, objID, queryCond.Condition, page.Sort)
+	if nil != err {
+		return nil, err
+	}
 	cnt, instItems, err := s.core.InstOperation().FindInst(params, obj, query, false)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s", pathParams("bk_obj_id"), err.Error())
 		return nil, err
 	}
 
+	maskInsts(permission, obj.Object().ObjectID, instItems)
+
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
@@ -309,12 +357,19 @@ func (s *topoService) SearchInstByAssociation(params types.ContextParams, pathPa
 		return nil, err
 	}
 
+	permission, err := s.checkAttributeRead(params, objID, nil, "")
+	if nil != err {
+		return nil, err
+	}
+
 	cnt, instItems, err := s.core.InstOperation().FindInstByAssociationInst(params, obj, data)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s", pathParams("bk_obj_id"), err.Error())
 		return nil, err
 	}
 
+	maskInsts(permission, obj.Object().ObjectID, instItems)
+
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
@@ -341,12 +396,19 @@ func (s *topoService) SearchInstByInstID(params types.ContextParams, pathParams,
 	queryCond := &metadata.QueryInput{}
 	queryCond.Condition = cond.ToMapStr()
 
+	permission, err := s.checkAttributeRead(params, obj.Object().ObjectID, nil, "")
+	if nil != err {
+		return nil, err
+	}
+
 	cnt, instItems, err := s.core.InstOperation().FindInst(params, obj, queryCond, false)
 	if nil != err {
 		blog.Errorf("[api-inst] failed to find the objects(%s), error info is %s", pathParams("bk_obj_id"), err.Error())
 		return nil, err
 	}
 
+	maskInsts(permission, obj.Object().ObjectID, instItems)
+
 	result := mapstr.MapStr{}
 	result.Set("count", cnt)
 	result.Set("info", instItems)
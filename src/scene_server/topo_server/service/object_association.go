@@ -13,7 +13,6 @@
 package service
 
 import (
-	"context"
 	"strconv"
 
 	"configcenter/src/common"
@@ -132,7 +131,7 @@ func (s *topoService) ImportInstanceAssociation(params types.ContextParams, path
 		return nil, params.Err.New(common.CCErrCommParamsInvalid, err.Error())
 	}
 
-	resp, err := s.core.AssociationOperation().ImportInstAssociation(context.Background(), params, objID, request.AssociationInfoMap)
+	resp, err := s.core.AssociationOperation().ImportInstAssociation(params.Context, params, objID, request.AssociationInfoMap)
 	return resp, err
 
 }
@@ -13,10 +13,14 @@
 package service
 
 import (
+	"strings"
+
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/core/inst"
+	"configcenter/src/scene_server/topo_server/core/privilege"
 	"configcenter/src/scene_server/topo_server/core/types"
 )
 
@@ -30,6 +34,13 @@ func (s *topoService) UpdateUserGroupPrivi(params types.ContextParams, pathParam
 		return nil, params.Err.New(common.CCErrCommParamsIsInvalid, err.Error())
 	}
 
+	if nil != priviData.AttributeConfig {
+		if _, err := privilege.ParseAttributeConfig(priviData.AttributeConfig); nil != err {
+			blog.Errorf("[api-privilege] failed to parse the attribute privileges, error info is %s ", err.Error())
+			return nil, params.Err.New(common.CCErrCommParamsIsInvalid, err.Error())
+		}
+	}
+
 	err = s.core.PermissionOperation().Permission(params).SetUserGroupPermission(params.SupplierAccount, pathParams("group_id"), priviData)
 	return nil, err
 }
@@ -45,3 +56,45 @@ func (s *topoService) GetUserPrivi(params types.ContextParams, pathParams, query
 
 	return s.core.PermissionOperation().Permission(params).GetUserPermission(params.SupplierAccount, pathParams("user_name"))
 }
+
+// checkAttributeWrite check whether the request user can write the attributes in the data
+func (s *topoService) checkAttributeWrite(params types.ContextParams, objID string, datas ...mapstr.MapStr) error {
+
+	permission, err := s.core.PermissionOperation().Permission(params).GetUserAttributePermission(params.SupplierAccount, params.User)
+	if nil != err {
+		blog.Errorf("[api-privilege] failed to get the attribute privileges of the user(%s), error info is %s", params.User, err.Error())
+		return err
+	}
+
+	for _, data := range datas {
+		if fields := permission.Unwritable(objID, data); 0 != len(fields) {
+			blog.Errorf("[api-privilege] the user(%s) has no permission to write the attributes(%v) of the object(%s)", params.User, fields, objID)
+			return params.Err.Errorf(common.CCErrTopoAttributeNoWritePermission, strings.Join(fields, ","))
+		}
+	}
+	return nil
+}
+
+// checkAttributeRead check whether the request user can query or sort by the attributes in the condition,
+// and return the attribute privileges to mask the search results
+func (s *topoService) checkAttributeRead(params types.ContextParams, objID string, cond mapstr.MapStr, sort string) (metadata.AttributePermission, error) {
+
+	permission, err := s.core.PermissionOperation().Permission(params).GetUserAttributePermission(params.SupplierAccount, params.User)
+	if nil != err {
+		blog.Errorf("[api-privilege] failed to get the attribute privileges of the user(%s), error info is %s", params.User, err.Error())
+		return nil, err
+	}
+
+	if fields := permission.Unreadable(objID, cond, sort); 0 != len(fields) {
+		blog.Errorf("[api-privilege] the user(%s) has no permission to query by the attributes(%v) of the object(%s)", params.User, fields, objID)
+		return nil, params.Err.Errorf(common.CCErrTopoAttributeNoReadPermission, strings.Join(fields, ","))
+	}
+	return permission, nil
+}
+
+// maskInsts mask the attributes of the insts which the request user can not read
+func maskInsts(permission metadata.AttributePermission, objID string, insts []inst.Inst) {
+	for _, item := range insts {
+		permission.Mask(objID, item.GetValues())
+	}
+}
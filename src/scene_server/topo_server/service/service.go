@@ -23,10 +23,12 @@ import (
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/graphql"
 	"configcenter/src/common/http/httpserver"
 	"configcenter/src/common/language"
 	"configcenter/src/common/mapstr"
 	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/openapi"
 	"configcenter/src/common/rdapi"
 	"configcenter/src/common/util"
 	"configcenter/src/scene_server/topo_server/app/options"
@@ -106,20 +108,28 @@ func (s *topoService) WebService() *restful.WebService {
 	innerActions := s.Actions()
 
 	for _, actionItem := range innerActions {
+		var builder *restful.RouteBuilder
 		switch actionItem.Verb {
 		case http.MethodPost:
-			ws.Route(ws.POST(actionItem.Path).To(actionItem.Handler))
+			builder = ws.POST(actionItem.Path)
 		case http.MethodDelete:
-			ws.Route(ws.DELETE(actionItem.Path).To(actionItem.Handler))
+			builder = ws.DELETE(actionItem.Path)
 		case http.MethodPut:
-			ws.Route(ws.PUT(actionItem.Path).To(actionItem.Handler))
+			builder = ws.PUT(actionItem.Path)
 		case http.MethodGet:
-			ws.Route(ws.GET(actionItem.Path).To(actionItem.Handler))
+			builder = ws.GET(actionItem.Path)
 		default:
 			blog.Errorf(" the url (%s), the http method (%s) is not supported", actionItem.Path, actionItem.Verb)
+			continue
 		}
+		ws.Route(openapi.Annotate(builder.To(actionItem.Handler), actionItem.Request, actionItem.Response))
 	}
 
+	// the exported audit logs are streamed, so it's not a json action
+	ws.Route(ws.POST("/audit/export").Produces(restful.MIME_JSON, "text/csv", "application/x-ndjson").To(s.AuditExport))
+	// the graphql result is not in the common response format
+	ws.Route(openapi.Annotate(ws.POST("/graphql").To(s.GraphQL), graphql.Request{}, graphql.Result{}))
+
 	return ws
 }
 
@@ -190,7 +200,7 @@ func (s *topoService) Actions() []*httpserver.Action {
 
 		func(act action) {
 
-			httpactions = append(httpactions, &httpserver.Action{Verb: act.Method, Path: act.Path, Handler: func(req *restful.Request, resp *restful.Response) {
+			httpactions = append(httpactions, &httpserver.Action{Verb: act.Method, Path: act.Path, Request: act.Request, Response: act.Response, Handler: func(req *restful.Request, resp *restful.Response) {
 				ownerID := util.GetActionOnwerID(req)
 				user := util.GetActionUser(req)
 
@@ -228,7 +238,11 @@ func (s *topoService) Actions() []*httpserver.Action {
 					}
 				}
 				metadata := meta.NewMetaDataFromMap(mData)
+				// the downstream calls are canceled when the caller disconnects or gives up the request
+				ctx, cancel := backbone.NewContextWithHeader(req.Request.Context(), req.Request.Header)
+				defer cancel()
 				data, dataErr := act.HandlerFunc(types.ContextParams{
+					Context:         ctx,
 					Err:             defErr,
 					Lang:            defLang,
 					MaxTopoLevel:    s.cfg.BusinessTopoLevelMax,
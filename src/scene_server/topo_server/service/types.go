@@ -35,6 +35,9 @@ type action struct {
 	Path                       string
 	HandlerFunc                LogicFunc
 	HandlerParseOriginDataFunc ParseOriginDataFunc
+	// Request and Response the samples of the request and the response body which document the action, optional
+	Request  interface{}
+	Response interface{}
 }
 
 // API the API interface
@@ -13,6 +13,9 @@
 package options
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/spf13/pflag"
 
 	"configcenter/src/common/core/cc/config"
@@ -42,5 +45,68 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 }
 
 type Config struct {
-	Mongo mongo.Config
+	Mongo   mongo.Config
+	Chain   ChainConfig
+	Archive ArchiveConfig
+}
+
+// ChainConfig the audit chain checkpoint config
+type ChainConfig struct {
+	// CheckpointKey the HMAC key to sign the checkpoints, checkpoint is disabled when it's empty
+	CheckpointKey string
+	// CheckpointInterval the interval of creating checkpoints
+	CheckpointInterval time.Duration
+}
+
+// ParseChainConfigFromKV parse the audit chain config from the config map
+func ParseChainConfigFromKV(prefix string, configmap map[string]string) ChainConfig {
+	conf := ChainConfig{
+		CheckpointKey:      configmap[prefix+".checkpointKey"],
+		CheckpointInterval: defaultCheckpointInterval,
+	}
+	if val, ok := configmap[prefix+".checkpointInterval"]; ok {
+		interval, err := strconv.Atoi(val)
+		if nil == err && interval > 0 {
+			conf.CheckpointInterval = time.Duration(interval) * time.Second
+		}
+	}
+	return conf
+}
+
+const defaultCheckpointInterval = time.Hour
+
+// ArchiveConfig the audit log retention and archive config
+type ArchiveConfig struct {
+	// Dir the local directory to store the archive files, archive is disabled when it's empty
+	Dir string
+	// Interval the interval of archiving the expired audit logs
+	Interval time.Duration
+	// RetentionDays the default days to keep the audit logs, 0 means keep forever
+	RetentionDays int
+	// RetentionRules the retention rules of supplier account and operation type, e.g. "0:1:180,*:3:30"
+	RetentionRules string
+}
+
+// ParseArchiveConfigFromKV parse the audit log archive config from the config map
+func ParseArchiveConfigFromKV(prefix string, configmap map[string]string) ArchiveConfig {
+	conf := ArchiveConfig{
+		Dir:            configmap[prefix+".archiveDir"],
+		Interval:       defaultArchiveInterval,
+		RetentionRules: configmap[prefix+".retentionRules"],
+	}
+	if val, ok := configmap[prefix+".archiveInterval"]; ok {
+		interval, err := strconv.Atoi(val)
+		if nil == err && interval > 0 {
+			conf.Interval = time.Duration(interval) * time.Second
+		}
+	}
+	if val, ok := configmap[prefix+".retentionDays"]; ok {
+		days, err := strconv.Atoi(val)
+		if nil == err && days > 0 {
+			conf.RetentionDays = days
+		}
+	}
+	return conf
 }
+
+const defaultArchiveInterval = 24 * time.Hour
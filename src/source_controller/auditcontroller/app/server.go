@@ -33,7 +33,7 @@ import (
 	"configcenter/src/storage/dal/mongo/local"
 )
 
-//Run ccapi server
+// Run ccapi server
 func Run(ctx context.Context, op *options.ServerOption) error {
 
 	svrInfo, err := newServerInfo(op)
@@ -71,6 +71,9 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	if false == configReady {
 		return fmt.Errorf("Failed to get configuration")
 	}
+	go coreService.Logics.RunCheckpoint(ctx)
+	go coreService.Logics.RunArchive(ctx)
+
 	if err := backbone.StartServer(ctx, engine, restful.NewContainer().Add(coreService.WebService())); err != nil {
 		return err
 	}
@@ -90,7 +93,19 @@ type AuditController struct {
 
 func (h *AuditController) onAduitConfigUpdate(previous, current cc.ProcessConfig) {
 	h.Config = &options.Config{
-		Mongo: mongo.ParseConfigFromKV("mongodb", current.ConfigMap),
+		Mongo:   mongo.ParseConfigFromKV("mongodb", current.ConfigMap),
+		Chain:   options.ParseChainConfigFromKV("audit", current.ConfigMap),
+		Archive: options.ParseArchiveConfigFromKV("audit", current.ConfigMap),
+	}
+	h.Service.Logics.CheckpointKey = h.Config.Chain.CheckpointKey
+	h.Service.Logics.CheckpointInterval = h.Config.Chain.CheckpointInterval
+	h.Service.Logics.ArchiveDir = h.Config.Archive.Dir
+	h.Service.Logics.ArchiveInterval = h.Config.Archive.Interval
+	retention, err := logics.ParseRetentionPolicy(h.Config.Archive.RetentionDays, h.Config.Archive.RetentionRules)
+	if err != nil {
+		blog.Errorf("parse audit retention rules failed, err: %s", err.Error())
+	} else {
+		h.Service.Logics.Retention = retention
 	}
 
 	instance, err := local.NewMgo(h.Config.Mongo.BuildURI(), time.Minute)
@@ -26,6 +26,7 @@ import (
 	"configcenter/src/common/util"
 	"configcenter/src/source_controller/auditcontroller/app"
 	"configcenter/src/source_controller/auditcontroller/app/options"
+	"configcenter/src/source_controller/auditcontroller/command"
 )
 
 func main() {
@@ -38,6 +39,10 @@ func main() {
 	op := options.NewServerOption()
 	op.AddFlags(pflag.CommandLine)
 
+	if err := command.Parse(os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	util.InitFlags()
 
 	if err := app.Run(context.Background(), op); err != nil {
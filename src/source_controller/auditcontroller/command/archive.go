@@ -0,0 +1,76 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+func runArchive(ctx context.Context, args []string) error {
+	var (
+		archiveflag    bool
+		importflag     bool
+		filepath       string
+		configposition string
+	)
+
+	// set flags
+	archivefs := pflag.NewFlagSet(auditArchiveCmdName, pflag.ExitOnError)
+	archivefs.BoolVar(&archiveflag, "archive", false, "archive flag, archive the expired audit logs by the retention rules now")
+	archivefs.BoolVar(&importflag, "import", false, "import flag, re-import the audit logs from the archive file")
+	archivefs.StringVar(&filepath, "file", "", "the archive file to import")
+	archivefs.StringVar(&configposition, "config", "conf/auditcontroller.conf", "The config path. e.g conf/auditcontroller.conf")
+	err := archivefs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	lgc, err := newLogics(configposition)
+	if err != nil {
+		return err
+	}
+
+	if archiveflag {
+		fmt.Printf("archiving expired audit logs into %s\n", lgc.ArchiveDir)
+		result, err := lgc.ArchiveExpired(ctx)
+		if err != nil {
+			fmt.Printf("archive error: %s", err.Error())
+			os.Exit(2)
+		}
+		for _, file := range result.Files {
+			fmt.Printf("archived into %s\n", file)
+		}
+		fmt.Printf("%d audit logs have been archived\n", result.Archived)
+	} else if importflag {
+		if filepath == "" {
+			fmt.Printf("import error: the archive file is required")
+			os.Exit(2)
+		}
+		fmt.Printf("importing audit logs from %s\n", filepath)
+		result, err := lgc.ImportArchive(ctx, filepath)
+		if err != nil {
+			fmt.Printf("import error: %s, %d imported before the error", err.Error(), result.Imported)
+			os.Exit(2)
+		}
+		fmt.Printf("%d audit logs have been imported, %d skipped\n", result.Imported, result.Skipped)
+	} else {
+		fmt.Printf("invalide argument")
+	}
+
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone/configcenter"
+	"configcenter/src/source_controller/auditcontroller/app/options"
+	"configcenter/src/source_controller/auditcontroller/logics"
+	"configcenter/src/storage/dal/mongo"
+	"configcenter/src/storage/dal/mongo/local"
+)
+
+const (
+	auditChainCmdName   = "auditchain"
+	auditArchiveCmdName = "auditarchive"
+)
+
+// Parse run app command
+func Parse(args []string) error {
+	if len(args) <= 1 {
+		return nil
+	}
+	switch args[1] {
+	case auditChainCmdName:
+		return runChain(context.Background(), args)
+	case auditArchiveCmdName:
+		return runArchive(context.Background(), args)
+	}
+	return nil
+}
+
+// newLogics connect to the mongo db with the config file
+func newLogics(configposition string) (*logics.Logics, error) {
+	pconfig, err := configcenter.ParseConfigWithFile(configposition)
+	if nil != err {
+		return nil, fmt.Errorf("parse config file error %s", err.Error())
+	}
+	config := mongo.ParseConfigFromKV("mongodb", pconfig.ConfigMap)
+	chain := options.ParseChainConfigFromKV("audit", pconfig.ConfigMap)
+	archive := options.ParseArchiveConfigFromKV("audit", pconfig.ConfigMap)
+	retention, err := logics.ParseRetentionPolicy(archive.RetentionDays, archive.RetentionRules)
+	if nil != err {
+		return nil, err
+	}
+	// connect to mongo db
+	db, err := local.NewMgo(config.BuildURI(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("connect mongo server failed %s", err.Error())
+	}
+	return &logics.Logics{
+		Instance:      db,
+		CheckpointKey: chain.CheckpointKey,
+		ArchiveDir:    archive.Dir,
+		Retention:     retention,
+	}, nil
+}
+
+func runChain(ctx context.Context, args []string) error {
+	var (
+		verifyflag     bool
+		checkpointflag bool
+		ownerID        string
+		configposition string
+	)
+
+	// set flags
+	chainfs := pflag.NewFlagSet(auditChainCmdName, pflag.ExitOnError)
+	chainfs.BoolVar(&verifyflag, "verify", false, "verify flag, walk through the audit chain and report the first broken link")
+	chainfs.BoolVar(&checkpointflag, "checkpoint", false, "checkpoint flag, create a signed checkpoint for the audit chain head")
+	chainfs.StringVar(&ownerID, "owner", common.BKDefaultOwnerID, "the supplier account of the audit chain to verify")
+	chainfs.StringVar(&configposition, "config", "conf/auditcontroller.conf", "The config path. e.g conf/auditcontroller.conf")
+	err := chainfs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	lgc, err := newLogics(configposition)
+	if err != nil {
+		return err
+	}
+
+	if verifyflag {
+		fmt.Printf("verifying audit chain of supplier account %s\n", ownerID)
+		if lgc.CheckpointKey == "" {
+			fmt.Printf("\033[33mcheckpoint key is not configured, the checkpoint signatures will not be verified\033[0m\n")
+		}
+		result, err := lgc.VerifyChain(ctx, ownerID)
+		if err != nil {
+			fmt.Printf("verify error: %s", err.Error())
+			os.Exit(2)
+		}
+		fmt.Printf("checked %d records, %d checkpoints, chain head at seq %d\n", result.Checked, result.Checkpoints, result.HeadSeq)
+		if !result.Intact {
+			fmt.Printf("\033[31maudit chain is broken at seq %d: %s\033[0m\n", result.BrokenLink.Seq, result.BrokenLink.Reason)
+			fmt.Printf("expected: %s\nactual:   %s\n", result.BrokenLink.Expected, result.BrokenLink.Actual)
+			os.Exit(3)
+		}
+		fmt.Printf("\033[32maudit chain is intact\033[0m\n")
+	} else if checkpointflag {
+		if lgc.CheckpointKey == "" {
+			fmt.Printf("checkpoint error: checkpoint key is not configured")
+			os.Exit(2)
+		}
+		if err := lgc.CreateCheckpoints(ctx); err != nil {
+			fmt.Printf("checkpoint error: %s", err.Error())
+			os.Exit(2)
+		}
+		fmt.Printf("audit chain checkpoints have been created\n")
+	} else {
+		fmt.Printf("invalide argument")
+	}
+
+	os.Exit(0)
+	return nil
+}
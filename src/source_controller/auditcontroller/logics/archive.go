@@ -0,0 +1,346 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+const (
+	// archivePageSize the number of records read in one batch when archiving
+	archivePageSize = 500
+	// archiveMaxPerFile the max number of records written into one archive file
+	archiveMaxPerFile = 100000
+	// archiveFileSuffix the archive file is gzip compressed NDJSON in mongodb extended json format
+	archiveFileSuffix = ".ndjson.gz"
+)
+
+// ownerOpType the distinct supplier account and operation type of the operation logs
+type ownerOpType struct {
+	ID struct {
+		OwnerID string `bson:"bk_supplier_account"`
+		OpType  int    `bson:"op_type"`
+	} `bson:"_id"`
+}
+
+// ArchiveExpired move the operation logs which exceed the retention days into the archive files,
+// a stub of each archived record is kept so that the audit chain is still verifiable
+func (lgc *Logics) ArchiveExpired(ctx context.Context) (*metadata.AuditArchiveResult, error) {
+	result := &metadata.AuditArchiveResult{Files: make([]string, 0)}
+	if "" == lgc.ArchiveDir {
+		return result, fmt.Errorf("audit archive directory is not configured")
+	}
+
+	groups := make([]ownerOpType, 0)
+	pipeline := []map[string]interface{}{
+		{common.BKDBMatch: map[string]interface{}{auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: 0}}},
+		{"$group": map[string]interface{}{"_id": map[string]interface{}{
+			common.BKOwnerIDField: "$" + common.BKOwnerIDField,
+			common.BKOpTypeField:  "$" + common.BKOpTypeField,
+		}}},
+	}
+	if err := lgc.Instance.Table(common.BKTableNameOperationLog).AggregateAll(ctx, pipeline, &groups); nil != err {
+		blog.Errorf("get operation types of audit logs failed, err: %v", err)
+		return result, err
+	}
+
+	now := time.Now()
+	expired := make(map[string][]interface{})
+	for _, group := range groups {
+		days := lgc.Retention.Days(group.ID.OwnerID, group.ID.OpType)
+		if days <= 0 {
+			continue
+		}
+		expireTime := now.AddDate(0, 0, -days)
+		// the re-imported records are kept for the retention days since they are imported
+		expired[group.ID.OwnerID] = append(expired[group.ID.OwnerID], map[string]interface{}{
+			common.BKOpTypeField: group.ID.OpType,
+			common.BKOpTimeField: map[string]interface{}{common.BKDBLT: expireTime},
+			common.BKDBOR: []map[string]interface{}{
+				{auditoplog.FieldImportTime: map[string]interface{}{common.BKDBExists: false}},
+				{auditoplog.FieldImportTime: map[string]interface{}{common.BKDBLT: expireTime}},
+			},
+		})
+	}
+
+	for ownerID, conds := range expired {
+		cond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: 0},
+			common.BKDBOR:            conds,
+		}
+		for {
+			file, cnt, err := lgc.archiveOnce(ctx, ownerID, cond)
+			if nil != err {
+				blog.Errorf("archive audit logs of %s failed, err: %v", ownerID, err)
+				return result, err
+			}
+			if 0 == cnt {
+				break
+			}
+			result.Files = append(result.Files, file)
+			result.Archived += cnt
+			blog.Infof("archive %d audit logs of %s into %s", cnt, ownerID, file)
+			if cnt < archiveMaxPerFile {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// archiveOnce write at most archiveMaxPerFile expired records into a new archive file,
+// then replace the records with their stubs
+func (lgc *Logics) archiveOnce(ctx context.Context, ownerID string, cond map[string]interface{}) (string, int64, error) {
+	dir := filepath.Join(lgc.ArchiveDir, ownerID)
+	if err := os.MkdirAll(dir, 0755); nil != err {
+		return "", 0, err
+	}
+	name := fmt.Sprintf("audit_%s_%s%s", ownerID, time.Now().Format("20060102150405.000"), archiveFileSuffix)
+	path := filepath.Join(dir, name)
+	tmpPath := path + ".tmp"
+
+	stubs, err := lgc.writeArchive(ctx, tmpPath, name, cond)
+	if nil != err {
+		os.Remove(tmpPath)
+		return "", 0, err
+	}
+	if 0 == len(stubs) {
+		os.Remove(tmpPath)
+		return "", 0, nil
+	}
+	if err := os.Rename(tmpPath, path); nil != err {
+		os.Remove(tmpPath)
+		return "", 0, err
+	}
+
+	firstSeq, lastSeq := stubs[0].(*metadata.AuditArchivedLog).Seq, stubs[len(stubs)-1].(*metadata.AuditArchivedLog).Seq
+	for start := 0; start < len(stubs); start += archivePageSize {
+		end := start + archivePageSize
+		if end > len(stubs) {
+			end = len(stubs)
+		}
+		// the records may have been archived into another file before the process crashed,
+		// so the stale stubs are replaced
+		staleCond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBIN: stubSeqs(stubs[start:end])},
+		}
+		if err := lgc.Instance.Table(common.BKTableNameAuditArchived).Delete(ctx, staleCond); nil != err {
+			return path, 0, err
+		}
+		if err := lgc.Instance.Table(common.BKTableNameAuditArchived).Insert(ctx, stubs[start:end]); nil != err {
+			return path, 0, err
+		}
+	}
+
+	deleteCond := make(map[string]interface{}, len(cond))
+	for key, val := range cond {
+		deleteCond[key] = val
+	}
+	deleteCond[auditoplog.ChainFieldSeq] = map[string]interface{}{common.BKDBGTE: firstSeq, common.BKDBLTE: lastSeq}
+	if err := lgc.Instance.Table(common.BKTableNameOperationLog).Delete(ctx, deleteCond); nil != err {
+		return path, 0, err
+	}
+	return path, int64(len(stubs)), nil
+}
+
+func stubSeqs(stubs []interface{}) []int64 {
+	seqs := make([]int64, 0, len(stubs))
+	for _, stub := range stubs {
+		seqs = append(seqs, stub.(*metadata.AuditArchivedLog).Seq)
+	}
+	return seqs
+}
+
+// writeArchive write the matched records into the gzip compressed NDJSON file in the order of seq,
+// and return the stubs of the written records
+func (lgc *Logics) writeArchive(ctx context.Context, path, name string, cond map[string]interface{}) ([]interface{}, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	writer := bufio.NewWriter(gz)
+
+	stubs := make([]interface{}, 0)
+	lastSeq := int64(0)
+	for len(stubs) < archiveMaxPerFile {
+		pageCond := make(map[string]interface{}, len(cond))
+		for key, val := range cond {
+			pageCond[key] = val
+		}
+		pageCond[auditoplog.ChainFieldSeq] = map[string]interface{}{common.BKDBGT: lastSeq}
+
+		limit := archiveMaxPerFile - len(stubs)
+		if limit > archivePageSize {
+			limit = archivePageSize
+		}
+		docs := make([]bson.M, 0)
+		err := lgc.Instance.Table(common.BKTableNameOperationLog).Find(pageCond).Sort(auditoplog.ChainFieldSeq).Limit(uint64(limit)).All(ctx, &docs)
+		if nil != err {
+			return nil, err
+		}
+		for _, doc := range docs {
+			line, err := bson.MarshalJSON(doc)
+			if nil != err {
+				return nil, err
+			}
+			// the encoder has already append the new line
+			if _, err := writer.Write(line); nil != err {
+				return nil, err
+			}
+
+			stub := new(metadata.AuditArchivedLog)
+			stub.OwnerID, _ = doc[common.BKOwnerIDField].(string)
+			stub.Seq, _ = toInt64(doc[auditoplog.ChainFieldSeq])
+			stub.PrevHash, _ = doc[auditoplog.ChainFieldPrevHash].(string)
+			stub.Hash, _ = doc[auditoplog.ChainFieldHash].(string)
+			stub.CreateTime, _ = doc[common.BKOpTimeField].(time.Time)
+			stub.Archive = name
+			stubs = append(stubs, stub)
+			lastSeq = stub.Seq
+		}
+		if len(docs) < limit {
+			break
+		}
+	}
+
+	if err := writer.Flush(); nil != err {
+		return nil, err
+	}
+	if err := gz.Close(); nil != err {
+		return nil, err
+	}
+	if err := file.Sync(); nil != err {
+		return nil, err
+	}
+	return stubs, nil
+}
+
+// ImportArchive re-import the operation logs from the archive file, each record is checked against
+// its stub before it is restored, so that the audit chain is still intact after the import.
+// the imported records are marked with the import time, and kept for the retention days since then.
+func (lgc *Logics) ImportArchive(ctx context.Context, path string) (*metadata.AuditArchiveResult, error) {
+	result := &metadata.AuditArchiveResult{Files: []string{path}}
+	file, err := os.Open(path)
+	if nil != err {
+		return result, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if nil != err {
+		return result, err
+	}
+	defer gz.Close()
+
+	reader := bufio.NewReader(gz)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if nil != err && io.EOF != err {
+			return result, err
+		}
+		if len(line) > 0 {
+			if err := lgc.importArchivedLine(ctx, line, result); nil != err {
+				return result, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+		}
+		if io.EOF == err {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (lgc *Logics) importArchivedLine(ctx context.Context, line []byte, result *metadata.AuditArchiveResult) error {
+	doc := bson.M{}
+	if err := bson.UnmarshalJSON(line, &doc); nil != err {
+		return err
+	}
+	ownerID, _ := doc[common.BKOwnerIDField].(string)
+	seq, _ := toInt64(doc[auditoplog.ChainFieldSeq])
+	hash, _ := doc[auditoplog.ChainFieldHash].(string)
+
+	actual, err := auditoplog.AuditDocHash(doc)
+	if nil != err {
+		return err
+	}
+	if actual != hash {
+		return fmt.Errorf("record of %s at seq %d has been modified, hash %s, expected %s", ownerID, seq, actual, hash)
+	}
+
+	cond := map[string]interface{}{common.BKOwnerIDField: ownerID, auditoplog.ChainFieldSeq: seq}
+	stub := new(metadata.AuditArchivedLog)
+	err = lgc.Instance.Table(common.BKTableNameAuditArchived).Find(cond).One(ctx, stub)
+	if nil != err {
+		if !lgc.Instance.IsNotFoundError(err) {
+			return err
+		}
+		// the record is not archived, or it has been imported already
+		result.Skipped++
+		return nil
+	}
+	if stub.Hash != hash {
+		return fmt.Errorf("record of %s at seq %d does not match its stub, hash %s, expected %s", ownerID, seq, hash, stub.Hash)
+	}
+
+	doc[auditoplog.FieldImportTime] = time.Now()
+	if err := lgc.Instance.Table(common.BKTableNameOperationLog).Insert(ctx, doc); nil != err {
+		if !lgc.Instance.IsDuplicatedError(err) {
+			return err
+		}
+		result.Skipped++
+	} else {
+		result.Imported++
+	}
+	return lgc.Instance.Table(common.BKTableNameAuditArchived).Delete(ctx, cond)
+}
+
+// RunArchive archive the expired operation logs periodically until the context is done,
+// the directory and the interval are read in every round, so that they take effect when the config is reloaded
+func (lgc *Logics) RunArchive(ctx context.Context) {
+	for {
+		timer := time.NewTimer(lgc.ArchiveInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if "" == lgc.ArchiveDir {
+				blog.V(3).Infof("audit archive directory is not configured, skip archiving the expired audit logs")
+				continue
+			}
+			result, err := lgc.ArchiveExpired(ctx)
+			if nil != err {
+				blog.Errorf("archive expired audit logs failed, err: %v", err)
+				continue
+			}
+			blog.Infof("archive %d expired audit logs into %d files", result.Archived, len(result.Files))
+		}
+	}
+}
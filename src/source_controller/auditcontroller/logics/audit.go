@@ -27,7 +27,7 @@ import (
 
 // AddLogMulti insert multiple row
 func (lgc *Logics) AddLogMulti(ctx context.Context, appID int64, opType auditoplog.AuditOpType, opTarget string, contents []auditoplog.AuditLogContext, opDesc, ownerID, user string) error {
-	var logRows []interface{}
+	var logRows []*metadata.OperationLog
 
 	for _, content := range contents {
 		if instNotChange(content.Content) {
@@ -51,13 +51,12 @@ func (lgc *Logics) AddLogMulti(ctx context.Context, appID int64, opType auditopl
 	if len(logRows) == 0 {
 		return nil
 	}
-	err := lgc.Instance.Table(common.BKTableNameOperationLog).Insert(ctx, logRows)
-	return err
+	return lgc.insertChained(ctx, ownerID, logRows)
 }
 
 // AddLogMultiWithExtKey insert multiple row with  extension key
 func (lgc *Logics) AddLogMultiWithExtKey(ctx context.Context, appID int64, opType auditoplog.AuditOpType, opTarget string, contents []auditoplog.AuditLogExt, opDesc, ownerID, user string) error {
-	var logRows []interface{}
+	var logRows []*metadata.OperationLog
 
 	for _, content := range contents {
 		if instNotChange(content.Content) {
@@ -81,8 +80,7 @@ func (lgc *Logics) AddLogMultiWithExtKey(ctx context.Context, appID int64, opTyp
 	if len(logRows) == 0 {
 		return nil
 	}
-	err := lgc.Instance.Table(common.BKTableNameOperationLog).Insert(ctx, logRows)
-	return err
+	return lgc.insertChained(ctx, ownerID, logRows)
 }
 
 // AddLogWithStr insert row
@@ -102,8 +100,7 @@ func (lgc *Logics) AddLogWithStr(ctx context.Context, appID, instID int64, opTyp
 	if instNotChange(content) {
 		return nil
 	}
-	err := lgc.Instance.Table(common.BKTableNameOperationLog).Insert(ctx, logRow)
-	return err
+	return lgc.insertChained(ctx, ownerID, []*metadata.OperationLog{logRow})
 }
 
 // Search query operation log
@@ -148,7 +145,7 @@ func instNotChange(content interface{}) bool {
 	delete(curData, common.LastTimeField)
 	bl := cmp.Equal(preData, curData)
 	if bl {
-		blog.V(5).Info("inst data same, %#v", content)
+		blog.V(5).Infof("inst data same, %#v", content)
 	}
 	return bl
 }
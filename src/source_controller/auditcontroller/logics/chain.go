@@ -0,0 +1,454 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+const (
+	// chainInsertRetry the max times to relink the rows when another auditcontroller extends the chain at the same time
+	chainInsertRetry = 5
+	// chainVerifyPageSize the number of records read in one batch when walking through the chain
+	chainVerifyPageSize = 500
+)
+
+// the reasons of a broken audit chain link
+const (
+	ChainBrokenSeqGap       = "sequence gap, records are missing"
+	ChainBrokenPrevHash     = "previous hash mismatch, records are missing or reordered"
+	ChainBrokenHash         = "hash mismatch, record content has been modified"
+	ChainBrokenCheckpoint   = "checkpoint hash mismatch, records are rewritten after checkpoint"
+	ChainBrokenSignature    = "checkpoint signature invalid"
+	ChainBrokenTruncated    = "checkpoint beyond the chain head, records are truncated"
+	ChainBrokenInvalidField = "record chain fields is invalid"
+)
+
+// chainHead the last link of the audit chain
+type chainHead struct {
+	Seq  int64  `bson:"seq"`
+	Hash string `bson:"hash"`
+}
+
+var chainLocks = struct {
+	sync.Mutex
+	owners map[string]*sync.Mutex
+}{owners: make(map[string]*sync.Mutex)}
+
+// chainLock serialize the chain writers of the same supplier account in this process,
+// the writers in the other auditcontrollers conflict with them on the unique chain index
+func chainLock(ownerID string) *sync.Mutex {
+	chainLocks.Lock()
+	defer chainLocks.Unlock()
+	lock, ok := chainLocks.owners[ownerID]
+	if !ok {
+		lock = new(sync.Mutex)
+		chainLocks.owners[ownerID] = lock
+	}
+	return lock
+}
+
+// getChainHead return the last link of the audit chain of the supplier account,
+// the last link may have been archived already
+func (lgc *Logics) getChainHead(ctx context.Context, ownerID string) (chainHead, error) {
+	head := chainHead{}
+	cond := map[string]interface{}{
+		common.BKOwnerIDField:    ownerID,
+		auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: 0},
+	}
+	for _, table := range []string{common.BKTableNameOperationLog, common.BKTableNameAuditArchived} {
+		rows := make([]chainHead, 0)
+		err := lgc.Instance.Table(table).Find(cond).Fields(auditoplog.ChainFieldSeq, auditoplog.ChainFieldHash).
+			Sort("-"+auditoplog.ChainFieldSeq).Limit(1).All(ctx, &rows)
+		if nil != err {
+			return chainHead{}, err
+		}
+		if len(rows) > 0 && rows[0].Seq > head.Seq {
+			head = rows[0]
+		}
+	}
+	return head, nil
+}
+
+// insertChained link the rows to the audit chain of the supplier account and insert them one by one,
+// so that only the row conflicting with another auditcontroller is relinked and retried
+func (lgc *Logics) insertChained(ctx context.Context, ownerID string, rows []*metadata.OperationLog) error {
+	lock := chainLock(ownerID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	head, err := lgc.getChainHead(ctx, ownerID)
+	if nil != err {
+		blog.Errorf("get audit chain head of %s failed, err: %v", ownerID, err)
+		return err
+	}
+	for _, row := range rows {
+		head, err = lgc.insertLink(ctx, ownerID, head, row)
+		if nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertLink link the row after the chain head and insert it, return the new chain head
+func (lgc *Logics) insertLink(ctx context.Context, ownerID string, head chainHead, row *metadata.OperationLog) (chainHead, error) {
+	var err error
+	for retry := 0; retry < chainInsertRetry; retry++ {
+		row.Seq = head.Seq + 1
+		row.PrevHash = head.Hash
+		row.Hash = ""
+		row.CreateTime = row.CreateTime.Truncate(time.Millisecond)
+		row.Hash, err = auditoplog.AuditLogHash(row)
+		if nil != err {
+			blog.Errorf("calculate audit log hash failed, err: %v, row: %#v", err, row)
+			return head, err
+		}
+
+		err = lgc.Instance.Table(common.BKTableNameOperationLog).Insert(ctx, row)
+		if nil == err {
+			return chainHead{Seq: row.Seq, Hash: row.Hash}, nil
+		}
+		if !lgc.Instance.IsDuplicatedError(err) {
+			return head, err
+		}
+		// another auditcontroller instance extended the chain at the same time, link to the new head
+		blog.Warnf("audit chain of %s extended by others, relink seq %d, retry %d", ownerID, row.Seq, retry)
+		newHead, headErr := lgc.getChainHead(ctx, ownerID)
+		if nil != headErr {
+			blog.Errorf("get audit chain head of %s failed, err: %v", ownerID, headErr)
+			return head, headErr
+		}
+		head = newHead
+	}
+	return head, err
+}
+
+// checkLink verify the record is the valid successor of the previous link
+func checkLink(prev chainHead, doc bson.M) (chainHead, *metadata.AuditChainBrokenLink) {
+	seq, seqOK := toInt64(doc[auditoplog.ChainFieldSeq])
+	prevHash, prevOK := doc[auditoplog.ChainFieldPrevHash].(string)
+	hash, hashOK := doc[auditoplog.ChainFieldHash].(string)
+	if !seqOK || !prevOK || !hashOK {
+		return prev, &metadata.AuditChainBrokenLink{
+			Seq:      prev.Seq + 1,
+			Reason:   ChainBrokenInvalidField,
+			Expected: fmt.Sprintf("seq %d", prev.Seq+1),
+			Actual:   fmt.Sprintf("%v", doc[auditoplog.ChainFieldSeq]),
+		}
+	}
+
+	if seq != prev.Seq+1 {
+		return prev, &metadata.AuditChainBrokenLink{
+			Seq:      prev.Seq + 1,
+			Reason:   ChainBrokenSeqGap,
+			Expected: fmt.Sprintf("seq %d", prev.Seq+1),
+			Actual:   fmt.Sprintf("seq %d", seq),
+		}
+	}
+
+	if prevHash != prev.Hash {
+		return prev, &metadata.AuditChainBrokenLink{
+			Seq:      seq,
+			Reason:   ChainBrokenPrevHash,
+			Expected: prev.Hash,
+			Actual:   prevHash,
+		}
+	}
+
+	actual, err := auditoplog.AuditDocHash(doc)
+	if nil != err || actual != hash {
+		return prev, &metadata.AuditChainBrokenLink{
+			Seq:      seq,
+			Reason:   ChainBrokenHash,
+			Expected: hash,
+			Actual:   actual,
+		}
+	}
+
+	return chainHead{Seq: seq, Hash: hash}, nil
+}
+
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// VerifyChain walk through the audit chain of the supplier account and report the first broken link.
+// the records written before the chain was introduced have no sequence and are skipped.
+func (lgc *Logics) VerifyChain(ctx context.Context, ownerID string) (*metadata.AuditChainVerifyResult, error) {
+	result := &metadata.AuditChainVerifyResult{OwnerID: ownerID, Intact: true}
+
+	checkpoints := make([]metadata.AuditChainCheckpoint, 0)
+	cpCond := map[string]interface{}{common.BKOwnerIDField: ownerID}
+	if err := lgc.Instance.Table(common.BKTableNameAuditCheckpoint).Find(cpCond).Sort(auditoplog.ChainFieldSeq).All(ctx, &checkpoints); nil != err {
+		blog.Errorf("get audit checkpoints of %s failed, err: %v", ownerID, err)
+		return nil, err
+	}
+	result.Checkpoints = int64(len(checkpoints))
+	checkpointMap := make(map[int64][]metadata.AuditChainCheckpoint)
+	for _, cp := range checkpoints {
+		if lgc.CheckpointKey != "" && !hmac.Equal([]byte(cp.Signature), []byte(SignCheckpoint(lgc.CheckpointKey, &cp))) {
+			result.Intact = false
+			result.BrokenLink = &metadata.AuditChainBrokenLink{
+				Seq:      cp.Seq,
+				Reason:   ChainBrokenSignature,
+				Expected: SignCheckpoint(lgc.CheckpointKey, &cp),
+				Actual:   cp.Signature,
+			}
+			return result, nil
+		}
+		checkpointMap[cp.Seq] = append(checkpointMap[cp.Seq], cp)
+	}
+
+	walker := &chainWalker{result: result, checkpoints: checkpointMap}
+	for {
+		docs := make([]bson.M, 0)
+		cond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: walker.prev.Seq},
+		}
+		err := lgc.Instance.Table(common.BKTableNameOperationLog).Find(cond).Sort(auditoplog.ChainFieldSeq).Limit(chainVerifyPageSize).All(ctx, &docs)
+		if nil != err {
+			blog.Errorf("walk through audit chain of %s failed, err: %v", ownerID, err)
+			return nil, err
+		}
+
+		for _, doc := range docs {
+			// the records between are archived, walk through their stubs
+			if seq, ok := toInt64(doc[auditoplog.ChainFieldSeq]); ok && seq > walker.prev.Seq+1 {
+				if err := lgc.walkArchived(ctx, ownerID, walker, seq-1); nil != err {
+					return nil, err
+				}
+				if !result.Intact {
+					return result, nil
+				}
+			}
+
+			next, broken := checkLink(walker.prev, doc)
+			if nil != broken {
+				result.Intact = false
+				result.BrokenLink = broken
+				return result, nil
+			}
+			if !walker.advance(next) {
+				return result, nil
+			}
+		}
+
+		if len(docs) < chainVerifyPageSize {
+			break
+		}
+	}
+
+	// the newest records may be archived too
+	if err := lgc.walkArchived(ctx, ownerID, walker, 0); nil != err {
+		return nil, err
+	}
+	if !result.Intact {
+		return result, nil
+	}
+
+	if len(checkpoints) > 0 {
+		last := checkpoints[len(checkpoints)-1]
+		if last.Seq > result.HeadSeq {
+			result.Intact = false
+			result.BrokenLink = &metadata.AuditChainBrokenLink{
+				Seq:      result.HeadSeq + 1,
+				Reason:   ChainBrokenTruncated,
+				Expected: fmt.Sprintf("seq %d", last.Seq),
+				Actual:   fmt.Sprintf("seq %d", result.HeadSeq),
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// chainWalker keep the state of walking through the audit chain
+type chainWalker struct {
+	prev        chainHead
+	result      *metadata.AuditChainVerifyResult
+	checkpoints map[int64][]metadata.AuditChainCheckpoint
+}
+
+// advance move the walker to the next link which has been checked, and return false if it
+// doesn't match the checkpoint
+func (w *chainWalker) advance(next chainHead) bool {
+	w.prev = next
+	w.result.Checked++
+	w.result.HeadSeq = next.Seq
+	for _, cp := range w.checkpoints[next.Seq] {
+		if cp.Hash != next.Hash {
+			w.result.Intact = false
+			w.result.BrokenLink = &metadata.AuditChainBrokenLink{
+				Seq:      next.Seq,
+				Reason:   ChainBrokenCheckpoint,
+				Expected: cp.Hash,
+				Actual:   next.Hash,
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// walkArchived walk through the stubs of the archived records after the walker, until the seq, 0 means no limit.
+// the content of the archived records can not be checked here, it's checked when they are re-imported.
+func (lgc *Logics) walkArchived(ctx context.Context, ownerID string, walker *chainWalker, until int64) error {
+	for {
+		seqCond := map[string]interface{}{common.BKDBGT: walker.prev.Seq}
+		if until > 0 {
+			seqCond[common.BKDBLTE] = until
+		}
+		cond := map[string]interface{}{
+			common.BKOwnerIDField:    ownerID,
+			auditoplog.ChainFieldSeq: seqCond,
+		}
+		stubs := make([]metadata.AuditArchivedLog, 0)
+		err := lgc.Instance.Table(common.BKTableNameAuditArchived).Find(cond).Sort(auditoplog.ChainFieldSeq).Limit(chainVerifyPageSize).All(ctx, &stubs)
+		if nil != err {
+			blog.Errorf("walk through archived audit logs of %s failed, err: %v", ownerID, err)
+			return err
+		}
+
+		for _, stub := range stubs {
+			if stub.Seq != walker.prev.Seq+1 {
+				walker.result.Intact = false
+				walker.result.BrokenLink = &metadata.AuditChainBrokenLink{
+					Seq:      walker.prev.Seq + 1,
+					Reason:   ChainBrokenSeqGap,
+					Expected: fmt.Sprintf("seq %d", walker.prev.Seq+1),
+					Actual:   fmt.Sprintf("seq %d", stub.Seq),
+				}
+				return nil
+			}
+			if stub.PrevHash != walker.prev.Hash {
+				walker.result.Intact = false
+				walker.result.BrokenLink = &metadata.AuditChainBrokenLink{
+					Seq:      stub.Seq,
+					Reason:   ChainBrokenPrevHash,
+					Expected: walker.prev.Hash,
+					Actual:   stub.PrevHash,
+				}
+				return nil
+			}
+			if !walker.advance(chainHead{Seq: stub.Seq, Hash: stub.Hash}) {
+				return nil
+			}
+		}
+
+		if len(stubs) < chainVerifyPageSize {
+			return nil
+		}
+	}
+}
+
+// SignCheckpoint sign the checkpoint with HMAC-SHA256
+func SignCheckpoint(key string, cp *metadata.AuditChainCheckpoint) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	fmt.Fprintf(mac, "%s|%d|%s|%d", cp.OwnerID, cp.Seq, cp.Hash, cp.CreateTime.UTC().UnixNano()/int64(time.Millisecond))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateCheckpoints write a signed checkpoint for the chain head of every supplier account which has new records
+func (lgc *Logics) CreateCheckpoints(ctx context.Context) error {
+	owners := make([]struct {
+		OwnerID string `bson:"_id"`
+	}, 0)
+	pipeline := []map[string]interface{}{
+		{common.BKDBMatch: map[string]interface{}{auditoplog.ChainFieldSeq: map[string]interface{}{common.BKDBGT: 0}}},
+		{"$group": map[string]interface{}{"_id": "$" + common.BKOwnerIDField}},
+	}
+	if err := lgc.Instance.Table(common.BKTableNameOperationLog).AggregateAll(ctx, pipeline, &owners); nil != err {
+		blog.Errorf("get supplier accounts of audit chain failed, err: %v", err)
+		return err
+	}
+
+	for _, owner := range owners {
+		head, err := lgc.getChainHead(ctx, owner.OwnerID)
+		if nil != err {
+			blog.Errorf("get audit chain head of %s failed, err: %v", owner.OwnerID, err)
+			return err
+		}
+
+		last := make([]metadata.AuditChainCheckpoint, 0)
+		cond := map[string]interface{}{common.BKOwnerIDField: owner.OwnerID}
+		err = lgc.Instance.Table(common.BKTableNameAuditCheckpoint).Find(cond).Sort("-"+auditoplog.ChainFieldSeq).Limit(1).All(ctx, &last)
+		if nil != err {
+			blog.Errorf("get last audit checkpoint of %s failed, err: %v", owner.OwnerID, err)
+			return err
+		}
+		if len(last) > 0 && last[0].Seq >= head.Seq {
+			continue
+		}
+
+		cp := &metadata.AuditChainCheckpoint{
+			OwnerID:    owner.OwnerID,
+			Seq:        head.Seq,
+			Hash:       head.Hash,
+			CreateTime: time.Now().Truncate(time.Millisecond),
+		}
+		cp.Signature = SignCheckpoint(lgc.CheckpointKey, cp)
+		if err := lgc.Instance.Table(common.BKTableNameAuditCheckpoint).Insert(ctx, cp); nil != err {
+			blog.Errorf("create audit checkpoint of %s failed, err: %v", owner.OwnerID, err)
+			return err
+		}
+		blog.V(3).Infof("create audit checkpoint of %s at seq %d", owner.OwnerID, head.Seq)
+	}
+	return nil
+}
+
+// RunCheckpoint create the signed checkpoints periodically until the context is done,
+// the key and the interval are read in every round, so that they take effect when the config is reloaded
+func (lgc *Logics) RunCheckpoint(ctx context.Context) {
+	for {
+		timer := time.NewTimer(lgc.CheckpointInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if lgc.CheckpointKey == "" {
+				blog.V(3).Infof("audit checkpoint key is not configured, skip creating signed checkpoints")
+				continue
+			}
+			if err := lgc.CreateCheckpoints(ctx); nil != err {
+				blog.Errorf("create audit checkpoints failed, err: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/metadata"
+)
+
+func buildChain(t *testing.T, n int) []bson.M {
+	docs := make([]bson.M, 0, n)
+	prev := chainHead{}
+	for i := 0; i < n; i++ {
+		row := &metadata.OperationLog{
+			OwnerID:    "0",
+			OpType:     int(auditoplog.AuditOpTypeModify),
+			Content:    map[string]interface{}{"bk_inst_id": i},
+			CreateTime: time.Now(),
+			Seq:        prev.Seq + 1,
+			PrevHash:   prev.Hash,
+		}
+		hash, err := auditoplog.AuditLogHash(row)
+		require.NoError(t, err)
+		row.Hash = hash
+		prev = chainHead{Seq: row.Seq, Hash: hash}
+
+		out, err := bson.Marshal(row)
+		require.NoError(t, err)
+		doc := bson.M{}
+		require.NoError(t, bson.Unmarshal(out, &doc))
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func walkChain(docs []bson.M) *metadata.AuditChainBrokenLink {
+	prev := chainHead{}
+	for _, doc := range docs {
+		var broken *metadata.AuditChainBrokenLink
+		prev, broken = checkLink(prev, doc)
+		if nil != broken {
+			return broken
+		}
+	}
+	return nil
+}
+
+func TestCheckLinkIntact(t *testing.T) {
+	require.Nil(t, walkChain(buildChain(t, 5)))
+}
+
+func TestCheckLinkModified(t *testing.T) {
+	docs := buildChain(t, 5)
+	docs[2]["operator"] = "mallory"
+	broken := walkChain(docs)
+	require.NotNil(t, broken)
+	require.Equal(t, int64(3), broken.Seq)
+	require.Equal(t, ChainBrokenHash, broken.Reason)
+}
+
+func TestCheckLinkDeleted(t *testing.T) {
+	docs := buildChain(t, 5)
+	docs = append(docs[:1], docs[2:]...)
+	broken := walkChain(docs)
+	require.NotNil(t, broken)
+	require.Equal(t, int64(2), broken.Seq)
+	require.Equal(t, ChainBrokenSeqGap, broken.Reason)
+}
+
+func TestCheckLinkRenumbered(t *testing.T) {
+	docs := buildChain(t, 5)
+	// delete the second record and renumber the rest, the previous hash still breaks
+	docs = append(docs[:1], docs[2:]...)
+	for idx := 1; idx < len(docs); idx++ {
+		docs[idx][auditoplog.ChainFieldSeq] = int64(idx + 1)
+	}
+	broken := walkChain(docs)
+	require.NotNil(t, broken)
+	require.Equal(t, int64(2), broken.Seq)
+	require.Equal(t, ChainBrokenPrevHash, broken.Reason)
+}
+
+func TestSignCheckpoint(t *testing.T) {
+	cp := &metadata.AuditChainCheckpoint{OwnerID: "0", Seq: 10, Hash: "abc", CreateTime: time.Now()}
+	sign := SignCheckpoint("key", cp)
+	require.Equal(t, sign, SignCheckpoint("key", cp))
+	require.NotEqual(t, sign, SignCheckpoint("other", cp))
+	cp.Seq = 11
+	require.NotEqual(t, sign, SignCheckpoint("key", cp))
+}
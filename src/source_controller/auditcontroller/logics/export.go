@@ -0,0 +1,155 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditoplog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+// exportPageSize the number of records read in one batch when exporting
+const exportPageSize = 500
+
+// exportHeader the columns of the exported csv file
+var exportHeader = []string{
+	common.BKOpTimeField,
+	common.BKOwnerIDField,
+	common.BKAppIDField,
+	"operator",
+	common.BKOpTypeField,
+	"op_target",
+	"inst_id",
+	"ext_key",
+	common.BKOpDescField,
+	"content",
+	auditoplog.ChainFieldSeq,
+	auditoplog.ChainFieldHash,
+}
+
+// ExportCondition build the query condition of the export option
+func ExportCondition(ownerID string, opt *metadata.AuditExportOption) map[string]interface{} {
+	cond := map[string]interface{}{common.BKOwnerIDField: ownerID}
+	timeCond := make(map[string]interface{})
+	if nil != opt.StartTime {
+		timeCond[common.BKDBGTE] = opt.StartTime.Time
+	}
+	if nil != opt.EndTime {
+		timeCond[common.BKDBLTE] = opt.EndTime.Time
+	}
+	if len(timeCond) > 0 {
+		cond[common.BKOpTimeField] = timeCond
+	}
+	if "" != opt.User {
+		cond["operator"] = opt.User
+	}
+	if "" != opt.OpTarget {
+		cond["op_target"] = opt.OpTarget
+	}
+	if 0 != opt.InstID {
+		cond["inst_id"] = opt.InstID
+	}
+	if 0 != opt.OpType {
+		cond[common.BKOpTypeField] = opt.OpType
+	}
+	if 0 != opt.BizID {
+		cond[common.BKAppIDField] = opt.BizID
+	}
+	return cond
+}
+
+// Export write the matched operation logs to the writer page by page in the order of seq,
+// the writer is flushed after each page if it supports, so the memory usage does not grow with the result
+func (lgc *Logics) Export(ctx context.Context, ownerID string, opt *metadata.AuditExportOption, w io.Writer) (int64, error) {
+	var encode func(row *metadata.OperationLog) error
+	var csvWriter *csv.Writer
+	switch opt.Format {
+	case metadata.AuditExportFormatCSV:
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(exportHeader); nil != err {
+			return 0, err
+		}
+		encode = func(row *metadata.OperationLog) error {
+			return csvWriter.Write(exportCSVRecord(row))
+		}
+	case metadata.AuditExportFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		encode = func(row *metadata.OperationLog) error {
+			return encoder.Encode(row)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported export format %s", opt.Format)
+	}
+
+	cond := ExportCondition(ownerID, opt)
+	lastSeq := int64(0)
+	cnt := int64(0)
+	for {
+		cond[auditoplog.ChainFieldSeq] = map[string]interface{}{common.BKDBGT: lastSeq}
+		rows := make([]metadata.OperationLog, 0)
+		err := lgc.Instance.Table(common.BKTableNameOperationLog).Find(cond).Sort(auditoplog.ChainFieldSeq).Limit(exportPageSize).All(ctx, &rows)
+		if nil != err {
+			blog.Errorf("export audit logs failed, err: %v, condition: %v", err, cond)
+			return cnt, err
+		}
+		for idx := range rows {
+			if err := encode(&rows[idx]); nil != err {
+				return cnt, err
+			}
+			lastSeq = rows[idx].Seq
+			cnt++
+		}
+		if nil != csvWriter {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); nil != err {
+				return cnt, err
+			}
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if len(rows) < exportPageSize {
+			return cnt, nil
+		}
+	}
+}
+
+func exportCSVRecord(row *metadata.OperationLog) []string {
+	content, err := json.Marshal(row.Content)
+	if nil != err {
+		content = []byte(fmt.Sprintf("%v", row.Content))
+	}
+	return []string{
+		row.CreateTime.Format("2006-01-02 15:04:05"),
+		row.OwnerID,
+		strconv.FormatInt(row.ApplicationID, 10),
+		row.User,
+		strconv.Itoa(row.OpType),
+		row.OpTarget,
+		strconv.FormatInt(row.InstID, 10),
+		row.ExtKey,
+		row.OpDesc,
+		string(content),
+		strconv.FormatInt(row.Seq, 10),
+		row.Hash,
+	}
+}
@@ -13,6 +13,8 @@
 package logics
 
 import (
+	"time"
+
 	"configcenter/src/common/backbone"
 	"configcenter/src/storage/dal"
 )
@@ -21,4 +23,14 @@ import (
 type Logics struct {
 	*backbone.Engine
 	Instance dal.RDB
+	// CheckpointKey the HMAC key to sign and verify the audit chain checkpoints
+	CheckpointKey string
+	// CheckpointInterval the interval of creating the checkpoints, it's reloaded with the config
+	CheckpointInterval time.Duration
+	// ArchiveDir the local directory to store the archived operation logs
+	ArchiveDir string
+	// ArchiveInterval the interval of archiving the expired operation logs, it's reloaded with the config
+	ArchiveInterval time.Duration
+	// Retention decide when the operation logs are archived
+	Retention *RetentionPolicy
 }
@@ -0,0 +1,97 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// retentionAny match any supplier account or operation type in the retention rules
+const retentionAny = "*"
+
+// RetentionRule keep the operation logs of the supplier account and operation type for the days
+type RetentionRule struct {
+	OwnerID string
+	OpType  string
+	Days    int
+}
+
+// RetentionPolicy decide how many days the operation logs are kept before they are archived
+type RetentionPolicy struct {
+	// DefaultDays apply to the logs which match none of the rules, 0 means keep forever
+	DefaultDays int
+	Rules       []RetentionRule
+}
+
+// ParseRetentionPolicy parse the retention rules like "0:1:180,*:3:30",
+// each rule is made of supplier account, operation type and days, "*" match any value
+func ParseRetentionPolicy(defaultDays int, rules string) (*RetentionPolicy, error) {
+	policy := &RetentionPolicy{DefaultDays: defaultDays}
+	for _, item := range strings.Split(rules, ",") {
+		item = strings.TrimSpace(item)
+		if "" == item {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if 3 != len(fields) {
+			return nil, fmt.Errorf("invalid retention rule %s, should be owner:op_type:days", item)
+		}
+		opType := strings.TrimSpace(fields[1])
+		if opType != retentionAny {
+			if _, err := strconv.Atoi(opType); nil != err {
+				return nil, fmt.Errorf("invalid retention rule %s, op_type should be integer or *", item)
+			}
+		}
+		days, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if nil != err || days < 0 {
+			return nil, fmt.Errorf("invalid retention rule %s, days should be non-negative integer", item)
+		}
+		policy.Rules = append(policy.Rules, RetentionRule{
+			OwnerID: strings.TrimSpace(fields[0]),
+			OpType:  opType,
+			Days:    days,
+		})
+	}
+	return policy, nil
+}
+
+// Days return the retention days of the operation logs, the most specific rule wins:
+// owner and op type > owner only > op type only > default
+func (p *RetentionPolicy) Days(ownerID string, opType int) int {
+	if nil == p {
+		return 0
+	}
+	strOpType := strconv.Itoa(opType)
+	best, bestScore := p.DefaultDays, 0
+	for _, rule := range p.Rules {
+		score := 1
+		if rule.OwnerID != retentionAny {
+			if rule.OwnerID != ownerID {
+				continue
+			}
+			score += 2
+		}
+		if rule.OpType != retentionAny {
+			if rule.OpType != strOpType {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule.Days, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionPolicy(t *testing.T) {
+	policy, err := ParseRetentionPolicy(90, " 0:1:180, *:3:30 ,0:*:60,*:*:120")
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 4)
+	require.Equal(t, RetentionRule{OwnerID: "0", OpType: "1", Days: 180}, policy.Rules[0])
+
+	for _, rules := range []string{"0:1", "0:a:10", "0:1:-1", "0:1:x"} {
+		_, err := ParseRetentionPolicy(0, rules)
+		require.Error(t, err, rules)
+	}
+}
+
+func TestRetentionPolicyDays(t *testing.T) {
+	policy, err := ParseRetentionPolicy(90, "0:1:180,*:3:30,0:*:60")
+	require.NoError(t, err)
+
+	require.Equal(t, 180, policy.Days("0", 1))
+	require.Equal(t, 60, policy.Days("0", 3))
+	require.Equal(t, 30, policy.Days("1", 3))
+	require.Equal(t, 90, policy.Days("1", 2))
+
+	var empty *RetentionPolicy
+	require.Equal(t, 0, empty.Days("0", 1))
+}
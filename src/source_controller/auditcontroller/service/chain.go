@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	restful "github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// VerifyChain walk through the audit chain of the supplier account and report the first broken link
+func (s *Service) VerifyChain(req *restful.Request, resp *restful.Response) {
+	language := util.GetActionLanguage(req)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(language)
+	ownerID := util.GetOwnerID(req.Request.Header)
+	ctx := util.GetDBContext(context.Background(), req.Request.Header)
+
+	result, err := s.Logics.VerifyChain(ctx, ownerID)
+	if nil != err {
+		blog.Errorf("VerifyChain verify audit chain of %s failed, err: %v", ownerID, err)
+		resp.WriteError(http.StatusInternalServerError, &metadata.RespError{Msg: defErr.Error(common.CCErrCommDBSelectFailed)})
+		return
+	}
+	if !result.Intact {
+		blog.Warnf("VerifyChain audit chain of %s is broken at seq %d, reason: %s", ownerID, result.BrokenLink.Seq, result.BrokenLink.Reason)
+	}
+	resp.WriteEntity(metadata.NewSuccessResp(result))
+}
@@ -0,0 +1,69 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// Export stream the matched operation logs in csv or ndjson format
+func (s *Service) Export(req *restful.Request, resp *restful.Response) {
+	language := util.GetActionLanguage(req)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(language)
+	ownerID := util.GetOwnerID(req.Request.Header)
+	ctx := util.GetDBContext(context.Background(), req.Request.Header)
+
+	opt := new(metadata.AuditExportOption)
+	if err := json.NewDecoder(req.Request.Body).Decode(opt); nil != err {
+		blog.Errorf("Export json unmarshal failed, error:%v", err)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+
+	var contentType string
+	switch opt.Format {
+	case metadata.AuditExportFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case "", metadata.AuditExportFormatNDJSON:
+		opt.Format = metadata.AuditExportFormatNDJSON
+		contentType = "application/x-ndjson"
+	default:
+		blog.Errorf("Export unsupported format %s", opt.Format)
+		resp.WriteError(http.StatusBadRequest, &metadata.RespError{Msg: defErr.Errorf(common.CCErrCommParamsIsInvalid, "format")})
+		return
+	}
+
+	fileName := fmt.Sprintf("audit_%s_%s.%s", ownerID, time.Now().Format("20060102150405"), opt.Format)
+	resp.Header().Set("Content-Type", contentType)
+	resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	resp.WriteHeader(http.StatusOK)
+
+	// the header has been sent, the error can only be logged
+	cnt, err := s.Logics.Export(ctx, ownerID, opt, resp.ResponseWriter)
+	if nil != err {
+		blog.Errorf("Export audit logs of %s failed after %d records, err: %v", ownerID, cnt, err)
+		return
+	}
+	blog.V(4).Infof("Export %d audit logs of %s", cnt, ownerID)
+}
@@ -53,6 +53,8 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.POST("set/{owner_id}/{biz_id}/{user}").To(s.AddSetLog))
 	ws.Route(ws.POST("/sets/{owner_id}/{biz_id}/{user}").To(s.AddSetLogs))
 	ws.Route(ws.POST("/search").To(s.Get))
+	ws.Route(ws.POST("/chain/verify").To(s.VerifyChain))
+	ws.Route(ws.POST("/export").Produces(restful.MIME_JSON, "text/csv", "application/x-ndjson").To(s.Export))
 	ws.Route(ws.GET("/healthz").To(s.Healthz))
 
 	return ws
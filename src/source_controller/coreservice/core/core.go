@@ -81,6 +81,7 @@ type InstanceOperation interface {
 	CreateModelInstance(ctx ContextParams, objID string, inputParam metadata.CreateModelInstance) (*metadata.CreateOneDataResult, error)
 	CreateManyModelInstance(ctx ContextParams, objID string, inputParam metadata.CreateManyModelInstance) (*metadata.CreateManyDataResult, error)
 	UpdateModelInstance(ctx ContextParams, objID string, inputParam metadata.UpdateOption) (*metadata.UpdatedCount, error)
+	ValidateManyModelInstance(ctx ContextParams, objID string, inputParam metadata.ValidateManyModelInstance) (*metadata.ValidatedManyDataResult, error)
 	SearchModelInstance(ctx ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error)
 	DeleteModelInstance(ctx ContextParams, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
 	CascadeDeleteModelInstance(ctx ContextParams, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
@@ -124,6 +125,8 @@ type DataSynchronizeOperation interface {
 	SynchronizeAssociationAdapter(ctx ContextParams, syncData *metadata.SynchronizeParameter) ([]metadata.ExceptionResult, error)
 	Find(ctx ContextParams, find *metadata.SynchronizeFindInfoParameter) ([]mapstr.MapStr, uint64, error)
 	ClearData(ctx ContextParams, input *metadata.SynchronizeClearDataParameter) error
+	SearchConflict(ctx ContextParams, input *metadata.SynchronizeConflictSearchParameter) ([]metadata.SynchronizeConflict, uint64, error)
+	ResolveConflict(ctx ContextParams, id int64, input *metadata.SynchronizeConflictResolveParameter) error
 }
 
 // AssociationOperation association methods
@@ -0,0 +1,304 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasynchronize
+
+import (
+	"time"
+
+	"github.com/coccyx/timeparser"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core"
+)
+
+// setSynchronizeLastTime record the last_time of the source data in the metadata,
+// the target data edited after the synchronization has a different last_time
+func setSynchronizeLastTime(info mapstr.MapStr) {
+	lastTime, ok := info.Get(common.LastTimeField)
+	if !ok {
+		return
+	}
+	meta, err := info.MapStr(common.MetadataField)
+	if err != nil {
+		return
+	}
+	meta.Set(common.MetaDataSynchronizeLastTimeField, lastTime)
+	info.Set(common.MetadataField, meta)
+}
+
+// getSynchronizeLastTime the last_time of the source data when the target data is synchronized
+func getSynchronizeLastTime(info mapstr.MapStr) (interface{}, bool) {
+	meta, err := info.MapStr(common.MetadataField)
+	if err != nil {
+		return nil, false
+	}
+	return meta.Get(common.MetaDataSynchronizeLastTimeField)
+}
+
+// parseSynchronizeTime the last_time is a time in the target db, a string or a unix time after json
+func parseSynchronizeTime(val interface{}) (time.Time, bool) {
+	switch t := val.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, false
+		}
+		return *t, true
+	case metadata.Time:
+		return t.Time, true
+	case string:
+		ts, err := timeparser.TimeParser(t)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return ts, true
+	default:
+		ts, err := util.GetInt64ByInterface(val)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(ts, 0), true
+	}
+}
+
+func sameSynchronizeTime(a, b interface{}) bool {
+	ta, okA := parseSynchronizeTime(a)
+	tb, okB := parseSynchronizeTime(b)
+	if !okA || !okB {
+		return false
+	}
+	// the time in db is milliseconds
+	return ta.Truncate(time.Millisecond).Equal(tb.Truncate(time.Millisecond))
+}
+
+// decideConflict decide which side is kept when the instance exists in the target.
+// the target data edited after the last synchronization is kept or overwritten according to the policy,
+// it is a conflict when the source data is edited too.
+func decideConflict(policy metadata.SynchronizeConflictPolicy, source, target mapstr.MapStr) (resolution metadata.SynchronizeConflictPolicy, conflict bool) {
+	syncLastTime, ok := getSynchronizeLastTime(target)
+	if !ok {
+		// synchronized by the old version, not know whether the target data is edited
+		return metadata.SynchronizeConflictPolicySource, false
+	}
+	if sameSynchronizeTime(target[common.LastTimeField], syncLastTime) {
+		return metadata.SynchronizeConflictPolicySource, false
+	}
+	conflict = !sameSynchronizeTime(source[common.LastTimeField], syncLastTime)
+
+	switch policy {
+	case metadata.SynchronizeConflictPolicyTarget, metadata.SynchronizeConflictPolicyManual:
+		return metadata.SynchronizeConflictPolicyTarget, conflict
+	case metadata.SynchronizeConflictPolicyNewest:
+		sourceTime, _ := parseSynchronizeTime(source[common.LastTimeField])
+		targetTime, _ := parseSynchronizeTime(target[common.LastTimeField])
+		if sourceTime.After(targetTime) {
+			return metadata.SynchronizeConflictPolicySource, conflict
+		}
+		return metadata.SynchronizeConflictPolicyTarget, conflict
+	default:
+		return metadata.SynchronizeConflictPolicySource, conflict
+	}
+}
+
+// handleConflict check the target data edited after the last synchronization, return true when the source data can be written.
+// the target is loaded with the whole page by findSynchronizeTargets
+func (s *synchronizeAdapter) handleConflict(ctx core.ContextParams, dbParam synchronizeAdapterDBParameter, conds mapstr.MapStr, item *metadata.SynchronizeItem, target mapstr.MapStr) (bool, errors.CCError) {
+	resolution, conflict := decideConflict(s.syncData.ConflictPolicy, item.Info, target)
+	pending := conflict && s.syncData.ConflictPolicy == metadata.SynchronizeConflictPolicyManual
+	if conflict {
+		if err := s.saveConflict(ctx, item, target, resolution, pending); err != nil {
+			return false, err
+		}
+	}
+	if resolution == metadata.SynchronizeConflictPolicySource {
+		return true, nil
+	}
+
+	// the target data is kept, the synchronize version is updated to prevent the data being cleared
+	keepData := mapstr.MapStr{
+		getSynchronize(common.MetadataField, common.MetaDataSynchronizeFlagField):    s.syncData.SynchronizeFlag,
+		getSynchronize(common.MetadataField, common.MetaDataSynchronizeVersionField): s.syncData.Version,
+	}
+	// the pending conflict keeps the old source last_time, the source data is checked again until it is resolved
+	if lastTime, ok := item.Info.Get(common.LastTimeField); ok && !pending {
+		keepData.Set(getSynchronize(common.MetadataField, common.MetaDataSynchronizeLastTimeField), lastTime)
+	}
+	if err := s.dbProxy.Table(dbParam.tableName).Update(ctx, conds, keepData); err != nil {
+		blog.Errorf("handleConflict update target data error,err:%s.DataClassify:%s,condition:%#v,rid:%s", err.Error(), s.syncData.DataClassify, conds, ctx.ReqID)
+		return false, ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+	}
+	return false, nil
+}
+
+// saveConflict put the conflict to the queue with both versions, the manual conflict is pending until it is resolved
+func (s *synchronizeAdapter) saveConflict(ctx core.ContextParams, item *metadata.SynchronizeItem, target mapstr.MapStr, resolution metadata.SynchronizeConflictPolicy, pending bool) errors.CCError {
+	target.Remove("_id")
+	now := time.Now().UTC()
+	conflict := metadata.SynchronizeConflict{
+		SynchronizeFlag: s.syncData.SynchronizeFlag,
+		DataClassify:    s.syncData.DataClassify,
+		InstID:          item.ID,
+		Source:          item.Info,
+		Target:          target,
+		Policy:          s.syncData.ConflictPolicy,
+		Status:          metadata.SynchronizeConflictStatusResolved,
+		Resolution:      resolution,
+		Resolver:        ctx.User,
+		CreateTime:      now,
+		LastTime:        now,
+	}
+	if conflict.Policy == "" {
+		conflict.Policy = metadata.SynchronizeConflictPolicySource
+	}
+
+	if pending {
+		conflict.Status = metadata.SynchronizeConflictStatusPending
+		conflict.Resolution = ""
+		conflict.Resolver = ""
+		// the instance has only one pending conflict, it is refreshed by the newer source data
+		pendingConds := mapstr.MapStr{
+			"synchronize_flag": conflict.SynchronizeFlag,
+			"data_classify":    conflict.DataClassify,
+			"inst_id":          conflict.InstID,
+			"status":           metadata.SynchronizeConflictStatusPending,
+		}
+		cnt, err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(pendingConds).Count(ctx)
+		if err != nil {
+			blog.Errorf("saveConflict find pending conflict error,err:%s,condition:%#v,rid:%s", err.Error(), pendingConds, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+		}
+		if cnt > 0 {
+			updateData := mapstr.MapStr{"source": conflict.Source, "target": conflict.Target, common.LastTimeField: now}
+			if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Update(ctx, pendingConds, updateData); err != nil {
+				blog.Errorf("saveConflict update pending conflict error,err:%s,condition:%#v,rid:%s", err.Error(), pendingConds, ctx.ReqID)
+				return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+			}
+			return nil
+		}
+	}
+
+	id, err := s.dbProxy.NextSequence(ctx, common.BKTableNameSynchronizeConflict)
+	if err != nil {
+		blog.Errorf("saveConflict get id error,err:%s,rid:%s", err.Error(), ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	conflict.ID = int64(id)
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Insert(ctx, conflict); err != nil {
+		blog.Errorf("saveConflict insert conflict error,err:%s,conflict:%#v,rid:%s", err.Error(), conflict, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBInsertFailed)
+	}
+	return nil
+}
+
+// SearchConflict search the conflict queue
+func (s *SynchronizeManager) SearchConflict(ctx core.ContextParams, input *metadata.SynchronizeConflictSearchParameter) ([]metadata.SynchronizeConflict, uint64, error) {
+	conds := mapstr.New()
+	if input.SynchronizeFlag != "" {
+		conds.Set("synchronize_flag", input.SynchronizeFlag)
+	}
+	if input.DataClassify != "" {
+		conds.Set("data_classify", input.DataClassify)
+	}
+	if input.InstID != 0 {
+		conds.Set("inst_id", input.InstID)
+	}
+	if input.Status != "" {
+		conds.Set("status", input.Status)
+	}
+
+	cnt, err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(conds).Count(ctx)
+	if err != nil {
+		blog.Errorf("SearchConflict count error,err:%s,condition:%#v,rid:%s", err.Error(), conds, ctx.ReqID)
+		return nil, 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	sort := input.Page.Sort
+	if sort == "" {
+		sort = "-id"
+	}
+	conflicts := make([]metadata.SynchronizeConflict, 0)
+	err = s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(conds).Sort(sort).
+		Start(uint64(input.Page.Start)).Limit(uint64(input.Page.Limit)).All(ctx, &conflicts)
+	if err != nil {
+		blog.Errorf("SearchConflict find error,err:%s,condition:%#v,rid:%s", err.Error(), conds, ctx.ReqID)
+		return nil, 0, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	return conflicts, cnt, nil
+}
+
+// ResolveConflict resolve the pending conflict, keep the source or the target data
+func (s *SynchronizeManager) ResolveConflict(ctx core.ContextParams, id int64, input *metadata.SynchronizeConflictResolveParameter) error {
+	if input.Resolution != metadata.SynchronizeConflictPolicySource && input.Resolution != metadata.SynchronizeConflictPolicyTarget {
+		return ctx.Error.Errorf(common.CCErrCommParamsInvalid, "resolution")
+	}
+
+	conds := mapstr.MapStr{"id": id}
+	conflict := metadata.SynchronizeConflict{}
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Find(conds).One(ctx, &conflict); err != nil {
+		if s.dbProxy.IsNotFoundError(err) {
+			return ctx.Error.Errorf(common.CCErrCoreServiceSyncConflictNotExist, id)
+		}
+		blog.Errorf("ResolveConflict find conflict error,err:%s,id:%d,rid:%s", err.Error(), id, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	if conflict.Status != metadata.SynchronizeConflictStatusPending {
+		return ctx.Error.Errorf(common.CCErrCoreServiceSyncConflictResolved, id)
+	}
+
+	tableName := common.GetInstTableName(conflict.DataClassify)
+	instConds := mapstr.MapStr{common.GetInstIDField(conflict.DataClassify): conflict.InstID}
+	switch input.Resolution {
+	case metadata.SynchronizeConflictPolicySource:
+		source := conflict.Source
+		source.Remove("_id")
+		cnt, err := s.dbProxy.Table(tableName).Find(instConds).Count(ctx)
+		if err != nil {
+			blog.Errorf("ResolveConflict find instance error,err:%s,conflict:%#v,rid:%s", err.Error(), conflict, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBSelectFailed)
+		}
+		if cnt > 0 {
+			err = s.dbProxy.Table(tableName).Update(ctx, instConds, source)
+		} else {
+			err = s.dbProxy.Table(tableName).Insert(ctx, source)
+		}
+		if err != nil {
+			blog.Errorf("ResolveConflict save source data error,err:%s,conflict:%#v,rid:%s", err.Error(), conflict, ctx.ReqID)
+			return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+		}
+	case metadata.SynchronizeConflictPolicyTarget:
+		// acknowledge the source data, it is a new conflict when the source data is edited again
+		if lastTime, ok := conflict.Source.Get(common.LastTimeField); ok {
+			updateData := mapstr.MapStr{getSynchronize(common.MetadataField, common.MetaDataSynchronizeLastTimeField): lastTime}
+			if err := s.dbProxy.Table(tableName).Update(ctx, instConds, updateData); err != nil {
+				blog.Errorf("ResolveConflict update target data error,err:%s,conflict:%#v,rid:%s", err.Error(), conflict, ctx.ReqID)
+				return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+			}
+		}
+	}
+
+	updateData := mapstr.MapStr{
+		"status":             metadata.SynchronizeConflictStatusResolved,
+		"resolution":         input.Resolution,
+		"resolver":           ctx.User,
+		common.LastTimeField: time.Now().UTC(),
+	}
+	if err := s.dbProxy.Table(common.BKTableNameSynchronizeConflict).Update(ctx, conds, updateData); err != nil {
+		blog.Errorf("ResolveConflict update conflict error,err:%s,id:%d,rid:%s", err.Error(), id, ctx.ReqID)
+		return ctx.Error.Error(common.CCErrCommDBUpdateFailed)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasynchronize
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+func TestDecideConflict(t *testing.T) {
+	synced := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	edited := synced.Add(time.Hour)
+	newer := synced.Add(2 * time.Hour)
+
+	newData := func(lastTime time.Time, syncLastTime interface{}) mapstr.MapStr {
+		meta := mapstr.MapStr{common.MetaDataSynchronizeFlagField: "test"}
+		if syncLastTime != nil {
+			meta.Set(common.MetaDataSynchronizeLastTimeField, syncLastTime)
+		}
+		return mapstr.MapStr{common.LastTimeField: lastTime.Format(time.RFC3339), common.MetadataField: meta}
+	}
+
+	testCases := []struct {
+		name       string
+		policy     metadata.SynchronizeConflictPolicy
+		source     mapstr.MapStr
+		target     mapstr.MapStr
+		resolution metadata.SynchronizeConflictPolicy
+		conflict   bool
+	}{
+		{"old data", metadata.SynchronizeConflictPolicyTarget, newData(edited, nil), newData(edited, nil), metadata.SynchronizeConflictPolicySource, false},
+		{"target not edited", metadata.SynchronizeConflictPolicyManual, newData(edited, nil), newData(synced, synced), metadata.SynchronizeConflictPolicySource, false},
+		{"target edited", metadata.SynchronizeConflictPolicyTarget, newData(synced, nil), newData(edited, synced.Format(time.RFC3339)), metadata.SynchronizeConflictPolicyTarget, false},
+		{"source wins", metadata.SynchronizeConflictPolicySource, newData(newer, nil), newData(edited, synced), metadata.SynchronizeConflictPolicySource, true},
+		{"default source wins", "", newData(newer, nil), newData(edited, synced), metadata.SynchronizeConflictPolicySource, true},
+		{"target wins", metadata.SynchronizeConflictPolicyTarget, newData(newer, nil), newData(edited, synced), metadata.SynchronizeConflictPolicyTarget, true},
+		{"newest source", metadata.SynchronizeConflictPolicyNewest, newData(newer, nil), newData(edited, synced), metadata.SynchronizeConflictPolicySource, true},
+		{"newest target", metadata.SynchronizeConflictPolicyNewest, newData(edited, nil), newData(newer, synced), metadata.SynchronizeConflictPolicyTarget, true},
+		{"manual", metadata.SynchronizeConflictPolicyManual, newData(newer, nil), newData(edited, synced), metadata.SynchronizeConflictPolicyTarget, true},
+	}
+	for _, testCase := range testCases {
+		resolution, conflict := decideConflict(testCase.policy, testCase.source, testCase.target)
+		if resolution != testCase.resolution || conflict != testCase.conflict {
+			t.Errorf("%s: expect %s %v, got %s %v", testCase.name, testCase.resolution, testCase.conflict, resolution, conflict)
+		}
+	}
+}
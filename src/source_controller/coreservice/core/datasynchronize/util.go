@@ -93,6 +93,9 @@ func (s *synchronizeAdapter) PreSynchronizeFilter(ctx core.ContextParams) errors
 						common.MetaDataSynchronizeVersionField: s.syncData.Version,
 					})
 			}
+			if s.syncData.OperateDataType == metadata.SynchronizeOperateDataTypeInstance {
+				setSynchronizeLastTime(item.Info)
+			}
 		}
 	}
 
@@ -125,22 +128,40 @@ func (s *synchronizeAdapter) saveSynchronize(ctx core.ContextParams, dbParam syn
 }
 
 func (s *synchronizeAdapter) replaceSynchronize(ctx core.ContextParams, dbParam synchronizeAdapterDBParameter) {
-	for _, item := range s.syncData.InfoArray {
-		_, ok := s.errorArray[item.ID]
-		if ok {
-			continue
-		}
-		conds := mapstr.MapStr{dbParam.InstIDField: item.ID}
-		exist, err := s.existSynchronizeID(ctx, dbParam.tableName, conds)
-		if err != nil {
-			blog.Errorf("replaceSynchronize existSynchronizeID error.DataClassify:%s,info:%#v,rid:%s", s.syncData.DataClassify, item, ctx.ReqID)
+	targets, err := s.findSynchronizeTargets(ctx, dbParam)
+	if err != nil {
+		for _, item := range s.syncData.InfoArray {
+			if _, ok := s.errorArray[item.ID]; ok {
+				continue
+			}
 			s.errorArray[item.ID] = synchronizeAdapterError{
 				instInfo: item,
 				err:      err,
 			}
+		}
+		return
+	}
+	for _, item := range s.syncData.InfoArray {
+		_, ok := s.errorArray[item.ID]
+		if ok {
 			continue
 		}
+		conds := mapstr.MapStr{dbParam.InstIDField: item.ID}
+		target, exist := targets[item.ID]
 		if exist {
+			if s.syncData.OperateDataType == metadata.SynchronizeOperateDataTypeInstance {
+				writable, err := s.handleConflict(ctx, dbParam, conds, item, target)
+				if err != nil {
+					s.errorArray[item.ID] = synchronizeAdapterError{
+						instInfo: item,
+						err:      err,
+					}
+					continue
+				}
+				if !writable {
+					continue
+				}
+			}
 			err := s.dbProxy.Table(dbParam.tableName).Update(ctx, conds, item.Info)
 			if err != nil {
 				blog.Errorf("replaceSynchronize update info error,err:%s.DataClassify:%s,condition:%#v,info:%#v,rid:%s", err.Error(), s.syncData.DataClassify, conds, item, ctx.ReqID)
@@ -181,15 +202,30 @@ func (s *synchronizeAdapter) deleteSynchronize(ctx core.ContextParams, dbParam s
 	}
 }
 
-func (s *synchronizeAdapter) existSynchronizeID(ctx core.ContextParams, tableName string, conds mapstr.MapStr) (bool, errors.CCError) {
-	cnt, err := s.dbProxy.Table(tableName).Find(conds).Count(ctx)
-	if err != nil {
-		blog.Errorf("existSynchronizeID error. DataClassify:%s,conds:%#v,rid:%s", s.syncData.DataClassify, conds, ctx.ReqID)
-		return false, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+// findSynchronizeTargets load the existing data of the page at once, the key is the instance id
+func (s *synchronizeAdapter) findSynchronizeTargets(ctx core.ContextParams, dbParam synchronizeAdapterDBParameter) (map[int64]mapstr.MapStr, errors.CCError) {
+	var instIDArr []int64
+	for _, item := range s.syncData.InfoArray {
+		instIDArr = append(instIDArr, item.ID)
 	}
-	if cnt > 0 {
-		return true, nil
+	targets := make(map[int64]mapstr.MapStr, len(instIDArr))
+	if len(instIDArr) == 0 {
+		return targets, nil
 	}
-	return false, nil
 
+	conds := mapstr.MapStr{dbParam.InstIDField: mapstr.MapStr{common.BKDBIN: instIDArr}}
+	var result []mapstr.MapStr
+	if err := s.dbProxy.Table(dbParam.tableName).Find(conds).All(ctx, &result); err != nil {
+		blog.Errorf("findSynchronizeTargets error,err:%s.DataClassify:%s,conds:%#v,rid:%s", err.Error(), s.syncData.DataClassify, conds, ctx.ReqID)
+		return nil, ctx.Error.Error(common.CCErrCommDBSelectFailed)
+	}
+	for _, target := range result {
+		instID, err := target.Int64(dbParam.InstIDField)
+		if err != nil {
+			blog.Warnf("findSynchronizeTargets instance id invalid,err:%s.DataClassify:%s,data:%#v,rid:%s", err.Error(), s.syncData.DataClassify, target, ctx.ReqID)
+			continue
+		}
+		targets[instID] = target
+	}
+	return targets, nil
 }
@@ -131,6 +131,43 @@ func (m *instanceManager) UpdateModelInstance(ctx core.ContextParams, objID stri
 	return &metadata.UpdatedCount{Count: cnt}, err
 }
 
+// ValidateManyModelInstance run the create or update validation of the instances without writing them
+func (m *instanceManager) ValidateManyModelInstance(ctx core.ContextParams, objID string, inputParam metadata.ValidateManyModelInstance) (*metadata.ValidatedManyDataResult, error) {
+	dataResult := &metadata.ValidatedManyDataResult{}
+	for itemIdx, item := range inputParam.Datas {
+		// the validator fills the lost field value, do not change the input data
+		data := item.Data.Clone()
+		var err error
+		if 0 == item.InstID {
+			data.Set(common.BKOwnerIDField, ctx.SupplierAccount)
+			err = m.validCreateInstanceData(ctx, objID, data)
+		} else {
+			var instMedataData metadata.Metadata
+			instMedataData.Label = make(metadata.Label)
+			if bizID := metadata.GetBusinessIDFromMeta(data[metadata.BKMetadata]); "" != bizID {
+				instMedataData.Label.Set(metadata.LabelBusinessID, bizID)
+			}
+			err = m.validUpdateInstanceData(ctx, objID, data, instMedataData, item.InstID)
+		}
+		if nil == err {
+			continue
+		}
+
+		exception := metadata.ExceptionResult{
+			Message:     err.Error(),
+			Code:        int64(common.CCErrCommParamsIsInvalid),
+			Data:        item.Data,
+			OriginIndex: int64(itemIdx),
+		}
+		if ccErr, ok := err.(errors.CCErrorCoder); ok {
+			exception.Code = int64(ccErr.GetCode())
+		}
+		dataResult.Exceptions = append(dataResult.Exceptions, exception)
+	}
+
+	return dataResult, nil
+}
+
 func (m *instanceManager) SearchModelInstance(ctx core.ContextParams, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error) {
 	condition, err := mongo.NewConditionFromMapStr(inputParam.Condition)
 	if nil != err {
@@ -13,6 +13,9 @@
 package service
 
 import (
+	"strconv"
+
+	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
@@ -80,3 +83,38 @@ func (s *coreService) SynchronizeClearData(params core.ContextParams, pathParams
 	}
 	return nil, nil
 }
+
+func (s *coreService) SearchSynchronizeConflict(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+
+	inputData := &metadata.SynchronizeConflictSearchParameter{}
+	if err := data.MarshalJSONInto(inputData); nil != err {
+		blog.Errorf("SearchSynchronizeConflict MarshalJSONInto error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	info, cnt, err := s.core.DataSynchronizeOperation().SearchConflict(params, inputData)
+	if err != nil {
+		blog.Errorf("SearchSynchronizeConflict error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	return mapstr.MapStr{"info": info, "count": cnt}, nil
+}
+
+func (s *coreService) ResolveSynchronizeConflict(params core.ContextParams, pathParams, queryParams ParamsGetter, data mapstr.MapStr) (interface{}, error) {
+
+	id, err := strconv.ParseInt(pathParams("id"), 10, 64)
+	if nil != err {
+		blog.Errorf("ResolveSynchronizeConflict id %s not integer, err:%s,rid:%s", pathParams("id"), err.Error(), params.ReqID)
+		return nil, params.Error.Errorf(common.CCErrCommParamsNeedInt, "id")
+	}
+	inputData := &metadata.SynchronizeConflictResolveParameter{}
+	if err := data.MarshalJSONInto(inputData); nil != err {
+		blog.Errorf("ResolveSynchronizeConflict MarshalJSONInto error, err:%s,input:%v,rid:%s", err.Error(), data, params.ReqID)
+		return nil, err
+	}
+	err = s.core.DataSynchronizeOperation().ResolveConflict(params, id, inputData)
+	if err != nil {
+		blog.Errorf("ResolveSynchronizeConflict error, err:%s,id:%d,input:%v,rid:%s", err.Error(), id, data, params.ReqID)
+		return nil, err
+	}
+	return nil, nil
+}
@@ -19,12 +19,14 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/eventclient"
 	"configcenter/src/common/http/httpserver"
 	"configcenter/src/common/language"
 	"configcenter/src/common/mapstr"
@@ -40,6 +42,7 @@ import (
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/mongo/local"
 	"configcenter/src/storage/dal/mongo/remote"
+	"configcenter/src/storage/dal/redis"
 
 	"github.com/emicklei/go-restful"
 )
@@ -63,6 +66,7 @@ type coreService struct {
 	actions  []action
 	cfg      options.Config
 	core     core.Core
+	eventC   eventclient.Client
 }
 
 func (s *coreService) SetConfig(cfg options.Config, engin *backbone.Engine, err errors.CCErrorIf, language language.CCLanguageIf) error {
@@ -96,6 +100,17 @@ func (s *coreService) SetConfig(cfg options.Config, engin *backbone.Engine, err
 	}
 	// connect the remote mongodb
 
+	if "" != cfg.Redis.Address {
+		cache, err := redis.NewFromConfig(cfg.Redis)
+		if nil != err {
+			blog.Errorf("failed to connect the redis server(%s), error info is %s", cfg.Redis.Address, err.Error())
+			return err
+		}
+		s.eventC = eventclient.NewClientViaRedis(cache, db)
+	} else {
+		blog.Warnf("the redis is not configured, the model events will not be pushed")
+	}
+
 	s.core = core.New(model.New(db, s), instances.New(db, s), association.New(db, s), datasynchronize.New(db, s))
 	return nil
 }
@@ -238,8 +253,11 @@ func (s *coreService) Actions() []*httpserver.Action {
 					}
 				}
 
+				// the db operations are canceled when the caller disconnects or gives up the request
+				ctx, cancel := backbone.NewContextWithHeader(req.Request.Context(), req.Request.Header)
+				defer cancel()
 				data, dataErr := act.HandlerFunc(core.ContextParams{
-					Context:         util.GetDBContext(context.Background(), req.Request.Header),
+					Context:         util.GetDBContext(ctx, req.Request.Header),
 					Error:           defErr,
 					Lang:            defLang,
 					Header:          req.Request.Header,
@@ -261,6 +279,9 @@ func (s *coreService) Actions() []*httpserver.Action {
 					return
 				}
 
+				if "" != act.ModelEvent {
+					s.pushModelEvent(req, act, mData)
+				}
 				s.sendResponse(resp, common.CCSuccess, data)
 
 			}})
@@ -269,3 +290,29 @@ func (s *coreService) Actions() []*httpserver.Action {
 	}
 	return httpactions
 }
+
+// pushModelEvent push the event of the model definitions changed by the action, the event server publishes it
+// to invalidate the caches of the model definitions
+func (s *coreService) pushModelEvent(req *restful.Request, act action, data mapstr.MapStr) {
+	if nil == s.eventC {
+		return
+	}
+
+	eventAction := metadata.EventActionUpdate
+	switch {
+	case strings.HasPrefix(act.Path, "/create"):
+		eventAction = metadata.EventActionCreate
+	case strings.HasPrefix(act.Path, "/delete"):
+		eventAction = metadata.EventActionDelete
+	}
+	event := eventclient.NewModelEvent(req.Request.Header, act.ModelEvent, eventAction)
+	curData := mapstr.MapStr{"input": data}
+	if objID := req.PathParameter(common.BKObjIDField); "" != objID {
+		curData.Set(common.BKObjIDField, objID)
+	}
+	event.Data = []metadata.EventData{{CurData: curData}}
+
+	if err := s.eventC.Push(context.Background(), event); nil != err {
+		blog.Errorf("failed to push the %s event of %s, error info is %s", event.ObjType, act.Path, err.Error())
+	}
+}
@@ -23,4 +23,6 @@ func (s *coreService) initDataSynchronize() {
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/synchronize/association", HandlerFunc: s.SynchronizeAssociation})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/synchronize", HandlerFunc: s.SynchronizeFind})
 	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/clear/synchronize/data", HandlerFunc: s.SynchronizeClearData})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/synchronize/conflict", HandlerFunc: s.SearchSynchronizeConflict})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/synchronize/conflict/resolve/{id}", HandlerFunc: s.ResolveSynchronizeConflict})
 }
@@ -14,6 +14,8 @@ package service
 
 import (
 	"net/http"
+
+	"configcenter/src/common/metadata"
 )
 
 func (s *coreService) initHealth() {
@@ -21,40 +23,40 @@ func (s *coreService) initHealth() {
 }
 
 func (s *coreService) initModelClassification() {
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/classification", HandlerFunc: s.CreateOneModelClassification})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/createmany/model/classification", HandlerFunc: s.CreateManyModelClassification})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/setmany/model/classification", HandlerFunc: s.SetManyModelClassificaiton})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/classification", HandlerFunc: s.SetOneModelClassificaition})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/classification", HandlerFunc: s.UpdateModelClassification})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/classification", HandlerFunc: s.DeleteModelClassification})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/classification/cascade", HandlerFunc: s.CascadeDeleteModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/classification", HandlerFunc: s.CreateOneModelClassification, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/createmany/model/classification", HandlerFunc: s.CreateManyModelClassification, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/setmany/model/classification", HandlerFunc: s.SetManyModelClassificaiton, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/classification", HandlerFunc: s.SetOneModelClassificaition, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/classification", HandlerFunc: s.UpdateModelClassification, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/classification", HandlerFunc: s.DeleteModelClassification, ModelEvent: metadata.EventObjTypeModelClassification})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/classification/cascade", HandlerFunc: s.CascadeDeleteModelClassification, ModelEvent: metadata.EventObjTypeModelClassification})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/classification", HandlerFunc: s.SearchModelClassification})
 }
 
 func (s *coreService) initModel() {
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model", HandlerFunc: s.CreateModel})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model", HandlerFunc: s.SetModel})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model", HandlerFunc: s.UpdateModel})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model", HandlerFunc: s.DeleteModel})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/cascade", HandlerFunc: s.CascadeDeleteModel})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model", HandlerFunc: s.CreateModel, ModelEvent: metadata.EventObjTypeModel})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model", HandlerFunc: s.SetModel, ModelEvent: metadata.EventObjTypeModel})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model", HandlerFunc: s.UpdateModel, ModelEvent: metadata.EventObjTypeModel})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model", HandlerFunc: s.DeleteModel, ModelEvent: metadata.EventObjTypeModel})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/cascade", HandlerFunc: s.CascadeDeleteModel, ModelEvent: metadata.EventObjTypeModel})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model", HandlerFunc: s.SearchModel})
 
 	// init model attribute groups methods
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/group", HandlerFunc: s.CreateModelAttributeGroup})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/{bk_obj_id}/group", HandlerFunc: s.SetModelAttributeGroup})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/group", HandlerFunc: s.UpdateModelAttributeGroup})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/group", HandlerFunc: s.UpdateModelAttributeGroupByCondition})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/group", HandlerFunc: s.DeleteModelAttributeGroup})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/group", HandlerFunc: s.DeleteModelAttributeGroupByCondition})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/group", HandlerFunc: s.CreateModelAttributeGroup, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/{bk_obj_id}/group", HandlerFunc: s.SetModelAttributeGroup, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/group", HandlerFunc: s.UpdateModelAttributeGroup, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/group", HandlerFunc: s.UpdateModelAttributeGroupByCondition, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/group", HandlerFunc: s.DeleteModelAttributeGroup, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/group", HandlerFunc: s.DeleteModelAttributeGroupByCondition, ModelEvent: metadata.EventObjTypeModelAttributeGroup})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/{bk_obj_id}/group", HandlerFunc: s.SearchModelAttributeGroup})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/group", HandlerFunc: s.SearchModelAttributeGroupByCondition})
 
 	// init attributes methods
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/attributes", HandlerFunc: s.CreateModelAttributes})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/{bk_obj_id}/attributes", HandlerFunc: s.SetModelAttributes})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/attributes", HandlerFunc: s.UpdateModelAttributes})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/attributes", HandlerFunc: s.UpdateModelAttributesByCondition})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/attributes", HandlerFunc: s.DeleteModelAttribute})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/attributes", HandlerFunc: s.CreateModelAttributes, ModelEvent: metadata.EventObjTypeModelAttribute})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/model/{bk_obj_id}/attributes", HandlerFunc: s.SetModelAttributes, ModelEvent: metadata.EventObjTypeModelAttribute})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/attributes", HandlerFunc: s.UpdateModelAttributes, ModelEvent: metadata.EventObjTypeModelAttribute})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/attributes", HandlerFunc: s.UpdateModelAttributesByCondition, ModelEvent: metadata.EventObjTypeModelAttribute})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/attributes", HandlerFunc: s.DeleteModelAttribute, ModelEvent: metadata.EventObjTypeModelAttribute})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/{bk_obj_id}/attributes", HandlerFunc: s.SearchModelAttributes})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/attributes", HandlerFunc: s.SearchModelAttributesByCondition})
 
@@ -62,15 +64,16 @@ func (s *coreService) initModel() {
 
 func (s *coreService) initAttrUnique() {
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/attributes/unique", HandlerFunc: s.SearchModelAttrUnique})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/attributes/unique", HandlerFunc: s.CreateModelAttrUnique})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/attributes/unique/{id}", HandlerFunc: s.UpdateModelAttrUnique})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/attributes/unique/{id}", HandlerFunc: s.DeleteModelAttrUnique})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/attributes/unique", HandlerFunc: s.CreateModelAttrUnique, ModelEvent: metadata.EventObjTypeModelAttrUnique})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/attributes/unique/{id}", HandlerFunc: s.UpdateModelAttrUnique, ModelEvent: metadata.EventObjTypeModelAttrUnique})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/attributes/unique/{id}", HandlerFunc: s.DeleteModelAttrUnique, ModelEvent: metadata.EventObjTypeModelAttrUnique})
 }
 
 func (s *coreService) initModelInstances() {
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/model/{bk_obj_id}/instance", HandlerFunc: s.CreateOneModelInstance})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/createmany/model/{bk_obj_id}/instance", HandlerFunc: s.CreateManyModelInstances})
 	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/model/{bk_obj_id}/instance", HandlerFunc: s.UpdateModelInstances})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/validatemany/model/{bk_obj_id}/instance", HandlerFunc: s.ValidateManyModelInstances})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/model/{bk_obj_id}/instances", HandlerFunc: s.SearchModelInstances})
 	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/instance", HandlerFunc: s.DeleteModelInstances})
 	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/instance/cascade", HandlerFunc: s.CascadeDeleteModelInstances})
@@ -91,13 +94,13 @@ func (s *coreService) initAssociationKind() {
 
 func (s *coreService) initModelAssociation() {
 
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/modelassociation", HandlerFunc: s.CreateModelAssociation})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/mainlinemodelassociation", HandlerFunc: s.CreateMainlineModelAssociation})
-	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/modelassociation", HandlerFunc: s.SetModelAssociation})
-	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/modelassociation", HandlerFunc: s.UpdateModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/modelassociation", HandlerFunc: s.CreateModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/create/mainlinemodelassociation", HandlerFunc: s.CreateMainlineModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/set/modelassociation", HandlerFunc: s.SetModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodPut, Path: "/update/modelassociation", HandlerFunc: s.UpdateModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
 	s.actions = append(s.actions, action{Method: http.MethodPost, Path: "/read/modelassociation", HandlerFunc: s.SearchModelAssociation})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/modelassociation", HandlerFunc: s.DeleteModelAssociation})
-	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/modelassociation/cascade", HandlerFunc: s.DeleteModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/modelassociation", HandlerFunc: s.DeleteModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
+	s.actions = append(s.actions, action{Method: http.MethodDelete, Path: "/delete/modelassociation/cascade", HandlerFunc: s.DeleteModelAssociation, ModelEvent: metadata.EventObjTypeModelAssociation})
 }
 
 func (s *coreService) initInstanceAssociation() {
@@ -33,6 +33,9 @@ type action struct {
 	Path                       string
 	HandlerFunc                LogicFunc
 	HandlerParseOriginDataFunc ParseOriginDataFunc
+	// ModelEvent the object type of the model event pushed after the action succeeds,
+	// the empty one means the action doesn't change the model definitions
+	ModelEvent string
 }
 
 // API the API interface
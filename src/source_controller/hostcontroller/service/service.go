@@ -73,6 +73,10 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.PUT("/usercustom/{bk_user}/{id}").To(s.UpdateUserCustomByID))
 	ws.Route(ws.POST("/usercustom/user/search/{bk_user}").To(s.GetUserCustomByUser))
 	ws.Route(ws.POST("/usercustom/default/search/{bk_user}").To(s.GetDefaultUserCustom))
+	ws.Route(ws.POST("/usertoken").To(s.AddUserToken))
+	ws.Route(ws.POST("/usertoken/search").To(s.SearchUserToken))
+	ws.Route(ws.PUT("/usertoken/{id}/revoke").To(s.RevokeUserToken))
+	ws.Route(ws.PUT("/usertoken/{id}/lastused").To(s.TouchUserToken))
 	ws.Route(ws.GET("/healthz").To(s.Healthz))
 	ws.Route(ws.POST("/transfer/host/default/module").To(s.TransferHostToDefaultModuleConfig))
 
@@ -0,0 +1,142 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful"
+)
+
+func (s *Service) AddUserToken(req *restful.Request, resp *restful.Response) {
+	language := util.GetLanguage(req.Request.Header)
+	ownerID := util.GetOwnerID(req.Request.Header)
+	defErr := s.Core.CCErr.CreateDefaultCCErrorIf(language)
+	ctx := util.GetDBContext(context.Background(), req.Request.Header)
+
+	token := new(meta.APIToken)
+	if err := json.NewDecoder(req.Request.Body).Decode(token); err != nil {
+		blog.Errorf("add user token failed, err: %v", err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	if 0 == len(token.User) {
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "bk_user")})
+		return
+	}
+	if 0 == len(token.TokenHash) {
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedSet, "token_hash")})
+		return
+	}
+
+	id, err := s.Instance.NextSequence(ctx, common.BKTableNameUserAPIToken)
+	if err != nil {
+		blog.Errorf("add user token, get id failed, err: %v", err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommDBInsertFailed)})
+		return
+	}
+	token.ID = int64(id)
+	token.OwnerID = ownerID
+	token.Revoked = false
+	token.CreateTime = meta.Now()
+	token.LastUsedTime = nil
+	token.RevokeTime = nil
+
+	err = s.Instance.Table(common.BKTableNameUserAPIToken).Insert(ctx, token)
+	if err != nil {
+		blog.Errorf("add user token failed, user: %s, err: %v", token.User, err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommDBInsertFailed)})
+		return
+	}
+
+	token.TokenHash = ""
+	resp.WriteEntity(meta.APITokenResult{
+		BaseResp: meta.SuccessBaseResp,
+		Data:     *token,
+	})
+}
+
+func (s *Service) SearchUserToken(req *restful.Request, resp *restful.Response) {
+	language := util.GetLanguage(req.Request.Header)
+	ownerID := util.GetOwnerID(req.Request.Header)
+	defErr := s.Core.CCErr.CreateDefaultCCErrorIf(language)
+	ctx := util.GetDBContext(context.Background(), req.Request.Header)
+
+	conds := make(map[string]interface{})
+	if err := json.NewDecoder(req.Request.Body).Decode(&conds); err != nil {
+		blog.Errorf("search user token failed, err: %v", err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommJSONUnmarshalFailed)})
+		return
+	}
+	conds = util.SetModOwner(conds, ownerID)
+
+	tokens := make([]meta.APIToken, 0)
+	err := s.Instance.Table(common.BKTableNameUserAPIToken).Find(conds).Sort("-id").All(ctx, &tokens)
+	if err != nil {
+		blog.Errorf("search user token failed, err: %v, params: %v", err, conds)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommDBSelectFailed)})
+		return
+	}
+	for idx := range tokens {
+		tokens[idx].TokenHash = ""
+	}
+
+	resp.WriteEntity(meta.SearchAPITokenResult{
+		BaseResp: meta.SuccessBaseResp,
+		Data:     tokens,
+	})
+}
+
+func (s *Service) RevokeUserToken(req *restful.Request, resp *restful.Response) {
+	s.updateUserToken(req, resp, "revoked", "revoke_time")
+}
+
+func (s *Service) TouchUserToken(req *restful.Request, resp *restful.Response) {
+	s.updateUserToken(req, resp, "", "last_used_time")
+}
+
+// updateUserToken set the flag field to true and the time field to now
+func (s *Service) updateUserToken(req *restful.Request, resp *restful.Response, flagField, timeField string) {
+	language := util.GetLanguage(req.Request.Header)
+	ownerID := util.GetOwnerID(req.Request.Header)
+	defErr := s.Core.CCErr.CreateDefaultCCErrorIf(language)
+	ctx := util.GetDBContext(context.Background(), req.Request.Header)
+
+	id, err := strconv.ParseInt(req.PathParameter("id"), 10, 64)
+	if err != nil {
+		blog.Errorf("update user token failed, invalid id %s, err: %v", req.PathParameter("id"), err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Errorf(common.CCErrCommParamsNeedInt, "id")})
+		return
+	}
+
+	conds := util.SetModOwner(map[string]interface{}{"id": id}, ownerID)
+	data := map[string]interface{}{timeField: meta.Now()}
+	if "" != flagField {
+		data[flagField] = true
+	}
+	err = s.Instance.Table(common.BKTableNameUserAPIToken).Update(ctx, conds, data)
+	if err != nil {
+		blog.Errorf("update user token %d failed, err: %v", id, err)
+		resp.WriteError(http.StatusBadRequest, &meta.RespError{Msg: defErr.Error(common.CCErrCommDBUpdateFailed)})
+		return
+	}
+	resp.WriteEntity(meta.SuccessBaseResp)
+}
@@ -0,0 +1,54 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/eventclient"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// modelEvent push the model event of the object type after the route changes the model definitions,
+// the event server publishes it to invalidate the caches of the model definitions like the core service does
+func (s *Service) modelEvent(objType string) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		chain.ProcessFilter(req, resp)
+
+		// the failed requests are responded with the error status
+		if http.StatusOK != resp.StatusCode() || nil == s.EventC {
+			return
+		}
+
+		eventAction := metadata.EventActionUpdate
+		switch req.Request.Method {
+		case http.MethodPost:
+			eventAction = metadata.EventActionCreate
+		case http.MethodDelete:
+			eventAction = metadata.EventActionDelete
+		}
+		event := eventclient.NewModelEvent(req.Request.Header, objType, eventAction)
+		if objID := req.PathParameter(common.BKObjIDField); "" != objID {
+			event.Data = []metadata.EventData{{CurData: mapstr.MapStr{common.BKObjIDField: objID}}}
+		}
+		if err := s.EventC.Push(context.Background(), event); nil != err {
+			blog.Errorf("failed to push the %s event of %s, error info is %s", objType, req.Request.URL.Path, err.Error())
+		}
+	}
+}
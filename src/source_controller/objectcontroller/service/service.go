@@ -52,52 +52,52 @@ func (s *Service) WebService() *restful.WebService {
 	ws.Route(ws.PUT("/insts/{obj_type}").To(s.UpdateInstObject))
 
 	ws.Route(ws.POST("/meta/objects").To(s.SelectObjects))
-	ws.Route(ws.DELETE("/meta/object/{id}").To(s.DeleteObject))
-	ws.Route(ws.POST("/meta/object").To(s.CreateObject))
-	ws.Route(ws.PUT("/meta/object/{id}").To(s.UpdateObject))
+	ws.Route(ws.DELETE("/meta/object/{id}").Filter(s.modelEvent(metadata.EventObjTypeModel)).To(s.DeleteObject))
+	ws.Route(ws.POST("/meta/object").Filter(s.modelEvent(metadata.EventObjTypeModel)).To(s.CreateObject))
+	ws.Route(ws.PUT("/meta/object/{id}").Filter(s.modelEvent(metadata.EventObjTypeModel)).To(s.UpdateObject))
 
 	ws.Route(ws.POST("/meta/objectassts").To(s.SelectObjectAssociations))
-	ws.Route(ws.DELETE("/meta/objectasst/{id}").To(s.DeleteObjectAssociation))
-	ws.Route(ws.POST("/meta/objectasst").To(s.CreateObjectAssociation))
-	ws.Route(ws.POST("/meta/mainlineobjectasst").To(s.CreateMainlineObjectAssociation))
-	ws.Route(ws.PUT("/meta/objectasst/{id}").To(s.UpdateObjectAssociation))
+	ws.Route(ws.DELETE("/meta/objectasst/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.DeleteObjectAssociation))
+	ws.Route(ws.POST("/meta/objectasst").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.CreateObjectAssociation))
+	ws.Route(ws.POST("/meta/mainlineobjectasst").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.CreateMainlineObjectAssociation))
+	ws.Route(ws.PUT("/meta/objectasst/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.UpdateObjectAssociation))
 
 	ws.Route(ws.POST("/meta/objectatt/{id}").To(s.SelectObjectAttByID))
 	ws.Route(ws.POST("/meta/objectatts").To(s.SelectObjectAttWithParams))
-	ws.Route(ws.DELETE("/meta/objectatt/{id}").To(s.DeleteObjectAttByID))
-	ws.Route(ws.POST("/meta/objectatt").To(s.CreateObjectAtt))
-	ws.Route(ws.PUT("/meta/objectatt/{id}").To(s.UpdateObjectAttByID))
-
-	ws.Route(ws.POST("/meta/objectatt/group/new").To(s.CreatePropertyGroup))
-	ws.Route(ws.PUT("/meta/objectatt/group/update").To(s.UpdatePropertyGroup))
-	ws.Route(ws.DELETE("/meta/objectatt/group/groupid/{id}").To(s.DeletePropertyGroup))
-	ws.Route(ws.PUT("/meta/objectatt/group/property").To(s.UpdatePropertyGroupObjectAtt))
-	ws.Route(ws.DELETE("/meta/objectatt/group/owner/{owner_id}/object/{object_id}/propertyids/{property_id}/groupids/{group_id}").To(s.DeletePropertyGroupObjectAtt))
+	ws.Route(ws.DELETE("/meta/objectatt/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelAttribute)).To(s.DeleteObjectAttByID))
+	ws.Route(ws.POST("/meta/objectatt").Filter(s.modelEvent(metadata.EventObjTypeModelAttribute)).To(s.CreateObjectAtt))
+	ws.Route(ws.PUT("/meta/objectatt/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelAttribute)).To(s.UpdateObjectAttByID))
+
+	ws.Route(ws.POST("/meta/objectatt/group/new").Filter(s.modelEvent(metadata.EventObjTypeModelAttributeGroup)).To(s.CreatePropertyGroup))
+	ws.Route(ws.PUT("/meta/objectatt/group/update").Filter(s.modelEvent(metadata.EventObjTypeModelAttributeGroup)).To(s.UpdatePropertyGroup))
+	ws.Route(ws.DELETE("/meta/objectatt/group/groupid/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelAttributeGroup)).To(s.DeletePropertyGroup))
+	ws.Route(ws.PUT("/meta/objectatt/group/property").Filter(s.modelEvent(metadata.EventObjTypeModelAttribute)).To(s.UpdatePropertyGroupObjectAtt))
+	ws.Route(ws.DELETE("/meta/objectatt/group/owner/{owner_id}/object/{object_id}/propertyids/{property_id}/groupids/{group_id}").Filter(s.modelEvent(metadata.EventObjTypeModelAttribute)).To(s.DeletePropertyGroupObjectAtt))
 	ws.Route(ws.POST("/meta/objectatt/group/property/owner/{owner_id}/object/{object_id}").To(s.SelectPropertyGroupByObjectID))
 	ws.Route(ws.POST("/meta/objectatt/group/search").To(s.SelectGroup))
 
 	ws.Route(ws.POST("/meta/object/classification/{owner_id}/objects").To(s.SelectClassificationWithObject))
 	ws.Route(ws.POST("/meta/object/classification/search").To(s.SelectClassifications))
-	ws.Route(ws.DELETE("/meta/object/classification/{id}").To(s.DeleteClassification))
-	ws.Route(ws.POST("/meta/object/classification").To(s.CreateClassification))
-	ws.Route(ws.PUT("/meta/object/classification/{id}").To(s.UpdateClassification))
+	ws.Route(ws.DELETE("/meta/object/classification/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelClassification)).To(s.DeleteClassification))
+	ws.Route(ws.POST("/meta/object/classification").Filter(s.modelEvent(metadata.EventObjTypeModelClassification)).To(s.CreateClassification))
+	ws.Route(ws.PUT("/meta/object/classification/{id}").Filter(s.modelEvent(metadata.EventObjTypeModelClassification)).To(s.UpdateClassification))
 
-	ws.Route(ws.POST("/object/{bk_obj_id}/unique/action/create").To(s.CreateObjectUnique))
-	ws.Route(ws.PUT("/object/{bk_obj_id}/unique/{id}/action/update").To(s.UpdateObjectUnique))
-	ws.Route(ws.DELETE("/object/{bk_obj_id}/unique/{id}/action/delete").To(s.DeleteObjectUnique))
+	ws.Route(ws.POST("/object/{bk_obj_id}/unique/action/create").Filter(s.modelEvent(metadata.EventObjTypeModelAttrUnique)).To(s.CreateObjectUnique))
+	ws.Route(ws.PUT("/object/{bk_obj_id}/unique/{id}/action/update").Filter(s.modelEvent(metadata.EventObjTypeModelAttrUnique)).To(s.UpdateObjectUnique))
+	ws.Route(ws.DELETE("/object/{bk_obj_id}/unique/{id}/action/delete").Filter(s.modelEvent(metadata.EventObjTypeModelAttrUnique)).To(s.DeleteObjectUnique))
 	ws.Route(ws.GET("/object/{bk_obj_id}/unique/action/search").To(s.SearchObjectUnique))
 
 	// association api
 	ws.Route(ws.POST("/association/action/search").To(s.SearchAssociationType))
-	ws.Route(ws.POST("/association/action/create").To(s.CreateAssociationType))
-	ws.Route(ws.PUT("/association/{id}/action/update").To(s.UpdateAssociationType))
-	ws.Route(ws.DELETE("/association/{id}/action/delete").To(s.DeleteAssociationType))
-
-	ws.Route(ws.POST("/object/association/action/search").To(s.SelectObjectAssociations))                 // optimization: new api path
-	ws.Route(ws.POST("/object/association/action/create").To(s.CreateObjectAssociation))                  // optimization: new api path
-	ws.Route(ws.POST("/object/association/mainline/action/create").To(s.CreateMainlineObjectAssociation)) // interface mainline association
-	ws.Route(ws.PUT("/object/association/{id}/action/update").To(s.UpdateObjectAssociation))              // optimization: new api path
-	ws.Route(ws.DELETE("/object/association/{id}/action/delete").To(s.DeleteObjectAssociation))           // optimization: new api path
+	ws.Route(ws.POST("/association/action/create").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.CreateAssociationType))
+	ws.Route(ws.PUT("/association/{id}/action/update").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.UpdateAssociationType))
+	ws.Route(ws.DELETE("/association/{id}/action/delete").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.DeleteAssociationType))
+
+	ws.Route(ws.POST("/object/association/action/search").To(s.SelectObjectAssociations))                                                                             // optimization: new api path
+	ws.Route(ws.POST("/object/association/action/create").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.CreateObjectAssociation))                  // optimization: new api path
+	ws.Route(ws.POST("/object/association/mainline/action/create").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.CreateMainlineObjectAssociation)) // interface mainline association
+	ws.Route(ws.PUT("/object/association/{id}/action/update").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.UpdateObjectAssociation))              // optimization: new api path
+	ws.Route(ws.DELETE("/object/association/{id}/action/delete").Filter(s.modelEvent(metadata.EventObjTypeModelAssociation)).To(s.DeleteObjectAssociation))           // optimization: new api path
 
 	ws.Route(ws.POST("/inst/association/action/search").To(s.SearchInstAssociations))
 	ws.Route(ws.POST("/inst/association/action/create").To(s.CreateInstAssociation))
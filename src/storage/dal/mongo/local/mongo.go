@@ -14,6 +14,7 @@ package local
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
@@ -155,50 +156,64 @@ func (f *Find) Limit(limit uint64) dal.Find {
 
 // All 查询多个
 func (f *Find) All(ctx context.Context, result interface{}) error {
+	span := startSpan(ctx, "find", f.collName)
 	f.dbc.Refresh()
 	query := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter)
 	query = query.Select(f.projection)
 	query = query.Skip(int(f.start))
 	query = query.Limit(int(f.limit))
 	query = query.Sort(f.sort...)
-	return query.All(result)
+	err := query.All(result)
+	finishSpan(span, err)
+	return err
 }
 
 // One 查询一个
 func (f *Find) One(ctx context.Context, result interface{}) error {
+	span := startSpan(ctx, "findOne", f.collName)
 	f.dbc.Refresh()
 
 	err := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter).One(result)
 	if err == mgo.ErrNotFound {
 		err = dal.ErrDocumentNotFound
 	}
+	finishSpan(span, err)
 	return err
 }
 
 // Count 统计数量(非事务)
 func (f *Find) Count(ctx context.Context) (uint64, error) {
+	span := startSpan(ctx, "count", f.collName)
 	count, err := f.dbc.DB(f.dbname).C(f.collName).Find(f.filter).Count()
+	finishSpan(span, err)
 	return uint64(count), err
 }
 
 // Insert 插入数据, docs 可以为 单个数据 或者 多个数据
 func (c *Collection) Insert(ctx context.Context, docs interface{}) error {
+	span := startSpan(ctx, "insert", c.collName)
 	c.dbc.Refresh()
-	return c.dbc.DB(c.dbname).C(c.collName).Insert(util.ConverToInterfaceSlice(docs)...)
+	err := c.dbc.DB(c.dbname).C(c.collName).Insert(util.ConverToInterfaceSlice(docs)...)
+	finishSpan(span, err)
+	return err
 }
 
 // Update 更新数据
 func (c *Collection) Update(ctx context.Context, filter dal.Filter, doc interface{}) error {
+	span := startSpan(ctx, "update", c.collName)
 	c.dbc.Refresh()
 	data := bson.M{"$set": doc}
 	_, err := c.dbc.DB(c.dbname).C(c.collName).UpdateAll(filter, data)
+	finishSpan(span, err)
 	return err
 }
 
 // Delete 删除数据
 func (c *Collection) Delete(ctx context.Context, filter dal.Filter) error {
+	span := startSpan(ctx, "delete", c.collName)
 	c.dbc.Refresh()
 	_, err := c.dbc.DB(c.dbname).C(c.collName).RemoveAll(filter)
+	finishSpan(span, err)
 	return err
 }
 
@@ -217,7 +232,9 @@ func (c *Mongo) NextSequence(ctx context.Context, sequenceName string) (uint64,
 	}
 	doc := Idgen{}
 
+	span := startSpan(ctx, "findAndModify", "cc_idgenerator")
 	_, err := coll.Find(bson.M{"_id": sequenceName}).Apply(change, &doc)
+	finishSpan(span, err)
 	if err != nil {
 		return 0, err
 	}
@@ -285,6 +302,26 @@ func (c *Collection) CreateIndex(ctx context.Context, index dal.Index) error {
 		keys = append(keys, key)
 	}
 
+	if 0 != len(index.PartialFilter) {
+		// mgo does not support the partial index, create it with the command
+		sort.Strings(keys)
+		key := bson.D{}
+		for _, k := range keys {
+			key = append(key, bson.DocElem{Name: k, Value: index.Keys[k]})
+		}
+		cmd := bson.D{
+			{Name: "createIndexes", Value: c.collName},
+			{Name: "indexes", Value: []bson.M{{
+				"key":                     key,
+				"name":                    index.Name,
+				"unique":                  index.Unique,
+				"background":              index.Background,
+				"partialFilterExpression": index.PartialFilter,
+			}}},
+		}
+		return c.dbc.DB(c.dbname).Run(cmd, nil)
+	}
+
 	i := mgo.Index{
 		Key:        keys,
 		Name:       index.Name,
@@ -358,10 +395,16 @@ func (c *Collection) DropColumn(ctx context.Context, field string) error {
 
 // AggregateAll aggregate all operation
 func (c *Collection) AggregateAll(ctx context.Context, pipeline interface{}, result interface{}) error {
-	return c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).All(result)
+	span := startSpan(ctx, "aggregate", c.collName)
+	err := c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).All(result)
+	finishSpan(span, err)
+	return err
 }
 
 // AggregateOne aggregate one operation
 func (c *Collection) AggregateOne(ctx context.Context, pipeline interface{}, result interface{}) error {
-	return c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).One(result)
+	span := startSpan(ctx, "aggregate", c.collName)
+	err := c.dbc.DB(c.dbname).C(c.collName).Pipe(pipeline).One(result)
+	finishSpan(span, err)
+	return err
 }
@@ -0,0 +1,37 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+
+	"configcenter/src/common/trace"
+	"configcenter/src/storage/dal"
+)
+
+// startSpan start a client span of the mongo operation
+func startSpan(ctx context.Context, operation, collection string) *trace.Span {
+	span, _ := trace.StartSpan(ctx, "mongo "+operation, trace.SpanKindClient)
+	span.SetAttribute("db.system", "mongodb")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.collection", collection)
+	return span
+}
+
+// finishSpan record the error of the operation and finish the span
+func finishSpan(span *trace.Span, err error) {
+	if err != nil && err != dal.ErrDocumentNotFound {
+		span.SetError(err)
+	}
+	span.Finish()
+}
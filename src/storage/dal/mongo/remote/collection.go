@@ -71,7 +71,7 @@ func (c *Collection) Update(ctx context.Context, filter dal.Filter, doc interfac
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, c.collection, &msg, &reply)
 	if err != nil {
 		return err
 	}
@@ -103,7 +103,7 @@ func (c *Collection) Delete(ctx context.Context, filter dal.Filter) error {
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, c.collection, &msg, &reply)
 	if err != nil {
 		return err
 	}
@@ -137,7 +137,7 @@ func (c *Collection) Insert(ctx context.Context, docs interface{}) error {
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, c.collection, &msg, &reply)
 	if err != nil {
 		return err
 	}
@@ -200,7 +200,7 @@ func (c *Collection) AggregateOne(ctx context.Context, pipeline interface{}, res
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, c.collection, &msg, &reply)
 	if err != nil {
 		return err
 	}
@@ -163,7 +163,7 @@ func (c *Mongo) NextSequence(ctx context.Context, sequenceName string) (uint64,
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, msg.Collection, &msg, &reply)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,45 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"errors"
+
+	"configcenter/src/common/trace"
+	"configcenter/src/storage/rpc"
+	"configcenter/src/storage/types"
+)
+
+// call send the operation to tmserver with a client span, the span is carried by the message header,
+// so that the operation executed by tmserver is traced as its child.
+func call(ctx context.Context, client rpc.Client, header *types.MsgHeader, collection string, msg interface{}, reply *types.OPReply) error {
+	span, _ := trace.StartSpan(ctx, "rpc "+header.OPCode.String(), trace.SpanKindClient)
+	span.SetAttribute("db.operation", header.OPCode.String())
+	if collection != "" {
+		span.SetAttribute("db.collection", collection)
+	}
+	if header.TxnID != "" {
+		span.SetAttribute("txn_id", header.TxnID)
+	}
+	header.TraceParent = span.TraceParent()
+
+	err := client.Call(types.CommandRDBOperation, msg, reply)
+	if err != nil {
+		span.SetError(err)
+	} else if !reply.Success {
+		span.SetError(errors.New(reply.Message))
+	}
+	span.Finish()
+	return err
+}
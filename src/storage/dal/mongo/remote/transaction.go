@@ -44,7 +44,7 @@ func (c *Mongo) StartTransaction(ctx context.Context) (dal.DB, error) {
 
 	// call
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, "", &msg, &reply)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +74,7 @@ func (c *Mongo) Commit(ctx context.Context) error {
 	msg.TxnID = c.TxnID
 
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, "", &msg, &reply)
 	c.TxnID = "" // clear TxnID
 	if err != nil {
 		return err
@@ -101,7 +101,7 @@ func (c *Mongo) Abort(ctx context.Context) error {
 	msg.TxnID = c.TxnID
 
 	reply := types.OPReply{}
-	err := c.rpc.Call(types.CommandRDBOperation, &msg, &reply)
+	err := call(ctx, c.rpc, &msg.MsgHeader, "", &msg, &reply)
 	c.TxnID = "" // clear TxnID
 	if err != nil {
 		return err
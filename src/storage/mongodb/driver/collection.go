@@ -83,6 +83,9 @@ func (c *collection) CreateIndex(index mongodb.Index) error {
 		Background: &index.Background,
 		Unique:     &index.Unique,
 	}
+	if 0 != len(index.PartialFilter) {
+		indexOpts.SetPartialFilterExpression(index.PartialFilter)
+	}
 
 	// in a session
 	if nil != c.innerSession {
@@ -34,4 +34,6 @@ type Index struct {
 	Name       string           `json:"name"`
 	Unique     bool             `json:"unique"`
 	Background bool             `json:"background"`
+	// PartialFilter only index the documents matching the filter, such as the ones containing the keys
+	PartialFilter map[string]interface{} `json:"partial_filter,omitempty"`
 }
@@ -14,7 +14,9 @@ package service
 
 import (
 	"context"
+	"errors"
 
+	"configcenter/src/common/trace"
 	"configcenter/src/storage/rpc"
 	"configcenter/src/storage/tmserver/core"
 	"configcenter/src/storage/types"
@@ -31,7 +33,24 @@ func (s *coreService) DBOperation(input rpc.Request) (interface{}, error) {
 		return &reply, nil
 	}
 
-	return s.core.ExecuteCommand(ctx, input)
+	// the operation is traced as the child of the caller's span carried by the message header
+	parent, _ := trace.ParseTraceParent(ctx.Header.TraceParent)
+	span := trace.NewSpan(parent, "tmserver "+ctx.Header.OPCode.String(), trace.SpanKindServer)
+	span.SetAttribute("db.operation", ctx.Header.OPCode.String())
+	span.SetAttribute("rid", ctx.Header.RequestID)
+	if ctx.Header.TxnID != "" {
+		span.SetAttribute("txn_id", ctx.Header.TxnID)
+	}
+	ctx.Context = trace.ContextWithSpanContext(ctx.Context, span.Context())
+	defer span.Finish()
+
+	result, err := s.core.ExecuteCommand(ctx, input)
+	if err != nil {
+		span.SetError(err)
+	} else if result != nil && !result.Success {
+		span.SetError(errors.New(result.Message))
+	}
+	return result, err
 
 }
 
@@ -14,9 +14,10 @@ package types
 
 // MsgHeader message header
 type MsgHeader struct {
-	OPCode    OPCode
-	TxnID     string
-	RequestID string
+	OPCode      OPCode
+	TxnID       string
+	RequestID   string
+	TraceParent string // w3c traceparent of the caller's span
 }
 
 // OPCode operation code type
@@ -30,6 +30,7 @@ import (
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/web_server/app/options"
 	"configcenter/src/web_server/logics"
+	"configcenter/src/web_server/middleware/user/plugins"
 	websvc "configcenter/src/web_server/service"
 )
 
@@ -111,7 +112,7 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 	service.Logics = &logics.Logics{Engine: engine}
 	service.Config = &webSvr.Config
 
-	if webSvr.Config.LoginVersion != common.BKDefaultLoginUserPluginVersion && webSvr.Config.LoginVersion != "" {
+	if webSvr.Config.LoginVersion != "" && !plugins.HasPlugin(webSvr.Config.LoginVersion) {
 		service.VersionPlg, err = plugin.Open("login.so")
 		if nil != err {
 			service.VersionPlg = nil
@@ -119,6 +120,8 @@ func Run(ctx context.Context, op *options.ServerOption) error {
 		}
 	}
 
+	go websvc.CleanExpiredExportJobFiles(ctx)
+
 	if err := backbone.StartServer(ctx, engine, service.WebService()); err != nil {
 		return err
 	}
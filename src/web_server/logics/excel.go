@@ -30,108 +30,129 @@ import (
 	"github.com/rentiansheng/xlsx"
 )
 
+// hostExtFieldTopoID the column of the host business topology, only exported
+const hostExtFieldTopoID = "cc_ext_field_topo"
+
 // BuildExcelFromData product excel from data
 func (lgc *Logics) BuildExcelFromData(ctx context.Context, objID string, fields map[string]Property, filter []string, data []mapstr.MapStr, xlsxFile *xlsx.File, header http.Header, meta metadata.Metadata) error {
-
-	ccLang := lgc.Language.CreateDefaultCCLanguageIf(util.GetLanguage(header))
-	ccErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
-	sheet, err := xlsxFile.AddSheet("inst")
+	exporter, err := lgc.NewExcelExporter(ctx, objID, fields, filter, xlsxFile, header, meta)
 	if err != nil {
-		blog.Errorf("setExcelRowDataByIndex add excel sheet error, err:%s, rid:%s", err.Error(), util.GetHTTPCCRequestID(header))
 		return err
-
-	}
-	addSystemField(fields, common.BKInnerObjIDObject, ccLang)
-
-	if 0 == len(filter) {
-		filter = getFilterFields(objID)
-	} else {
-		filter = append(filter, getFilterFields(objID)...)
 	}
-
-	instPrimaryKeyValMap := make(map[int64][]PropertyPrimaryVal)
-	productExcelHealer(fields, filter, sheet, ccLang)
-	// indexID := getFieldsIDIndexMap(fields)
-
-	rowIndex := common.HostAddMethodExcelIndexOffset
-
-	for _, rowMap := range data {
-
-		instIDKey := metadata.GetInstIDFieldByObjID(objID)
-		instID, err := rowMap.Int64(instIDKey)
-		if err != nil {
-			blog.Errorf("setExcelRowDataByIndex inst:%+v, not inst id key:%s, objID:%s, rid:%s", rowMap, instIDKey, objID, util.GetHTTPCCRequestID(header))
-			return ccErr.Errorf(common.CCErrCommInstFieldNotFound, "instIDKey", objID)
-		}
-
-		primaryKeyArr := setExcelRowDataByIndex(rowMap, sheet, rowIndex, fields)
-		instPrimaryKeyValMap[instID] = primaryKeyArr
-		rowIndex++
-
-	}
-
-	err = lgc.BuildAssociationExcelFromData(ctx, objID, instPrimaryKeyValMap, xlsxFile, header, meta)
-	if err != nil {
+	if err := exporter.WritePage(data); err != nil {
 		return err
 	}
-	return nil
+	return exporter.Finish()
 }
 
 // BuildHostExcelFromData product excel from data
 func (lgc *Logics) BuildHostExcelFromData(ctx context.Context, objID string, fields map[string]Property, filter []string, data []mapstr.MapStr, xlsxFile *xlsx.File, header http.Header, meta metadata.Metadata) error {
+	return lgc.BuildExcelFromData(ctx, objID, fields, filter, data, xlsxFile, header, meta)
+}
+
+// ExcelExporter write the insts to the excel sheet page by page, only the primary keys of the written insts
+// are kept to build the association sheet, the data of the host is the result of the host search
+type ExcelExporter struct {
+	lgc                  *Logics
+	ctx                  context.Context
+	objID                string
+	fields               map[string]Property
+	sheet                *xlsx.Sheet
+	xlsxFile             *xlsx.File
+	header               http.Header
+	meta                 metadata.Metadata
+	rowIndex             int
+	instPrimaryKeyValMap map[int64][]PropertyPrimaryVal
+}
+
+// NewExcelExporter add the sheet of the insts with the header to the excel file
+func (lgc *Logics) NewExcelExporter(ctx context.Context, objID string, fields map[string]Property, filter []string, xlsxFile *xlsx.File, header http.Header, meta metadata.Metadata) (*ExcelExporter, error) {
 	ccLang := lgc.Language.CreateDefaultCCLanguageIf(util.GetLanguage(header))
-	ccErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
 
-	sheet, err := xlsxFile.AddSheet("host")
-	if err != nil {
-		blog.Errorf("BuildHostExcelFromData add excel sheet error, err:%s, rid:%s", err.Error(), util.GetHTTPCCRequestID(header))
-		return err
+	sheetName := "inst"
+	if common.BKInnerObjIDHost == objID {
+		sheetName = "host"
 	}
-	extFieldsTopoID := "cc_ext_field_topo"
-	extFields := map[string]string{
-		extFieldsTopoID: ccLang.Language("web_ext_field_topo"),
+	sheet, err := xlsxFile.AddSheet(sheetName)
+	if err != nil {
+		blog.Errorf("NewExcelExporter add excel sheet error, err:%s, rid:%s", err.Error(), util.GetHTTPCCRequestID(header))
+		return nil, err
 	}
-	fields = addExtFields(fields, extFields)
-	addSystemField(fields, common.BKInnerObjIDHost, ccLang)
 
+	if common.BKInnerObjIDHost == objID {
+		extFields := map[string]string{
+			hostExtFieldTopoID: ccLang.Language("web_ext_field_topo"),
+		}
+		fields = addExtFields(fields, extFields)
+		addSystemField(fields, common.BKInnerObjIDHost, ccLang)
+	} else {
+		addSystemField(fields, common.BKInnerObjIDObject, ccLang)
+		if 0 == len(filter) {
+			filter = getFilterFields(objID)
+		} else {
+			filter = append(filter, getFilterFields(objID)...)
+		}
+	}
 	productExcelHealer(fields, filter, sheet, ccLang)
 
-	instPrimaryKeyValMap := make(map[int64][]PropertyPrimaryVal)
-	//indexID := getFieldsIDIndexMap(fields)
-	rowIndex := common.HostAddMethodExcelIndexOffset
-	for _, hostData := range data {
+	return &ExcelExporter{
+		lgc:                  lgc,
+		ctx:                  ctx,
+		objID:                objID,
+		fields:               fields,
+		sheet:                sheet,
+		xlsxFile:             xlsxFile,
+		header:               header,
+		meta:                 meta,
+		rowIndex:             common.HostAddMethodExcelIndexOffset,
+		instPrimaryKeyValMap: make(map[int64][]PropertyPrimaryVal),
+	}, nil
+}
 
-		rowMap, err := mapstr.NewFromInterface(hostData[common.BKInnerObjIDHost])
-		if err != nil {
-			msg := fmt.Sprintf("data format error:%v", hostData)
-			blog.Errorf(msg)
-			return errors.New(msg)
-		}
-		moduleMap, ok := hostData[common.BKInnerObjIDModule].([]interface{})
-		if ok {
-			topo := util.GetStrValsFromArrMapInterfaceByKey(moduleMap, "TopModuleName")
-			rowMap[extFieldsTopoID] = strings.Join(topo, "\n")
+// Rows return the count of the written rows
+func (e *ExcelExporter) Rows() int {
+	return e.rowIndex - common.HostAddMethodExcelIndexOffset
+}
+
+// WritePage write a page of the insts to the sheet
+func (e *ExcelExporter) WritePage(data []mapstr.MapStr) error {
+	ccErr := e.lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(e.header))
+	instIDKey := metadata.GetInstIDFieldByObjID(e.objID)
+
+	for _, rowMap := range data {
+		if common.BKInnerObjIDHost == e.objID {
+			hostData := rowMap
+			var err error
+			rowMap, err = mapstr.NewFromInterface(hostData[common.BKInnerObjIDHost])
+			if err != nil {
+				msg := fmt.Sprintf("data format error:%v", hostData)
+				blog.Errorf(msg)
+				return errors.New(msg)
+			}
+			moduleMap, ok := hostData[common.BKInnerObjIDModule].([]interface{})
+			if ok {
+				topo := util.GetStrValsFromArrMapInterfaceByKey(moduleMap, "TopModuleName")
+				rowMap[hostExtFieldTopoID] = strings.Join(topo, "\n")
+			}
 		}
 
-		instIDKey := metadata.GetInstIDFieldByObjID(objID)
 		instID, err := rowMap.Int64(instIDKey)
 		if err != nil {
-			blog.Errorf("setExcelRowDataByIndex inst:%+v, not inst id key:%s, objID:%s, rid:%s", rowMap, instIDKey, objID, util.GetHTTPCCRequestID(header))
-			return ccErr.Errorf(common.CCErrCommInstFieldNotFound, "instIDKey", objID)
+			blog.Errorf("ExcelExporter inst:%+v, not inst id key:%s, objID:%s, rid:%s", rowMap, instIDKey, e.objID, util.GetHTTPCCRequestID(e.header))
+			return ccErr.Errorf(common.CCErrCommInstFieldNotFound, "instIDKey", e.objID)
 		}
-		primaryKeyArr := setExcelRowDataByIndex(rowMap, sheet, rowIndex, fields)
-		instPrimaryKeyValMap[instID] = primaryKeyArr
-		rowIndex++
 
-	}
-
-	err = lgc.BuildAssociationExcelFromData(ctx, objID, instPrimaryKeyValMap, xlsxFile, header, meta)
-	if err != nil {
-		return err
+		e.instPrimaryKeyValMap[instID] = setExcelRowDataByIndex(rowMap, e.sheet, e.rowIndex, e.fields)
+		e.rowIndex++
 	}
 	return nil
 }
 
+// Finish add the association sheet of the written insts
+func (e *ExcelExporter) Finish() error {
+	return e.lgc.BuildAssociationExcelFromData(e.ctx, e.objID, e.instPrimaryKeyValMap, e.xlsxFile, e.header, e.meta)
+}
+
 func (lgc *Logics) BuildAssociationExcelFromData(ctx context.Context, objID string, instPrimaryInfo map[int64][]PropertyPrimaryVal, xlsxFile *xlsx.File, header http.Header, meta metadata.Metadata) error {
 	var instIDArr []int64
 	for instID := range instPrimaryInfo {
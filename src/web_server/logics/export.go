@@ -0,0 +1,172 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	redis "gopkg.in/redis.v5"
+)
+
+const (
+	// DefaultExportPageSize the count of the insts searched each time when exporting
+	DefaultExportPageSize = 500
+	// MaxExportPageSize the max count of the insts searched each time when exporting
+	MaxExportPageSize = 2000
+
+	// ExportJobExpire the export job and the exported file are kept for one day
+	ExportJobExpire = 24 * time.Hour
+
+	exportJobKeyPrefix = common.BKCacheKeyV3Prefix + "web:export_job:"
+)
+
+// the status of the export job
+const (
+	ExportJobStatusRunning = "running"
+	ExportJobStatusSuccess = "success"
+	ExportJobStatusFailure = "failure"
+)
+
+// ExportPageHandler handle a page of the exported insts
+type ExportPageHandler func(data []mapstr.MapStr) error
+
+// ForEachHostPage search the exported hosts page by page sorted by the host id, and handle each page in turn
+func (lgc *Logics) ForEachHostPage(ctx context.Context, appIDStr, hostIDStr string, header http.Header, pageSize int, handler ExportPageHandler) error {
+	cond := getHostSearchCond(appIDStr, hostIDStr)
+	return forEachPage(pageSize, common.BKHostIDField, func(page mapstr.MapStr) (*metadata.QueryInstResult, error) {
+		cond["page"] = page
+		return lgc.Engine.CoreAPI.ApiServer().GetHostData(ctx, header, cond)
+	}, handler)
+}
+
+// ForEachInstPage search the exported insts page by page sorted by the inst id, and handle each page in turn
+func (lgc *Logics) ForEachInstPage(ctx context.Context, ownerID, objID, instIDStr string, header http.Header, meta metadata.Metadata, pageSize int, handler ExportPageHandler) error {
+	cond := getInstSearchCond(ownerID, objID, instIDStr, meta)
+	return forEachPage(pageSize, metadata.GetInstIDFieldByObjID(objID), func(page mapstr.MapStr) (*metadata.QueryInstResult, error) {
+		cond["page"] = page
+		return lgc.Engine.CoreAPI.ApiServer().GetInstDetail(ctx, header, ownerID, objID, cond)
+	}, handler)
+}
+
+func forEachPage(pageSize int, sort string, search func(page mapstr.MapStr) (*metadata.QueryInstResult, error), handler ExportPageHandler) error {
+	if pageSize <= 0 {
+		pageSize = DefaultExportPageSize
+	}
+	if pageSize > MaxExportPageSize {
+		pageSize = MaxExportPageSize
+	}
+
+	for start := 0; ; start += pageSize {
+		result, err := search(mapstr.MapStr{"start": start, "limit": pageSize, "sort": sort})
+		if nil != err {
+			return err
+		}
+		if !result.Result {
+			return errors.New(result.ErrMsg)
+		}
+		if 0 == len(result.Data.Info) {
+			return nil
+		}
+		if err := handler(result.Data.Info); nil != err {
+			return err
+		}
+		if len(result.Data.Info) < pageSize || start+pageSize >= result.Data.Count {
+			return nil
+		}
+	}
+}
+
+// ExportJob the background export job, the exported file is written to the local disk of the web server
+// which runs the job, and can be downloaded by the creator before it expires
+type ExportJob struct {
+	ID         string    `json:"id"`
+	User       string    `json:"bk_user"`
+	ObjectID   string    `json:"bk_obj_id"`
+	Format     string    `json:"format"`
+	Sheet      string    `json:"sheet"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message"`
+	Rows       int       `json:"rows"`
+	FileName   string    `json:"file_name"`
+	CreateTime time.Time `json:"create_time"`
+	FinishTime time.Time `json:"finish_time"`
+	// Node the address of the web server which runs the job, the exported file is kept on it
+	Node string `json:"node"`
+}
+
+// SaveExportJob save the status of the export job
+func SaveExportJob(cacheCli *redis.Client, job *ExportJob) error {
+	out, err := json.Marshal(job)
+	if nil != err {
+		return err
+	}
+	return cacheCli.Set(exportJobKeyPrefix+job.ID, string(out), ExportJobExpire).Err()
+}
+
+// GetExportJob get the export job by id, return nil if the job is not found or expired
+func GetExportJob(cacheCli *redis.Client, id string) (*ExportJob, error) {
+	val, err := cacheCli.Get(exportJobKeyPrefix + id).Result()
+	if redis.Nil == err {
+		return nil, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	job := new(ExportJob)
+	if err := json.Unmarshal([]byte(val), job); nil != err {
+		return nil, err
+	}
+	return job, nil
+}
+
+// RunExportJob run the export job in the background and update the job status when the job finished
+func RunExportJob(cacheCli *redis.Client, job *ExportJob, header http.Header, export func() (int, error)) {
+	rid := util.GetHTTPCCRequestID(header)
+	go func() {
+		defer func() {
+			if err := recover(); nil != err {
+				blog.Errorf("export job %s panic, err:%v, rid:%s", job.ID, err, rid)
+				job.Status = ExportJobStatusFailure
+				job.Message = "internal error"
+				job.FinishTime = time.Now()
+				if err := SaveExportJob(cacheCli, job); nil != err {
+					blog.Errorf("save export job %s failed, err:%s, rid:%s", job.ID, err.Error(), rid)
+				}
+			}
+		}()
+
+		rows, err := export()
+		job.Rows = rows
+		job.FinishTime = time.Now()
+		if nil != err {
+			blog.Errorf("export job %s failed, err:%s, rid:%s", job.ID, err.Error(), rid)
+			job.Status = ExportJobStatusFailure
+			job.Message = err.Error()
+		} else {
+			job.Status = ExportJobStatusSuccess
+		}
+		if err := SaveExportJob(cacheCli, job); nil != err {
+			blog.Errorf("save export job %s failed, err:%s, rid:%s", job.ID, err.Error(), rid)
+		}
+	}()
+}
@@ -0,0 +1,66 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"errors"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachPage(t *testing.T) {
+	total := 5
+	search := func(page mapstr.MapStr) (*metadata.QueryInstResult, error) {
+		start, limit := page["start"].(int), page["limit"].(int)
+		result := &metadata.QueryInstResult{BaseResp: metadata.SuccessBaseResp}
+		result.Data.Count = total
+		for i := start; i < start+limit && i < total; i++ {
+			result.Data.Info = append(result.Data.Info, mapstr.MapStr{"bk_inst_id": i})
+		}
+		return result, nil
+	}
+
+	var pages []int
+	err := forEachPage(2, "bk_inst_id", search, func(data []mapstr.MapStr) error {
+		pages = append(pages, len(data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 2, 1}, pages)
+
+	pages = nil
+	total = 4
+	err = forEachPage(2, "bk_inst_id", search, func(data []mapstr.MapStr) error {
+		pages = append(pages, len(data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 2}, pages)
+
+	handlerErr := errors.New("write failed")
+	err = forEachPage(2, "bk_inst_id", search, func(data []mapstr.MapStr) error {
+		return handlerErr
+	})
+	require.Equal(t, handlerErr, err)
+
+	err = forEachPage(2, "bk_inst_id", func(page mapstr.MapStr) (*metadata.QueryInstResult, error) {
+		return &metadata.QueryInstResult{BaseResp: metadata.BaseResp{Result: false, ErrMsg: "search failed"}}, nil
+	}, func(data []mapstr.MapStr) error {
+		return nil
+	})
+	require.EqualError(t, err, "search failed")
+}
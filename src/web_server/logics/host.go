@@ -32,6 +32,18 @@ import (
 // GetHostData get host data from excel
 func (lgc *Logics) GetHostData(appIDStr, hostIDStr string, header http.Header) ([]mapstr.MapStr, error) {
 	hostInfo := make([]mapstr.MapStr, 0)
+	sHostCond := getHostSearchCond(appIDStr, hostIDStr)
+	result, err := lgc.Engine.CoreAPI.ApiServer().GetHostData(context.Background(), header, sHostCond)
+	if nil != err || false == result.Result {
+		return hostInfo, errors.New("no host")
+	}
+
+	return result.Data.Info, nil
+}
+
+// getHostSearchCond get the condition of the exported hosts, all the hosts of the business are exported
+// if the business is not -1, otherwise the hosts in the host id list
+func getHostSearchCond(appIDStr, hostIDStr string) mapstr.MapStr {
 	sHostCond := make(map[string]interface{})
 	appID, _ := strconv.Atoi(appIDStr)
 	hostIDArr := strings.Split(hostIDStr, ",")
@@ -88,12 +100,7 @@ func (lgc *Logics) GetHostData(appIDStr, hostIDStr string, header http.Header) (
 		sHostCond["page"] = make(map[string]interface{})
 
 	}
-	result, err := lgc.Engine.CoreAPI.ApiServer().GetHostData(context.Background(), header, sHostCond)
-	if nil != err || false == result.Result {
-		return hostInfo, errors.New("no host")
-	}
-
-	return result.Data.Info, nil
+	return sHostCond
 }
 
 // GetImportHosts get import hosts
@@ -120,7 +127,7 @@ func (lgc *Logics) GetImportHosts(f *xlsx.File, header http.Header, defLang lang
 }
 
 // ImportHosts import host info
-func (lgc *Logics) ImportHosts(ctx context.Context, f *xlsx.File, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata) (resultData mapstr.MapStr, errCode int, err error) {
+func (lgc *Logics) ImportHosts(ctx context.Context, f *xlsx.File, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata, opt ImportOption) (resultData mapstr.MapStr, errCode int, err error) {
 	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
 	hosts, errMsg, err := lgc.GetImportHosts(f, header, defLang, meta)
 	resultData = mapstr.New()
@@ -140,6 +147,7 @@ func (lgc *Logics) ImportHosts(ctx context.Context, f *xlsx.File, header http.He
 	params["host_info"] = hosts
 	params["bk_supplier_id"] = common.BKDefaultSupplierID
 	params["input_type"] = common.InputTypeExcel
+	opt.setParams(params)
 
 	result, resultErr := lgc.CoreAPI.ApiServer().AddHost(context.Background(), header, params)
 	if nil != resultErr {
@@ -151,7 +159,8 @@ func (lgc *Logics) ImportHosts(ctx context.Context, f *xlsx.File, header http.He
 	errCode = result.Code
 	err = defErr.New(result.Code, result.ErrMsg)
 
-	if len(f.Sheets) > 2 {
+	// the associations can not be previewed, and should not be imported when the hosts are rejected
+	if len(f.Sheets) > 2 && opt.isWritten(resultData) {
 		asstInfoMap := GetAssociationExcelData(f.Sheets[1], common.HostAddMethodExcelAssociationIndexOffset)
 		if len(asstInfoMap) > 0 {
 			asstInfoMapInput := &metadata.RequestImportAssociation{
@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+)
+
+// ImportOption the options of importing the insts and the hosts
+type ImportOption struct {
+	// ValidateOnly only return the preview of the rows, nothing is written
+	ValidateOnly bool
+	// AllOrNothing validate all the rows first, nothing is written if any row is invalid
+	AllOrNothing bool
+}
+
+// setParams set the options to the params of the import request
+func (opt ImportOption) setParams(params mapstr.MapStr) {
+	params["validate_only"] = opt.ValidateOnly
+	params["all_or_nothing"] = opt.AllOrNothing
+}
+
+// isWritten whether the rows of the import request are written, nothing is written when validate only,
+// or when the all or nothing import is rejected by the validation or rolled back by a failed row
+func (opt ImportOption) isWritten(resultData mapstr.MapStr) bool {
+	if opt.ValidateOnly {
+		return false
+	}
+	if !opt.AllOrNothing {
+		return true
+	}
+	if resultData.Exists("preview") {
+		return false
+	}
+	for _, key := range []string{"error", "update_error"} {
+		if errs, ok := resultData[key].([]interface{}); ok && 0 != len(errs) {
+			return false
+		}
+		if errs, ok := resultData[key].([]string); ok && 0 != len(errs) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAssociationOption check the option of importing the associations, which can be neither previewed nor rolled back
+func (opt ImportOption) checkAssociationOption(defErr errors.DefaultCCErrorIf) error {
+	if opt.ValidateOnly {
+		return defErr.Errorf(common.CCErrCommParamsIsInvalid, "validate_only")
+	}
+	if opt.AllOrNothing {
+		return defErr.Errorf(common.CCErrCommParamsIsInvalid, "all_or_nothing")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"testing"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportOption(t *testing.T) {
+	params := mapstr.New()
+	opt := ImportOption{AllOrNothing: true}
+	opt.setParams(params)
+	require.Equal(t, false, params["validate_only"])
+	require.Equal(t, true, params["all_or_nothing"])
+
+	require.True(t, opt.isWritten(mapstr.MapStr{"success": []string{"1"}}))
+	require.True(t, opt.isWritten(mapstr.MapStr{"success": []interface{}{"1"}, "error": nil}))
+	require.False(t, opt.isWritten(mapstr.MapStr{"preview": mapstr.New()}))
+	require.False(t, opt.isWritten(mapstr.MapStr{"error": []interface{}{"row 2 failed"}}))
+	require.False(t, opt.isWritten(mapstr.MapStr{"update_error": []string{"row 2 failed"}}))
+	require.True(t, ImportOption{}.isWritten(mapstr.MapStr{"error": []string{"row 2 failed"}}))
+	require.False(t, ImportOption{ValidateOnly: true}.isWritten(mapstr.New()))
+	require.True(t, ImportOption{}.isWritten(mapstr.MapStr{"preview": mapstr.New()}))
+
+	defErr := errors.NewFromCtx(errors.EmptyErrorsSetting).CreateDefaultCCErrorIf("en")
+	require.NoError(t, ImportOption{}.checkAssociationOption(defErr))
+	require.Error(t, ImportOption{ValidateOnly: true}.checkAssociationOption(defErr))
+	require.Error(t, opt.checkAssociationOption(defErr))
+}
@@ -55,25 +55,7 @@ func (lgc *Logics) GetImportInsts(f *xlsx.File, objID string, header http.Header
 //GetInstData get inst data
 func (lgc *Logics) GetInstData(ownerID, objID, instIDStr string, header http.Header, kvMap mapstr.MapStr, meta metadata.Metadata) ([]mapstr.MapStr, error) {
 
-	instIDArr := strings.Split(instIDStr, ",")
-	searchCond := mapstr.MapStr{}
-
-	iInstIDArr := make([]int, 0)
-	for _, j := range instIDArr {
-		instID, _ := strconv.Atoi(j)
-		iInstIDArr = append(iInstIDArr, instID)
-	}
-
-	searchCond["fields"] = []string{}
-	searchCond["condition"] = mapstr.MapStr{
-		common.BKInstIDField: mapstr.MapStr{
-			common.BKDBIN: iInstIDArr,
-		},
-		common.BKOwnerIDField: ownerID,
-		common.BKObjIDField:   objID,
-	}
-	searchCond["page"] = nil
-	searchCond[metadata.BKMetadata] = meta
+	searchCond := getInstSearchCond(ownerID, objID, instIDStr, meta)
 	result, err := lgc.Engine.CoreAPI.ApiServer().GetInstDetail(context.Background(), header, ownerID, objID, searchCond)
 	if nil != err || !result.Result {
 		blog.Errorf("get inst detail error:%v , search condition:%#v", err, searchCond)
@@ -101,8 +83,32 @@ func (lgc *Logics) GetInstData(ownerID, objID, instIDStr string, header http.Hea
 	return result.Data.Info, nil
 }
 
+// getInstSearchCond get the condition of the exported insts
+func getInstSearchCond(ownerID, objID, instIDStr string, meta metadata.Metadata) mapstr.MapStr {
+	instIDArr := strings.Split(instIDStr, ",")
+	searchCond := mapstr.MapStr{}
+
+	iInstIDArr := make([]int, 0)
+	for _, j := range instIDArr {
+		instID, _ := strconv.Atoi(j)
+		iInstIDArr = append(iInstIDArr, instID)
+	}
+
+	searchCond["fields"] = []string{}
+	searchCond["condition"] = mapstr.MapStr{
+		common.BKInstIDField: mapstr.MapStr{
+			common.BKDBIN: iInstIDArr,
+		},
+		common.BKOwnerIDField: ownerID,
+		common.BKObjIDField:   objID,
+	}
+	searchCond["page"] = nil
+	searchCond[metadata.BKMetadata] = meta
+	return searchCond
+}
+
 // ImportHosts import host info
-func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata) (resultData mapstr.MapStr, errCode int, err error) {
+func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata, opt ImportOption) (resultData mapstr.MapStr, errCode int, err error) {
 	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
 	resultData = mapstr.New()
 	insts, errMsg, err := lgc.GetImportInsts(f, objID, header, 0, true, defLang, meta)
@@ -121,6 +127,7 @@ func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string,
 	params := mapstr.MapStr{}
 	params["input_type"] = common.InputTypeExcel
 	params["BatchInfo"] = insts
+	opt.setParams(params)
 	result, resultErr := lgc.CoreAPI.ApiServer().AddInst(context.Background(), header, util.GetOwnerID(header), objID, params)
 	if nil != err {
 		blog.Errorf("ImportInsts add inst info  http request  error:%s, rid:%s", resultErr.Error(), util.GetHTTPCCRequestID(header))
@@ -133,7 +140,8 @@ func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string,
 		err = defErr.New(result.Code, result.ErrMsg)
 	}
 
-	if len(f.Sheets) > 2 {
+	// the associations can not be previewed, and should not be imported when the insts are rejected
+	if len(f.Sheets) > 2 && opt.isWritten(resultData) {
 		asstInfoMap := GetAssociationExcelData(f.Sheets[1], common.HostAddMethodExcelAssociationIndexOffset)
 
 		if len(asstInfoMap) > 0 {
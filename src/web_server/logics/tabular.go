@@ -0,0 +1,365 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	lang "configcenter/src/common/language"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TabularExporter write the insts or the associations of the insts to the csv or ndjson file page by page,
+// so that the exported insts need not to be loaded into memory at once
+type TabularExporter struct {
+	lgc          *Logics
+	ctx          context.Context
+	objID        string
+	format       string
+	sheet        string
+	fields       map[string]Property
+	exportFields []Property
+	columns      []string
+	writer       tabularWriter
+	header       http.Header
+	meta         metadata.Metadata
+	rows         int
+}
+
+// NewTabularExporter create the exporter of the insts, the data of the host is the result of the host search
+// which includes the topology of the host
+func (lgc *Logics) NewTabularExporter(ctx context.Context, objID string, fields map[string]Property, filter []string, format, sheet string, w io.Writer, header http.Header, meta metadata.Metadata) (*TabularExporter, error) {
+	ccLang := lgc.Language.CreateDefaultCCLanguageIf(util.GetLanguage(header))
+
+	if common.BKInnerObjIDHost == objID {
+		extFields := map[string]string{
+			hostExtFieldTopoID: ccLang.Language("web_ext_field_topo"),
+		}
+		fields = addExtFields(fields, extFields)
+		addSystemField(fields, common.BKInnerObjIDHost, ccLang)
+	} else {
+		addSystemField(fields, common.BKInnerObjIDObject, ccLang)
+		if 0 == len(filter) {
+			filter = getFilterFields(objID)
+		} else {
+			filter = append(filter, getFilterFields(objID)...)
+		}
+	}
+
+	e := &TabularExporter{
+		lgc:          lgc,
+		ctx:          ctx,
+		objID:        objID,
+		format:       format,
+		sheet:        sheet,
+		fields:       fields,
+		exportFields: getTabularFields(fields, filter, ccLang),
+		writer:       newTabularWriter(format, w),
+		header:       header,
+		meta:         meta,
+	}
+	for _, field := range e.exportFields {
+		e.columns = append(e.columns, field.ID)
+	}
+
+	columns := e.columns
+	if TabularSheetAssociation == sheet {
+		columns = tabularAssociationFields
+	}
+	if err := e.writer.WriteHeader(columns); nil != err {
+		blog.Errorf("NewTabularExporter write %s header error, err:%s, rid:%s", format, err.Error(), util.GetHTTPCCRequestID(header))
+		return nil, err
+	}
+	return e, nil
+}
+
+// Rows return the count of the written rows
+func (e *TabularExporter) Rows() int {
+	return e.rows
+}
+
+// Flush flush the written rows to the file
+func (e *TabularExporter) Flush() error {
+	return e.writer.Flush()
+}
+
+// WritePage write a page of the insts, or the associations of the insts, and flush them to the file
+func (e *TabularExporter) WritePage(data []mapstr.MapStr) error {
+	ccErr := e.lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(e.header))
+
+	instPrimaryKeyValMap := make(map[int64][]PropertyPrimaryVal)
+	instIDKey := metadata.GetInstIDFieldByObjID(e.objID)
+	for _, item := range data {
+		rowMap := item
+		if common.BKInnerObjIDHost == e.objID {
+			var err error
+			rowMap, err = getHostRowData(item)
+			if nil != err {
+				return err
+			}
+		}
+
+		instID, err := rowMap.Int64(instIDKey)
+		if err != nil {
+			blog.Errorf("TabularExporter inst:%+v, not inst id key:%s, objID:%s, rid:%s", rowMap, instIDKey, e.objID, util.GetHTTPCCRequestID(e.header))
+			return ccErr.Errorf(common.CCErrCommInstFieldNotFound, "instIDKey", e.objID)
+		}
+		if TabularSheetAssociation == e.sheet {
+			instPrimaryKeyValMap[instID] = getPrimaryKeyVals(rowMap, e.fields)
+			continue
+		}
+
+		row := make(map[string]interface{}, len(e.exportFields))
+		for _, field := range e.exportFields {
+			if val, ok := rowMap[field.ID]; ok {
+				row[field.ID] = getExportValue(field, val)
+			}
+		}
+		if err := e.writer.WriteRow(e.columns, row); nil != err {
+			blog.Errorf("TabularExporter write %s row error, err:%s, rid:%s", e.format, err.Error(), util.GetHTTPCCRequestID(e.header))
+			return err
+		}
+		e.rows++
+	}
+
+	if TabularSheetAssociation == e.sheet && 0 != len(instPrimaryKeyValMap) {
+		if err := e.writeAssociation(instPrimaryKeyValMap); nil != err {
+			return err
+		}
+	}
+	return e.writer.Flush()
+}
+
+// writeAssociation write the associations of the insts, the insts are identified by the unique fields
+// the same as the excel association sheet
+func (e *TabularExporter) writeAssociation(instPrimaryInfo map[int64][]PropertyPrimaryVal) error {
+	var instIDArr []int64
+	for instID := range instPrimaryInfo {
+		instIDArr = append(instIDArr, instID)
+	}
+	instAsst, err := e.lgc.fetchAssocationData(e.ctx, e.header, e.objID, instIDArr)
+	if err != nil {
+		return err
+	}
+	asstData, err := e.lgc.getAssociationData(e.ctx, e.header, e.objID, instAsst, e.meta)
+	if err != nil {
+		return err
+	}
+
+	for _, inst := range instAsst {
+		srcInst, ok := instPrimaryInfo[inst.InstID]
+		if !ok {
+			blog.Warnf("TabularExporter association inst:%+v, not inst id :%d, objID:%s, rid:%s", inst, inst.InstID, e.objID, util.GetHTTPCCRequestID(e.header))
+			continue
+		}
+		dstInst, ok := asstData[inst.AsstObjectID][inst.AsstInstID]
+		if !ok {
+			blog.Warnf("TabularExporter association inst:%+v, not inst id :%d, objID:%s, rid:%s", inst, inst.AsstInstID, inst.AsstObjectID, util.GetHTTPCCRequestID(e.header))
+			continue
+		}
+		row := map[string]interface{}{
+			tabularAsstObjIDField: inst.ObjectAsstID,
+			tabularAsstOPField:    "",
+			tabularAsstSrcField:   buildEexcelPrimaryKey(srcInst),
+			tabularAsstDstField:   buildEexcelPrimaryKey(dstInst),
+		}
+		if err := e.writer.WriteRow(tabularAssociationFields, row); nil != err {
+			blog.Errorf("TabularExporter write %s association row error, err:%s, rid:%s", e.format, err.Error(), util.GetHTTPCCRequestID(e.header))
+			return err
+		}
+		e.rows++
+	}
+	return nil
+}
+
+// getHostRowData get the host fields and the business topology of the host from the host search result
+func getHostRowData(hostData mapstr.MapStr) (mapstr.MapStr, error) {
+	rowMap, err := mapstr.NewFromInterface(hostData[common.BKInnerObjIDHost])
+	if err != nil {
+		msg := fmt.Sprintf("data format error:%v", hostData)
+		blog.Errorf(msg)
+		return nil, errors.New(msg)
+	}
+	moduleMap, ok := hostData[common.BKInnerObjIDModule].([]interface{})
+	if ok {
+		topo := util.GetStrValsFromArrMapInterfaceByKey(moduleMap, "TopModuleName")
+		rowMap[hostExtFieldTopoID] = strings.Join(topo, "\n")
+	}
+	return rowMap, nil
+}
+
+// BuildTabularFromData write the insts or the associations of the insts to the csv or ndjson file
+func (lgc *Logics) BuildTabularFromData(ctx context.Context, objID string, fields map[string]Property, filter []string, data []mapstr.MapStr, format, sheet string, w io.Writer, header http.Header, meta metadata.Metadata) error {
+	exporter, err := lgc.NewTabularExporter(ctx, objID, fields, filter, format, sheet, w, header, meta)
+	if nil != err {
+		return err
+	}
+	if err := exporter.WritePage(data); nil != err {
+		return err
+	}
+	return exporter.Flush()
+}
+
+// GetImportTabularInsts get insts from the csv or ndjson file
+func (lgc *Logics) GetImportTabularInsts(r io.Reader, format, objID string, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata) (map[int]map[string]interface{}, []string, error) {
+	fields, err := lgc.GetObjFieldIDs(objID, nil, nil, header, meta)
+	if nil != err {
+		return nil, nil, errors.New(defLang.Languagef("web_get_object_field_failure", err.Error()))
+	}
+	records, errMsg, err := readTabularRecords(r, format, defLang)
+	if nil != err {
+		return nil, nil, err
+	}
+	if 0 != len(errMsg) {
+		return nil, errMsg, nil
+	}
+	insts, err := getTabularData(records, fields, common.KvMap{"import_from": common.HostAddMethodExcel}, true, defLang)
+	return insts, nil, err
+}
+
+// ImportInstsFromTabular import the insts or the associations of the insts from the csv or ndjson file
+func (lgc *Logics) ImportInstsFromTabular(ctx context.Context, r io.Reader, format, sheet, objID string, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata, opt ImportOption) (resultData mapstr.MapStr, errCode int, err error) {
+	if TabularSheetAssociation == sheet {
+		if err := opt.checkAssociationOption(lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))); nil != err {
+			return nil, common.CCErrCommParamsIsInvalid, err
+		}
+		return lgc.ImportAssociationFromTabular(ctx, r, format, objID, header, defLang)
+	}
+
+	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
+	resultData = mapstr.New()
+	insts, errMsg, err := lgc.GetImportTabularInsts(r, format, objID, header, defLang, meta)
+	if nil != err {
+		blog.Errorf("ImportInstsFromTabular get %s inst info from %s error, error:%s, rid:%s", objID, format, err.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, err.Error())
+	}
+	if 0 != len(errMsg) {
+		resultData.Set("err", errMsg)
+		return resultData, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, " file empty")
+	}
+	if 0 == len(insts) {
+		return nil, common.CCErrWebFileContentEmpty, defErr.Errorf(common.CCErrWebFileContentEmpty, "")
+	}
+
+	params := mapstr.MapStr{}
+	params["input_type"] = common.InputTypeExcel
+	params["BatchInfo"] = insts
+	opt.setParams(params)
+	result, resultErr := lgc.CoreAPI.ApiServer().AddInst(ctx, header, util.GetOwnerID(header), objID, params)
+	if nil != resultErr {
+		blog.Errorf("ImportInstsFromTabular add inst info http request error:%s, rid:%s", resultErr.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrCommHTTPDoRequestFailed, defErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+
+	resultData.Merge(result.Data)
+	if !result.Result {
+		return resultData, result.Code, defErr.New(result.Code, result.ErrMsg)
+	}
+	return resultData, 0, nil
+}
+
+// ImportHostsFromTabular import the hosts or the associations of the hosts from the csv or ndjson file
+func (lgc *Logics) ImportHostsFromTabular(ctx context.Context, r io.Reader, format, sheet string, header http.Header, defLang lang.DefaultCCLanguageIf, meta metadata.Metadata, opt ImportOption) (resultData mapstr.MapStr, errCode int, err error) {
+	if TabularSheetAssociation == sheet {
+		if err := opt.checkAssociationOption(lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))); nil != err {
+			return nil, common.CCErrCommParamsIsInvalid, err
+		}
+		return lgc.ImportAssociationFromTabular(ctx, r, format, common.BKInnerObjIDHost, header, defLang)
+	}
+
+	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
+	resultData = mapstr.New()
+	hosts, errMsg, err := lgc.GetImportTabularInsts(r, format, common.BKInnerObjIDHost, header, defLang, meta)
+	if nil != err {
+		blog.Errorf("ImportHostsFromTabular get import hosts from %s error, error:%s, rid:%s", format, err.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, err.Error())
+	}
+	if 0 != len(errMsg) {
+		resultData.Set("err", errMsg)
+		return resultData, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, " file empty")
+	}
+	if 0 == len(hosts) {
+		return nil, common.CCErrWebFileContentEmpty, defErr.Errorf(common.CCErrWebFileContentEmpty, "")
+	}
+
+	params := mapstr.MapStr{}
+	params["host_info"] = hosts
+	params["bk_supplier_id"] = common.BKDefaultSupplierID
+	params["input_type"] = common.InputTypeExcel
+	opt.setParams(params)
+	result, resultErr := lgc.CoreAPI.ApiServer().AddHost(ctx, header, params)
+	if nil != resultErr {
+		blog.Errorf("ImportHostsFromTabular add host info http request error:%s, rid:%s", resultErr.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrCommHTTPDoRequestFailed, defErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+
+	resultData.Merge(result.Data)
+	if !result.Result {
+		return resultData, result.Code, defErr.New(result.Code, result.ErrMsg)
+	}
+	return resultData, 0, nil
+}
+
+// ImportAssociationFromTabular import the associations of the insts from the csv or ndjson file,
+// the rows which operate is empty are ignored
+func (lgc *Logics) ImportAssociationFromTabular(ctx context.Context, r io.Reader, format, objID string, header http.Header, defLang lang.DefaultCCLanguageIf) (resultData mapstr.MapStr, errCode int, err error) {
+	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
+	resultData = mapstr.New()
+	records, errMsg, err := readTabularRecords(r, format, defLang)
+	if nil != err {
+		blog.Errorf("ImportAssociationFromTabular read %s association from %s error, error:%s, rid:%s", objID, format, err.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, err.Error())
+	}
+	if 0 != len(errMsg) {
+		resultData.Set("err", errMsg)
+		return resultData, common.CCErrWebFileContentFail, defErr.Errorf(common.CCErrWebFileContentFail, " file empty")
+	}
+	asstInfoMap := getTabularAssociationData(records)
+	if 0 == len(asstInfoMap) {
+		return nil, common.CCErrWebFileContentEmpty, defErr.Errorf(common.CCErrWebFileContentEmpty, "")
+	}
+
+	asstInfoMapInput := &metadata.RequestImportAssociation{
+		AssociationInfoMap: asstInfoMap,
+	}
+	asstResult, asstResultErr := lgc.CoreAPI.ApiServer().ImportAssociation(ctx, header, objID, asstInfoMapInput)
+	if nil != asstResultErr {
+		blog.Errorf("ImportAssociationFromTabular http request import %s association error:%s, rid:%s", objID, asstResultErr.Error(), util.GetHTTPCCRequestID(header))
+		return nil, common.CCErrCommHTTPDoRequestFailed, defErr.Error(common.CCErrCommHTTPDoRequestFailed)
+	}
+	resultData.Set("asst_error", asstResult.Data.ErrMsgMap)
+	if !asstResult.Result {
+		return resultData, asstResult.Code, defErr.New(asstResult.Code, asstResult.ErrMsg)
+	}
+	return resultData, 0, nil
+}
+
+// AddDownTabularHttpHeader set the http header of the csv or ndjson file download
+func AddDownTabularHttpHeader(c *gin.Context, name, format string) {
+	c.Header("Content-Type", GetTabularContentType(format))
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Header("Cache-Control", "must-revalidate, post-check=0, pre-check=0")
+	c.Header("Pragma", "no-cache")
+	c.Header("Expires", "0")
+}
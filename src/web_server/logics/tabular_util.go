@@ -0,0 +1,477 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	lang "configcenter/src/common/language"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+const (
+	// FileFormatExcel the excel workbook
+	FileFormatExcel = "xlsx"
+	// FileFormatCSV the comma separated values, the first row is the field ids
+	FileFormatCSV = "csv"
+	// FileFormatNDJSON the newline delimited json, one json object each line
+	FileFormatNDJSON = "ndjson"
+
+	// TabularSheetInst the file contains the insts
+	TabularSheetInst = "inst"
+	// TabularSheetAssociation the file contains the associations of the insts
+	TabularSheetAssociation = "association"
+
+	// maxNDJSONLineSize the max size of a line in the ndjson file
+	maxNDJSONLineSize = 4 << 20
+)
+
+// association columns of the csv and ndjson files, the same as the json fields of metadata.ExcelAssocation
+const (
+	tabularAsstObjIDField = "bk_obj_asst_id"
+	tabularAsstOPField    = "operate"
+	tabularAsstSrcField   = "src_primary_key"
+	tabularAsstDstField   = "dst_primary_key"
+)
+
+var tabularAssociationFields = []string{tabularAsstObjIDField, tabularAsstOPField, tabularAsstSrcField, tabularAsstDstField}
+
+// GetFileFormat return the file format by the format parameter, or by the file name extension if the parameter is empty,
+// the second return value is false if the format is not supported
+func GetFileFormat(format, fileName string) (string, bool) {
+	if "" == format {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	}
+	switch strings.ToLower(format) {
+	case "", FileFormatExcel, "xls":
+		return FileFormatExcel, true
+	case FileFormatCSV:
+		return FileFormatCSV, true
+	case FileFormatNDJSON, "jsonl":
+		return FileFormatNDJSON, true
+	}
+	return format, false
+}
+
+// GetTabularContentType return the http content type of the file format
+func GetTabularContentType(format string) string {
+	switch format {
+	case FileFormatCSV:
+		return "text/csv; charset=utf-8"
+	case FileFormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/octet-stream"
+}
+
+// getTabularFields return the fields exported to the csv or ndjson file sorted by the column index,
+// the fields are filtered the same as the excel header
+func getTabularFields(fields map[string]Property, filter []string, defLang lang.DefaultCCLanguageIf) []Property {
+	ret := make([]Property, 0)
+	for id, field := range fields {
+		if _, skip := getPropertyTypeAliasName(field.PropertyType, defLang); skip || field.NotExport {
+			continue
+		}
+		if util.Contains(filter, field.ID) {
+			continue
+		}
+		// the extra fields have no property id
+		field.ID = id
+		ret = append(ret, field)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].ExcelColIndex == ret[j].ExcelColIndex {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].ExcelColIndex < ret[j].ExcelColIndex
+	})
+	return ret
+}
+
+// getExportValue convert the field value to the exported value, the enum id is translated to the enum name
+func getExportValue(property Property, val interface{}) interface{} {
+	if nil == val {
+		return nil
+	}
+	if common.FieldTypeEnum == property.PropertyType {
+		arrVal, _ := property.Option.([]interface{})
+		strEnumID, _ := val.(string)
+		return getEnumNameByID(strEnumID, arrVal)
+	}
+	return val
+}
+
+// getCSVValue convert the exported value to the csv cell
+func getCSVValue(val interface{}) string {
+	switch realVal := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return realVal
+	case bool:
+		if realVal {
+			return fieldTypeBoolTrue
+		}
+		return fieldTypeBoolFalse
+	case float64:
+		return strconv.FormatFloat(realVal, 'f', -1, 64)
+	case []interface{}, map[string]interface{}, mapstr.MapStr:
+		out, err := json.Marshal(realVal)
+		if nil != err {
+			return fmt.Sprintf("%v", realVal)
+		}
+		return string(out)
+	default:
+		return fmt.Sprintf("%v", realVal)
+	}
+}
+
+// getImportValue convert the imported value to the field value the same as the excel import,
+// the value which can not be converted is kept and validated by the server
+func getImportValue(field Property, val interface{}) interface{} {
+	strVal, isStr := val.(string)
+	switch field.PropertyType {
+	case common.FieldTypeBool:
+		if isStr {
+			if bl, err := strconv.ParseBool(strVal); nil == err {
+				return bl
+			}
+		}
+	case common.FieldTypeEnum:
+		if option, ok := field.Option.([]interface{}); ok && isStr {
+			return getEnumIDByName(strVal, option)
+		}
+	case common.FieldTypeInt:
+		if intVal, err := util.GetInt64ByInterface(val); nil == err {
+			return intVal
+		}
+		if floatVal, err := util.GetFloat64ByInterface(val); nil == err && floatVal == float64(int64(floatVal)) {
+			return int64(floatVal)
+		}
+	case common.FieldTypeFloat:
+		if floatVal, err := util.GetFloat64ByInterface(val); nil == err {
+			return floatVal
+		}
+	default:
+		if util.IsStrProperty(field.PropertyType) && !isStr {
+			return fmt.Sprintf("%v", val)
+		}
+	}
+	if num, ok := val.(json.Number); ok {
+		if intVal, err := num.Int64(); nil == err {
+			return intVal
+		}
+		if floatVal, err := num.Float64(); nil == err {
+			return floatVal
+		}
+		return num.String()
+	}
+	return val
+}
+
+// getPrimaryKeyVals return the unique field values of the inst which identify the inst in the associations
+func getPrimaryKeyVals(rowMap mapstr.MapStr, fields map[string]Property) []PropertyPrimaryVal {
+	primaryKeyArr := make([]PropertyPrimaryVal, 0)
+	for _, id := range sortedFieldIDs(fields) {
+		property := fields[id]
+		if !property.IsOnly || "" == property.ID {
+			continue
+		}
+		val, ok := rowMap[property.ID]
+		if !ok {
+			continue
+		}
+		strVal := getPrimaryKey(val)
+		if !property.NotExport {
+			strVal = getCSVValue(getExportValue(property, val))
+		}
+		primaryKeyArr = append(primaryKeyArr, PropertyPrimaryVal{
+			ID:     property.ID,
+			Name:   property.Name,
+			StrVal: strVal,
+		})
+	}
+	return primaryKeyArr
+}
+
+// tabularWriter write the rows to the csv or ndjson file
+type tabularWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(columns []string, row map[string]interface{}) error
+	Flush() error
+}
+
+func newTabularWriter(format string, w io.Writer) tabularWriter {
+	if FileFormatNDJSON == format {
+		return &ndjsonWriter{w: bufio.NewWriter(w)}
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(columns []string, row map[string]interface{}) error {
+	record := make([]string, len(columns))
+	for idx, column := range columns {
+		record[idx] = getCSVValue(row[column])
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) WriteHeader(columns []string) error {
+	return nil
+}
+
+func (n *ndjsonWriter) WriteRow(columns []string, row map[string]interface{}) error {
+	data := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		if val, ok := row[column]; ok && nil != val {
+			data[column] = val
+		}
+	}
+	out, err := json.Marshal(data)
+	if nil != err {
+		return err
+	}
+	if _, err := n.w.Write(out); nil != err {
+		return err
+	}
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// tabularRecord a row read from the csv or ndjson file, the values are keyed by the column name
+type tabularRecord struct {
+	Row    int
+	Values map[string]interface{}
+}
+
+// readTabularRecords read the rows from the csv or ndjson file, the row number starts from 1,
+// the header of the csv file is the first row. the rows which can not be parsed are reported in the error messages.
+func readTabularRecords(r io.Reader, format string, defLang lang.DefaultCCLanguageIf) ([]tabularRecord, []string, error) {
+	if FileFormatNDJSON == format {
+		return readNDJSONRecords(r, defLang)
+	}
+	return readCSVRecords(r, defLang)
+}
+
+func readCSVRecords(r io.Reader, defLang lang.DefaultCCLanguageIf) ([]tabularRecord, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = false
+
+	header, err := reader.Read()
+	if io.EOF == err {
+		return nil, nil, errors.New(defLang.Language("web_import_file_not_data"))
+	}
+	if nil != err {
+		return nil, nil, errors.New(defLang.Languagef("web_import_file_parse_error", 1, err.Error()))
+	}
+	// the utf-8 bom written by the spreadsheet applications
+	if 0 != len(header) {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	records := make([]tabularRecord, 0)
+	errMsg := make([]string, 0)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, nil, err
+			}
+			errMsg = append(errMsg, defLang.Languagef("web_import_file_parse_error", row, err.Error()))
+			continue
+		}
+		values := make(map[string]interface{})
+		for idx, cell := range record {
+			if idx >= len(header) || "" == strings.TrimSpace(header[idx]) || "" == cell {
+				continue
+			}
+			values[strings.TrimSpace(header[idx])] = cell
+		}
+		if 0 == len(values) {
+			continue
+		}
+		records = append(records, tabularRecord{Row: row, Values: values})
+	}
+	return records, errMsg, nil
+}
+
+func readNDJSONRecords(r io.Reader, defLang lang.DefaultCCLanguageIf) ([]tabularRecord, []string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLineSize)
+
+	records := make([]tabularRecord, 0)
+	errMsg := make([]string, 0)
+	for row := 1; scanner.Scan(); row++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if 1 == row {
+			line = bytes.TrimPrefix(line, []byte("\ufeff"))
+		}
+		if 0 == len(line) {
+			continue
+		}
+		values := make(map[string]interface{})
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); nil != err {
+			errMsg = append(errMsg, defLang.Languagef("web_import_file_parse_error", row, err.Error()))
+			continue
+		}
+		for key, val := range values {
+			if nil == val {
+				delete(values, key)
+			}
+		}
+		if 0 == len(values) {
+			continue
+		}
+		records = append(records, tabularRecord{Row: row, Values: values})
+	}
+	if err := scanner.Err(); nil != err {
+		return nil, nil, err
+	}
+	return records, errMsg, nil
+}
+
+// getTabularData convert the rows of the csv or ndjson file to the insts the same as the excel import,
+// the columns are the field ids or the field names, the columns which are not the fields are kept as they are.
+// return the insts keyed by the row number.
+func getTabularData(records []tabularRecord, fields map[string]Property, defFields common.KvMap, isCheckHeader bool, defLang lang.DefaultCCLanguageIf) (map[int]map[string]interface{}, error) {
+
+	nameIDMap := make(map[string]string)
+	for id, field := range fields {
+		if "" == field.ID {
+			continue
+		}
+		nameIDMap[field.Name] = id
+		nameIDMap[field.Name+defLang.Language("web_excel_header_required")] = id
+	}
+	for id := range fields {
+		nameIDMap[id] = id
+	}
+
+	// the same as the excel header check, most of the columns should be the fields
+	columns := make(map[string]bool)
+	for _, record := range records {
+		for column := range record.Values {
+			columns[column] = true
+		}
+	}
+	var errColumns []string
+	for column := range columns {
+		if _, ok := nameIDMap[column]; !ok {
+			errColumns = append(errColumns, column)
+		}
+	}
+	sort.Strings(errColumns)
+	if isCheckHeader && len(errColumns) > len(columns)/2 {
+		blog.Errorf(defLang.Languagef("web_import_field_not_found", strings.Join(errColumns, ",")))
+		return nil, errors.New(defLang.Languagef("web_import_field_not_found", errColumns[0]+"..."))
+	}
+
+	insts := make(map[int]map[string]interface{})
+	for _, record := range records {
+		inst := make(map[string]interface{})
+		for column, val := range record.Values {
+			if strVal, ok := val.(string); ok && "" == strVal {
+				continue
+			}
+			id, ok := nameIDMap[column]
+			if !ok {
+				inst[column] = getImportValue(Property{}, val)
+				continue
+			}
+			inst[id] = getImportValue(fields[id], val)
+		}
+		// the business topology of the host is only exported
+		delete(inst, hostExtFieldTopoID)
+		if 0 == len(inst) {
+			continue
+		}
+		for k, v := range defFields {
+			inst[k] = v
+		}
+		insts[record.Row] = inst
+	}
+	return insts, nil
+}
+
+// getTabularAssociationData convert the rows of the csv or ndjson file to the associations
+func getTabularAssociationData(records []tabularRecord) map[int]metadata.ExcelAssocation {
+	asstInfoArr := make(map[int]metadata.ExcelAssocation)
+	for _, record := range records {
+		op := fmt.Sprintf("%v", record.Values[tabularAsstOPField])
+		if nil == record.Values[tabularAsstOPField] || "" == op {
+			continue
+		}
+		asstInfoArr[record.Row] = metadata.ExcelAssocation{
+			ObjectAsstID: getCSVValue(record.Values[tabularAsstObjIDField]),
+			Operate:      getAssociationExcelOperateFlag(op),
+			SrcPrimary:   getCSVValue(record.Values[tabularAsstSrcField]),
+			DstPrimary:   getCSVValue(record.Values[tabularAsstDstField]),
+		}
+	}
+	return asstInfoArr
+}
+
+// sortedFieldIDs return the field ids sorted by the column index
+func sortedFieldIDs(fields map[string]Property) []string {
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		if fields[ids[i]].ExcelColIndex == fields[ids[j]].ExcelColIndex {
+			return ids[i] < ids[j]
+		}
+		return fields[ids[i]].ExcelColIndex < fields[ids[j]].ExcelColIndex
+	})
+	return ids
+}
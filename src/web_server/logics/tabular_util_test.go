@@ -0,0 +1,169 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"configcenter/src/common"
+	lang "configcenter/src/common/language"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testTabularLang = lang.NewFromCtx(map[string]lang.LanguageMap{
+	"en": {
+		"web_excel_header_required":   "(Required)",
+		"web_import_field_not_found":  "Import nonexistent fields, %s",
+		"web_import_file_not_data":    "No data in the file",
+		"web_import_file_parse_error": "row %d could not parse content, error:%s;",
+	},
+}).CreateDefaultCCLanguageIf("en")
+
+func testTabularFields() map[string]Property {
+	return map[string]Property{
+		"bk_inst_name": {ID: "bk_inst_name", Name: "Name", PropertyType: common.FieldTypeSingleChar, IsRequire: true, IsOnly: true, ExcelColIndex: 1},
+		"status": {ID: "status", Name: "Status", PropertyType: common.FieldTypeEnum, ExcelColIndex: 2, Option: []interface{}{
+			map[string]interface{}{"id": "1", "name": "online"},
+			map[string]interface{}{"id": "2", "name": "offline"},
+		}},
+		"cpu":      {ID: "cpu", Name: "CPU", PropertyType: common.FieldTypeInt, ExcelColIndex: 3},
+		"disabled": {ID: "disabled", Name: "Disabled", PropertyType: common.FieldTypeBool, ExcelColIndex: 4},
+	}
+}
+
+func TestGetFileFormat(t *testing.T) {
+	cases := []struct {
+		format, fileName, expect string
+		ok                       bool
+	}{
+		{"", "", FileFormatExcel, true},
+		{"", "host.xlsx", FileFormatExcel, true},
+		{"", "host.CSV", FileFormatCSV, true},
+		{"", "host.jsonl", FileFormatNDJSON, true},
+		{"ndjson", "host.xlsx", FileFormatNDJSON, true},
+		{"", "host.txt", "txt", false},
+	}
+	for _, item := range cases {
+		format, ok := GetFileFormat(item.format, item.fileName)
+		require.Equal(t, item.ok, ok, item.fileName)
+		require.Equal(t, item.expect, format, item.fileName)
+	}
+}
+
+func TestTabularRoundTrip(t *testing.T) {
+	fields := testTabularFields()
+	exportFields := getTabularFields(fields, []string{"disabled"}, testTabularLang)
+	columns := make([]string, 0)
+	for _, field := range exportFields {
+		columns = append(columns, field.ID)
+	}
+	require.Equal(t, []string{"bk_inst_name", "status", "cpu"}, columns)
+
+	for _, format := range []string{FileFormatCSV, FileFormatNDJSON} {
+		buf := &bytes.Buffer{}
+		writer := newTabularWriter(format, buf)
+		require.NoError(t, writer.WriteHeader(columns))
+		row := map[string]interface{}{}
+		for _, field := range exportFields {
+			row[field.ID] = getExportValue(field, mapstr.MapStr{"bk_inst_name": "a,b", "status": "2", "cpu": float64(8)}[field.ID])
+		}
+		require.NoError(t, writer.WriteRow(columns, row))
+		require.NoError(t, writer.Flush())
+
+		records, errMsg, err := readTabularRecords(buf, format, testTabularLang)
+		require.NoError(t, err)
+		require.Empty(t, errMsg)
+		insts, err := getTabularData(records, fields, common.KvMap{"import_from": common.HostAddMethodExcel}, true, testTabularLang)
+		require.NoError(t, err, format)
+		require.Len(t, insts, 1, format)
+		for _, inst := range insts {
+			require.Equal(t, "a,b", inst["bk_inst_name"], format)
+			require.Equal(t, "2", inst["status"], format)
+			require.Equal(t, int64(8), inst["cpu"], format)
+			require.Equal(t, common.HostAddMethodExcel, inst["import_from"], format)
+		}
+	}
+}
+
+func TestReadCSVRecords(t *testing.T) {
+	content := "\ufeffName(Required),Status,disabled,bk_host_id,cc_ext_field_topo\nhost1,online,true,3,biz\n,,,,\nhost2,offline,false,4,\n"
+	records, errMsg, err := readTabularRecords(strings.NewReader(content), FileFormatCSV, testTabularLang)
+	require.NoError(t, err)
+	require.Empty(t, errMsg)
+	require.Len(t, records, 2)
+	require.Equal(t, 2, records[0].Row)
+	require.Equal(t, 4, records[1].Row)
+
+	insts, err := getTabularData(records, testTabularFields(), nil, true, testTabularLang)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"bk_inst_name": "host1", "status": "1", "disabled": true, "bk_host_id": "3"}, insts[2])
+	require.Equal(t, map[string]interface{}{"bk_inst_name": "host2", "status": "2", "disabled": false, "bk_host_id": "4"}, insts[4])
+
+	_, _, err = readTabularRecords(strings.NewReader(""), FileFormatCSV, testTabularLang)
+	require.Error(t, err)
+
+	records, _, err = readTabularRecords(strings.NewReader("a,b,c\n1,2,3\n"), FileFormatCSV, testTabularLang)
+	require.NoError(t, err)
+	_, err = getTabularData(records, testTabularFields(), nil, true, testTabularLang)
+	require.Error(t, err)
+}
+
+func TestReadNDJSONRecords(t *testing.T) {
+	content := "{\"bk_inst_name\":\"host1\",\"cpu\":4}\n\n{bad json}\n{\"bk_inst_name\":\"host2\",\"cpu\":2.5}\n"
+	_, errMsg, err := readTabularRecords(strings.NewReader(content), FileFormatNDJSON, testTabularLang)
+	require.NoError(t, err)
+	require.Len(t, errMsg, 1)
+	require.Contains(t, errMsg[0], "row 3")
+
+	content = "{\"bk_inst_name\":\"host1\",\"cpu\":4}\n\n{\"bk_inst_name\":\"host2\",\"cpu\":2.5,\"bk_host_id\":7}\n"
+	records, errMsg, err := readTabularRecords(strings.NewReader(content), FileFormatNDJSON, testTabularLang)
+	require.NoError(t, err)
+	require.Empty(t, errMsg)
+	insts, err := getTabularData(records, testTabularFields(), nil, true, testTabularLang)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), insts[1]["cpu"])
+	require.Equal(t, 2.5, insts[3]["cpu"])
+	require.Equal(t, int64(7), insts[3]["bk_host_id"])
+}
+
+func TestGetTabularAssociationData(t *testing.T) {
+	content := "bk_obj_asst_id,operate,src_primary_key,dst_primary_key\nhost_connect_switch,add,IP:1.1.1.1,Name:sw1\nhost_connect_switch,,IP:1.1.1.2,Name:sw2\nhost_connect_switch,delete,IP:1.1.1.3,Name:sw3\n"
+	records, errMsg, err := readTabularRecords(strings.NewReader(content), FileFormatCSV, testTabularLang)
+	require.NoError(t, err)
+	require.Empty(t, errMsg)
+
+	asst := getTabularAssociationData(records)
+	require.Len(t, asst, 2)
+	require.Equal(t, metadata.ExcelAssocation{
+		ObjectAsstID: "host_connect_switch",
+		Operate:      getAssociationExcelOperateFlag("add"),
+		SrcPrimary:   "IP:1.1.1.1",
+		DstPrimary:   "Name:sw1",
+	}, asst[2])
+	require.Equal(t, getAssociationExcelOperateFlag("delete"), asst[4].Operate)
+}
+
+func TestGetPrimaryKeyVals(t *testing.T) {
+	fields := testTabularFields()
+	fields["status"] = Property{ID: "status", Name: "Status", PropertyType: common.FieldTypeEnum, IsOnly: true, ExcelColIndex: 2, Option: fields["status"].Option}
+	vals := getPrimaryKeyVals(mapstr.MapStr{"bk_inst_name": "host1", "status": "1"}, fields)
+	require.Equal(t, []PropertyPrimaryVal{
+		{ID: "bk_inst_name", Name: "Name", StrVal: "host1"},
+		{ID: "status", Name: "Status", StrVal: "online"},
+	}, vals)
+}
@@ -18,9 +18,11 @@ import (
 	"strings"
 
 	"configcenter/src/common"
+	"configcenter/src/common/apitoken"
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/http/httpclient"
+	"configcenter/src/common/util"
 	"configcenter/src/web_server/app/options"
 	webCommon "configcenter/src/web_server/common"
 	"configcenter/src/web_server/middleware/auth"
@@ -45,11 +47,28 @@ func ValidLogin(config options.Config, disc discovery.DiscoveryInterface) gin.Ha
 		path1 := pathArr[1]
 
 		switch path1 {
-		case "healthz", "metrics":
+		case "healthz", "metrics", "login":
 			c.Next()
 			return
 		}
 
+		// the personal api token can only be used with the v3 apis, no session is needed.
+		// the token is checked here before the request is proxied, and its scope is checked by the api server
+		if path1 == "api" && len(pathArr) > 2 && "v3" == pathArr[2] {
+			if token := apitoken.FromHeader(c.Request.Header); "" != token {
+				if _, err := apitoken.Authenticate(c.Request.Context(), Engine.CoreAPI, c.Request.Header, token); nil != err {
+					blog.Errorf("authenticate api token failed, err: %v, rid: %s", err, util.GetHTTPCCRequestID(c.Request.Header))
+					c.JSON(401, gin.H{
+						"status": "invalid api token",
+					})
+					c.Abort()
+					return
+				}
+				proxyAPIServer(c, disc)
+				return
+			}
+		}
+
 		if isAuthed(c, config) {
 			// valid resource access privilege
 			auth := auth.NewAuth()
@@ -73,18 +92,7 @@ func ValidLogin(config options.Config, disc discovery.DiscoveryInterface) gin.Ha
 			c.Request.Header.Add(common.BKHTTPSupplierID, supplierID)
 
 			if path1 == "api" {
-				servers, err := disc.ApiServer().GetServers()
-				if nil != err || 0 == len(servers) {
-					blog.Errorf("no api server can be used. err: %v", err)
-					c.JSON(503, gin.H{
-						"status": "no api server can be used.",
-					})
-					c.Abort()
-					return
-				}
-				url := servers[0]
-				httpclient.ProxyHttp(c, url)
-
+				proxyAPIServer(c, disc)
 			} else {
 				c.Next()
 			}
@@ -107,6 +115,21 @@ func ValidLogin(config options.Config, disc discovery.DiscoveryInterface) gin.Ha
 
 }
 
+// proxyAPIServer forward the request to the api server
+func proxyAPIServer(c *gin.Context, disc discovery.DiscoveryInterface) {
+	servers, err := disc.ApiServer().GetServers()
+	if nil != err || 0 == len(servers) {
+		blog.Errorf("no api server can be used. err: %v", err)
+		c.JSON(503, gin.H{
+			"status": "no api server can be used.",
+		})
+		c.Abort()
+		return
+	}
+	url := servers[0]
+	httpclient.ProxyHttp(c, url)
+}
+
 // IsAuthed check user is authed
 func isAuthed(c *gin.Context, config options.Config) bool {
 	if "1" == config.Session.Skip {
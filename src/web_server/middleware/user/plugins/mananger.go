@@ -41,3 +41,13 @@ func CurrentPlugin(c *gin.Context, version string) metadata.LoginUserPluginInerf
 
 	return nil
 }
+
+// HasPlugin check whether the login plugin of the version is registered
+func HasPlugin(version string) bool {
+	for _, plugin := range manager.LoginPluginInfo {
+		if plugin.Version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// the ber tags used by the ldap protocol, see rfc 4511
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+	tagSet         = 0x31
+
+	classApplication = 0x40
+	classContext     = 0x80
+	constructed      = 0x20
+
+	// maxPacketSize limit the size of the packet read from the server
+	maxPacketSize = 16 << 20
+)
+
+// packet is a ber encoded element, the children are decoded lazily by the callers
+type packet struct {
+	Tag   byte
+	Value []byte
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var buf []byte
+	for l := length; l > 0; l >>= 8 {
+		buf = append([]byte{byte(l)}, buf...)
+	}
+	return append([]byte{0x80 | byte(len(buf))}, buf...)
+}
+
+func encode(tag byte, value []byte) []byte {
+	ret := append([]byte{tag}, encodeLength(len(value))...)
+	return append(ret, value...)
+}
+
+func encodeConstructed(tag byte, children ...[]byte) []byte {
+	var value []byte
+	for _, child := range children {
+		value = append(value, child...)
+	}
+	return encode(tag, value)
+}
+
+func encodeInt(tag byte, val int64) []byte {
+	var buf []byte
+	for {
+		buf = append([]byte{byte(val)}, buf...)
+		if (val >= -0x80 && val < 0x80) || len(buf) >= 8 {
+			break
+		}
+		val >>= 8
+	}
+	return encode(tag, buf)
+}
+
+func encodeString(tag byte, val string) []byte {
+	return encode(tag, []byte(val))
+}
+
+func encodeBool(val bool) []byte {
+	if val {
+		return encode(tagBoolean, []byte{0xff})
+	}
+	return encode(tagBoolean, []byte{0x00})
+}
+
+// readPacket read one ber element from the reader
+func readPacket(r *bufio.Reader) (*packet, error) {
+	tag, err := r.ReadByte()
+	if nil != err {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if nil != err {
+		return nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		num := int(first & 0x7f)
+		if 0 == num || num > 4 {
+			return nil, fmt.Errorf("unsupported ber length of %d bytes", num)
+		}
+		length = 0
+		for i := 0; i < num; i++ {
+			b, err := r.ReadByte()
+			if nil != err {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPacketSize {
+		return nil, fmt.Errorf("ber packet size %d exceed the limit", length)
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); nil != err {
+		return nil, err
+	}
+	return &packet{Tag: tag, Value: value}, nil
+}
+
+// children decode the elements contained in the constructed packet
+func (p *packet) children() ([]*packet, error) {
+	var ret []*packet
+	data := p.Value
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errors.New("truncated ber packet")
+		}
+		tag, first := data[0], data[1]
+		data = data[2:]
+		length := int(first)
+		if first&0x80 != 0 {
+			num := int(first & 0x7f)
+			if 0 == num || num > 4 || len(data) < num {
+				return nil, errors.New("invalid ber length")
+			}
+			length = 0
+			for _, b := range data[:num] {
+				length = length<<8 | int(b)
+			}
+			data = data[num:]
+		}
+		if length > len(data) {
+			return nil, errors.New("truncated ber packet")
+		}
+		ret = append(ret, &packet{Tag: tag, Value: data[:length]})
+		data = data[length:]
+	}
+	return ret, nil
+}
+
+func (p *packet) int() int64 {
+	var val int64
+	for idx, b := range p.Value {
+		if 0 == idx && b&0x80 != 0 {
+			val = -1
+		}
+		val = val<<8 | int64(b)
+	}
+	return val
+}
@@ -0,0 +1,280 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// the application tags of the ldap protocol operations
+const (
+	opBindRequest     = 0
+	opBindResponse    = 1
+	opUnbindRequest   = 2
+	opSearchRequest   = 3
+	opSearchEntry     = 4
+	opSearchDone      = 5
+	opSearchReference = 19
+
+	scopeWholeSubtree = 2
+	derefNever        = 0
+
+	resultSuccess             = 0
+	resultSizeLimitExceeded   = 4
+	resultInvalidCredentials  = 49
+	protocolVersion           = 3
+	defaultLDAPPort           = "389"
+	defaultLDAPSPort          = "636"
+	defaultConnectionTimeout  = 10 * time.Second
+	defaultSearchTimeLimitSec = 30
+)
+
+// ErrInvalidCredentials is returned when the server reject the bind
+var ErrInvalidCredentials = errors.New("ldap invalid credentials")
+
+// resultError is the error returned by the ldap server
+type resultError struct {
+	Code    int64
+	Message string
+}
+
+func (e *resultError) Error() string {
+	return fmt.Sprintf("ldap result code %d, message: %s", e.Code, e.Message)
+}
+
+// entry is an entry returned by the search operation
+type entry struct {
+	DN    string
+	Attrs map[string][]string
+}
+
+// get return the first value of the attribute, the attribute name is case insensitive
+func (e *entry) get(attr string) string {
+	if vals := e.Attrs[strings.ToLower(attr)]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// conn is a minimal ldap v3 client which supports simple bind and search only
+type conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	msgID   int64
+	timeout time.Duration
+}
+
+// dial connect to the ldap server, the address should be like ldap://host:389 or ldaps://host:636
+func dial(address string, skipVerify bool, timeout time.Duration) (*conn, error) {
+	u, err := url.Parse(address)
+	if nil != err {
+		return nil, fmt.Errorf("invalid ldap url %s, err: %v", address, err)
+	}
+	if timeout <= 0 {
+		timeout = defaultConnectionTimeout
+	}
+
+	host := u.Host
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if "" == u.Port() {
+			host = net.JoinHostPort(u.Hostname(), defaultLDAPPort)
+		}
+		netConn, err = net.DialTimeout("tcp", host, timeout)
+	case "ldaps":
+		if "" == u.Port() {
+			host = net.JoinHostPort(u.Hostname(), defaultLDAPSPort)
+		}
+		dialer := &net.Dialer{Timeout: timeout}
+		netConn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{
+			ServerName:         u.Hostname(),
+			InsecureSkipVerify: skipVerify,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported ldap url scheme %s", u.Scheme)
+	}
+	if nil != err {
+		return nil, fmt.Errorf("connect ldap server %s failed, err: %v", host, err)
+	}
+	return &conn{conn: netConn, reader: bufio.NewReader(netConn), timeout: timeout}, nil
+}
+
+// close send the unbind request and close the connection
+func (c *conn) close() {
+	c.send(encode(classApplication|opUnbindRequest, nil))
+	c.conn.Close()
+}
+
+func (c *conn) send(op []byte) (int64, error) {
+	c.msgID++
+	msg := encodeConstructed(tagSequence, encodeInt(tagInteger, c.msgID), op)
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	_, err := c.conn.Write(msg)
+	return c.msgID, err
+}
+
+// receive read the next response of the message and return the protocol operation
+func (c *conn) receive(msgID int64) (*packet, error) {
+	for {
+		msg, err := readPacket(c.reader)
+		if nil != err {
+			return nil, fmt.Errorf("read ldap response failed, err: %v", err)
+		}
+		children, err := msg.children()
+		if nil != err {
+			return nil, err
+		}
+		if len(children) < 2 {
+			return nil, errors.New("invalid ldap message")
+		}
+		if children[0].int() != msgID {
+			continue
+		}
+		return children[1], nil
+	}
+}
+
+// parseResult parse the LDAPResult of the response
+func parseResult(op *packet) error {
+	children, err := op.children()
+	if nil != err {
+		return err
+	}
+	if len(children) < 3 {
+		return errors.New("invalid ldap result")
+	}
+	code := children[0].int()
+	if resultSuccess == code {
+		return nil
+	}
+	return &resultError{Code: code, Message: string(children[2].Value)}
+}
+
+// bind authenticate with the dn and password, an empty password is rejected
+// because the server treat it as an unauthenticated bind which always succeed
+func (c *conn) bind(dn, password string) error {
+	if "" == password {
+		return ErrInvalidCredentials
+	}
+	msgID, err := c.send(encodeConstructed(classApplication|constructed|opBindRequest,
+		encodeInt(tagInteger, protocolVersion),
+		encodeString(tagOctetString, dn),
+		encodeString(classContext|0, password)))
+	if nil != err {
+		return err
+	}
+	op, err := c.receive(msgID)
+	if nil != err {
+		return err
+	}
+	if classApplication|constructed|opBindResponse != op.Tag {
+		return fmt.Errorf("unexpected ldap bind response tag %x", op.Tag)
+	}
+	err = parseResult(op)
+	if re, ok := err.(*resultError); ok && resultInvalidCredentials == re.Code {
+		return ErrInvalidCredentials
+	}
+	return err
+}
+
+// search search the whole subtree of the base dn, the result is truncated
+// without error when the size limit is exceeded
+func (c *conn) search(baseDN, filter string, attrs []string, sizeLimit int64) ([]*entry, error) {
+	compiled, err := compileFilter(filter)
+	if nil != err {
+		return nil, err
+	}
+	attrList := make([][]byte, 0, len(attrs))
+	for _, attr := range attrs {
+		attrList = append(attrList, encodeString(tagOctetString, attr))
+	}
+	msgID, err := c.send(encodeConstructed(classApplication|constructed|opSearchRequest,
+		encodeString(tagOctetString, baseDN),
+		encodeInt(tagEnumerated, scopeWholeSubtree),
+		encodeInt(tagEnumerated, derefNever),
+		encodeInt(tagInteger, sizeLimit),
+		encodeInt(tagInteger, defaultSearchTimeLimitSec),
+		encodeBool(false),
+		compiled,
+		encodeConstructed(tagSequence, attrList...)))
+	if nil != err {
+		return nil, err
+	}
+
+	var entries []*entry
+	for {
+		op, err := c.receive(msgID)
+		if nil != err {
+			return nil, err
+		}
+		switch op.Tag {
+		case classApplication | constructed | opSearchEntry:
+			e, err := parseEntry(op)
+			if nil != err {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case classApplication | constructed | opSearchReference:
+			// referrals are not followed
+		case classApplication | constructed | opSearchDone:
+			err := parseResult(op)
+			if re, ok := err.(*resultError); ok && resultSizeLimitExceeded == re.Code {
+				return entries, nil
+			}
+			return entries, err
+		default:
+			return nil, fmt.Errorf("unexpected ldap search response tag %x", op.Tag)
+		}
+	}
+}
+
+func parseEntry(op *packet) (*entry, error) {
+	children, err := op.children()
+	if nil != err {
+		return nil, err
+	}
+	if len(children) < 2 {
+		return nil, errors.New("invalid ldap search entry")
+	}
+	e := &entry{DN: string(children[0].Value), Attrs: make(map[string][]string)}
+	attrs, err := children[1].children()
+	if nil != err {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		parts, err := attr.children()
+		if nil != err {
+			return nil, err
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		vals, err := parts[1].children()
+		if nil != err {
+			return nil, err
+		}
+		name := strings.ToLower(string(parts[0].Value))
+		for _, val := range vals {
+			e.Attrs[name] = append(e.Attrs[name], string(val.Value))
+		}
+	}
+	return e, nil
+}
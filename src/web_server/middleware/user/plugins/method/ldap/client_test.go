@@ -0,0 +1,124 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"bufio"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	require.Equal(t, `alice`, EscapeFilter("alice"))
+	require.Equal(t, `\2a\29\28uid=\5c`, EscapeFilter(`*)(uid=\`))
+}
+
+func TestCompileFilter(t *testing.T) {
+	ret, err := compileFilter("(uid=alice)")
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0xa3, 0x0c, 0x04, 0x03}, append([]byte("uid"), append([]byte{0x04, 0x05}, []byte("alice")...)...)...), ret)
+
+	ret, err = compileFilter("objectClass=*")
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0x87, 0x0b}, []byte("objectClass")...), ret)
+
+	ret, err = compileFilter(`(&(objectClass=person)(|(uid=al*ce)(cn>=a))(!(uid=\2a)))`)
+	require.NoError(t, err)
+	require.Equal(t, byte(0xa0), ret[0])
+
+	for _, filter := range []string{"(uid=alice", "(&)", "(=alice)", "(uid=\\zz)", "(uid=a))", "(!(uid=a)"} {
+		_, err := compileFilter(filter)
+		require.Error(t, err, filter)
+	}
+}
+
+func TestEncodeInt(t *testing.T) {
+	for _, val := range []int64{0, 1, 127, 128, 255, 256, 1000, -1, -129} {
+		p := &packet{Value: encodeInt(tagInteger, val)[2:]}
+		require.Equal(t, val, p.int())
+	}
+}
+
+// serve is a fake ldap server which accept the password "secret" only and return one entry
+func serve(t *testing.T, listener net.Listener) {
+	netConn, err := listener.Accept()
+	if nil != err {
+		return
+	}
+	defer netConn.Close()
+	reader := bufio.NewReader(netConn)
+	for {
+		msg, err := readPacket(reader)
+		if nil != err {
+			return
+		}
+		children, err := msg.children()
+		require.NoError(t, err)
+		msgID, op := children[0].int(), children[1]
+		reply := func(ops ...[]byte) {
+			for _, op := range ops {
+				netConn.Write(encodeConstructed(tagSequence, encodeInt(tagInteger, msgID), op))
+			}
+		}
+		result := func(tag byte, code int64) []byte {
+			return encodeConstructed(classApplication|constructed|tag,
+				encodeInt(tagEnumerated, code), encodeString(tagOctetString, ""), encodeString(tagOctetString, ""))
+		}
+
+		switch op.Tag {
+		case classApplication | constructed | opBindRequest:
+			fields, err := op.children()
+			require.NoError(t, err)
+			if "secret" == string(fields[2].Value) {
+				reply(result(opBindResponse, resultSuccess))
+			} else {
+				reply(result(opBindResponse, resultInvalidCredentials))
+			}
+		case classApplication | constructed | opSearchRequest:
+			attrs := encodeConstructed(tagSequence,
+				encodeConstructed(tagSequence, encodeString(tagOctetString, "uid"),
+					encodeConstructed(tagSet, encodeString(tagOctetString, "alice"))),
+				encodeConstructed(tagSequence, encodeString(tagOctetString, "memberOf"),
+					encodeConstructed(tagSet, encodeString(tagOctetString, "cn=ops"), encodeString(tagOctetString, "cn=dev"))))
+			reply(encodeConstructed(classApplication|constructed|opSearchEntry,
+				encodeString(tagOctetString, "uid=alice,dc=example,dc=com"), attrs),
+				result(opSearchDone, resultSizeLimitExceeded))
+		case classApplication | opUnbindRequest:
+			return
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go serve(t, listener)
+
+	c, err := dial("ldap://"+listener.Addr().String(), false, 0)
+	require.NoError(t, err)
+	defer c.close()
+
+	require.Equal(t, ErrInvalidCredentials, c.bind("cn=admin", ""))
+	require.Equal(t, ErrInvalidCredentials, c.bind("cn=admin", "wrong"))
+	require.NoError(t, c.bind("cn=admin", "secret"))
+
+	entries, err := c.search("dc=example,dc=com", "(uid=alice)", []string{"uid", "memberOf"}, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "uid=alice,dc=example,dc=com", entries[0].DN)
+	require.Equal(t, "alice", entries[0].get("UID"))
+	require.Equal(t, []string{"cn=ops", "cn=dev"}, entries[0].Attrs["memberof"])
+}
@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// the context tags of the search filter choices, see rfc 4511 section 4.5.1
+const (
+	filterAnd             = 0
+	filterOr              = 1
+	filterNot             = 2
+	filterEqualityMatch   = 3
+	filterSubstrings      = 4
+	filterGreaterOrEqual  = 5
+	filterLessOrEqual     = 6
+	filterPresent         = 7
+	filterApproxMatch     = 8
+	substringInitial      = 0
+	substringAny          = 1
+	substringFinal        = 2
+	maxFilterNestingLevel = 32
+)
+
+// EscapeFilter escape the special characters of the value used in the search filter, see rfc 4515
+func EscapeFilter(val string) string {
+	var buf strings.Builder
+	for i := 0; i < len(val); i++ {
+		switch c := val[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&buf, "\\%02x", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// compileFilter compile the string representation of the search filter to ber
+func compileFilter(filter string) ([]byte, error) {
+	filter = strings.TrimSpace(filter)
+	if !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	ret, pos, err := compileItem(filter, 0, 0)
+	if nil != err {
+		return nil, err
+	}
+	if pos != len(filter) {
+		return nil, fmt.Errorf("invalid filter %s, unexpected characters at %d", filter, pos)
+	}
+	return ret, nil
+}
+
+// compileItem compile the filter item begin at pos, return the position after the item
+func compileItem(filter string, pos, level int) ([]byte, int, error) {
+	if level > maxFilterNestingLevel {
+		return nil, pos, fmt.Errorf("invalid filter %s, nested too deep", filter)
+	}
+	if pos >= len(filter) || '(' != filter[pos] {
+		return nil, pos, fmt.Errorf("invalid filter %s, expect ( at %d", filter, pos)
+	}
+	pos++
+	if pos >= len(filter) {
+		return nil, pos, fmt.Errorf("invalid filter %s, unexpected end", filter)
+	}
+
+	switch filter[pos] {
+	case '&', '|':
+		tag := byte(classContext | constructed | filterAnd)
+		if '|' == filter[pos] {
+			tag = classContext | constructed | filterOr
+		}
+		pos++
+		var children [][]byte
+		for pos < len(filter) && '(' == filter[pos] {
+			child, next, err := compileItem(filter, pos, level+1)
+			if nil != err {
+				return nil, next, err
+			}
+			children = append(children, child)
+			pos = next
+		}
+		if 0 == len(children) {
+			return nil, pos, fmt.Errorf("invalid filter %s, empty set at %d", filter, pos)
+		}
+		return closeItem(filter, encodeConstructed(tag, children...), pos)
+	case '!':
+		child, next, err := compileItem(filter, pos+1, level+1)
+		if nil != err {
+			return nil, next, err
+		}
+		return closeItem(filter, encodeConstructed(classContext|constructed|filterNot, child), next)
+	}
+
+	end := strings.IndexByte(filter[pos:], ')')
+	if end < 0 {
+		return nil, pos, fmt.Errorf("invalid filter %s, expect ) after %d", filter, pos)
+	}
+	item := filter[pos : pos+end]
+	ret, err := compileSimple(item)
+	if nil != err {
+		return nil, pos, fmt.Errorf("invalid filter %s, %v", filter, err)
+	}
+	return ret, pos + end + 1, nil
+}
+
+func closeItem(filter string, ret []byte, pos int) ([]byte, int, error) {
+	if pos >= len(filter) || ')' != filter[pos] {
+		return nil, pos, fmt.Errorf("invalid filter %s, expect ) at %d", filter, pos)
+	}
+	return ret, pos + 1, nil
+}
+
+// compileSimple compile the item like attr=value, attr>=value, attr<=value, attr~=value and attr=*
+func compileSimple(item string) ([]byte, error) {
+	idx := strings.IndexByte(item, '=')
+	if idx <= 0 {
+		return nil, fmt.Errorf("item %s has no attribute", item)
+	}
+	attr, value := item[:idx], item[idx+1:]
+	tag := byte(filterEqualityMatch)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag, attr = filterGreaterOrEqual, attr[:len(attr)-1]
+	case '<':
+		tag, attr = filterLessOrEqual, attr[:len(attr)-1]
+	case '~':
+		tag, attr = filterApproxMatch, attr[:len(attr)-1]
+	}
+	if "" == attr {
+		return nil, fmt.Errorf("item %s has no attribute", item)
+	}
+
+	if filterEqualityMatch == tag && "*" == value {
+		return encodeString(classContext|filterPresent, attr), nil
+	}
+	if filterEqualityMatch == tag && strings.Contains(value, "*") {
+		parts := strings.Split(value, "*")
+		var subs [][]byte
+		for i, part := range parts {
+			if "" == part {
+				continue
+			}
+			raw, err := unescapeFilter(part)
+			if nil != err {
+				return nil, err
+			}
+			subTag := byte(substringAny)
+			if 0 == i {
+				subTag = substringInitial
+			} else if len(parts)-1 == i {
+				subTag = substringFinal
+			}
+			subs = append(subs, encodeString(classContext|subTag, raw))
+		}
+		return encodeConstructed(classContext|constructed|filterSubstrings,
+			encodeString(tagOctetString, attr),
+			encodeConstructed(tagSequence, subs...)), nil
+	}
+
+	raw, err := unescapeFilter(value)
+	if nil != err {
+		return nil, err
+	}
+	return encodeConstructed(classContext|constructed|tag,
+		encodeString(tagOctetString, attr),
+		encodeString(tagOctetString, raw)), nil
+}
+
+// unescapeFilter decode the \XX escaped characters in the filter value
+func unescapeFilter(val string) (string, error) {
+	if !strings.Contains(val, "\\") {
+		return val, nil
+	}
+	var buf []byte
+	for i := 0; i < len(val); i++ {
+		if '\\' != val[i] {
+			buf = append(buf, val[i])
+			continue
+		}
+		if i+3 > len(val) {
+			return "", fmt.Errorf("invalid escape in value %s", val)
+		}
+		b, err := hex.DecodeString(val[i+1 : i+3])
+		if nil != err {
+			return "", fmt.Errorf("invalid escape in value %s", val)
+		}
+		buf = append(buf, b...)
+		i += 2
+	}
+	return string(buf), nil
+}
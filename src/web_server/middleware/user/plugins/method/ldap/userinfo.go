@@ -0,0 +1,246 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	webCommon "configcenter/src/web_server/common"
+	"configcenter/src/web_server/middleware/user/plugins/manager"
+	"configcenter/src/web_server/middleware/user/plugins/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/holmeswang/contrib/sessions"
+)
+
+func init() {
+	plugin := &metadata.LoginPluginInfo{
+		Name:       "ldap login system",
+		Version:    common.BKLDAPLoginUserPluginVersion,
+		HandleFunc: &user{},
+	}
+	manager.RegisterPlugin(plugin)
+}
+
+const (
+	loginPath = "/login"
+
+	// the form fields posted by the login page
+	formUserName = "username"
+	formPassword = "password"
+
+	defaultUserListLimit = 1000
+)
+
+// options the ldap configuration of the web server
+type options struct {
+	URL            string
+	SkipVerify     bool
+	BindDN         string
+	BindPassword   string
+	BaseDN         string
+	UserFilter     string
+	UserListFilter string
+	UserListLimit  int64
+	UserNameAttr   string
+	DisplayAttr    string
+	EmailAttr      string
+	PhoneAttr      string
+	GroupAttr      string
+}
+
+func getOption(config map[string]string, key, defaultValue string) string {
+	if val := strings.TrimSpace(config[key]); "" != val {
+		return val
+	}
+	return defaultValue
+}
+
+func parseOptions(config map[string]string) (*options, error) {
+	opt := &options{
+		URL:            getOption(config, "ldap.url", ""),
+		SkipVerify:     "true" == config["ldap.tls_skip_verify"],
+		BindDN:         getOption(config, "ldap.bind_dn", ""),
+		BindPassword:   config["ldap.bind_password"],
+		BaseDN:         getOption(config, "ldap.base_dn", ""),
+		UserFilter:     getOption(config, "ldap.user_filter", "(uid=%s)"),
+		UserListFilter: getOption(config, "ldap.user_list_filter", "(objectClass=person)"),
+		UserListLimit:  defaultUserListLimit,
+		UserNameAttr:   getOption(config, "ldap.username_attr", "uid"),
+		DisplayAttr:    getOption(config, "ldap.display_attr", "cn"),
+		EmailAttr:      getOption(config, "ldap.email_attr", "mail"),
+		PhoneAttr:      getOption(config, "ldap.phone_attr", "telephoneNumber"),
+		GroupAttr:      getOption(config, "ldap.group_attr", "memberOf"),
+	}
+	if "" == opt.URL {
+		return nil, fmt.Errorf("config ldap.url not found")
+	}
+	if "" == opt.BaseDN {
+		return nil, fmt.Errorf("config ldap.base_dn not found")
+	}
+	if !strings.Contains(opt.UserFilter, "%s") {
+		return nil, fmt.Errorf("config ldap.user_filter should contain %%s for the user name")
+	}
+	if limit := config["ldap.user_list_limit"]; "" != limit {
+		val, err := strconv.ParseInt(limit, 10, 64)
+		if nil != err || val <= 0 {
+			return nil, fmt.Errorf("config ldap.user_list_limit should be positive integer")
+		}
+		opt.UserListLimit = val
+	}
+	return opt, nil
+}
+
+// connect connect to the server and bind with the service account if it is configured
+func (opt *options) connect() (*conn, error) {
+	c, err := dial(opt.URL, opt.SkipVerify, 0)
+	if nil != err {
+		return nil, err
+	}
+	if "" != opt.BindDN {
+		if err := c.bind(opt.BindDN, opt.BindPassword); nil != err {
+			c.close()
+			return nil, fmt.Errorf("bind ldap service account failed, err: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (opt *options) attributes() []string {
+	return []string{opt.UserNameAttr, opt.DisplayAttr, opt.EmailAttr, opt.PhoneAttr, opt.GroupAttr}
+}
+
+type user struct {
+}
+
+// LoginUser search the user entry with the posted user name and bind with its dn and the posted password
+func (m *user) LoginUser(c *gin.Context, config map[string]string, isMultiOwner bool) (user *metadata.LoginUserInfo, loginSucc bool) {
+	if http.MethodPost != c.Request.Method {
+		return nil, false
+	}
+	userName := strings.TrimSpace(c.PostForm(formUserName))
+	password := c.PostForm(formPassword)
+	if "" == userName || "" == password {
+		return nil, false
+	}
+
+	opt, err := parseOptions(config)
+	if nil != err {
+		blog.Errorf("ldap login failed, err: %v", err)
+		return nil, false
+	}
+	conn, err := opt.connect()
+	if nil != err {
+		blog.Errorf("ldap login failed, err: %v", err)
+		return nil, false
+	}
+	defer conn.close()
+
+	filter := fmt.Sprintf(opt.UserFilter, EscapeFilter(userName))
+	entries, err := conn.search(opt.BaseDN, filter, opt.attributes(), 2)
+	if nil != err {
+		blog.Errorf("ldap login search user %s failed, err: %v", userName, err)
+		return nil, false
+	}
+	if 1 != len(entries) {
+		blog.Errorf("ldap login failed, found %d entries of user %s", len(entries), userName)
+		return nil, false
+	}
+	entry := entries[0]
+	if err := conn.bind(entry.DN, password); nil != err {
+		blog.Errorf("ldap login bind user %s failed, err: %v", entry.DN, err)
+		return nil, false
+	}
+
+	bkToken, err := util.NewToken()
+	if nil != err {
+		blog.Errorf("ldap login failed, create login token err: %v", err)
+		return nil, false
+	}
+	util.SetLoginToken(c, bkToken)
+
+	if name := entry.get(opt.UserNameAttr); "" != name {
+		userName = name
+	}
+	ownerID := config["ldap.supplier_account"]
+	if "" == ownerID {
+		ownerID = common.BKDefaultOwnerID
+	}
+	roles := util.ParseRoleMapping(config["ldap.role_mapping"], config["ldap.default_role"])
+	user = &metadata.LoginUserInfo{
+		UserName: userName,
+		ChName:   entry.get(opt.DisplayAttr),
+		Phone:    entry.get(opt.PhoneAttr),
+		Email:    entry.get(opt.EmailAttr),
+		Role:     roles.Role(entry.Attrs[strings.ToLower(opt.GroupAttr)]),
+		BkToken:  bkToken,
+		OnwerUin: ownerID,
+		IsOwner:  false,
+		Language: config["session.defaultlanguage"],
+	}
+	blog.V(3).Infof("ldap login success, user: %s, role: %s", user.UserName, user.Role)
+	return user, true
+}
+
+// GetUserList get user list from the ldap server with the service account
+func (m *user) GetUserList(c *gin.Context, config map[string]string) ([]*metadata.LoginSystemUserInfo, error) {
+	session := sessions.Default(c)
+	skiplogin, _ := session.Get(webCommon.IsSkipLogin).(string)
+	if "1" == skiplogin {
+		return []*metadata.LoginSystemUserInfo{{CnName: "admin", EnName: "admin"}}, nil
+	}
+
+	opt, err := parseOptions(config)
+	if nil != err {
+		return nil, err
+	}
+	conn, err := opt.connect()
+	if nil != err {
+		blog.Errorf("get user list error：%v", err)
+		return nil, err
+	}
+	defer conn.close()
+
+	entries, err := conn.search(opt.BaseDN, opt.UserListFilter, []string{opt.UserNameAttr, opt.DisplayAttr}, opt.UserListLimit)
+	if nil != err {
+		blog.Errorf("get user list error：%v", err)
+		return nil, fmt.Errorf("get user list from ldap failed, err: %v", err)
+	}
+	userList := make([]*metadata.LoginSystemUserInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.get(opt.UserNameAttr)
+		if "" == name {
+			continue
+		}
+		chName := entry.get(opt.DisplayAttr)
+		if "" == chName {
+			chName = name
+		}
+		userList = append(userList, &metadata.LoginSystemUserInfo{CnName: chName, EnName: name})
+	}
+	return userList, nil
+}
+
+// GetLoginUrl return the login page of the web server which post the user name and password back
+func (m *user) GetLoginUrl(c *gin.Context, config map[string]string, input *metadata.LogoutRequestParams) string {
+	params := url.Values{}
+	params.Set("c_url", util.RedirectPath(c.Request.URL.RequestURI()))
+	return util.SiteURL(config, input.HTTPScheme) + loginPath + "?" + params.Encode()
+}
@@ -0,0 +1,270 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval the min interval of fetching the key set again for an unknown key id,
+// so that the forged tokens can not make the provider be requested too often
+const jwksRefreshInterval = time.Minute
+
+// jsonWebKey the public key published by the provider, only the rsa and ec keys are supported
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type keySet struct {
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+var (
+	keySetLock  sync.Mutex
+	keySetCache = make(map[string]*keySet)
+)
+
+// keyFunc return the public keys which may sign the token, all the keys are returned when the kid is empty
+type keyFunc func(kid string) ([]crypto.PublicKey, error)
+
+// keys return the keyFunc with the key set of the provider, the key set is fetched again
+// when the key id is unknown, as the provider may have rotated its keys
+func (p *provider) keys(config map[string]string) keyFunc {
+	return func(kid string) ([]crypto.PublicKey, error) {
+		if "" == p.JwksURI {
+			return nil, errors.New("jwks_uri of the oidc provider not found")
+		}
+
+		keySetLock.Lock()
+		set, ok := keySetCache[p.JwksURI]
+		keySetLock.Unlock()
+		if !ok || (!set.contains(kid) && time.Since(set.fetched) > jwksRefreshInterval) {
+			fetched, err := fetchKeySet(config, p.JwksURI)
+			if nil != err {
+				return nil, err
+			}
+			keySetLock.Lock()
+			keySetCache[p.JwksURI] = fetched
+			keySetLock.Unlock()
+			set = fetched
+		}
+
+		if "" != kid {
+			if key, ok := set.keys[kid]; ok {
+				return []crypto.PublicKey{key}, nil
+			}
+			return nil, fmt.Errorf("id_token key %s not found in the oidc provider key set", kid)
+		}
+		keys := make([]crypto.PublicKey, 0, len(set.keys))
+		for _, key := range set.keys {
+			keys = append(keys, key)
+		}
+		return keys, nil
+	}
+}
+
+func (s *keySet) contains(kid string) bool {
+	if "" == kid {
+		return len(s.keys) > 0
+	}
+	_, ok := s.keys[kid]
+	return ok
+}
+
+func fetchKeySet(config map[string]string, jwksURI string) (*keySet, error) {
+	status, reply, err := newHTTPClient(config).GETEx(jwksURI, nil, nil)
+	if nil != err {
+		return nil, fmt.Errorf("get oidc provider key set failed, err: %v", err)
+	}
+	if http.StatusOK != status {
+		return nil, fmt.Errorf("get oidc provider key set failed, status: %d, reply: %s", status, reply)
+	}
+	return parseKeySet(reply)
+}
+
+// parseKeySet decode the jwks document, the keys not used for signature or not supported are skipped
+func parseKeySet(data []byte) (*keySet, error) {
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &jwks); nil != err {
+		return nil, fmt.Errorf("decode oidc provider key set failed, err: %v", err)
+	}
+
+	set := &keySet{keys: make(map[string]crypto.PublicKey), fetched: time.Now()}
+	for idx, jwk := range jwks.Keys {
+		if "" != jwk.Use && "sig" != jwk.Use {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if nil != err {
+			continue
+		}
+		kid := jwk.Kid
+		if "" == kid {
+			kid = fmt.Sprintf("#%d", idx)
+		}
+		set.keys[kid] = key
+	}
+	return set, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if nil != err {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if nil != err {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported ec curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if nil != err {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if nil != err {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(val string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(val, "="))
+	if nil != err {
+		return nil, err
+	}
+	if 0 == len(data) {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// verifySignature verify the signature of the jwt with the keys of the provider,
+// only the asymmetric algorithms are accepted so that the token can not be forged with the public key
+func verifySignature(token string, keys keyFunc) error {
+	parts := strings.Split(token, ".")
+	if 3 != len(parts) {
+		return errors.New("id_token is not a jwt")
+	}
+	headerData, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[0], "="))
+	if nil != err {
+		return fmt.Errorf("decode id_token header failed, err: %v", err)
+	}
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := json.Unmarshal(headerData, &header); nil != err {
+		return fmt.Errorf("decode id_token header failed, err: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if nil != err {
+		return fmt.Errorf("decode id_token signature failed, err: %v", err)
+	}
+
+	var hash crypto.Hash
+	switch header.Alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("id_token algorithm %s is not supported", header.Alg)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	digest := hasher.Sum(nil)
+
+	candidates, err := keys(header.Kid)
+	if nil != err {
+		return err
+	}
+	for _, key := range candidates {
+		if verifyDigest(header.Alg, key, hash, digest, sig) {
+			return nil
+		}
+	}
+	return errors.New("id_token signature is invalid")
+}
+
+func verifyDigest(alg string, key crypto.PublicKey, hash crypto.Hash, digest, sig []byte) bool {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return nil == rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+		case "PS":
+			return nil == rsa.VerifyPSS(pub, hash, digest, sig, nil)
+		}
+	case *ecdsa.PublicKey:
+		if "ES" != alg[:2] {
+			return false
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
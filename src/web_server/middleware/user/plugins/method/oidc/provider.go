@@ -0,0 +1,280 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/httpclient"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	httpTimeout   = 30 * time.Second
+)
+
+// provider the endpoints of the openid connect provider
+type provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResult struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+var (
+	providerLock  sync.Mutex
+	providerCache = make(map[string]*provider)
+)
+
+func newHTTPClient(config map[string]string) *httpclient.HttpClient {
+	cli := httpclient.NewHttpClient()
+	cli.SetTimeOut(httpTimeout)
+	if "true" == config["oidc.tls_skip_verify"] {
+		cli.SetTlsNoVerity()
+	}
+	return cli
+}
+
+// getProvider return the endpoints of the provider, the endpoints configured explicitly
+// take precedence over the ones found by the discovery document of the issuer
+func getProvider(config map[string]string) (*provider, error) {
+	issuer := strings.TrimRight(config["oidc.issuer"], "/")
+	if "" == issuer {
+		return nil, errors.New("config oidc.issuer not found")
+	}
+
+	providerLock.Lock()
+	p, ok := providerCache[issuer]
+	providerLock.Unlock()
+	if !ok {
+		p = &provider{Issuer: issuer}
+		if "" == config["oidc.authorization_endpoint"] || "" == config["oidc.token_endpoint"] || "" == config["oidc.jwks_uri"] {
+			status, reply, err := newHTTPClient(config).GETEx(issuer+discoveryPath, nil, nil)
+			if nil != err {
+				return nil, fmt.Errorf("get openid configuration failed, err: %v", err)
+			}
+			if http.StatusOK != status {
+				return nil, fmt.Errorf("get openid configuration failed, status: %d, reply: %s", status, reply)
+			}
+			if err := json.Unmarshal(reply, p); nil != err {
+				return nil, fmt.Errorf("decode openid configuration failed, err: %v", err)
+			}
+			if strings.TrimRight(p.Issuer, "/") != issuer {
+				return nil, fmt.Errorf("openid configuration issuer %s not match %s", p.Issuer, issuer)
+			}
+		}
+		providerLock.Lock()
+		providerCache[issuer] = p
+		providerLock.Unlock()
+	}
+
+	ret := *p
+	if endpoint := config["oidc.authorization_endpoint"]; "" != endpoint {
+		ret.AuthorizationEndpoint = endpoint
+	}
+	if endpoint := config["oidc.token_endpoint"]; "" != endpoint {
+		ret.TokenEndpoint = endpoint
+	}
+	if endpoint := config["oidc.userinfo_endpoint"]; "" != endpoint {
+		ret.UserinfoEndpoint = endpoint
+	}
+	if endpoint := config["oidc.jwks_uri"]; "" != endpoint {
+		ret.JwksURI = endpoint
+	}
+	return &ret, nil
+}
+
+// newCodeVerifier create a pkce code verifier of 43 characters
+func newCodeVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); nil != err {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// codeChallenge return the S256 pkce code challenge of the code verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authCodeURL return the url of the authorization endpoint to redirect the user to
+func (p *provider) authCodeURL(config map[string]string, redirectURL, state, nonce, verifier string) string {
+	scope := config["oidc.scope"]
+	if "" == scope {
+		scope = "openid profile email"
+	}
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", config["oidc.client_id"])
+	params.Set("redirect_uri", redirectURL)
+	params.Set("scope", scope)
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge(verifier))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// exchange exchange the authorization code for the tokens
+func (p *provider) exchange(config map[string]string, redirectURL, code, verifier string) (*tokenResult, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURL)
+	form.Set("client_id", config["oidc.client_id"])
+	form.Set("code_verifier", verifier)
+	if secret := config["oidc.client_secret"]; "" != secret {
+		form.Set("client_secret", secret)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Accept", "application/json")
+	status, reply, err := newHTTPClient(config).POSTEx(p.TokenEndpoint, header, []byte(form.Encode()))
+	if nil != err {
+		return nil, fmt.Errorf("request token endpoint failed, err: %v", err)
+	}
+	result := new(tokenResult)
+	if err := json.Unmarshal(reply, result); nil != err {
+		return nil, fmt.Errorf("decode token reply failed, status: %d, err: %v", status, err)
+	}
+	if http.StatusOK != status || "" != result.Error {
+		return nil, fmt.Errorf("exchange token failed, status: %d, error: %s, description: %s", status, result.Error, result.ErrorDescription)
+	}
+	if "" == result.IDToken {
+		return nil, errors.New("token reply has no id_token")
+	}
+	return result, nil
+}
+
+// userinfo get the claims from the userinfo endpoint with the access token
+func (p *provider) userinfo(config map[string]string, accessToken string) (map[string]interface{}, error) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+accessToken)
+	header.Set("Accept", "application/json")
+	status, reply, err := newHTTPClient(config).GETEx(p.UserinfoEndpoint, header, nil)
+	if nil != err {
+		return nil, fmt.Errorf("request userinfo endpoint failed, err: %v", err)
+	}
+	if http.StatusOK != status {
+		return nil, fmt.Errorf("request userinfo endpoint failed, status: %d, reply: %s", status, reply)
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(reply, &claims); nil != err {
+		return nil, fmt.Errorf("decode userinfo reply failed, err: %v", err)
+	}
+	return claims, nil
+}
+
+// parseIDToken verify the signature of the id token with the key set of the provider, then decode
+// the claims and validate the issuer, audience, expiry and nonce
+func parseIDToken(idToken, issuer, clientID, nonce string, now time.Time, keys keyFunc) (map[string]interface{}, error) {
+	if err := verifySignature(idToken, keys); nil != err {
+		return nil, err
+	}
+	parts := strings.Split(idToken, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if nil != err {
+		return nil, fmt.Errorf("decode id_token payload failed, err: %v", err)
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); nil != err {
+		return nil, fmt.Errorf("decode id_token claims failed, err: %v", err)
+	}
+
+	if iss, _ := claims["iss"].(string); strings.TrimRight(iss, "/") != strings.TrimRight(issuer, "/") {
+		return nil, fmt.Errorf("id_token issuer %s not match %s", iss, issuer)
+	}
+	if !containsString(claimStrings(claims["aud"]), clientID) {
+		return nil, fmt.Errorf("id_token audience %v not contains %s", claims["aud"], clientID)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Unix() >= int64(exp) {
+		return nil, errors.New("id_token is expired")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("id_token nonce not match")
+	}
+	return claims, nil
+}
+
+// claimStrings convert the claim which may be a string or an array of strings to string slice
+func claimStrings(claim interface{}) []string {
+	switch val := claim.(type) {
+	case string:
+		if "" == val {
+			return nil
+		}
+		return []string{val}
+	case []interface{}:
+		ret := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				ret = append(ret, str)
+			}
+		}
+		return ret
+	default:
+		if nil != claim {
+			blog.V(5).Infof("ignore oidc claim of type %T", claim)
+		}
+		return nil
+	}
+}
+
+// claimString return the first non empty string claim of the keys
+func claimString(claims map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if val, ok := claims[key].(string); ok && "" != val {
+			return val
+		}
+	}
+	return ""
+}
+
+func containsString(arr []string, str string) bool {
+	for _, item := range arr {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
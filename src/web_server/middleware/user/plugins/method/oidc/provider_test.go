@@ -0,0 +1,147 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+func testKeys(kid string) ([]crypto.PublicKey, error) {
+	return []crypto.PublicKey{&testKey.PublicKey}, nil
+}
+
+func buildIDToken(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"key1"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, testKey, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestCodeChallenge(t *testing.T) {
+	require.Equal(t, "qmvdtmSjAcaRYCTnmxZU_RpugbxKNkb2qDg-37CWjwE", codeChallenge("dBjftJeZ4CVP-mJ92K2mToFBf3tZ45LhN2EyKw1M_cY"))
+
+	verifier, err := newCodeVerifier()
+	require.NoError(t, err)
+	require.Len(t, verifier, 43)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	p := &provider{AuthorizationEndpoint: "https://idp.example.com/auth?kc_idp_hint=corp"}
+	config := map[string]string{"oidc.client_id": "cmdb"}
+	authURL := p.authCodeURL(config, "https://cmdb.example.com/login/callback", "state", "nonce", "verifier")
+	require.True(t, strings.HasPrefix(authURL, "https://idp.example.com/auth?kc_idp_hint=corp&"))
+	require.Contains(t, authURL, "code_challenge_method=S256")
+	require.Contains(t, authURL, "code_challenge="+codeChallenge("verifier"))
+	require.Contains(t, authURL, "scope=openid+profile+email")
+}
+
+func TestParseIDToken(t *testing.T) {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":                "https://idp.example.com/",
+		"aud":                []string{"other", "cmdb"},
+		"exp":                now.Add(time.Minute).Unix(),
+		"nonce":              "nonce",
+		"preferred_username": "alice",
+		"groups":             []string{"cmdb-admin"},
+	}
+	ret, err := parseIDToken(buildIDToken(t, claims), "https://idp.example.com", "cmdb", "nonce", now, testKeys)
+	require.NoError(t, err)
+	require.Equal(t, "alice", claimString(ret, "preferred_username", "sub"))
+	require.Equal(t, []string{"cmdb-admin"}, claimStrings(ret["groups"]))
+
+	_, err = parseIDToken(buildIDToken(t, claims), "https://idp.example.com", "cmdb", "other", now, testKeys)
+	require.Error(t, err)
+	_, err = parseIDToken(buildIDToken(t, claims), "https://idp.example.com", "web", "nonce", now, testKeys)
+	require.Error(t, err)
+	_, err = parseIDToken(buildIDToken(t, claims), "https://evil.example.com", "cmdb", "nonce", now, testKeys)
+	require.Error(t, err)
+	_, err = parseIDToken(buildIDToken(t, claims), "https://idp.example.com", "cmdb", "nonce", now.Add(time.Hour), testKeys)
+	require.Error(t, err)
+	_, err = parseIDToken("not-a-jwt", "https://idp.example.com", "cmdb", "nonce", now, testKeys)
+	require.Error(t, err)
+}
+
+func TestParseIDTokenSignature(t *testing.T) {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   "https://idp.example.com",
+		"aud":   "cmdb",
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": "nonce",
+	}
+	token := buildIDToken(t, claims)
+	parts := strings.Split(token, ".")
+
+	forged, err := json.Marshal(map[string]interface{}{"iss": "https://idp.example.com", "aud": "cmdb",
+		"exp": now.Add(time.Minute).Unix(), "nonce": "nonce", "sub": "admin"})
+	require.NoError(t, err)
+	_, err = parseIDToken(parts[0]+"."+base64.RawURLEncoding.EncodeToString(forged)+"."+parts[2],
+		"https://idp.example.com", "cmdb", "nonce", now, testKeys)
+	require.Error(t, err)
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	_, err = parseIDToken(none+"."+parts[1]+".", "https://idp.example.com", "cmdb", "nonce", now, testKeys)
+	require.Error(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKeys := func(kid string) ([]crypto.PublicKey, error) {
+		return []crypto.PublicKey{&otherKey.PublicKey}, nil
+	}
+	_, err = parseIDToken(token, "https://idp.example.com", "cmdb", "nonce", now, otherKeys)
+	require.Error(t, err)
+}
+
+func TestParseKeySet(t *testing.T) {
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{
+			"kty": "RSA",
+			"kid": "key1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(testKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(testKey.E)).Bytes()),
+		},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+	}})
+	require.NoError(t, err)
+	set, err := parseKeySet(jwks)
+	require.NoError(t, err)
+	require.Len(t, set.keys, 1)
+
+	keys := func(kid string) ([]crypto.PublicKey, error) {
+		return []crypto.PublicKey{set.keys[kid]}, nil
+	}
+	now := time.Now()
+	claims := map[string]interface{}{"iss": "https://idp.example.com", "aud": "cmdb", "exp": now.Add(time.Minute).Unix(), "nonce": "nonce"}
+	_, err = parseIDToken(buildIDToken(t, claims), "https://idp.example.com", "cmdb", "nonce", now, keys)
+	require.NoError(t, err)
+}
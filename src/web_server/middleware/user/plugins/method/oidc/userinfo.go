@@ -0,0 +1,250 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	webCommon "configcenter/src/web_server/common"
+	"configcenter/src/web_server/middleware/user/plugins/manager"
+	"configcenter/src/web_server/middleware/user/plugins/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/holmeswang/contrib/sessions"
+)
+
+func init() {
+	plugin := &metadata.LoginPluginInfo{
+		Name:       "openid connect login system",
+		Version:    common.BKOIDCLoginUserPluginVersion,
+		HandleFunc: &user{},
+	}
+	manager.RegisterPlugin(plugin)
+}
+
+const (
+	callbackPath = "/login/callback"
+
+	sessionStateKey       = "oidc_state"
+	sessionNonceKey       = "oidc_nonce"
+	sessionVerifierKey    = "oidc_code_verifier"
+	sessionRedirectURLKey = "oidc_redirect_url"
+	sessionAccessTokenKey = "oidc_access_token"
+)
+
+type user struct {
+}
+
+// LoginUser finish the authorization code flow when the provider redirect the user back with the code
+func (m *user) LoginUser(c *gin.Context, config map[string]string, isMultiOwner bool) (user *metadata.LoginUserInfo, loginSucc bool) {
+	if errCode := c.Query("error"); "" != errCode {
+		blog.Errorf("oidc provider return error: %s, description: %s", errCode, c.Query("error_description"))
+		return nil, false
+	}
+	code := c.Query("code")
+	state := c.Query("state")
+	if "" == code || "" == state {
+		return nil, false
+	}
+
+	session := sessions.Default(c)
+	sessionState, _ := session.Get(sessionStateKey).(string)
+	nonce, _ := session.Get(sessionNonceKey).(string)
+	verifier, _ := session.Get(sessionVerifierKey).(string)
+	redirectURL, _ := session.Get(sessionRedirectURLKey).(string)
+	session.Delete(sessionStateKey)
+	session.Delete(sessionNonceKey)
+	session.Delete(sessionVerifierKey)
+	session.Delete(sessionRedirectURLKey)
+	if "" == sessionState || sessionState != state {
+		blog.Errorf("oidc login state not match, maybe the login is expired or forged")
+		return nil, false
+	}
+
+	p, err := getProvider(config)
+	if nil != err {
+		blog.Errorf("get oidc provider failed, err: %v", err)
+		return nil, false
+	}
+	token, err := p.exchange(config, redirectURL, code, verifier)
+	if nil != err {
+		blog.Errorf("oidc login failed, err: %v", err)
+		return nil, false
+	}
+	claims, err := parseIDToken(token.IDToken, p.Issuer, config["oidc.client_id"], nonce, time.Now(), p.keys(config))
+	if nil != err {
+		blog.Errorf("oidc login failed, err: %v", err)
+		return nil, false
+	}
+	if "" != p.UserinfoEndpoint && "" != token.AccessToken {
+		info, err := p.userinfo(config, token.AccessToken)
+		if nil != err {
+			blog.Warnf("get oidc userinfo failed, use the id_token claims only, err: %v", err)
+		}
+		for key, val := range info {
+			if _, ok := claims[key]; !ok {
+				claims[key] = val
+			}
+		}
+	}
+
+	usernameClaim := config["oidc.username_claim"]
+	if "" == usernameClaim {
+		usernameClaim = "preferred_username"
+	}
+	groupsClaim := config["oidc.groups_claim"]
+	if "" == groupsClaim {
+		groupsClaim = "groups"
+	}
+	userName := claimString(claims, usernameClaim, "sub")
+	if "" == userName {
+		blog.Errorf("oidc login failed, the claims has no user name")
+		return nil, false
+	}
+
+	bkToken, err := util.NewToken()
+	if nil != err {
+		blog.Errorf("oidc login failed, create login token err: %v", err)
+		return nil, false
+	}
+	util.SetLoginToken(c, bkToken)
+	session.Set(sessionAccessTokenKey, token.AccessToken)
+
+	ownerID := config["oidc.supplier_account"]
+	if "" == ownerID {
+		ownerID = common.BKDefaultOwnerID
+	}
+	roles := util.ParseRoleMapping(config["oidc.role_mapping"], config["oidc.default_role"])
+	user = &metadata.LoginUserInfo{
+		UserName: userName,
+		ChName:   claimString(claims, "name", usernameClaim, "sub"),
+		Phone:    claimString(claims, "phone_number"),
+		Email:    claimString(claims, "email"),
+		Role:     roles.Role(claimStrings(claims[groupsClaim])),
+		BkToken:  bkToken,
+		OnwerUin: ownerID,
+		IsOwner:  false,
+		Language: config["session.defaultlanguage"],
+	}
+	blog.V(3).Infof("oidc login success, user: %s, role: %s", user.UserName, user.Role)
+	return user, true
+}
+
+type scimListResult struct {
+	Resources []map[string]interface{} `json:"Resources"`
+}
+
+// GetUserList get user list from the scim compatible user list endpoint,
+// only the current user is returned when the endpoint is not configured
+func (m *user) GetUserList(c *gin.Context, config map[string]string) ([]*metadata.LoginSystemUserInfo, error) {
+	session := sessions.Default(c)
+	skiplogin, _ := session.Get(webCommon.IsSkipLogin).(string)
+	if "1" == skiplogin {
+		return []*metadata.LoginSystemUserInfo{{CnName: "admin", EnName: "admin"}}, nil
+	}
+
+	listURL := config["oidc.user_list_url"]
+	if "" == listURL {
+		userName, _ := session.Get(common.WEBSessionUinKey).(string)
+		chName, _ := session.Get(common.WEBSessionChineseNameKey).(string)
+		return []*metadata.LoginSystemUserInfo{{CnName: chName, EnName: userName}}, nil
+	}
+
+	accessToken := config["oidc.user_list_token"]
+	if "" == accessToken {
+		accessToken, _ = session.Get(sessionAccessTokenKey).(string)
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+accessToken)
+	header.Set("Accept", "application/json")
+	status, reply, err := newHTTPClient(config).GETEx(listURL, header, nil)
+	if nil != err {
+		blog.Errorf("get user list error：%v", err)
+		return nil, fmt.Errorf("http do error:%s", err.Error())
+	}
+	if http.StatusOK != status {
+		blog.Errorf("get user list url: %s, status: %d, return：%s", listURL, status, reply)
+		return nil, fmt.Errorf("get user list reply status %d", status)
+	}
+
+	var users []map[string]interface{}
+	if err := json.Unmarshal(reply, &users); nil != err {
+		result := scimListResult{}
+		if err := json.Unmarshal(reply, &result); nil != err {
+			blog.Errorf("get user list json error: %v, rawData:%s", err, reply)
+			return nil, fmt.Errorf("get user list reply error")
+		}
+		users = result.Resources
+	}
+
+	nameField := config["oidc.user_list_username_field"]
+	if "" == nameField {
+		nameField = "userName"
+	}
+	displayField := config["oidc.user_list_display_field"]
+	if "" == displayField {
+		displayField = "displayName"
+	}
+	userList := make([]*metadata.LoginSystemUserInfo, 0, len(users))
+	for _, item := range users {
+		name := claimString(item, nameField)
+		if "" == name {
+			continue
+		}
+		userList = append(userList, &metadata.LoginSystemUserInfo{
+			CnName: claimString(item, displayField, nameField),
+			EnName: name,
+		})
+	}
+	return userList, nil
+}
+
+// GetLoginUrl start the authorization code flow with pkce, the state, nonce and
+// code verifier are kept in the session until the provider redirect the user back
+func (m *user) GetLoginUrl(c *gin.Context, config map[string]string, input *metadata.LogoutRequestParams) string {
+	p, err := getProvider(config)
+	if nil != err {
+		blog.Errorf("get oidc provider failed, err: %v", err)
+		return ""
+	}
+
+	redirectURL := config["oidc.redirect_url"]
+	if "" == redirectURL {
+		redirectURL = util.SiteURL(config, input.HTTPScheme) + callbackPath
+	}
+	state, stateErr := util.NewToken()
+	nonce, nonceErr := util.NewToken()
+	verifier, verifierErr := newCodeVerifier()
+	if nil != stateErr || nil != nonceErr || nil != verifierErr {
+		blog.Errorf("create oidc login state failed, err: %v, %v, %v", stateErr, nonceErr, verifierErr)
+		return ""
+	}
+
+	session := sessions.Default(c)
+	session.Set(sessionStateKey, state)
+	session.Set(sessionNonceKey, nonce)
+	session.Set(sessionVerifierKey, verifier)
+	session.Set(sessionRedirectURLKey, redirectURL)
+	session.Set(common.WEBSessionLoginRedirectKey, util.RedirectPath(c.Request.URL.RequestURI()))
+	if err := session.Save(); nil != err {
+		blog.Errorf("save oidc login state failed, err: %v", err)
+		return ""
+	}
+	return p.authCodeURL(config, redirectURL, state, nonce, verifier)
+}
@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"configcenter/src/common"
+)
+
+// RoleMapping map the groups of the login system to the roles of cmdb
+type RoleMapping struct {
+	DefaultRole string
+	groups      []string
+	roles       map[string]string
+}
+
+// ParseRoleMapping parse the role mapping like "cmdb-admin:1;cmdb-user:0",
+// the group matched first in the configure order decides the role of the user
+func ParseRoleMapping(mapping, defaultRole string) *RoleMapping {
+	rm := &RoleMapping{DefaultRole: defaultRole, roles: make(map[string]string)}
+	for _, item := range strings.Split(mapping, ";") {
+		idx := strings.LastIndex(item, ":")
+		if idx <= 0 {
+			continue
+		}
+		group := strings.TrimSpace(item[:idx])
+		if _, ok := rm.roles[group]; ok {
+			continue
+		}
+		rm.groups = append(rm.groups, group)
+		rm.roles[group] = strings.TrimSpace(item[idx+1:])
+	}
+	return rm
+}
+
+// Role return the cmdb role of the user who belongs to the groups
+func (rm *RoleMapping) Role(groups []string) string {
+	in := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		in[group] = true
+	}
+	for _, group := range rm.groups {
+		if in[group] {
+			return rm.roles[group]
+		}
+	}
+	return rm.DefaultRole
+}
+
+// NewToken create a random token used as login token or oauth state
+func NewToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); nil != err {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SetLoginToken set the login token cookie which is checked with the session in every request
+func SetLoginToken(c *gin.Context, token string) {
+	c.SetCookie(common.HTTPCookieBKToken, token, 0, "/", "", false, true)
+}
+
+// SiteURL return the site url with the http scheme, without the ending slash
+func SiteURL(config map[string]string, httpScheme string) string {
+	var siteURL string
+	if common.LogoutHTTPSchemeHTTPS == httpScheme {
+		siteURL = config["site.https_domain_url"]
+	}
+	if "" == siteURL {
+		siteURL = config["site.domain_url"]
+	}
+	return strings.TrimRight(siteURL, "/")
+}
+
+// RedirectPath return the relative path to redirect to after login,
+// absolute url is not allowed to avoid open redirect
+func RedirectPath(target string) string {
+	u, err := url.Parse(target)
+	if nil != err || "" != u.Scheme || "" != u.Host || !strings.HasPrefix(u.Path, "/") || strings.HasPrefix(u.Path, "//") {
+		return "/"
+	}
+	return u.RequestURI()
+}
@@ -0,0 +1,34 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleMapping(t *testing.T) {
+	rm := ParseRoleMapping("cmdb-admin:1; cmdb-user:0;cn=ops,dc=example,dc=com:1;invalid", "0")
+	require.Equal(t, "1", rm.Role([]string{"cmdb-user", "cmdb-admin"}))
+	require.Equal(t, "0", rm.Role([]string{"cmdb-user"}))
+	require.Equal(t, "1", rm.Role([]string{"cn=ops,dc=example,dc=com"}))
+	require.Equal(t, "0", rm.Role(nil))
+}
+
+func TestRedirectPath(t *testing.T) {
+	require.Equal(t, "/index?a=1", RedirectPath("/index?a=1"))
+	require.Equal(t, "/", RedirectPath("http://evil.com/index"))
+	require.Equal(t, "/", RedirectPath("//evil.com/index"))
+	require.Equal(t, "/", RedirectPath("index"))
+}
@@ -0,0 +1,373 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/httpclient"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	webCommon "configcenter/src/web_server/common"
+	"configcenter/src/web_server/logics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rentiansheng/xlsx"
+	"github.com/rs/xid"
+)
+
+// downloadWriter write the exported file to the response page by page, the download headers are set
+// on the first write, so that the error occurred before that can still be returned as json
+type downloadWriter struct {
+	c       *gin.Context
+	name    string
+	format  string
+	written bool
+}
+
+func newDownloadWriter(c *gin.Context, name, format string) *downloadWriter {
+	return &downloadWriter{c: c, name: name, format: format}
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		logics.AddDownTabularHttpHeader(w.c, w.name, w.format)
+		w.c.Status(http.StatusOK)
+		w.written = true
+	}
+	n, err := w.c.Writer.Write(p)
+	w.c.Writer.Flush()
+	return n, err
+}
+
+// Done set the download headers if nothing has been written
+func (w *downloadWriter) Done() {
+	if !w.written {
+		w.Write(nil)
+	}
+}
+
+// Fail return the error message if nothing has been written, otherwise close the connection,
+// so that the client knows the downloaded file is incomplete
+func (w *downloadWriter) Fail(msg string) {
+	if !w.written {
+		w.c.String(http.StatusOK, msg)
+		return
+	}
+	conn, _, err := w.c.Writer.Hijack()
+	if nil != err {
+		blog.Errorf("close the connection of the failed export error:%s", err.Error())
+		return
+	}
+	conn.Close()
+}
+
+// exportPageSize the count of the insts searched each time when exporting, the export.page_size configure
+func (s *Service) exportPageSize() int {
+	pageSize, err := strconv.Atoi(s.Config.ConfigMap["export.page_size"])
+	if nil != err || pageSize <= 0 {
+		return logics.DefaultExportPageSize
+	}
+	return pageSize
+}
+
+// getExportFileName get the download file name of the exported file
+func getExportFileName(name, sheet, format string) string {
+	if logics.FileFormatExcel != format && logics.TabularSheetAssociation == sheet {
+		return fmt.Sprintf("%s_%s.%s", name, logics.TabularSheetAssociation, format)
+	}
+	return fmt.Sprintf("%s.%s", name, format)
+}
+
+// getExportJobDir the directory of the files exported by the background jobs
+func getExportJobDir() string {
+	return fmt.Sprintf("%s/export/job", webCommon.ResourcePath)
+}
+
+func getExportJobFilePath(job *logics.ExportJob) string {
+	return fmt.Sprintf("%s/%s.%s", getExportJobDir(), job.ID, job.Format)
+}
+
+// exportJobProxiedHeader mark the download request proxied to the web server which runs the job
+const exportJobProxiedHeader = "Cc_Export_Job_Proxied"
+
+// CleanExpiredExportJobFiles remove the exported files of the expired jobs periodically until the context is done
+func CleanExpiredExportJobFiles(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		cleanExpiredExportJobFiles()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanExpiredExportJobFiles remove the exported files of the expired jobs
+func cleanExpiredExportJobFiles() {
+	files, err := ioutil.ReadDir(getExportJobDir())
+	if nil != err {
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() || time.Since(file.ModTime()) < logics.ExportJobExpire {
+			continue
+		}
+		if err := os.Remove(filepath.Join(getExportJobDir(), file.Name())); nil != err {
+			blog.Warnf("remove expired export file %s error:%s", file.Name(), err.Error())
+		}
+	}
+}
+
+// startExportJob create the export job and run it in the background, the insts are searched page by page
+func (s *Service) startExportJob(c *gin.Context, objID, fileName string, fields map[string]logics.Property, format, sheet string, meta metadata.Metadata, forEach func(header http.Header, handler logics.ExportPageHandler) error) {
+	pheader := c.Request.Header
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+
+	if err := os.MkdirAll(getExportJobDir(), os.ModeDir|os.ModePerm); nil != err {
+		blog.Errorf("create export job directory error:%s, rid:%s", err.Error(), util.GetHTTPCCRequestID(pheader))
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebFileSaveFail, defErr.Errorf(common.CCErrWebFileSaveFail, err.Error()).Error(), nil))
+		return
+	}
+
+	job := &logics.ExportJob{
+		ID:         xid.New().String(),
+		User:       util.GetUser(pheader),
+		ObjectID:   objID,
+		Format:     format,
+		Sheet:      sheet,
+		Status:     logics.ExportJobStatusRunning,
+		FileName:   fileName,
+		CreateTime: time.Now(),
+	}
+	if srvInfo := common.GetServerInfo(); nil != srvInfo {
+		job.Node = srvInfo.Address()
+	}
+	if err := logics.SaveExportJob(s.CacheCli, job); nil != err {
+		blog.Errorf("save export job error:%s, rid:%s", err.Error(), util.GetHTTPCCRequestID(pheader))
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommDBInsertFailed, defErr.Error(common.CCErrCommDBInsertFailed).Error(), nil))
+		return
+	}
+
+	// the request header is used after the request finished
+	header := make(http.Header, len(pheader))
+	for key, val := range pheader {
+		header[key] = append([]string(nil), val...)
+	}
+	filePath := getExportJobFilePath(job)
+	logics.RunExportJob(s.CacheCli, job, header, func() (int, error) {
+		if logics.FileFormatExcel == format {
+			return s.exportExcelFile(filePath, objID, fields, header, meta, forEach)
+		}
+		return s.exportTabularFile(filePath, objID, fields, format, sheet, header, meta, forEach)
+	})
+
+	c.String(http.StatusOK, getReturnStr(0, "", job))
+}
+
+// exportTabularFile write the csv or ndjson file to the disk page by page
+func (s *Service) exportTabularFile(filePath, objID string, fields map[string]logics.Property, format, sheet string, header http.Header, meta metadata.Metadata, forEach func(header http.Header, handler logics.ExportPageHandler) error) (int, error) {
+	file, err := os.Create(filePath)
+	if nil != err {
+		return 0, err
+	}
+	defer file.Close()
+
+	exporter, err := s.Logics.NewTabularExporter(context.Background(), objID, fields, nil, format, sheet, file, header, meta)
+	if nil != err {
+		return 0, err
+	}
+	if err := forEach(header, exporter.WritePage); nil != err {
+		return exporter.Rows(), err
+	}
+	return exporter.Rows(), exporter.Flush()
+}
+
+// exportExcelFile search the insts page by page and write them to the workbook, the searched pages are
+// not kept, the workbook is saved to the disk after all the insts are written
+func (s *Service) exportExcelFile(filePath, objID string, fields map[string]logics.Property, header http.Header, meta metadata.Metadata, forEach func(header http.Header, handler logics.ExportPageHandler) error) (int, error) {
+	file := xlsx.NewFile()
+	exporter, err := s.Logics.NewExcelExporter(context.Background(), objID, fields, nil, file, header, meta)
+	if nil != err {
+		return 0, err
+	}
+	if err := forEach(header, exporter.WritePage); nil != err {
+		return exporter.Rows(), err
+	}
+	if err := exporter.Finish(); nil != err {
+		return exporter.Rows(), err
+	}
+	logics.ProductExcelCommentSheet(file, s.Language.CreateDefaultCCLanguageIf(util.GetLanguage(header)))
+	if err := file.Save(filePath); nil != err {
+		return exporter.Rows(), err
+	}
+	return exporter.Rows(), nil
+}
+
+// ExportHostJob export the hosts in the background
+func (s *Service) ExportHostJob(c *gin.Context) {
+	logics.SetProxyHeader(c)
+	pheader := c.Request.Header
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+
+	appIDStr := c.PostForm("bk_biz_id")
+	hostIDStr := c.PostForm("bk_host_id")
+	customFieldsStr := c.PostForm(common.ExportCustomFields)
+	sheet := c.PostForm("export_sheet")
+	format, ok := logics.GetFileFormat(c.PostForm("export_format"), "")
+	if !ok {
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil))
+		return
+	}
+
+	objID := common.BKInnerObjIDHost
+	filterFields := logics.GetFilterFields(objID)
+	customFields := logics.GetCustomFields(filterFields, customFieldsStr)
+	fields, err := s.Logics.GetObjFieldIDs(objID, filterFields, customFields, pheader, metadata.Metadata{})
+	if nil != err {
+		blog.Errorf("ExportHostJob get %s field error:%s, rid:%s", objID, err.Error(), util.GetHTTPCCRequestID(pheader))
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommExcelTemplateFailed, defErr.Errorf(common.CCErrCommExcelTemplateFailed, objID).Error(), nil))
+		return
+	}
+
+	s.startExportJob(c, objID, getExportFileName(objID, sheet, format), fields, format, sheet, metadata.Metadata{},
+		func(header http.Header, handler logics.ExportPageHandler) error {
+			return s.Logics.ForEachHostPage(context.Background(), appIDStr, hostIDStr, header, s.exportPageSize(), handler)
+		})
+}
+
+// ExportInstJob export the insts in the background
+func (s *Service) ExportInstJob(c *gin.Context) {
+	logics.SetProxyHeader(c)
+	pheader := c.Request.Header
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+
+	ownerID := c.Param(common.BKOwnerIDField)
+	objID := c.Param(common.BKObjIDField)
+	instIDStr := c.PostForm(common.BKInstIDField)
+	customFieldsStr := c.PostForm(common.ExportCustomFields)
+	sheet := c.PostForm("export_sheet")
+	format, ok := logics.GetFileFormat(c.PostForm("export_format"), "")
+	if !ok {
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil))
+		return
+	}
+
+	inputJson := c.PostForm(metadata.BKMetadata)
+	metaInfo := metadata.Metadata{}
+	if err := json.Unmarshal([]byte(inputJson), &metaInfo); 0 != len(inputJson) && nil != err {
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommJSONUnmarshalFailed, defErr.Error(common.CCErrCommJSONUnmarshalFailed).Error(), nil))
+		return
+	}
+
+	customFields := logics.GetCustomFields(nil, customFieldsStr)
+	fields, err := s.Logics.GetObjFieldIDs(objID, nil, customFields, pheader, metaInfo)
+	if nil != err {
+		blog.Errorf("ExportInstJob get %s field error:%s, rid:%s", objID, err.Error(), util.GetHTTPCCRequestID(pheader))
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommExcelTemplateFailed, defErr.Errorf(common.CCErrCommExcelTemplateFailed, objID).Error(), nil))
+		return
+	}
+
+	s.startExportJob(c, objID, getExportFileName("inst_"+objID, sheet, format), fields, format, sheet, metaInfo,
+		func(header http.Header, handler logics.ExportPageHandler) error {
+			return s.Logics.ForEachInstPage(context.Background(), ownerID, objID, instIDStr, header, metaInfo, s.exportPageSize(), handler)
+		})
+}
+
+// getUserExportJob get the export job created by the current user
+func (s *Service) getUserExportJob(c *gin.Context) (*logics.ExportJob, bool) {
+	pheader := c.Request.Header
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
+	jobID := c.Param("job_id")
+
+	job, err := logics.GetExportJob(s.CacheCli, jobID)
+	if nil != err {
+		blog.Errorf("get export job %s error:%s, rid:%s", jobID, err.Error(), util.GetHTTPCCRequestID(pheader))
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommDBSelectFailed, defErr.Error(common.CCErrCommDBSelectFailed).Error(), nil))
+		return nil, false
+	}
+	if nil == job || job.User != util.GetUser(pheader) {
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebExportJobNotFound, defErr.Errorf(common.CCErrWebExportJobNotFound, jobID).Error(), nil))
+		return nil, false
+	}
+	return job, true
+}
+
+// GetExportJob get the status of the export job
+func (s *Service) GetExportJob(c *gin.Context) {
+	logics.SetProxyHeader(c)
+	job, ok := s.getUserExportJob(c)
+	if !ok {
+		return
+	}
+	c.String(http.StatusOK, getReturnStr(0, "", job))
+}
+
+// DownloadExportJob download the file exported by the finished export job
+func (s *Service) DownloadExportJob(c *gin.Context) {
+	logics.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(c.Request.Header))
+	job, ok := s.getUserExportJob(c)
+	if !ok {
+		return
+	}
+	if logics.ExportJobStatusSuccess != job.Status {
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebExportJobNotFinished, defErr.Errorf(common.CCErrWebExportJobNotFinished, job.ID).Error(), nil))
+		return
+	}
+
+	filePath := getExportJobFilePath(job)
+	if _, err := os.Stat(filePath); nil != err {
+		// the file is written to the web server which runs the job, download it from that server
+		if s.proxyExportJobDownload(c, job) {
+			return
+		}
+		blog.Errorf("export job %s file not found, err:%s, rid:%s", job.ID, err.Error(), util.GetHTTPCCRequestID(c.Request.Header))
+		c.String(http.StatusOK, getReturnStr(common.CCErrWebExportJobNotFound, defErr.Errorf(common.CCErrWebExportJobNotFound, job.ID).Error(), nil))
+		return
+	}
+
+	if logics.FileFormatExcel == job.Format {
+		logics.AddDownExcelHttpHeader(c, job.FileName)
+	} else {
+		logics.AddDownTabularHttpHeader(c, job.FileName, job.Format)
+	}
+	c.File(filePath)
+}
+
+// proxyExportJobDownload proxy the download request to the web server which runs the job,
+// return false if the job runs on the current server or the request has been proxied
+func (s *Service) proxyExportJobDownload(c *gin.Context, job *logics.ExportJob) bool {
+	if "" == job.Node || "" != c.Request.Header.Get(exportJobProxiedHeader) {
+		return false
+	}
+	if srvInfo := common.GetServerInfo(); nil != srvInfo && srvInfo.Address() == job.Node {
+		return false
+	}
+	blog.V(4).Infof("proxy the download of export job %s to %s, rid:%s", job.ID, job.Node, util.GetHTTPCCRequestID(c.Request.Header))
+	c.Request.Header.Set(exportJobProxiedHeader, "1")
+	httpclient.ProxyHttp(c, job.Node)
+	return true
+}
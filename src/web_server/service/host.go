@@ -19,6 +19,7 @@ import (
 	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"configcenter/src/common"
@@ -47,6 +48,30 @@ func (s *Service) ImportHost(c *gin.Context) {
 	}
 	logics.SetProxyHeader(c)
 
+	format, ok := logics.GetFileFormat(c.PostForm("import_format"), file.Filename)
+	if !ok {
+		msg := getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil)
+		c.String(http.StatusOK, string(msg))
+		return
+	}
+	if logics.FileFormatExcel != format {
+		fd, err := file.Open()
+		if nil != err {
+			msg := getReturnStr(common.CCErrWebOpenFileFail, defErr.Errorf(common.CCErrWebOpenFileFail, err.Error()).Error(), nil)
+			c.String(http.StatusOK, string(msg))
+			return
+		}
+		defer fd.Close()
+		data, errCode, err := s.Logics.ImportHostsFromTabular(context.Background(), fd, format, c.PostForm("import_sheet"), c.Request.Header, defLang, metadata.Metadata{}, getImportOption(c))
+		if nil != err {
+			msg := getReturnStr(errCode, err.Error(), data)
+			c.String(http.StatusOK, string(msg))
+			return
+		}
+		c.String(http.StatusOK, getReturnStr(0, "", data))
+		return
+	}
+
 	randNum := rand.Uint32()
 	dir := webCommon.ResourcePath + "/import/"
 	_, err = os.Stat(dir)
@@ -67,7 +92,7 @@ func (s *Service) ImportHost(c *gin.Context) {
 		c.String(http.StatusOK, string(msg))
 		return
 	}
-	data, errCode, err := s.Logics.ImportHosts(context.Background(), f, c.Request.Header, defLang, metadata.Metadata{})
+	data, errCode, err := s.Logics.ImportHosts(context.Background(), f, c.Request.Header, defLang, metadata.Metadata{}, getImportOption(c))
 
 	if nil != err {
 		msg := getReturnStr(errCode, err.Error(), data)
@@ -91,15 +116,12 @@ func (s *Service) ExportHost(c *gin.Context) {
 	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(pheader))
 	customFieldsStr := c.PostForm(common.ExportCustomFields)
 
-	hostInfo, err := s.Logics.GetHostData(appIDStr, hostIDStr, pheader)
-	if err != nil {
-		blog.Error(err.Error())
-		msg := getReturnStr(common.CCErrWebGetHostFail, defErr.Errorf(common.CCErrWebGetHostFail, err.Error()).Error(), nil)
-		c.String(http.StatusInternalServerError, msg, nil)
+	format, ok := logics.GetFileFormat(c.PostForm("export_format"), "")
+	if !ok {
+		msg := getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil)
+		c.String(http.StatusOK, msg)
 		return
 	}
-	var file *xlsx.File
-	file = xlsx.NewFile()
 
 	objID := common.BKInnerObjIDHost
 	filterFields := logics.GetFilterFields(objID)
@@ -111,6 +133,36 @@ func (s *Service) ExportHost(c *gin.Context) {
 		c.Writer.Write([]byte(reply))
 		return
 	}
+
+	if logics.FileFormatExcel != format {
+		sheet := c.PostForm("export_sheet")
+		w := newDownloadWriter(c, getExportFileName(objID, sheet, format), format)
+		exporter, err := s.Logics.NewTabularExporter(context.Background(), objID, fields, nil, format, sheet, w, pheader, metadata.Metadata{})
+		if nil == err {
+			err = s.Logics.ForEachHostPage(context.Background(), appIDStr, hostIDStr, pheader, s.exportPageSize(), exporter.WritePage)
+		}
+		if nil == err {
+			err = exporter.Flush()
+		}
+		if nil != err {
+			blog.Errorf("ExportHost object:%s to %s error:%s, rid:%s", objID, format, err.Error(), util.GetHTTPCCRequestID(pheader))
+			w.Fail(getReturnStr(common.CCErrWebGetHostFail, defErr.Errorf(common.CCErrWebGetHostFail, err.Error()).Error(), nil))
+			return
+		}
+		w.Done()
+		return
+	}
+
+	hostInfo, err := s.Logics.GetHostData(appIDStr, hostIDStr, pheader)
+	if err != nil {
+		blog.Error(err.Error())
+		msg := getReturnStr(common.CCErrWebGetHostFail, defErr.Errorf(common.CCErrWebGetHostFail, err.Error()).Error(), nil)
+		c.String(http.StatusInternalServerError, msg, nil)
+		return
+	}
+
+	var file *xlsx.File
+	file = xlsx.NewFile()
 	err = s.Logics.BuildHostExcelFromData(context.Background(), objID, fields, nil, hostInfo, file, pheader, metadata.Metadata{})
 	if nil != err {
 		blog.Errorf("ExportHost object:%s error:%s, rid:%s", objID, err.Error(), util.GetHTTPCCRequestID(c.Request.Header))
@@ -197,3 +249,13 @@ func getReturnStr(code int, message string, data interface{}) string {
 	return string(msg)
 
 }
+
+// getImportOption get the options of the import from the form values
+func getImportOption(c *gin.Context) logics.ImportOption {
+	validateOnly, _ := strconv.ParseBool(c.PostForm("validate_only"))
+	allOrNothing, _ := strconv.ParseBool(c.PostForm("all_or_nothing"))
+	return logics.ImportOption{
+		ValidateOnly: validateOnly,
+		AllOrNothing: allOrNothing,
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 	webCommon "configcenter/src/web_server/common"
 	"configcenter/src/web_server/logics"
 
@@ -55,6 +56,30 @@ func (s *Service) ImportInst(c *gin.Context) {
 		return
 	}
 
+	format, ok := logics.GetFileFormat(c.PostForm("import_format"), file.Filename)
+	if !ok {
+		msg := getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil)
+		c.String(http.StatusOK, string(msg))
+		return
+	}
+	if logics.FileFormatExcel != format {
+		fd, err := file.Open()
+		if nil != err {
+			msg := getReturnStr(common.CCErrWebOpenFileFail, defErr.Errorf(common.CCErrWebOpenFileFail, err.Error()).Error(), nil)
+			c.String(http.StatusOK, string(msg))
+			return
+		}
+		defer fd.Close()
+		data, errCode, err := s.Logics.ImportInstsFromTabular(context.Background(), fd, format, c.PostForm("import_sheet"), objID, c.Request.Header, defLang, metaInfo, getImportOption(c))
+		if nil != err {
+			msg := getReturnStr(errCode, err.Error(), data)
+			c.String(http.StatusOK, string(msg))
+			return
+		}
+		c.String(http.StatusOK, getReturnStr(0, "", data))
+		return
+	}
+
 	randNum := rand.Uint32()
 	dir := webCommon.ResourcePath + "/import/"
 	_, err = os.Stat(dir)
@@ -76,7 +101,7 @@ func (s *Service) ImportInst(c *gin.Context) {
 		return
 	}
 
-	data, errCode, err := s.Logics.ImportInsts(context.Background(), f, objID, c.Request.Header, defLang, metaInfo)
+	data, errCode, err := s.Logics.ImportInsts(context.Background(), f, objID, c.Request.Header, defLang, metaInfo, getImportOption(c))
 
 	if nil != err {
 		msg := getReturnStr(errCode, err.Error(), data)
@@ -108,6 +133,40 @@ func (s *Service) ExportInst(c *gin.Context) {
 		return
 	}
 
+	format, ok := logics.GetFileFormat(c.PostForm("export_format"), "")
+	if !ok {
+		msg := getReturnStr(common.CCErrWebFileFormatNotSupported, defErr.Errorf(common.CCErrWebFileFormatNotSupported, format).Error(), nil)
+		c.String(http.StatusOK, msg)
+		return
+	}
+
+	customFields := logics.GetCustomFields(nil, customFieldsStr)
+	fields, err := s.Logics.GetObjFieldIDs(objID, nil, customFields, pheader, metaInfo)
+	if logics.FileFormatExcel != format {
+		if nil != err {
+			blog.Errorf("ExportInst get %s field error:%s", objID, err.Error())
+			reply := getReturnStr(common.CCErrCommExcelTemplateFailed, defErr.Errorf(common.CCErrCommExcelTemplateFailed, objID).Error(), nil)
+			c.Writer.Write([]byte(reply))
+			return
+		}
+		sheet := c.PostForm("export_sheet")
+		w := newDownloadWriter(c, getExportFileName("inst_"+objID, sheet, format), format)
+		exporter, err := s.Logics.NewTabularExporter(context.Background(), objID, fields, nil, format, sheet, w, pheader, metaInfo)
+		if nil == err {
+			err = s.Logics.ForEachInstPage(context.Background(), ownerID, objID, instIDStr, pheader, metaInfo, s.exportPageSize(), exporter.WritePage)
+		}
+		if nil == err {
+			err = exporter.Flush()
+		}
+		if nil != err {
+			blog.Errorf("ExportInst object:%s to %s error:%s, rid:%s", objID, format, err.Error(), util.GetHTTPCCRequestID(pheader))
+			w.Fail(getReturnStr(common.CCErrWebGetObjectFail, defErr.Errorf(common.CCErrWebGetObjectFail, err.Error()).Error(), nil))
+			return
+		}
+		w.Done()
+		return
+	}
+
 	kvMap := mapstr.MapStr{}
 	instInfo, err := s.Logics.GetInstData(ownerID, objID, instIDStr, pheader, kvMap, metaInfo)
 
@@ -123,8 +182,6 @@ func (s *Service) ExportInst(c *gin.Context) {
 
 	file = xlsx.NewFile()
 
-	customFields := logics.GetCustomFields(nil, customFieldsStr)
-	fields, err := s.Logics.GetObjFieldIDs(objID, nil, customFields, pheader, metaInfo)
 	err = s.Logics.BuildExcelFromData(context.Background(), objID, fields, nil, instInfo, file, pheader, metaInfo)
 	if nil != err {
 		blog.Errorf("ExportHost object:%s error:%s", objID, err.Error())
@@ -13,8 +13,15 @@
 package service
 
 import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
 	"configcenter/src/web_server/middleware/user"
+	"configcenter/src/web_server/middleware/user/plugins/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/holmeswang/contrib/sessions"
@@ -33,3 +40,65 @@ func (s *Service) LogOutUser(c *gin.Context) {
 	c.JSON(200, ret)
 	return
 }
+
+// loginPageTemplate the login page of the login systems which authenticate with user name and password
+var loginPageTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>CMDB</title></head>
+<body>
+<form method="post" action="/login">
+{{if .Message}}<p style="color:red">{{.Message}}</p>{{end}}
+<input type="hidden" name="c_url" value="{{.RedirectURL}}">
+<p><input type="text" name="username" placeholder="username" autofocus></p>
+<p><input type="password" name="password" placeholder="password"></p>
+<p><button type="submit">login</button></p>
+</form>
+</body>
+</html>`))
+
+type loginPage struct {
+	RedirectURL string
+	Message     string
+}
+
+func renderLoginPage(c *gin.Context, status int, page loginPage) {
+	buf := new(bytes.Buffer)
+	if err := loginPageTemplate.Execute(buf, page); nil != err {
+		blog.Errorf("render login page failed, err: %v", err)
+		c.String(http.StatusInternalServerError, "render login page failed")
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// LoginPage show the login page
+func (s *Service) LoginPage(c *gin.Context) {
+	renderLoginPage(c, http.StatusOK, loginPage{RedirectURL: util.RedirectPath(c.Query("c_url"))})
+}
+
+// Login login the user with the credentials posted by the login page,
+// or with the callback of the login system, then redirect to the page before login
+func (s *Service) Login(c *gin.Context) {
+	userManger := user.NewUser(*s.Config, s.Engine, s.CacheCli, s.VersionPlg)
+	session := sessions.Default(c)
+	if !userManger.LoginUser(c) {
+		if http.MethodPost == c.Request.Method {
+			renderLoginPage(c, http.StatusUnauthorized, loginPage{
+				RedirectURL: util.RedirectPath(c.PostForm("c_url")),
+				Message:     "invalid user name or password",
+			})
+			return
+		}
+		session.Save()
+		c.Data(http.StatusUnauthorized, "text/html; charset=utf-8", []byte(`login failed, <a href="/">try again</a>`))
+		return
+	}
+
+	target := c.PostForm("c_url")
+	if "" == target {
+		target, _ = session.Get(common.WEBSessionLoginRedirectKey).(string)
+	}
+	session.Delete(common.WEBSessionLoginRedirectKey)
+	session.Save()
+	c.Redirect(http.StatusFound, util.RedirectPath(target))
+}
@@ -51,9 +51,16 @@ func (s *Service) WebService() *gin.Engine {
 
 	ws.POST("/hosts/import", s.ImportHost)
 	ws.POST("/hosts/export", s.ExportHost)
+	ws.POST("/hosts/export/job", s.ExportHostJob)
 	ws.GET("/importtemplate/:bk_obj_id", s.BuildDownLoadExcelTemplate)
 	ws.POST("/insts/owner/:bk_supplier_account/object/:bk_obj_id/import", s.ImportInst)
 	ws.POST("/insts/owner/:bk_supplier_account/object/:bk_obj_id/export", s.ExportInst)
+	ws.POST("/insts/owner/:bk_supplier_account/object/:bk_obj_id/export/job", s.ExportInstJob)
+	ws.GET("/export/job/:job_id", s.GetExportJob)
+	ws.GET("/export/job/:job_id/download", s.DownloadExportJob)
+	ws.GET("/login", s.LoginPage)
+	ws.POST("/login", s.Login)
+	ws.GET("/login/callback", s.Login)
 	ws.POST("/logout", s.LogOutUser)
 	ws.POST("/object/owner/:bk_supplier_account/object/:bk_obj_id/import", s.ImportObject)
 	ws.POST("/object/owner/:bk_supplier_account/object/:bk_obj_id/export", s.ExportObject)